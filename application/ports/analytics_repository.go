@@ -1,14 +1,20 @@
 package ports
 
 import (
+	"codeecho/domain/entities"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
 	"codeecho/internal/models"
 	"time"
 )
 
 // AnalyticsRepository interface defines the contract for analytics data access
 type AnalyticsRepository interface {
-	GetProjectOverview(projectID int) (*models.ProjectOverview, error)
-	GetFileOwnership(projectID int) ([]models.FileOwnership, error)
+	// GetProjectOverview returns overview statistics with the debt trend of the last trendMonths
+	// monthly health snapshots
+	GetProjectOverview(projectID int, trendMonths int) (*models.ProjectOverview, error)
+	// GetFileOwnership returns per-file author shares weighted by the given ownership metric
+	GetFileOwnership(projectID int, metric values.OwnershipMetric) ([]models.FileOwnership, error)
 	GetAuthorHotspots(projectID int) ([]models.AuthorHotspot, error)
 	// GetTemporalCoupling returns file pairs with filtering support
 	// Optional date range: if startDate or endDate is empty string they are ignored.
@@ -16,8 +22,50 @@ type AnalyticsRepository interface {
 	// minCouplingScore: minimum coupling score threshold (0.0 to 1.0)
 	// fileTypes: comma-separated file extensions like "php,js,py"
 	GetTemporalCoupling(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error)
-	// GetProjectFileTypes returns available file extensions for a project
-	GetProjectFileTypes(projectID int) ([]string, error)
-	// GetBusFactorAnalysis returns bus factor data for all files in a project
-	GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string) ([]models.BusFactorData, error)
+	// GetCouplingGraph returns every file pair passing the same filters as GetTemporalCoupling, uncapped
+	GetCouplingGraph(projectID int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error)
+	// GetAggregatedTemporalCoupling returns coupling between groups of files (directories, layers)
+	// with the same filters as GetTemporalCoupling. group maps a file to its group, or false to skip it;
+	// changeSets selects which commits count as one logical change.
+	GetAggregatedTemporalCoupling(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string, group func(filePath string) (string, bool), changeSets services.ChangeSetGrouping) ([]models.TemporalCoupling, error)
+	// GetProjectFileTypes returns available file extensions with file counts and LOC for a project
+	GetProjectFileTypes(projectID int) ([]models.FileTypeStat, error)
+	// GetLanguageBreakdown returns code, comment and blank lines per language at the analysed commit
+	GetLanguageBreakdown(projectID int) ([]models.LanguageBreakdown, error)
+	// GetBusFactorAnalysis returns bus factor data for all files in a project, weighting
+	// authors by the given ownership metric
+	GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string, metric values.OwnershipMetric) ([]models.BusFactorData, error)
+	// GetFileAges returns the files of the analysed tree with their size and last modification time
+	GetFileAges(projectID int) ([]models.FileAge, error)
+	// GetLineAges returns the blame segments of the analysed tree
+	GetLineAges(projectID int) ([]models.LineAgeSegment, error)
+	// GetFileAuthorship returns per-file, per-author commit counts and file creators for the
+	// files in the analysed tree, optionally restricted to a path prefix
+	GetFileAuthorship(projectID int, path string) ([]models.FileAuthorship, error)
+	// GetAuthorActivity returns every author's commit count and first and last commit times
+	GetAuthorActivity(projectID int) ([]models.AuthorActivity, error)
+	// GetDefectHotspots returns the files touched by bug-fix commits with their bug-fix and total
+	// commit counts and current code lines, optionally within dates and under a path prefix
+	GetDefectHotspots(projectID int, startDate, endDate, path string) ([]models.DefectHotspot, error)
+	// GetCommitCategoryCounts returns the number of commits per category, with unclassified
+	// commits under the empty category
+	GetCommitCategoryCounts(projectID int, startDate, endDate string) (map[string]int, error)
+	// GetIssues returns the issues referenced by a project's commits, optionally of one type
+	GetIssues(projectID int, issueType string) ([]models.IssueSummary, error)
+	// GetIssue returns one referenced issue, or nil when no commit references it
+	GetIssue(projectID int, key string) (*models.IssueSummary, error)
+	// GetIssueCommits returns the commits referencing an issue, newest first
+	GetIssueCommits(projectID int, key string) ([]models.IssueCommit, error)
+	// GetIssueFiles returns the files changed by the commits referencing an issue
+	GetIssueFiles(projectID int, key string) ([]models.IssueFile, error)
+	// GetIssueAuthors returns the authors of the commits referencing an issue
+	GetIssueAuthors(projectID int, key string) ([]models.IssueAuthor, error)
+	// GetFileIssues returns the issues referenced by the commits that changed a file
+	GetFileIssues(projectID int, filePath string) ([]models.FileIssue, error)
+	// GetPeriodCommits returns the commits made after the start (the zero time for the beginning
+	// of history) up to and including the end, with the files each one changed
+	GetPeriodCommits(projectID int, start, end time.Time) ([]services.PeriodCommit, error)
+	// GetRiskPolicy returns the current version of a project's risk policy, or nil when it uses
+	// the default policy
+	GetRiskPolicy(projectID int) (*entities.RiskPolicy, error)
 }
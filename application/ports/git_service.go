@@ -1,5 +1,13 @@
 package ports
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrFileNotFound is returned when a file does not exist in the tree of a commit
+var ErrFileNotFound = errors.New("file not found")
+
 // GitService defines the interface for git operations
 type GitService interface {
 	// GetCommits retrieves commits from a git repository
@@ -22,6 +30,25 @@ type GitService interface {
 
 	// ProcessLocalArchive extracts and processes an uploaded local directory archive
 	ProcessLocalArchive(archivePath, extractPath string) (string, error)
+
+	// GetFileContentAtCommit reads the blob of a file as it was at the given commit, failing
+	// with ErrFileNotFound when the file is not part of that commit
+	GetFileContentAtCommit(repoPath string, commitHash string, filePath string) (string, error)
+
+	// WalkFilesAtCommit calls visit for every file in the tree of the given commit
+	WalkFilesAtCommit(repoPath string, commitHash string, visit func(file *GitFile) error) error
+
+	// ListFilesAtCommit lists the paths of the files in the tree of the given commit without reading them
+	ListFilesAtCommit(repoPath string, commitHash string) ([]string, error)
+
+	// DiffRefs lists the files changed on head since it diverged from base
+	DiffRefs(repoPath string, baseRef string, headRef string) ([]*GitChange, error)
+
+	// BlameFile attributes every line of a file at the given commit to the commit that last changed it
+	BlameFile(repoPath string, commitHash string, filePath string) ([]*GitBlameLine, error)
+
+	// GetTags lists the tags of a repository that point at commits
+	GetTags(repoPath string) ([]*GitTag, error)
 }
 
 // GitAuthConfig holds authentication configuration for private repositories
@@ -40,6 +67,31 @@ type GitCommit struct {
 	Changes   []*GitChange
 }
 
+// GitFile represents a file in the tree of a commit. Binary files are recognised from
+// their first bytes and their Content is left empty rather than read.
+type GitFile struct {
+	Path     string
+	Size     int64
+	IsBinary bool
+	Content  string
+}
+
+// GitBlameLine represents the origin of a single line of a file
+type GitBlameLine struct {
+	Author     string
+	CommitHash string
+	Date       time.Time
+}
+
+// GitTag represents a tag and the commit it points at. TaggedAt is the tagger date of
+// annotated tags and the author date of the commit for lightweight tags.
+type GitTag struct {
+	Name       string
+	CommitHash string
+	CommitDate time.Time
+	TaggedAt   time.Time
+}
+
 // GitChange represents a file change in a commit
 type GitChange struct {
 	FilePath     string
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"fmt"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+)
+
+// ClassificationUseCase manages the rules that classify a project's commits as bugfix,
+// feature, refactor, docs, test or chore, and applies them to the stored commits
+type ClassificationUseCase struct {
+	commitRepo repositories.CommitRepository
+	ruleRepo   repositories.ClassificationRuleRepository
+	issueRepo  repositories.IssueRepository
+}
+
+// NewClassificationUseCase creates a new classification use case
+func NewClassificationUseCase(commitRepo repositories.CommitRepository, ruleRepo repositories.ClassificationRuleRepository, issueRepo repositories.IssueRepository) *ClassificationUseCase {
+	return &ClassificationUseCase{
+		commitRepo: commitRepo,
+		ruleRepo:   ruleRepo,
+		issueRepo:  issueRepo,
+	}
+}
+
+// GetRules returns the rules applied to a project and whether they are the defaults
+func (uc *ClassificationUseCase) GetRules(projectID int) ([]entities.ClassificationRule, bool, error) {
+	rules, err := uc.ruleRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(rules) == 0 {
+		return services.DefaultClassificationRules, true, nil
+	}
+	return rules, false, nil
+}
+
+// ReplaceRules validates and stores a project's rules, then reclassifies its commits with
+// them. An empty list restores the defaults. It returns the number of reclassified commits.
+func (uc *ClassificationUseCase) ReplaceRules(projectID int, rules []entities.ClassificationRule) (int, error) {
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := uc.ruleRepo.ReplaceForProject(projectID, rules); err != nil {
+		return 0, err
+	}
+	return uc.Reclassify(projectID)
+}
+
+// Reclassify recomputes the category of every stored commit of a project under its current
+// rules and imported issue types, returning the number of commits whose category changed
+func (uc *ClassificationUseCase) Reclassify(projectID int) (int, error) {
+	rules, err := uc.ruleRepo.GetByProjectID(projectID)
+	if err != nil {
+		return 0, err
+	}
+	issueTypes, err := uc.issueRepo.GetIssueTypes(projectID)
+	if err != nil {
+		return 0, err
+	}
+	patterns, err := uc.issueRepo.GetPatterns(projectID)
+	if err != nil {
+		return 0, err
+	}
+	issuePattern, err := services.CompileIssuePatterns(patterns)
+	if err != nil {
+		return 0, err
+	}
+	classifier := services.NewCommitClassifier(rules, issueTypes, issuePattern)
+
+	commits, err := uc.commitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load commits: %w", err)
+	}
+
+	changed := make(map[int]values.CommitCategory)
+	for _, commit := range commits {
+		if category := classifier.Classify(commit.Message); category != commit.Category {
+			changed[commit.ID] = category
+		}
+	}
+
+	if err := uc.commitRepo.UpdateCategories(changed); err != nil {
+		return 0, fmt.Errorf("failed to update commit categories: %w", err)
+	}
+	return len(changed), nil
+}
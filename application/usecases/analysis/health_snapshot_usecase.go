@@ -0,0 +1,192 @@
+package analysis
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+)
+
+// HealthSnapshotUseCase computes the monthly debt score snapshots of a project (see
+// services.ScoreDebt) by replaying its stored history and reading its trees from git
+type HealthSnapshotUseCase struct {
+	commitRepo   repositories.CommitRepository
+	changeRepo   repositories.ChangeRepository
+	snapshotRepo repositories.HealthSnapshotRepository
+	policyRepo   repositories.RiskPolicyRepository
+	gitService   ports.GitService
+}
+
+// NewHealthSnapshotUseCase creates a new health snapshot use case
+func NewHealthSnapshotUseCase(
+	commitRepo repositories.CommitRepository,
+	changeRepo repositories.ChangeRepository,
+	snapshotRepo repositories.HealthSnapshotRepository,
+	policyRepo repositories.RiskPolicyRepository,
+	gitService ports.GitService,
+) *HealthSnapshotUseCase {
+	return &HealthSnapshotUseCase{
+		commitRepo:   commitRepo,
+		changeRepo:   changeRepo,
+		snapshotRepo: snapshotRepo,
+		policyRepo:   policyRepo,
+		gitService:   gitService,
+	}
+}
+
+// hotspotComplexity is the cached mean complexity of a file revision; binary files and files
+// that could not be read are not measured
+type hotspotComplexity struct {
+	mean     float64
+	measured bool
+}
+
+// RecordSnapshots computes and stores the monthly snapshots (UTC) of a project under its current
+// risk policy, returning the number stored. A rebuild computes every month from the project's
+// first commit to its last and replaces all stored snapshots; it reads a tree from git per
+// month, so it runs on explicit request or in the background of policy changes. Otherwise only
+// the months from the latest stored snapshot on are computed, as after an analysis: that month
+// may have been incomplete, and earlier months keep the policy they were computed under.
+func (uc *HealthSnapshotUseCase) RecordSnapshots(projectID int, repoPath string, rebuild bool) (int, error) {
+	stored, err := uc.policyRepo.GetCurrent(projectID)
+	if err != nil {
+		return 0, err
+	}
+	policy := services.EffectiveRiskPolicy(stored)
+	assessor := services.NewRiskAssessor(policy)
+
+	var since time.Time
+	if !rebuild {
+		latest, err := uc.snapshotRepo.GetLatest(projectID)
+		if err != nil {
+			return 0, err
+		}
+		if latest != nil {
+			since = latest.Month
+		}
+	}
+
+	commits, err := uc.commitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return 0, nil
+	}
+	changes, err := uc.changeRepo.GetByProjectID(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load changes: %w", err)
+	}
+
+	changesByCommit := make(map[int][]services.PeriodChange)
+	for _, change := range changes {
+		if change.FilePath != nil {
+			changesByCommit[change.CommitID] = append(changesByCommit[change.CommitID], services.PeriodChange{
+				FilePath:     change.FilePath.String(),
+				LinesAdded:   change.LinesAdded,
+				LinesDeleted: change.LinesDeleted,
+			})
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		if !commits[i].Timestamp.Equal(commits[j].Timestamp) {
+			return commits[i].Timestamp.Before(commits[j].Timestamp)
+		}
+		return commits[i].ID < commits[j].ID
+	})
+
+	tracker := services.NewDebtTracker()
+	complexities := make(map[string]hotspotComplexity)
+	computedAt := time.Now()
+	lastMonth := monthStart(commits[len(commits)-1].Timestamp)
+
+	var snapshots []entities.HealthSnapshot
+	var monthCommit *entities.Commit
+	next := 0
+	for month := monthStart(commits[0].Timestamp); !month.After(lastMonth); month = month.AddDate(0, 1, 0) {
+		end := month.AddDate(0, 1, 0)
+		for ; next < len(commits) && commits[next].Timestamp.Before(end); next++ {
+			commit := commits[next]
+			tracker.Add(commit.Timestamp, services.PeriodCommit{Author: commit.Author, Changes: changesByCommit[commit.ID]})
+			monthCommit = commit
+		}
+		if month.Before(since) {
+			continue
+		}
+
+		snapshot, err := uc.measure(tracker, complexities, assessor, repoPath, monthCommit.Hash.String(), end)
+		if err != nil {
+			return 0, fmt.Errorf("failed to measure %s: %w", month.Format("2006-01"), err)
+		}
+		snapshot.ProjectID = projectID
+		snapshot.Month = month
+		snapshot.PolicyVersion = policy.Version
+		snapshot.ComputedAt = computedAt
+		snapshots = append(snapshots, snapshot)
+	}
+
+	// The old snapshots are kept while the months are measured, so the trend stays readable
+	if rebuild {
+		if err := uc.snapshotRepo.DeleteByProjectID(projectID); err != nil {
+			return 0, fmt.Errorf("failed to clear health snapshots: %w", err)
+		}
+	}
+	if err := uc.snapshotRepo.Save(snapshots); err != nil {
+		return 0, fmt.Errorf("failed to store health snapshots: %w", err)
+	}
+
+	log.Printf("Recorded %d health snapshots for project %d", len(snapshots), projectID)
+	return len(snapshots), nil
+}
+
+// measure scores the tree of a commit under the assessor's policy with the activity window ending at end
+func (uc *HealthSnapshotUseCase) measure(tracker *services.DebtTracker, complexities map[string]hotspotComplexity, assessor *services.RiskAssessor, repoPath, commitHash string, end time.Time) (entities.HealthSnapshot, error) {
+	files, err := uc.gitService.ListFilesAtCommit(repoPath, commitHash)
+	if err != nil {
+		return entities.HealthSnapshot{}, err
+	}
+
+	inputs := tracker.Measure(files, end, assessor)
+	for _, file := range inputs.Hotspots {
+		// A file's content only changes with its commits, so revisions are cached by the time
+		// of their last modification
+		modified, _ := tracker.LastModified(file)
+		key := file + "@" + modified.Format(time.RFC3339Nano)
+		complexity, ok := complexities[key]
+		if !ok {
+			content, err := uc.gitService.GetFileContentAtCommit(repoPath, commitHash, file)
+			if err != nil {
+				log.Printf("Skipping complexity of %s at %s: %v", file, commitHash, err)
+			} else if !services.IsBinaryContent(content) {
+				complexity = hotspotComplexity{mean: services.MeasureComplexity(content).MeanComplexity, measured: true}
+			}
+			complexities[key] = complexity
+		}
+		if complexity.measured {
+			inputs.HotspotComplexity = append(inputs.HotspotComplexity, complexity.mean)
+		}
+	}
+
+	score := services.ScoreDebt(inputs, assessor)
+	return entities.HealthSnapshot{
+		CommitHash:        commitHash,
+		Score:             score.Score,
+		HotspotComplexity: score.HotspotComplexity,
+		Coupling:          score.Coupling,
+		BusFactorRisk:     score.BusFactorRisk,
+		StaleCode:         score.StaleCode,
+		Files:             inputs.Files,
+		ActiveFiles:       inputs.ActiveFiles,
+	}, nil
+}
+
+// monthStart returns the first instant of the UTC calendar month of a time
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+)
+
+type snapshotTestCommits struct {
+	repositories.CommitRepository
+	commits []*entities.Commit
+}
+
+func (r *snapshotTestCommits) GetByProjectID(projectID int) ([]*entities.Commit, error) {
+	return r.commits, nil
+}
+
+type snapshotTestChanges struct {
+	repositories.ChangeRepository
+	changes []*entities.Change
+}
+
+func (r *snapshotTestChanges) GetByProjectID(projectID int) ([]*entities.Change, error) {
+	return r.changes, nil
+}
+
+// snapshotTestStore keeps the snapshots saved last
+type snapshotTestStore struct {
+	repositories.HealthSnapshotRepository
+	saved   []entities.HealthSnapshot
+	cleared bool
+}
+
+func (r *snapshotTestStore) GetLatest(projectID int) (*entities.HealthSnapshot, error) {
+	if len(r.saved) == 0 {
+		return nil, nil
+	}
+	return &r.saved[len(r.saved)-1], nil
+}
+
+func (r *snapshotTestStore) Save(snapshots []entities.HealthSnapshot) error {
+	r.saved = snapshots
+	return nil
+}
+
+func (r *snapshotTestStore) DeleteByProjectID(projectID int) error {
+	r.cleared = true
+	r.saved = nil
+	return nil
+}
+
+type snapshotTestPolicies struct {
+	repositories.RiskPolicyRepository
+	current *entities.RiskPolicy
+}
+
+func (r *snapshotTestPolicies) GetCurrent(projectID int) (*entities.RiskPolicy, error) {
+	return r.current, nil
+}
+
+// snapshotTestGit serves the tree of each commit; every file has the same content
+type snapshotTestGit struct {
+	ports.GitService
+	trees map[string][]string
+}
+
+func (g *snapshotTestGit) ListFilesAtCommit(repoPath string, commitHash string) ([]string, error) {
+	return g.trees[commitHash], nil
+}
+
+func (g *snapshotTestGit) GetFileContentAtCommit(repoPath string, commitHash string, filePath string) (string, error) {
+	return "func f() {\n\tif a {\n\t}\n}\n", nil
+}
+
+func TestRecordSnapshots(t *testing.T) {
+	commits := &snapshotTestCommits{}
+	changes := &snapshotTestChanges{}
+	gitService := &snapshotTestGit{trees: map[string][]string{}}
+	tree := []string{}
+	for i, entry := range []struct {
+		day    time.Time
+		author string
+		files  []string
+	}{
+		{time.Date(2025, 1, 5, 9, 0, 0, 0, time.UTC), "ann", []string{"main.go"}},
+		{time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC), "bob", []string{"main.go", "util.go"}},
+		{time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), "ann", []string{"util.go"}},
+	} {
+		hash, err := values.NewGitHash(fmt.Sprintf("%040x", i+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit := entities.NewCommit(1, hash, entry.author, entry.day, "change")
+		commit.ID = i + 1
+		commits.commits = append(commits.commits, commit)
+		for _, file := range entry.files {
+			path, err := values.NewFilePath(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changes.changes = append(changes.changes, entities.NewChange(commit.ID, path, 10, 0))
+			if !slices.Contains(tree, file) {
+				tree = append(tree, file)
+			}
+		}
+		gitService.trees[hash.String()] = append([]string(nil), tree...)
+	}
+	store := &snapshotTestStore{}
+	policies := &snapshotTestPolicies{}
+	uc := NewHealthSnapshotUseCase(commits, changes, store, policies, gitService)
+
+	// Every month from the first commit to the last is measured on its last tree, February
+	// on January's
+	recorded, err := uc.RecordSnapshots(1, "/repo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded != 3 || len(store.saved) != 3 {
+		t.Fatalf("expected three snapshots, got %d", recorded)
+	}
+	for i, want := range []struct {
+		month string
+		hash  string
+	}{
+		{"2025-01", commits.commits[1].Hash.String()},
+		{"2025-02", commits.commits[1].Hash.String()},
+		{"2025-03", commits.commits[2].Hash.String()},
+	} {
+		snapshot := store.saved[i]
+		if snapshot.Month.Format("2006-01") != want.month || snapshot.CommitHash != want.hash || snapshot.Files != 2 {
+			t.Errorf("snapshot %d: expected %s at %s over two files, got %+v", i, want.month, want.hash, snapshot)
+		}
+		if snapshot.Score < 0 || snapshot.Score > 100 || snapshot.HotspotComplexity == 0 {
+			t.Errorf("snapshot %d: expected a measured score, got %+v", i, snapshot)
+		}
+	}
+
+	// Later runs recompute the latest month only, under a changed policy as well, unless rebuilding
+	changed := services.DefaultRiskPolicy
+	changed.Version = 2
+	policies.current = &changed
+	if recorded, err = uc.RecordSnapshots(1, "/repo", false); err != nil || recorded != 1 ||
+		store.saved[0].Month.Month() != time.March || store.saved[0].PolicyVersion != 2 {
+		t.Errorf("expected March to be recomputed under version 2, got %d (%v)", recorded, err)
+	}
+	if recorded, err = uc.RecordSnapshots(1, "/repo", true); err != nil || recorded != 3 || !store.cleared || store.saved[0].PolicyVersion != 2 {
+		t.Errorf("expected a rebuild of three months under version 2, got %d (%v)", recorded, err)
+	}
+}
@@ -0,0 +1,148 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+)
+
+// IssueUseCase manages the patterns that extract issue keys such as PROJ-123 or #456 from
+// commit messages, the resulting commit-issue links and imported issue metadata
+type IssueUseCase struct {
+	commitRepo     repositories.CommitRepository
+	issueRepo      repositories.IssueRepository
+	classification *ClassificationUseCase
+}
+
+// NewIssueUseCase creates a new issue use case
+func NewIssueUseCase(commitRepo repositories.CommitRepository, ruleRepo repositories.ClassificationRuleRepository, issueRepo repositories.IssueRepository) *IssueUseCase {
+	return &IssueUseCase{
+		commitRepo:     commitRepo,
+		issueRepo:      issueRepo,
+		classification: NewClassificationUseCase(commitRepo, ruleRepo, issueRepo),
+	}
+}
+
+// GetPatterns returns the issue key patterns applied to a project and whether they are the defaults
+func (uc *IssueUseCase) GetPatterns(projectID int) ([]string, bool, error) {
+	patterns, err := uc.issueRepo.GetPatterns(projectID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(patterns) == 0 {
+		return services.DefaultIssuePatterns, true, nil
+	}
+	return patterns, false, nil
+}
+
+// ReplacePatterns validates and stores a project's issue key patterns, then relinks and
+// reclassifies its commits with them. An empty list restores the defaults. It returns the
+// number of commit-issue links.
+func (uc *IssueUseCase) ReplacePatterns(projectID int, patterns []string) (int, error) {
+	if _, err := services.CompileIssuePatterns(patterns); err != nil {
+		return 0, err
+	}
+
+	if err := uc.issueRepo.ReplacePatterns(projectID, patterns); err != nil {
+		return 0, err
+	}
+	linked, err := uc.Relink(projectID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := uc.classification.Reclassify(projectID); err != nil {
+		return 0, err
+	}
+	return linked, nil
+}
+
+// Relink extracts the issue keys of every stored commit of a project under its current
+// patterns, replacing the existing links, and returns the number of links
+func (uc *IssueUseCase) Relink(projectID int) (int, error) {
+	patterns, err := uc.issueRepo.GetPatterns(projectID)
+	if err != nil {
+		return 0, err
+	}
+	pattern, err := services.CompileIssuePatterns(patterns)
+	if err != nil {
+		return 0, err
+	}
+
+	commits, err := uc.commitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load commits: %w", err)
+	}
+
+	if err := uc.issueRepo.DeleteLinksByProjectID(projectID); err != nil {
+		return 0, fmt.Errorf("failed to clear issue links: %w", err)
+	}
+
+	linked := 0
+	for _, commit := range commits {
+		keys := services.ExtractIssueKeys(commit.Message, pattern)
+		if err := uc.issueRepo.LinkCommit(projectID, commit.ID, keys); err != nil {
+			return 0, fmt.Errorf("failed to link commit %s: %w", commit.Hash.String(), err)
+		}
+		linked += len(keys)
+	}
+	return linked, nil
+}
+
+// ImportIssues validates and stores issue metadata such as type and priority, then
+// reclassifies the project's commits so linked issue types take effect. It returns the
+// number of reclassified commits.
+func (uc *IssueUseCase) ImportIssues(projectID int, issues []*entities.Issue) (int, error) {
+	for _, issue := range issues {
+		if err := issue.Validate(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := uc.issueRepo.UpsertIssues(projectID, issues); err != nil {
+		return 0, err
+	}
+	return uc.classification.Reclassify(projectID)
+}
+
+// issueImportItem is the metadata of one issue in an import file
+type issueImportItem struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Type     string `json:"type"`
+	Priority string `json:"priority"`
+	Status   string `json:"status"`
+}
+
+// ParseIssueImport reads issue metadata exported from a tracker, either as a JSON array of
+// {"key", "title", "type", "priority", "status"} objects or as an object with that array
+// under "issues", and validates it
+func ParseIssueImport(data []byte) ([]*entities.Issue, error) {
+	var items []issueImportItem
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fmt.Errorf("invalid issue import: %w", err)
+		}
+	} else {
+		var wrapper struct {
+			Issues []issueImportItem `json:"issues"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid issue import: %w", err)
+		}
+		items = wrapper.Issues
+	}
+
+	issues := make([]*entities.Issue, 0, len(items))
+	for _, item := range items {
+		issue := &entities.Issue{Key: item.Key, Title: item.Title, Type: item.Type, Priority: item.Priority, Status: item.Status}
+		if err := issue.Validate(); err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
@@ -0,0 +1,35 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIssueImport(t *testing.T) {
+	for _, data := range []string{
+		`[{"key":"proj-1","title":"Keywords","type":"bug","priority":"high","status":"done"}]`,
+		`{"issues":[{"key":" PROJ-1 ","title":"Keywords","type":"bug","priority":"high","status":"done"}]}`,
+	} {
+		issues, err := ParseIssueImport([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if len(issues) != 1 {
+			t.Fatalf("%s: expected one issue, got %d", data, len(issues))
+		}
+		issue := issues[0]
+		if issue.Key != "PROJ-1" || issue.Title != "Keywords" || issue.Type != "bug" || issue.Priority != "high" || issue.Status != "done" {
+			t.Errorf("%s: unexpected issue %+v", data, issue)
+		}
+	}
+
+	for _, data := range []string{
+		`{"issues":[{"title":"no key"}]}`,
+		`[{"key":"PROJ-1","type":"` + strings.Repeat("x", 51) + `"}]`,
+		`{"issues":`,
+	} {
+		if _, err := ParseIssueImport([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"codeecho/infrastructure/analyzer"
 	"codeecho/infrastructure/database"
 	"codeecho/infrastructure/git"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 )
 
 // Global map to track active analyses and provide cancellation
@@ -48,6 +48,7 @@ func isAnalysisCancelled(projectID int) bool {
 type ProjectAnalysisUseCase struct {
 	analyzer    *analyzer.RepositoryAnalyzer
 	projectRepo repositories.ProjectRepository
+	health      *HealthSnapshotUseCase
 }
 
 // NewProjectAnalysisUseCase creates a new project analysis use case
@@ -56,15 +57,25 @@ func NewProjectAnalysisUseCase(projectRepo repositories.ProjectRepository) *Proj
 	gitService := git.NewGitService()
 
 	// Initialize required repositories
-	commitRepo := mysql.NewCommitRepository(database.DB)
-	changeRepo := mysql.NewChangeRepository(database.DB)
+	commitRepo := sqlstore.NewCommitRepository(database.DB)
+	changeRepo := sqlstore.NewChangeRepository(database.DB)
 
 	// Initialize analyzer with required dependencies
 	repositoryAnalyzer := analyzer.NewRepositoryAnalyzer(gitService, projectRepo, commitRepo, changeRepo, database.DB)
+	repositoryAnalyzer.SetFileMetricsRepository(sqlstore.NewFileMetricsRepository(database.DB))
+	repositoryAnalyzer.SetBlameRepository(sqlstore.NewBlameRepository(database.DB))
+	repositoryAnalyzer.SetCouplingRepository(sqlstore.NewCouplingRepository(database.DB))
+	repositoryAnalyzer.SetFileStatsRepository(sqlstore.NewFileStatsRepository(database.DB))
+	repositoryAnalyzer.SetClassificationRuleRepository(sqlstore.NewClassificationRuleRepository(database.DB))
+	repositoryAnalyzer.SetIssueRepository(sqlstore.NewIssueRepository(database.DB))
+	repositoryAnalyzer.SetReleaseRepository(sqlstore.NewReleaseRepository(database.DB))
+
+	health := NewHealthSnapshotUseCase(commitRepo, changeRepo, sqlstore.NewHealthSnapshotRepository(database.DB), sqlstore.NewRiskPolicyRepository(database.DB), gitService)
 
 	return &ProjectAnalysisUseCase{
 		analyzer:    repositoryAnalyzer,
 		projectRepo: projectRepo,
+		health:      health,
 	}
 }
 
@@ -110,8 +121,16 @@ func (uc *ProjectAnalysisUseCase) AnalyzeRepository(projectID int, repoPath stri
 		log.Printf("Analysis for project %d was cancelled during execution", projectID)
 		return fmt.Errorf("analysis cancelled")
 	}
+	if result != nil {
+		return result
+	}
 
-	return result
+	// The debt trend is derived data, so failing to update it does not fail the analysis
+	if _, err := uc.health.RecordSnapshots(projectID, repoPath, false); err != nil {
+		log.Printf("Error recording health snapshots for project %d: %v", projectID, err)
+	}
+
+	return nil
 }
 
 // GetAnalysisStatus returns the current analysis status of a project
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"fmt"
+	"log"
+
+	"codeecho/domain/repositories"
+)
+
+// RebuildUseCase recomputes derived tables from the stored commit history, for projects
+// analysed before those tables were maintained during ingestion
+type RebuildUseCase struct {
+	projectRepo   repositories.ProjectRepository
+	couplingRepo  repositories.CouplingRepository
+	fileStatsRepo repositories.FileStatsRepository
+}
+
+// NewRebuildUseCase creates a new rebuild use case
+func NewRebuildUseCase(projectRepo repositories.ProjectRepository, couplingRepo repositories.CouplingRepository, fileStatsRepo repositories.FileStatsRepository) *RebuildUseCase {
+	return &RebuildUseCase{
+		projectRepo:   projectRepo,
+		couplingRepo:  couplingRepo,
+		fileStatsRepo: fileStatsRepo,
+	}
+}
+
+// RebuildCoupling replaces a project's pre-computed temporal coupling with one recomputed from
+// its stored commits and changes and marks it complete, returning the number of commits recorded.
+// An analysis of the project storing commits meanwhile waits for the rebuild to finish.
+func (uc *RebuildUseCase) RebuildCoupling(projectID int) (int, error) {
+	return uc.couplingRepo.Rebuild(projectID)
+}
+
+// RebuildFileStats replaces a project's per-file daily statistics with ones recomputed from
+// its stored commits and changes and marks them complete, returning the number of commits recorded.
+// An analysis of the project storing commits meanwhile waits for the rebuild to finish.
+func (uc *RebuildUseCase) RebuildFileStats(projectID int) (int, error) {
+	return uc.fileStatsRepo.Rebuild(projectID)
+}
+
+// BackfillFileStats rebuilds the per-file daily statistics of every project they are not
+// complete for, such as projects analysed before the statistics were kept, returning the IDs
+// of the projects rebuilt. The ownership and hotspot analytics read only these statistics.
+func (uc *RebuildUseCase) BackfillFileStats() ([]int, error) {
+	projects, err := uc.projectRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	var rebuilt []int
+	for _, project := range projects {
+		complete, err := uc.fileStatsRepo.IsComplete(project.ID)
+		if err != nil {
+			return rebuilt, fmt.Errorf("failed to check the file statistics of project %d: %w", project.ID, err)
+		}
+		if complete {
+			continue
+		}
+		log.Printf("Backfilling the daily file statistics of project %d", project.ID)
+		if _, err := uc.RebuildFileStats(project.ID); err != nil {
+			return rebuilt, fmt.Errorf("failed to backfill project %d: %w", project.ID, err)
+		}
+		rebuilt = append(rebuilt, project.ID)
+	}
+	return rebuilt, nil
+}
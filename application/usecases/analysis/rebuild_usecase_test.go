@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+type rebuildTestProjects struct {
+	repositories.ProjectRepository
+	projects []*entities.Project
+}
+
+func (r *rebuildTestProjects) GetAll() ([]*entities.Project, error) {
+	return r.projects, nil
+}
+
+// rebuildTestFileStats keeps the completeness marks and counts the rebuilds per project
+type rebuildTestFileStats struct {
+	repositories.FileStatsRepository
+	complete map[int]bool
+	rebuilt  map[int]int
+}
+
+func (r *rebuildTestFileStats) IsComplete(projectID int) (bool, error) {
+	return r.complete[projectID], nil
+}
+
+func (r *rebuildTestFileStats) Rebuild(projectID int) (int, error) {
+	r.rebuilt[projectID]++
+	r.complete[projectID] = true
+	return 1, nil
+}
+
+func TestBackfillFileStats(t *testing.T) {
+	// Project 1 was analysed with the statistics kept, project 2 before
+	stats := &rebuildTestFileStats{complete: map[int]bool{1: true}, rebuilt: map[int]int{}}
+	projects := &rebuildTestProjects{projects: []*entities.Project{{ID: 1}, {ID: 2}}}
+	uc := NewRebuildUseCase(projects, nil, stats)
+
+	rebuilt, err := uc.BackfillFileStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rebuilt, []int{2}) {
+		t.Errorf("expected only project 2 to be backfilled, got %v", rebuilt)
+	}
+	if stats.rebuilt[1] != 0 || stats.rebuilt[2] != 1 {
+		t.Errorf("unexpected rebuilds %v", stats.rebuilt)
+	}
+
+	if rebuilt, err := uc.BackfillFileStats(); err != nil || len(rebuilt) != 0 {
+		t.Errorf("expected a second run to rebuild nothing, got %v, %v", rebuilt, err)
+	}
+}
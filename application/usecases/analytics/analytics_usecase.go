@@ -2,6 +2,8 @@ package analytics
 
 import (
 	"codeecho/application/ports"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
 	"codeecho/internal/models"
 	"time"
 )
@@ -18,28 +20,48 @@ func NewAnalyticsUseCase(repo ports.AnalyticsRepository) *AnalyticsUseCase {
 	}
 }
 
-// GetProjectOverview retrieves project overview with health trends and risk metrics
-func (uc *AnalyticsUseCase) GetProjectOverview(projectID int) (*models.ProjectOverview, error) {
-	overview, err := uc.repo.GetProjectOverview(projectID)
+// DefaultDebtTrendMonths is the number of monthly health snapshots in the overview's debt trend
+const DefaultDebtTrendMonths = 12
+
+// GetRiskAssessor returns the assessor of a project's current risk policy
+func (uc *AnalyticsUseCase) GetRiskAssessor(projectID int) (*services.RiskAssessor, error) {
+	stored, err := uc.repo.GetRiskPolicy(projectID)
 	if err != nil {
 		return nil, err
 	}
+	return services.NewRiskAssessor(services.EffectiveRiskPolicy(stored)), nil
+}
 
-	// Add business logic if needed (e.g., risk calculations, trend analysis)
-	uc.calculateRiskMetrics(overview)
+// GetProjectOverview retrieves project overview with the debt trend of the last trendMonths
+// months and risk metrics rated under the project's risk policy
+func (uc *AnalyticsUseCase) GetProjectOverview(projectID int, trendMonths int) (*models.ProjectOverview, error) {
+	overview, err := uc.repo.GetProjectOverview(projectID, trendMonths)
+	if err != nil {
+		return nil, err
+	}
+
+	assessor, err := uc.GetRiskAssessor(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.calculateRiskMetrics(projectID, overview, assessor); err != nil {
+		return nil, err
+	}
 
 	return overview, nil
 }
 
-// GetFileOwnership retrieves file ownership data for knowledge risk analysis
-func (uc *AnalyticsUseCase) GetFileOwnership(projectID int) ([]models.FileOwnership, error) {
-	ownership, err := uc.repo.GetFileOwnership(projectID)
+// GetFileOwnership retrieves file ownership data for knowledge risk analysis, rating files by
+// the share of their main owner
+func (uc *AnalyticsUseCase) GetFileOwnership(projectID int, metric values.OwnershipMetric, assessor *services.RiskAssessor) ([]models.FileOwnership, error) {
+	ownership, err := uc.repo.GetFileOwnership(projectID, metric)
 	if err != nil {
 		return nil, err
 	}
 
 	// Apply business rules for knowledge risk assessment
-	uc.assessKnowledgeRisk(ownership)
+	uc.assessKnowledgeRisk(ownership, assessor)
+	applyOwnershipFragmentation(ownership)
 
 	return ownership, nil
 }
@@ -67,8 +89,8 @@ func (uc *AnalyticsUseCase) GetTemporalCoupling(projectID int, limit int, startD
 	return pairs, nil
 }
 
-// GetProjectFileTypes retrieves available file types for a project
-func (uc *AnalyticsUseCase) GetProjectFileTypes(projectID int) ([]string, error) {
+// GetProjectFileTypes retrieves available file types with file counts and LOC for a project
+func (uc *AnalyticsUseCase) GetProjectFileTypes(projectID int) ([]models.FileTypeStat, error) {
 	fileTypes, err := uc.repo.GetProjectFileTypes(projectID)
 	if err != nil {
 		return nil, err
@@ -76,40 +98,47 @@ func (uc *AnalyticsUseCase) GetProjectFileTypes(projectID int) ([]string, error)
 	return fileTypes, nil
 }
 
-// calculateRiskMetrics applies business logic for risk calculations
-func (uc *AnalyticsUseCase) calculateRiskMetrics(overview *models.ProjectOverview) {
-	// Calculate total hotspots based on high-risk components
-	totalHotspots := 0
-	highCouplingRisks := 0
+// GetLanguageBreakdown retrieves the lines of code per language for a project
+func (uc *AnalyticsUseCase) GetLanguageBreakdown(projectID int) ([]models.LanguageBreakdown, error) {
+	return uc.repo.GetLanguageBreakdown(projectID)
+}
 
-	for _, snapshot := range overview.RiskSnapshots {
-		if snapshot.Level == "High" || snapshot.Level == "Critical" {
+// calculateRiskMetrics rates the overview's risk snapshots by change frequency and counts the
+// high-risk hotspots and coupled file pairs
+func (uc *AnalyticsUseCase) calculateRiskMetrics(projectID int, overview *models.ProjectOverview, assessor *services.RiskAssessor) error {
+	totalHotspots := 0
+	for i := range overview.RiskSnapshots {
+		snapshot := &overview.RiskSnapshots[i]
+		level := assessor.ChangeFrequencyLevel(snapshot.Changes)
+		snapshot.Level = level.Title()
+		if level.AtLeast(values.RiskHigh) {
 			totalHotspots++
 		}
-		if snapshot.Changes > 15 { // Business rule for high coupling
+	}
+
+	policy := assessor.Policy()
+	pairs, err := uc.repo.GetCouplingGraph(projectID, "", "", policy.CouplingMinSharedCommits, policy.Coupling.High, "")
+	if err != nil {
+		return err
+	}
+	highCouplingRisks := 0
+	for _, pair := range pairs {
+		if assessor.CouplingLevel(pair.SharedCommits, pair.CouplingScore).AtLeast(values.RiskHigh) {
 			highCouplingRisks++
 		}
 	}
 
 	overview.TotalHotspots = totalHotspots
 	overview.HighCouplingRisks = highCouplingRisks
+	overview.RiskPolicyVersion = assessor.Version()
+	return nil
 }
 
-// assessKnowledgeRisk applies business rules for knowledge risk assessment
-func (uc *AnalyticsUseCase) assessKnowledgeRisk(ownership []models.FileOwnership) {
+// assessKnowledgeRisk rates files by the ownership share of their main owner
+func (uc *AnalyticsUseCase) assessKnowledgeRisk(ownership []models.FileOwnership, assessor *services.RiskAssessor) {
 	for i := range ownership {
 		file := &ownership[i]
-
-		// Update risk level based on ownership concentration
-		if file.OwnershipPercentage > 90 {
-			file.RiskLevel = "critical"
-		} else if file.OwnershipPercentage > 70 {
-			file.RiskLevel = "high"
-		} else if file.OwnershipPercentage > 50 {
-			file.RiskLevel = "medium"
-		} else {
-			file.RiskLevel = "low"
-		}
+		file.RiskLevel = string(assessor.OwnershipLevel(file.OwnershipPercentage))
 	}
 }
 
@@ -127,6 +156,12 @@ func (uc *AnalyticsUseCase) analyzeHotspots(hotspots []models.AuthorHotspot) {
 }
 
 // GetBusFactorAnalysis retrieves bus factor analysis for all files in a project
-func (uc *AnalyticsUseCase) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string) ([]models.BusFactorData, error) {
-	return uc.repo.GetBusFactorAnalysis(projectID, startDate, endDate, repository, path)
+func (uc *AnalyticsUseCase) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string, metric values.OwnershipMetric) ([]models.BusFactorData, error) {
+	data, err := uc.repo.GetBusFactorAnalysis(projectID, startDate, endDate, repository, path, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	applyBusFactorFragmentation(data)
+	return data, nil
 }
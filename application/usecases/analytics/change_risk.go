@@ -0,0 +1,218 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"codeecho/application/ports"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// ErrInvalidDiff is returned when the changes between two refs cannot be listed, usually
+// because a ref does not exist
+var ErrInvalidDiff = errors.New("failed to diff refs")
+
+// changeRiskOwners is the number of owners listed per changed file
+const changeRiskOwners = 3
+
+// ChangeRiskUseCase assesses the risk of the files changed between two refs from the project's
+// analysed history
+type ChangeRiskUseCase struct {
+	repo       ports.AnalyticsRepository
+	gitService ports.GitService
+	analytics  *AnalyticsUseCase
+}
+
+// NewChangeRiskUseCase creates a new change risk use case
+func NewChangeRiskUseCase(repo ports.AnalyticsRepository, gitService ports.GitService) *ChangeRiskUseCase {
+	return &ChangeRiskUseCase{
+		repo:       repo,
+		gitService: gitService,
+		analytics:  NewAnalyticsUseCase(repo),
+	}
+}
+
+// Assess reports, for the files changed on head since it diverged from base, which are
+// hotspots, their bus factor and owners, the owners to ask for review, and the files that
+// usually change with them but were left out. Ownership is weighted by metric.
+func (uc *ChangeRiskUseCase) Assess(projectID int, repoPath, base, head string, metric values.OwnershipMetric) (*models.ChangeRisk, error) {
+	changes, err := uc.gitService.DiffRefs(repoPath, base, head)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s...%s: %v", ErrInvalidDiff, base, head, err)
+	}
+	headFiles, err := uc.gitService.ListFilesAtCommit(repoPath, head)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDiff, err)
+	}
+
+	assessor, err := uc.analytics.GetRiskAssessor(projectID)
+	if err != nil {
+		return nil, err
+	}
+	ownership, err := uc.repo.GetBusFactorAnalysis(projectID, nil, nil, "", "", metric)
+	if err != nil {
+		return nil, err
+	}
+	ownershipByFile := make(map[string]models.BusFactorData, len(ownership))
+	for _, file := range ownership {
+		ownershipByFile[file.FilePath] = file
+	}
+
+	risk := &models.ChangeRisk{
+		ProjectID:           projectID,
+		Base:                base,
+		Head:                head,
+		RiskPolicyVersion:   assessor.Version(),
+		Files:               make([]models.ChangedFileRisk, 0, len(changes)),
+		SuggestedReviewers:  []models.SuggestedReviewer{},
+		MissingCoupledFiles: []models.MissingCoupledFile{},
+	}
+	overall := values.RiskLow
+	touched := make(map[string]bool, len(changes))
+	reviewers := make(map[string]*models.SuggestedReviewer)
+
+	for _, change := range changes {
+		touched[change.FilePath] = true
+		history := ownershipByFile[change.FilePath]
+
+		shares := make([]float64, 0, len(history.OwnershipDistribution))
+		changeCount := 0
+		for _, owner := range history.OwnershipDistribution {
+			shares = append(shares, owner.OwnershipPercent)
+			changeCount += owner.Commits
+		}
+		busFactor := services.BusFactor(shares)
+		hotspotLevel := assessor.ChangeFrequencyLevel(changeCount)
+		busFactorLevel := assessor.BusFactorLevel(busFactor)
+		fileLevel := hotspotLevel
+		if busFactorLevel.AtLeast(fileLevel) {
+			fileLevel = busFactorLevel
+		}
+		if fileLevel.AtLeast(overall) {
+			overall = fileLevel
+		}
+
+		file := models.ChangedFileRisk{
+			FilePath:       change.FilePath,
+			LinesAdded:     change.LinesAdded,
+			LinesDeleted:   change.LinesDeleted,
+			ChangeCount:    changeCount,
+			Hotspot:        hotspotLevel.AtLeast(values.RiskHigh),
+			HotspotLevel:   string(hotspotLevel),
+			BusFactor:      busFactor,
+			BusFactorLevel: string(busFactorLevel),
+			RiskLevel:      string(fileLevel),
+			Owners:         make([]models.FileOwner, 0, changeRiskOwners),
+		}
+		for i, owner := range history.OwnershipDistribution {
+			if i < changeRiskOwners {
+				file.Owners = append(file.Owners, models.FileOwner{Author: owner.Author, OwnershipPercent: owner.OwnershipPercent})
+			}
+			// The main owners making up the bus factor are the ones to ask for review
+			if i < busFactor {
+				reviewer := reviewers[owner.Author]
+				if reviewer == nil {
+					reviewer = &models.SuggestedReviewer{Author: owner.Author}
+					reviewers[owner.Author] = reviewer
+				}
+				reviewer.Files = append(reviewer.Files, change.FilePath)
+				reviewer.Ownership += owner.OwnershipPercent
+			}
+		}
+
+		if file.Hotspot {
+			risk.Summary.Hotspots++
+		}
+		if busFactorLevel.AtLeast(values.RiskHigh) {
+			risk.Summary.LowBusFactorFiles++
+		}
+		risk.Files = append(risk.Files, file)
+	}
+
+	sort.Slice(risk.Files, func(i, j int) bool {
+		if risk.Files[i].ChangeCount != risk.Files[j].ChangeCount {
+			return risk.Files[i].ChangeCount > risk.Files[j].ChangeCount
+		}
+		return risk.Files[i].FilePath < risk.Files[j].FilePath
+	})
+
+	for _, reviewer := range reviewers {
+		risk.SuggestedReviewers = append(risk.SuggestedReviewers, *reviewer)
+	}
+	sort.Slice(risk.SuggestedReviewers, func(i, j int) bool {
+		a, b := risk.SuggestedReviewers[i], risk.SuggestedReviewers[j]
+		if len(a.Files) != len(b.Files) {
+			return len(a.Files) > len(b.Files)
+		}
+		if a.Ownership != b.Ownership {
+			return a.Ownership > b.Ownership
+		}
+		return a.Author < b.Author
+	})
+
+	missing, err := uc.missingCoupledFiles(projectID, assessor, touched, headFiles)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range missing {
+		if level := values.RiskLevel(file.RiskLevel); level.AtLeast(overall) {
+			overall = level
+		}
+	}
+	risk.MissingCoupledFiles = missing
+
+	risk.RiskLevel = string(overall)
+	risk.Summary.FilesChanged = len(risk.Files)
+	risk.Summary.MissingCoupledFiles = len(missing)
+	return risk, nil
+}
+
+// missingCoupledFiles lists the files of the head tree that were not changed although they
+// are coupled at medium risk or above with a changed file, strongest coupling first
+func (uc *ChangeRiskUseCase) missingCoupledFiles(projectID int, assessor *services.RiskAssessor, touched map[string]bool, headFiles []string) ([]models.MissingCoupledFile, error) {
+	policy := assessor.Policy()
+	pairs, err := uc.repo.GetCouplingGraph(projectID, "", "", policy.CouplingMinSharedCommits, policy.Coupling.Medium, "")
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]bool, len(headFiles))
+	for _, file := range headFiles {
+		exists[file] = true
+	}
+
+	missing := make([]models.MissingCoupledFile, 0)
+	for _, pair := range pairs {
+		level := assessor.CouplingLevel(pair.SharedCommits, pair.CouplingScore)
+		if !level.AtLeast(values.RiskMedium) {
+			continue
+		}
+		for _, side := range [][2]string{{pair.FileA, pair.FileB}, {pair.FileB, pair.FileA}} {
+			changed, other := side[0], side[1]
+			if !touched[changed] || touched[other] || !exists[other] {
+				continue
+			}
+			missing = append(missing, models.MissingCoupledFile{
+				FilePath:      other,
+				CoupledWith:   changed,
+				SharedCommits: pair.SharedCommits,
+				CouplingScore: pair.CouplingScore,
+				RiskLevel:     string(level),
+			})
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].CouplingScore != missing[j].CouplingScore {
+			return missing[i].CouplingScore > missing[j].CouplingScore
+		}
+		if missing[i].FilePath != missing[j].FilePath {
+			return missing[i].FilePath < missing[j].FilePath
+		}
+		return missing[i].CoupledWith < missing[j].CoupledWith
+	})
+	return missing, nil
+}
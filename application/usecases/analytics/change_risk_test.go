@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+type riskTestGit struct {
+	ports.GitService
+	changes []*ports.GitChange
+	files   []string
+	diffErr error
+}
+
+func (g *riskTestGit) DiffRefs(repoPath string, baseRef string, headRef string) ([]*ports.GitChange, error) {
+	return g.changes, g.diffErr
+}
+
+func (g *riskTestGit) ListFilesAtCommit(repoPath string, commitHash string) ([]string, error) {
+	return g.files, nil
+}
+
+// riskTestRepo serves the project's ownership and coupling under the default risk policy
+type riskTestRepo struct {
+	ports.AnalyticsRepository
+	ownership []models.BusFactorData
+	coupling  []models.TemporalCoupling
+}
+
+func (r *riskTestRepo) GetRiskPolicy(projectID int) (*entities.RiskPolicy, error) {
+	return nil, nil
+}
+
+func (r *riskTestRepo) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string, metric values.OwnershipMetric) ([]models.BusFactorData, error) {
+	return r.ownership, nil
+}
+
+func (r *riskTestRepo) GetCouplingGraph(projectID int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error) {
+	return r.coupling, nil
+}
+
+func TestAssessChangeRisk(t *testing.T) {
+	owner := func(author string, commits int, percent float64) models.AuthorOwnership {
+		return models.AuthorOwnership{Author: author, Commits: commits, OwnershipPercent: percent}
+	}
+	repo := &riskTestRepo{
+		ownership: []models.BusFactorData{
+			// ann owns the parser, a hotspot with 15 changes
+			{FilePath: "core/parser.go", OwnershipDistribution: []models.AuthorOwnership{owner("ann", 12, 80), owner("bob", 3, 20)}},
+			{FilePath: "docs/README.md", OwnershipDistribution: []models.AuthorOwnership{owner("ann", 1, 40), owner("bob", 1, 30), owner("cy", 1, 30)}},
+		},
+		coupling: []models.TemporalCoupling{
+			{FileA: "core/lexer.go", FileB: "core/parser.go", SharedCommits: 5, CouplingScore: 0.95},
+			// Changed together, deleted on head and too rarely shared to count
+			{FileA: "core/parser.go", FileB: "docs/README.md", SharedCommits: 4, CouplingScore: 0.8},
+			{FileA: "core/old.go", FileB: "core/parser.go", SharedCommits: 4, CouplingScore: 0.8},
+			{FileA: "core/ast.go", FileB: "core/parser.go", SharedCommits: 2, CouplingScore: 0.9},
+		},
+	}
+	gitService := &riskTestGit{
+		changes: []*ports.GitChange{
+			{FilePath: "docs/README.md", LinesAdded: 1},
+			{FilePath: "core/parser.go", LinesAdded: 10, LinesDeleted: 2},
+		},
+		files: []string{"core/ast.go", "core/lexer.go", "core/parser.go", "docs/README.md"},
+	}
+	uc := NewChangeRiskUseCase(repo, gitService)
+
+	risk, err := uc.Assess(1, "/repo", "main", "feature", values.OwnershipByCommits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if risk.RiskLevel != string(values.RiskCritical) {
+		t.Errorf("expected the missing lexer to make the change critical, got %s", risk.RiskLevel)
+	}
+	if len(risk.Files) != 2 {
+		t.Fatalf("expected 2 changed files, got %+v", risk.Files)
+	}
+	parser, readme := risk.Files[0], risk.Files[1]
+	if parser.FilePath != "core/parser.go" || !parser.Hotspot || parser.ChangeCount != 15 || parser.BusFactor != 1 ||
+		parser.RiskLevel != string(values.RiskHigh) || parser.LinesAdded != 10 || len(parser.Owners) != 2 {
+		t.Errorf("unexpected parser risk %+v", parser)
+	}
+	if readme.Hotspot || readme.BusFactor != 2 || readme.RiskLevel != string(values.RiskMedium) || len(readme.Owners) != 3 {
+		t.Errorf("unexpected README risk %+v", readme)
+	}
+
+	if len(risk.SuggestedReviewers) != 2 || risk.SuggestedReviewers[0].Author != "ann" || len(risk.SuggestedReviewers[0].Files) != 2 ||
+		risk.SuggestedReviewers[1].Author != "bob" {
+		t.Errorf("expected ann on both files, then bob, got %+v", risk.SuggestedReviewers)
+	}
+	if len(risk.MissingCoupledFiles) != 1 || risk.MissingCoupledFiles[0].FilePath != "core/lexer.go" ||
+		risk.MissingCoupledFiles[0].CoupledWith != "core/parser.go" || risk.MissingCoupledFiles[0].RiskLevel != string(values.RiskCritical) {
+		t.Errorf("expected only the lexer to be missing, got %+v", risk.MissingCoupledFiles)
+	}
+	if s := risk.Summary; s.FilesChanged != 2 || s.Hotspots != 1 || s.LowBusFactorFiles != 1 || s.MissingCoupledFiles != 1 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+
+	gitService.diffErr = errors.New("reference not found")
+	if _, err := uc.Assess(1, "/repo", "main", "missing", values.OwnershipByCommits); !errors.Is(err, ErrInvalidDiff) {
+		t.Errorf("expected ErrInvalidDiff for an unknown ref, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"codeecho/domain/services"
+	"codeecho/internal/models"
+)
+
+// DefaultStaleAfterDays is the age from which code is reported as stale
+const DefaultStaleAfterDays = 365
+
+// GetCodeAge builds the code age map of a project: every file in the analysed tree
+// with the age of its last change and the age of its lines according to blame,
+// bucketed by age and rolled up into a directory treemap.
+// pathPrefix optionally restricts the report to a subdirectory.
+func (uc *AnalyticsUseCase) GetCodeAge(projectID int, pathPrefix string, staleAfterDays int, now time.Time) (*models.CodeAgeReport, error) {
+	files, err := uc.repo.GetFileAges(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := uc.repo.GetLineAges(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pathPrefix != "" {
+		files = filterFileAges(files, pathPrefix)
+	}
+
+	report := buildCodeAgeReport(files, segments, staleAfterDays, now)
+	report.ProjectID = projectID
+	return report, nil
+}
+
+// buildCodeAgeReport classifies files and lines into age buckets and assembles the treemap
+func buildCodeAgeReport(files []models.FileAge, segments []models.LineAgeSegment, staleAfterDays int, now time.Time) *models.CodeAgeReport {
+	report := &models.CodeAgeReport{
+		GeneratedAt:    now,
+		StaleAfterDays: staleAfterDays,
+		Buckets:        make([]models.CodeAgeBucket, len(services.CodeAgeBuckets)),
+		Files:          files,
+		Treemap:        &models.CodeAgeNode{Name: "/", Path: "", Type: "directory"},
+	}
+
+	for i, bucket := range services.CodeAgeBuckets {
+		report.Buckets[i] = models.CodeAgeBucket{Label: bucket.Label, MinDays: bucket.MinDays}
+		if bucket.MaxDays > 0 {
+			maxDays := bucket.MaxDays
+			report.Buckets[i].MaxDays = &maxDays
+		}
+	}
+
+	segmentsByFile := make(map[string][]models.LineAgeSegment)
+	for _, s := range segments {
+		segmentsByFile[s.FilePath] = append(segmentsByFile[s.FilePath], s)
+	}
+
+	for i := range report.Files {
+		file := &report.Files[i]
+
+		if file.LastModified != nil {
+			file.AgeDays = services.AgeInDays(*file.LastModified, now)
+		}
+		bucketIndex := services.AgeBucketIndex(file.AgeDays)
+		file.Bucket = services.CodeAgeBuckets[bucketIndex].Label
+		report.Buckets[bucketIndex].Files++
+		if file.AgeDays >= staleAfterDays {
+			report.StaleFiles++
+		}
+
+		// Without blame every line is as old as the file's last change
+		lineAges := []lineAge{{days: file.AgeDays, lines: file.Lines}}
+		if fileSegments := segmentsByFile[file.FilePath]; len(fileSegments) > 0 {
+			lineAges = lineAges[:0]
+			file.Lines = 0
+			for _, s := range fileSegments {
+				lineAges = append(lineAges, lineAge{days: services.AgeInDays(s.CommittedAt, now), lines: s.Lines})
+				file.Lines += s.Lines
+			}
+		}
+
+		file.LineAgeBuckets = make(map[string]int)
+		weightedAge := 0
+		for _, la := range lineAges {
+			index := services.AgeBucketIndex(la.days)
+			file.LineAgeBuckets[services.CodeAgeBuckets[index].Label] += la.lines
+			report.Buckets[index].Lines += la.lines
+			if la.days >= staleAfterDays {
+				report.StaleLines += la.lines
+			}
+			weightedAge += la.days * la.lines
+		}
+		file.MedianLineAgeDays = medianLineAge(lineAges)
+		report.TotalLines += file.Lines
+
+		meanAge := float64(file.AgeDays)
+		if file.Lines > 0 {
+			meanAge = float64(weightedAge) / float64(file.Lines)
+		}
+		addToCodeAgeTree(report.Treemap, file, meanAge)
+	}
+
+	report.TotalFiles = len(report.Files)
+	if report.TotalLines > 0 {
+		report.StaleLinePercent = float64(report.StaleLines) / float64(report.TotalLines) * 100
+	}
+
+	finalizeCodeAgeNode(report.Treemap)
+
+	// Oldest files first, as they are the first candidates for a stale-code review
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].AgeDays > report.Files[j].AgeDays
+	})
+
+	return report
+}
+
+// lineAge is a number of lines sharing the same age
+type lineAge struct {
+	days  int
+	lines int
+}
+
+// medianLineAge returns the age of the middle line when lines are ordered by age
+func medianLineAge(ages []lineAge) int {
+	total := 0
+	for _, la := range ages {
+		total += la.lines
+	}
+	if total == 0 {
+		if len(ages) > 0 {
+			return ages[0].days
+		}
+		return 0
+	}
+
+	sorted := append([]lineAge(nil), ages...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].days < sorted[j].days })
+
+	seen := 0
+	for _, la := range sorted {
+		seen += la.lines
+		if seen*2 >= total {
+			return la.days
+		}
+	}
+	return sorted[len(sorted)-1].days
+}
+
+// addToCodeAgeTree inserts a file leaf below root, creating intermediate directories.
+// While building, directory AgeDays accumulates line-weighted age sums which
+// finalizeCodeAgeNode turns into means.
+func addToCodeAgeTree(root *models.CodeAgeNode, file *models.FileAge, meanAge float64) {
+	parts := strings.Split(file.FilePath, "/")
+	weight := float64(codeAgeWeight(file.Lines))
+
+	node := root
+	for i, part := range parts {
+		node.Lines += file.Lines
+		node.AgeDays += meanAge * weight
+		if file.LastModified != nil && (node.LastModified == nil || file.LastModified.After(*node.LastModified)) {
+			node.LastModified = file.LastModified
+		}
+
+		if i == len(parts)-1 {
+			node.Children = append(node.Children, &models.CodeAgeNode{
+				Name:         part,
+				Path:         file.FilePath,
+				Type:         "file",
+				Lines:        file.Lines,
+				AgeDays:      meanAge,
+				LastModified: file.LastModified,
+			})
+			return
+		}
+
+		var child *models.CodeAgeNode
+		for _, existing := range node.Children {
+			if existing.Type == "directory" && existing.Name == part {
+				child = existing
+				break
+			}
+		}
+		if child == nil {
+			child = &models.CodeAgeNode{
+				Name: part,
+				Path: strings.Join(parts[:i+1], "/"),
+				Type: "directory",
+			}
+			node.Children = append(node.Children, child)
+		}
+		node = child
+	}
+}
+
+// finalizeCodeAgeNode converts accumulated directory age sums into line-weighted means
+// and returns the weight of the node
+func finalizeCodeAgeNode(node *models.CodeAgeNode) int {
+	if node.Type == "file" {
+		return codeAgeWeight(node.Lines)
+	}
+
+	weight := 0
+	for _, child := range node.Children {
+		weight += finalizeCodeAgeNode(child)
+	}
+	if weight > 0 {
+		node.AgeDays /= float64(weight)
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Lines > node.Children[j].Lines
+	})
+	return weight
+}
+
+// codeAgeWeight weights a file by its lines so that empty files still count
+func codeAgeWeight(lines int) int {
+	if lines < 1 {
+		return 1
+	}
+	return lines
+}
+
+// filterFileAges keeps the files located below pathPrefix
+func filterFileAges(files []models.FileAge, pathPrefix string) []models.FileAge {
+	filtered := make([]models.FileAge, 0, len(files))
+	for _, f := range files {
+		if strings.HasPrefix(f.FilePath, pathPrefix) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
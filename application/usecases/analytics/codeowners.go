@@ -0,0 +1,288 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// DefaultCodeownersStaleMonths is how long declared owners may go without touching their files
+// before the CODEOWNERS check reports them
+const DefaultCodeownersStaleMonths = 6
+
+// Errors reading a repository's CODEOWNERS file
+var (
+	ErrNoCodeowners      = errors.New("no CODEOWNERS file")
+	ErrInvalidCodeowners = errors.New("invalid CODEOWNERS file")
+)
+
+// CodeownersMapping maps authors and teams to CODEOWNERS handles. Handles wins; otherwise
+// authors become @name, or stay as is when they are e-mail addresses, and teams become
+// @org/team-name, or @team-name without Org.
+type CodeownersMapping struct {
+	// Teams attributes the ownership of team members to their team when proposing entries
+	Teams   bool
+	Org     string
+	Handles map[string]string
+}
+
+// authorHandle returns the CODEOWNERS handle of an author
+func (m CodeownersMapping) authorHandle(author string) string {
+	if handle, ok := m.Handles[author]; ok {
+		return handle
+	}
+	if strings.Contains(author, "@") {
+		return author
+	}
+	return "@" + strings.Join(strings.Fields(author), "-")
+}
+
+// teamHandle returns the CODEOWNERS handle of a team
+func (m CodeownersMapping) teamHandle(team string) string {
+	if handle, ok := m.Handles[team]; ok {
+		return handle
+	}
+	slug := strings.ToLower(strings.Join(strings.Fields(team), "-"))
+	if m.Org != "" {
+		return "@" + m.Org + "/" + slug
+	}
+	return "@" + slug
+}
+
+// CodeownersUseCase proposes CODEOWNERS entries from ownership and checks an existing
+// CODEOWNERS file against who actually works on the files
+type CodeownersUseCase struct {
+	repo       ports.AnalyticsRepository
+	teamRepo   repositories.TeamRepository
+	gitService ports.GitService
+}
+
+// NewCodeownersUseCase creates a new CODEOWNERS use case
+func NewCodeownersUseCase(repo ports.AnalyticsRepository, teamRepo repositories.TeamRepository, gitService ports.GitService) *CodeownersUseCase {
+	return &CodeownersUseCase{
+		repo:       repo,
+		teamRepo:   teamRepo,
+		gitService: gitService,
+	}
+}
+
+// Propose proposes a CODEOWNERS file with an entry per directory at opts.Depth, listing the
+// owners whose average share of the directory's files at HEAD, weighted by metric, reaches
+// opts.MinShare
+func (uc *CodeownersUseCase) Propose(projectID int, repoPath string, metric values.OwnershipMetric, opts services.CodeownersOptions, mapping CodeownersMapping) (*models.CodeownersProposal, error) {
+	if opts.Depth <= 0 {
+		opts.Depth = services.DefaultCodeownersDepth
+	}
+	if opts.MinShare <= 0 {
+		opts.MinShare = services.DefaultCodeownersMinShare
+	}
+	if opts.MaxOwners <= 0 {
+		opts.MaxOwners = services.DefaultCodeownersMaxOwners
+	}
+
+	headFiles, err := uc.gitService.ListFilesAtCommit(repoPath, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at HEAD: %w", err)
+	}
+	exists := make(map[string]bool, len(headFiles))
+	for _, file := range headFiles {
+		exists[file] = true
+	}
+	ownership, err := uc.repo.GetFileOwnership(projectID, metric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ownership: %w", err)
+	}
+
+	ownerOf := mapping.authorHandle
+	if mapping.Teams {
+		teams, err := uc.teamRepo.GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teams: %w", err)
+		}
+		teamHandles := make(map[string]string)
+		for _, team := range teams {
+			for _, member := range team.Members {
+				for _, identity := range member.Identities {
+					if _, exists := teamHandles[identity]; !exists {
+						teamHandles[identity] = mapping.teamHandle(team.Name)
+					}
+				}
+			}
+		}
+		// Authors outside any team keep their own handle
+		ownerOf = func(author string) string {
+			if handle, ok := teamHandles[author]; ok {
+				return handle
+			}
+			return mapping.authorHandle(author)
+		}
+	}
+
+	files := make([]services.OwnedFile, 0, len(ownership))
+	for _, file := range ownership {
+		if !exists[file.FilePath] {
+			continue
+		}
+		shares := make(map[string]float64, len(file.Contributors))
+		for _, contrib := range file.Contributors {
+			shares[ownerOf(contrib.Author)] += contrib.Percentage
+		}
+		files = append(files, services.OwnedFile{FilePath: file.FilePath, Shares: shares})
+	}
+	proposals := services.ProposeCodeowners(files, opts)
+
+	proposal := &models.CodeownersProposal{
+		ProjectID: projectID,
+		Ownership: string(metric),
+		Depth:     opts.Depth,
+		MinShare:  opts.MinShare,
+		MaxOwners: opts.MaxOwners,
+		Teams:     mapping.Teams,
+		Entries:   make([]models.CodeownersEntry, 0, len(proposals)),
+	}
+	for _, p := range proposals {
+		entry := models.CodeownersEntry{Pattern: p.Pattern, Files: p.Files, Owners: make([]models.CodeownersOwnerShare, 0, len(p.Owners))}
+		for _, owner := range p.Owners {
+			entry.Owners = append(entry.Owners, models.CodeownersOwnerShare{Owner: owner.Owner, Share: owner.Share})
+		}
+		proposal.Entries = append(proposal.Entries, entry)
+	}
+	proposal.Content = services.FormatCodeowners(fmt.Sprintf(
+		"Proposed by codeecho from %s ownership at directory depth %d: owners hold at least %.0f%% of a directory.",
+		metric, opts.Depth, opts.MinShare), proposals)
+	return proposal, nil
+}
+
+// CheckDrift reads the repository's CODEOWNERS file at HEAD and reports the rules whose owners
+// have not touched the files they own for staleMonths, whose owners resolve to no known author
+// or team, or that own no files. Owners are resolved with the same mapping as proposals.
+func (uc *CodeownersUseCase) CheckDrift(projectID int, repoPath string, staleMonths int, mapping CodeownersMapping, now time.Time) (*models.CodeownersDriftReport, error) {
+	if staleMonths <= 0 {
+		staleMonths = DefaultCodeownersStaleMonths
+	}
+
+	path, content := "", ""
+	for _, location := range services.CodeownersLocations {
+		if data, err := uc.gitService.GetFileContentAtCommit(repoPath, "HEAD", location); err == nil {
+			path, content = location, data
+			break
+		}
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w at HEAD (looked in %s)", ErrNoCodeowners, strings.Join(services.CodeownersLocations, ", "))
+	}
+	rules, err := services.ParseCodeowners(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrInvalidCodeowners, path, err)
+	}
+
+	files, err := uc.gitService.ListFilesAtCommit(repoPath, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at HEAD: %w", err)
+	}
+	ownership, err := uc.repo.GetFileOwnership(projectID, values.OwnershipByCommits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ownership: %w", err)
+	}
+	teams, err := uc.teamRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	touches := make(map[string]map[string]time.Time, len(ownership))
+	authorsOf := make(map[string][]string)
+	for _, file := range ownership {
+		touches[file.FilePath] = make(map[string]time.Time, len(file.Contributors))
+		for _, contrib := range file.Contributors {
+			touched, _ := time.Parse(time.RFC3339Nano, contrib.LastModified)
+			touches[file.FilePath][contrib.Author] = touched
+			handle := strings.ToLower(mapping.authorHandle(contrib.Author))
+			if !containsString(authorsOf[handle], contrib.Author) {
+				authorsOf[handle] = append(authorsOf[handle], contrib.Author)
+			}
+		}
+	}
+	resolve := codeownersResolver(authorsOf, teams, mapping)
+
+	drifts := services.CheckCodeowners(rules, files, touches, resolve, now.AddDate(0, -staleMonths, 0))
+	report := &models.CodeownersDriftReport{
+		ProjectID:        projectID,
+		Path:             path,
+		StaleAfterMonths: staleMonths,
+		Rules:            len(rules),
+		DriftedRules:     []models.CodeownersRuleDrift{},
+	}
+	for _, drift := range drifts {
+		if !drift.Drifted() {
+			continue
+		}
+		rule := models.CodeownersRuleDrift{
+			Line:          drift.Rule.Line,
+			Pattern:       drift.Rule.Pattern,
+			Owners:        drift.Rule.Owners,
+			Files:         drift.Files,
+			StaleOwners:   make([]models.CodeownersStaleOwner, 0, len(drift.StaleOwners)),
+			UnknownOwners: append([]string{}, drift.UnknownOwners...),
+			RecentAuthors: append([]string{}, drift.RecentAuthors...),
+		}
+		if !drift.LastChange.IsZero() {
+			lastChange := drift.LastChange
+			rule.LastChange = &lastChange
+		}
+		for _, stale := range drift.StaleOwners {
+			owner := models.CodeownersStaleOwner{Owner: stale.Owner}
+			if !stale.LastTouched.IsZero() {
+				lastTouched := stale.LastTouched
+				owner.LastTouched = &lastTouched
+			}
+			rule.StaleOwners = append(rule.StaleOwners, owner)
+		}
+		report.DriftedRules = append(report.DriftedRules, rule)
+	}
+	return report, nil
+}
+
+// codeownersResolver maps CODEOWNERS handles, case-insensitively, to the authors they stand
+// for: the authors with that handle, or the members of the team with that handle
+func codeownersResolver(authorsOf map[string][]string, teams []*entities.Team, mapping CodeownersMapping) func(owner string) ([]string, bool) {
+	owners := make(map[string][]string, len(authorsOf)+len(teams))
+	for handle, authors := range authorsOf {
+		owners[handle] = authors
+	}
+	for _, team := range teams {
+		handle := strings.ToLower(mapping.teamHandle(team.Name))
+		for _, member := range team.Members {
+			owners[handle] = append(owners[handle], member.Identities...)
+		}
+		if _, exists := owners[handle]; !exists {
+			owners[handle] = []string{}
+		}
+	}
+	for _, authors := range owners {
+		sort.Strings(authors)
+	}
+
+	return func(owner string) ([]string, bool) {
+		authors, known := owners[strings.ToLower(owner)]
+		return authors, known
+	}
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// codeownersTestGit serves the files at HEAD and the contents of some of them
+type codeownersTestGit struct {
+	ports.GitService
+	files    []string
+	contents map[string]string
+}
+
+func (g *codeownersTestGit) ListFilesAtCommit(repoPath string, commitHash string) ([]string, error) {
+	return g.files, nil
+}
+
+func (g *codeownersTestGit) GetFileContentAtCommit(repoPath string, commitHash string, filePath string) (string, error) {
+	if content, ok := g.contents[filePath]; ok {
+		return content, nil
+	}
+	return "", ports.ErrFileNotFound
+}
+
+type codeownersTestRepo struct {
+	ports.AnalyticsRepository
+	ownership []models.FileOwnership
+}
+
+func (r *codeownersTestRepo) GetFileOwnership(projectID int, metric values.OwnershipMetric) ([]models.FileOwnership, error) {
+	return r.ownership, nil
+}
+
+type codeownersTestTeams struct {
+	repositories.TeamRepository
+	teams []*entities.Team
+}
+
+func (r *codeownersTestTeams) GetAll() ([]*entities.Team, error) {
+	return r.teams, nil
+}
+
+func TestCodeowners(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent, old := now.AddDate(0, -1, 0), now.AddDate(-1, 0, 0)
+	owner := func(author string, percent float64, touched time.Time) models.AuthorContribution {
+		return models.AuthorContribution{Author: author, Percentage: percent, LastModified: touched.Format(time.RFC3339Nano)}
+	}
+	repo := &codeownersTestRepo{ownership: []models.FileOwnership{
+		{FilePath: "README.md", Contributors: []models.AuthorContribution{owner("ann", 100, recent)}},
+		{FilePath: "core/a.go", Contributors: []models.AuthorContribution{owner("ann", 100, recent)}},
+		{FilePath: "core/b.go", Contributors: []models.AuthorContribution{owner("Bob Smith", 100, recent)}},
+		{FilePath: "web/app.js", Contributors: []models.AuthorContribution{owner("cy", 70, old), owner("dan", 30, recent)}},
+		// Deleted before HEAD
+		{FilePath: "old/x.go", Contributors: []models.AuthorContribution{owner("eve", 100, old)}},
+	}}
+	gitService := &codeownersTestGit{files: []string{"README.md", "core/a.go", "core/b.go", "web/app.js"}}
+	teams := &codeownersTestTeams{teams: []*entities.Team{
+		entities.NewTeam("Platform Core", "", []entities.TeamMember{
+			{Name: "Ann", Identities: []string{"ann"}},
+			{Name: "Bob", Identities: []string{"Bob Smith"}},
+		}),
+	}}
+	uc := NewCodeownersUseCase(repo, teams, gitService)
+	mapping := CodeownersMapping{Org: "acme", Handles: map[string]string{"Bob Smith": "@bsmith"}}
+
+	t.Run("Propose", func(t *testing.T) {
+		proposal, err := uc.Propose(1, "/repo", values.OwnershipByCommits, services.CodeownersOptions{}, mapping)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var patterns []string
+		for _, entry := range proposal.Entries {
+			patterns = append(patterns, entry.Pattern)
+		}
+		if !slices.Equal(patterns, []string{"*", "/core/", "/web/"}) {
+			t.Fatalf("expected entries for the files at HEAD only, got %v", patterns)
+		}
+		core := proposal.Entries[1]
+		if core.Files != 2 || len(core.Owners) != 2 || core.Owners[0].Owner != "@ann" || core.Owners[1].Owner != "@bsmith" {
+			t.Errorf("expected ann and the mapped handle of Bob Smith on core, got %+v", core)
+		}
+		if proposal.Depth != services.DefaultCodeownersDepth || proposal.MinShare != services.DefaultCodeownersMinShare {
+			t.Errorf("expected the default options, got depth %d and min share %.0f", proposal.Depth, proposal.MinShare)
+		}
+
+		// With teams, the team owns the root and core folds into it; cy outside any team keeps
+		// their own handle
+		teamMapping := mapping
+		teamMapping.Teams = true
+		proposal, err = uc.Propose(1, "/repo", values.OwnershipByCommits, services.CodeownersOptions{}, teamMapping)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proposal.Entries) != 2 {
+			t.Fatalf("expected the root and web entries, got %+v", proposal.Entries)
+		}
+		root, web := proposal.Entries[0], proposal.Entries[1]
+		if root.Pattern != "*" || len(root.Owners) != 1 || root.Owners[0].Owner != "@acme/platform-core" || root.Owners[0].Share != 100 {
+			t.Errorf("expected the team to own the root, got %+v", root)
+		}
+		if web.Pattern != "/web/" || len(web.Owners) != 2 || web.Owners[0].Owner != "@cy" {
+			t.Errorf("unexpected web entry %+v", web)
+		}
+		if !strings.Contains(proposal.Content, "@acme/platform-core") {
+			t.Errorf("expected the team handle in the content:\n%s", proposal.Content)
+		}
+	})
+
+	t.Run("CheckDrift", func(t *testing.T) {
+		gitService.contents = map[string]string{"CODEOWNERS": strings.Join([]string{
+			"*         @ann",
+			"/core/    @acme/platform-core",
+			"/web/     @cy @ghost",
+			"/docs/    @ann",
+		}, "\n")}
+		report, err := uc.CheckDrift(1, "/repo", 0, mapping, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Path != "CODEOWNERS" || report.Rules != 4 || report.StaleAfterMonths != DefaultCodeownersStaleMonths {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if len(report.DriftedRules) != 2 {
+			t.Fatalf("expected the web and docs rules to drift, got %+v", report.DriftedRules)
+		}
+		web, docs := report.DriftedRules[0], report.DriftedRules[1]
+		if web.Pattern != "/web/" || len(web.StaleOwners) != 1 || web.StaleOwners[0].Owner != "@cy" ||
+			!slices.Equal(web.UnknownOwners, []string{"@ghost"}) || !slices.Equal(web.RecentAuthors, []string{"dan"}) {
+			t.Errorf("unexpected web drift %+v", web)
+		}
+		if docs.Pattern != "/docs/" || docs.Files != 0 || len(docs.StaleOwners) != 0 {
+			t.Errorf("expected the docs rule to own no files, got %+v", docs)
+		}
+
+		gitService.contents = nil
+		if _, err := uc.CheckDrift(1, "/repo", 0, mapping, now); !errors.Is(err, ErrNoCodeowners) {
+			t.Errorf("expected ErrNoCodeowners, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/internal/models"
+)
+
+// Supported sampling intervals for complexity trends
+const (
+	SampleIntervalCommit = "commit"
+	SampleIntervalWeek   = "week"
+	SampleIntervalMonth  = "month"
+)
+
+// trendStabilityBand is the relative complexity change treated as "stable"
+const trendStabilityBand = 0.05
+
+// ComplexityTrendUseCase samples file complexity across history by reading blobs from git
+type ComplexityTrendUseCase struct {
+	projectRepo    repositories.ProjectRepository
+	commitRepo     repositories.CommitRepository
+	complexityRepo repositories.ComplexityRepository
+	gitService     ports.GitService
+}
+
+// NewComplexityTrendUseCase creates a new complexity trend use case
+func NewComplexityTrendUseCase(
+	projectRepo repositories.ProjectRepository,
+	commitRepo repositories.CommitRepository,
+	complexityRepo repositories.ComplexityRepository,
+	gitService ports.GitService,
+) *ComplexityTrendUseCase {
+	return &ComplexityTrendUseCase{
+		projectRepo:    projectRepo,
+		commitRepo:     commitRepo,
+		complexityRepo: complexityRepo,
+		gitService:     gitService,
+	}
+}
+
+// GetComplexityTrend returns the complexity series for a file, sampling any missing points from git
+func (uc *ComplexityTrendUseCase) GetComplexityTrend(projectID int, filePath, interval string) (*models.ComplexityTrend, error) {
+	if interval == "" {
+		interval = SampleIntervalCommit
+	}
+	if interval != SampleIntervalCommit && interval != SampleIntervalWeek && interval != SampleIntervalMonth {
+		return nil, fmt.Errorf("invalid interval: %s", interval)
+	}
+
+	project, err := uc.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	commits, err := uc.commitRepo.GetByFilePath(projectID, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file history: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no history found for file %s", filePath)
+	}
+
+	stored, err := uc.complexityRepo.GetByFilePath(projectID, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored samples: %w", err)
+	}
+	storedByCommit := make(map[int]*entities.ComplexitySample, len(stored))
+	for _, sample := range stored {
+		storedByCommit[sample.CommitID] = sample
+	}
+
+	trend := &models.ComplexityTrend{
+		FilePath: filePath,
+		Interval: interval,
+		Samples:  []models.ComplexitySample{},
+	}
+
+	for _, commit := range selectSampleCommits(commits, interval) {
+		sample, exists := storedByCommit[commit.ID]
+		if !exists {
+			sample, err = uc.sampleCommit(project, commit, filePath)
+			if errors.Is(err, ports.ErrFileNotFound) {
+				// The commit deleted or renamed the file; there is nothing to measure
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to sample %s at %s: %w", filePath, commit.GetShortHash(), err)
+			}
+		}
+
+		trend.Samples = append(trend.Samples, models.ComplexitySample{
+			CommitHash:      commit.Hash.String(),
+			Author:          commit.Author,
+			Timestamp:       commit.Timestamp.Format(time.RFC3339),
+			LinesOfCode:     sample.LinesOfCode,
+			TotalComplexity: sample.TotalComplexity,
+			MeanComplexity:  sample.MeanComplexity,
+			MaxComplexity:   sample.MaxComplexity,
+		})
+	}
+
+	uc.classifyTrend(trend)
+
+	return trend, nil
+}
+
+// sampleCommit measures the file at a commit and stores the result
+func (uc *ComplexityTrendUseCase) sampleCommit(project *entities.Project, commit *entities.Commit, filePath string) (*entities.ComplexitySample, error) {
+	content, err := uc.gitService.GetFileContentAtCommit(project.RepoPath, commit.Hash.String(), filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := services.MeasureComplexity(content)
+	sample := &entities.ComplexitySample{
+		ProjectID:       project.ID,
+		CommitID:        commit.ID,
+		CommitHash:      commit.Hash.String(),
+		FilePath:        filePath,
+		Timestamp:       commit.Timestamp,
+		LinesOfCode:     metrics.LinesOfCode,
+		TotalComplexity: metrics.TotalComplexity,
+		MeanComplexity:  metrics.MeanComplexity,
+		MaxComplexity:   metrics.MaxComplexity,
+	}
+
+	if err := uc.complexityRepo.Save(sample); err != nil {
+		return nil, fmt.Errorf("failed to store sample: %w", err)
+	}
+
+	return sample, nil
+}
+
+// classifyTrend sets the deltas and overall direction of a trend
+func (uc *ComplexityTrendUseCase) classifyTrend(trend *models.ComplexityTrend) {
+	trend.Direction = "stable"
+	if len(trend.Samples) < 2 {
+		return
+	}
+
+	first := trend.Samples[0]
+	last := trend.Samples[len(trend.Samples)-1]
+	trend.ComplexityDelta = last.TotalComplexity - first.TotalComplexity
+	trend.LOCDelta = last.LinesOfCode - first.LinesOfCode
+
+	baseline := float64(first.TotalComplexity)
+	if baseline == 0 {
+		baseline = 1
+	}
+	change := float64(trend.ComplexityDelta) / baseline
+	if change > trendStabilityBand {
+		trend.Direction = "worsening"
+	} else if change < -trendStabilityBand {
+		trend.Direction = "improving"
+	}
+}
+
+// selectSampleCommits keeps every commit, or the latest commit per week/month bucket
+func selectSampleCommits(commits []*entities.Commit, interval string) []*entities.Commit {
+	if interval == SampleIntervalCommit {
+		return commits
+	}
+
+	var selected []*entities.Commit
+	lastBucket := ""
+	for _, commit := range commits {
+		var bucket string
+		if interval == SampleIntervalWeek {
+			year, week := commit.Timestamp.ISOWeek()
+			bucket = fmt.Sprintf("%d-W%02d", year, week)
+		} else {
+			bucket = commit.Timestamp.Format("2006-01")
+		}
+
+		// Commits are ordered oldest first, so the last commit of a bucket replaces earlier ones
+		if bucket == lastBucket {
+			selected[len(selected)-1] = commit
+		} else {
+			selected = append(selected, commit)
+			lastBucket = bucket
+		}
+	}
+	return selected
+}
@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+)
+
+type trendTestProjects struct {
+	repositories.ProjectRepository
+}
+
+func (r *trendTestProjects) GetByID(id int) (*entities.Project, error) {
+	return &entities.Project{ID: id, RepoPath: "/repo"}, nil
+}
+
+type trendTestCommits struct {
+	repositories.CommitRepository
+	commits []*entities.Commit
+}
+
+func (r *trendTestCommits) GetByFilePath(projectID int, filePath string) ([]*entities.Commit, error) {
+	return r.commits, nil
+}
+
+type trendTestSamples struct {
+	repositories.ComplexityRepository
+	saved   int
+	saveErr error
+}
+
+func (r *trendTestSamples) GetByFilePath(projectID int, filePath string) ([]*entities.ComplexitySample, error) {
+	return nil, nil
+}
+
+func (r *trendTestSamples) Save(sample *entities.ComplexitySample) error {
+	if r.saveErr == nil {
+		r.saved++
+	}
+	return r.saveErr
+}
+
+// trendTestGit serves file contents by commit hash, with errors for the other hashes
+type trendTestGit struct {
+	ports.GitService
+	contents map[string]string
+	errs     map[string]error
+}
+
+func (g *trendTestGit) GetFileContentAtCommit(repoPath string, commitHash string, filePath string) (string, error) {
+	if content, ok := g.contents[commitHash]; ok {
+		return content, nil
+	}
+	return "", g.errs[commitHash]
+}
+
+func TestGetComplexityTrendSkipsOnlyAbsentFiles(t *testing.T) {
+	hash := func(n int) string { return fmt.Sprintf("%040x", n) }
+	commits := &trendTestCommits{}
+	for n := 1; n <= 3; n++ {
+		h, err := values.NewGitHash(hash(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit := entities.NewCommit(1, h, "ann", time.Date(2025, 3, n, 0, 0, 0, 0, time.UTC), "change")
+		commit.ID = n
+		commits.commits = append(commits.commits, commit)
+	}
+
+	gitService := &trendTestGit{
+		contents: map[string]string{
+			hash(1): "func a() {\n\tif x {\n\t}\n}\n",
+			hash(3): "func a() {\n}\n",
+		},
+		errs: map[string]error{hash(2): fmt.Errorf("%w: main.go at %s", ports.ErrFileNotFound, hash(2))},
+	}
+	samples := &trendTestSamples{}
+	uc := NewComplexityTrendUseCase(&trendTestProjects{}, commits, samples, gitService)
+
+	// The file was deleted at the second commit and restored at the third
+	trend, err := uc.GetComplexityTrend(1, "main.go", SampleIntervalCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trend.Samples) != 2 || trend.Samples[0].CommitHash != hash(1) || trend.Samples[1].CommitHash != hash(3) {
+		t.Errorf("unexpected samples: %+v", trend.Samples)
+	}
+	if samples.saved != 2 {
+		t.Errorf("stored %d samples, want 2", samples.saved)
+	}
+
+	// Other git and storage failures are reported rather than leaving gaps in the series
+	gitService.errs[hash(2)] = errors.New("object not found")
+	if _, err := uc.GetComplexityTrend(1, "main.go", SampleIntervalCommit); err == nil || !strings.Contains(err.Error(), "object not found") {
+		t.Errorf("expected the git error to be reported, got %v", err)
+	}
+
+	samples.saveErr = errors.New("disk full")
+	delete(gitService.errs, hash(2))
+	gitService.contents[hash(2)] = "x := 1\n"
+	if _, err := uc.GetComplexityTrend(1, "main.go", SampleIntervalCommit); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected the storage error to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"sort"
+
+	"codeecho/domain/services"
+	"codeecho/internal/models"
+)
+
+// GetCouplingSum aggregates the coupling graph per file: how many files each one co-changes
+// with and how many commits it shares with them. Files are ordered by sum of coupling;
+// limit <= 0 returns every coupled file.
+func (uc *AnalyticsUseCase) GetCouplingSum(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.FileCouplingSum, error) {
+	pairs, err := uc.repo.GetCouplingGraph(projectID, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := couplingSums(pairs)
+	result := make([]models.FileCouplingSum, 0, len(sums))
+	for _, sum := range sums {
+		result = append(result, *sum)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SumOfCoupling != result[j].SumOfCoupling {
+			return result[i].SumOfCoupling > result[j].SumOfCoupling
+		}
+		if result[i].CoupledFiles != result[j].CoupledFiles {
+			return result[i].CoupledFiles > result[j].CoupledFiles
+		}
+		return result[i].FilePath < result[j].FilePath
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// GetCouplingClusters detects communities of files that change together in the coupling
+// graph, weighting edges by coupling score. Clusters smaller than minSize are dropped and
+// limit <= 0 returns every cluster.
+func (uc *AnalyticsUseCase) GetCouplingClusters(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string, minSize int) (*models.CouplingClusterReport, error) {
+	pairs, err := uc.repo.GetCouplingGraph(projectID, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]services.CouplingEdge, 0, len(pairs))
+	for _, pair := range pairs {
+		edges = append(edges, services.CouplingEdge{FileA: pair.FileA, FileB: pair.FileB, Weight: pair.CouplingScore})
+	}
+	communities := services.DetectCouplingCommunities(edges)
+
+	report := &models.CouplingClusterReport{
+		ProjectID:  projectID,
+		TotalPairs: len(pairs),
+		Modularity: services.CouplingModularity(edges, communities),
+		Clusters:   make([]models.CouplingCluster, 0, len(communities)),
+	}
+
+	clusterOf := make(map[string]int)
+	for i, members := range communities {
+		report.TotalFiles += len(members)
+		for _, member := range members {
+			clusterOf[member] = i
+		}
+	}
+
+	internalPairs := make([][]models.TemporalCoupling, len(communities))
+	externalPairs := make([]int, len(communities))
+	for _, pair := range pairs {
+		a, b := clusterOf[pair.FileA], clusterOf[pair.FileB]
+		if a == b {
+			internalPairs[a] = append(internalPairs[a], pair)
+			continue
+		}
+		externalPairs[a]++
+		externalPairs[b]++
+	}
+
+	for i, members := range communities {
+		if len(members) < minSize {
+			continue
+		}
+		cluster := models.CouplingCluster{
+			ID:            len(report.Clusters) + 1,
+			Size:          len(members),
+			Files:         members,
+			InternalPairs: len(internalPairs[i]),
+			ExternalPairs: externalPairs[i],
+		}
+		if possible := len(members) * (len(members) - 1) / 2; possible > 0 {
+			cluster.Density = float64(cluster.InternalPairs) / float64(possible)
+		}
+
+		totalScore := 0.0
+		for _, pair := range internalPairs[i] {
+			totalScore += pair.CouplingScore
+		}
+		if cluster.InternalPairs > 0 {
+			cluster.AverageScore = totalScore / float64(cluster.InternalPairs)
+		}
+
+		bestSum := -1
+		for file, sum := range couplingSums(internalPairs[i]) {
+			if sum.SumOfCoupling > bestSum || (sum.SumOfCoupling == bestSum && file < cluster.Hub) {
+				cluster.Hub, bestSum = file, sum.SumOfCoupling
+			}
+		}
+
+		report.Clusters = append(report.Clusters, cluster)
+		if limit > 0 && len(report.Clusters) == limit {
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// couplingSums accumulates the per-file coupling totals of a set of pairs
+func couplingSums(pairs []models.TemporalCoupling) map[string]*models.FileCouplingSum {
+	sums := make(map[string]*models.FileCouplingSum)
+	add := func(file, partner string, totalCommits int, pair models.TemporalCoupling) {
+		sum, ok := sums[file]
+		if !ok {
+			sum = &models.FileCouplingSum{FilePath: file, TotalCommits: totalCommits}
+			sums[file] = sum
+		}
+		sum.CoupledFiles++
+		sum.SumOfCoupling += pair.SharedCommits
+		// AverageCoupling holds the running total until all pairs are seen
+		sum.AverageCoupling += pair.CouplingScore
+		if pair.CouplingScore > sum.MaxCoupling || (pair.CouplingScore == sum.MaxCoupling && partner < sum.StrongestPartner) {
+			sum.MaxCoupling = pair.CouplingScore
+			sum.StrongestPartner = partner
+		}
+	}
+	for _, pair := range pairs {
+		add(pair.FileA, pair.FileB, pair.TotalCommitsA, pair)
+		add(pair.FileB, pair.FileA, pair.TotalCommitsB, pair)
+	}
+	for _, sum := range sums {
+		sum.AverageCoupling /= float64(sum.CoupledFiles)
+	}
+	return sums
+}
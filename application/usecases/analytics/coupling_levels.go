@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"fmt"
+
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// CouplingOptions configures the level at which temporal coupling is measured and which
+// commits form one logical change
+type CouplingOptions struct {
+	Level values.CouplingLevel
+	// Depth is the number of leading directories kept for the directory level
+	Depth int
+	// Layers define the groups of the layer level; files matching no layer are ignored
+	Layers []services.ArchitectureLayer
+	// ChangeSets groups commits into logical change sets; the zero value uses single commits
+	ChangeSets services.ChangeSetGrouping
+}
+
+// GetLevelTemporalCoupling measures temporal coupling at the requested level (between files,
+// directories at a depth, or user-defined architectural layers) over the requested change sets
+func (uc *AnalyticsUseCase) GetLevelTemporalCoupling(projectID int, options CouplingOptions, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error) {
+	perCommit := options.ChangeSets.Mode == "" || options.ChangeSets.Mode == values.ChangeSetByCommit
+
+	var group func(filePath string) (string, bool)
+	switch options.Level {
+	case values.CouplingByFile, "":
+		if perCommit {
+			return uc.GetTemporalCoupling(projectID, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+		}
+		group = func(filePath string) (string, bool) {
+			return filePath, true
+		}
+	case values.CouplingByDirectory:
+		if options.Depth <= 0 {
+			return nil, fmt.Errorf("directory coupling requires a positive depth")
+		}
+		group = func(filePath string) (string, bool) {
+			return directoryAtDepth(filePath, options.Depth), true
+		}
+	case values.CouplingByLayer:
+		if len(options.Layers) == 0 {
+			return nil, fmt.Errorf("layer coupling requires at least one layer")
+		}
+		group = func(filePath string) (string, bool) {
+			return services.LayerOf(options.Layers, filePath)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported coupling level %q", options.Level)
+	}
+
+	return uc.repo.GetAggregatedTemporalCoupling(projectID, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes, group, options.ChangeSets)
+}
@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"sort"
+
+	"codeecho/internal/models"
+)
+
+// GetDefectHotspots ranks the files of a project by the number of bug-fix commits that touched
+// them, then by defect density (bug-fix commits per 1,000 code lines). Files with fewer than
+// minBugfixes bug-fix commits are skipped; limit <= 0 returns every file. startDate and endDate
+// (YYYY-MM-DD) and pathPrefix are optional.
+func (uc *AnalyticsUseCase) GetDefectHotspots(projectID int, startDate, endDate, pathPrefix string, minBugfixes, limit int) (*models.DefectHotspotReport, error) {
+	hotspots, err := uc.repo.GetDefectHotspots(projectID, startDate, endDate, pathPrefix)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := uc.repo.GetCommitCategoryCounts(projectID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DefectHotspotReport{
+		ProjectID:    projectID,
+		Path:         pathPrefix,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Categories:   make(map[string]int),
+		Unclassified: counts[""],
+		Files:        make([]models.DefectHotspot, 0, len(hotspots)),
+	}
+	for category, commits := range counts {
+		if category != "" {
+			report.Categories[category] = commits
+		}
+	}
+
+	for _, file := range hotspots {
+		if file.BugfixCommits < minBugfixes {
+			continue
+		}
+		if file.TotalCommits > 0 {
+			file.BugfixRatio = float64(file.BugfixCommits) / float64(file.TotalCommits)
+		}
+		if file.CodeLines > 0 {
+			file.DefectDensity = float64(file.BugfixCommits) * 1000 / float64(file.CodeLines)
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		a, b := report.Files[i], report.Files[j]
+		if a.BugfixCommits != b.BugfixCommits {
+			return a.BugfixCommits > b.BugfixCommits
+		}
+		if a.DefectDensity != b.DefectDensity {
+			return a.DefectDensity > b.DefectDensity
+		}
+		return a.FilePath < b.FilePath
+	})
+	if limit > 0 && len(report.Files) > limit {
+		report.Files = report.Files[:limit]
+	}
+
+	return report, nil
+}
@@ -0,0 +1,144 @@
+package analytics
+
+import (
+	"sort"
+
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// fileContributor is the per-author input to the fragmentation metrics of a file
+type fileContributor struct {
+	author     string
+	commits    int
+	linesAdded int
+}
+
+// fileFragmentation holds the fragmentation metrics computed for a single file
+type fileFragmentation struct {
+	fragmentation      float64
+	mainDeveloper      string
+	mainDeveloperShare float64
+	effort             []models.AuthorEffort
+}
+
+// computeFragmentation derives the fractal value of the commit distribution, the main
+// developer (most lines added) and the entity effort distribution (share of commits)
+func computeFragmentation(contributors []fileContributor) fileFragmentation {
+	result := fileFragmentation{effort: make([]models.AuthorEffort, 0, len(contributors))}
+
+	commits := make([]int, 0, len(contributors))
+	totalCommits, totalAdded, maxAdded := 0, 0, -1
+	for _, contributor := range contributors {
+		commits = append(commits, contributor.commits)
+		totalCommits += contributor.commits
+		totalAdded += contributor.linesAdded
+		if contributor.linesAdded > maxAdded {
+			maxAdded = contributor.linesAdded
+			result.mainDeveloper = contributor.author
+		}
+	}
+	result.fragmentation = services.FractalValue(commits)
+	if totalAdded > 0 {
+		result.mainDeveloperShare = float64(maxAdded) / float64(totalAdded) * 100
+	}
+
+	for _, contributor := range contributors {
+		effort := models.AuthorEffort{Author: contributor.author, Commits: contributor.commits}
+		if totalCommits > 0 {
+			effort.Percentage = float64(contributor.commits) / float64(totalCommits) * 100
+		}
+		result.effort = append(result.effort, effort)
+	}
+	sort.SliceStable(result.effort, func(i, j int) bool {
+		return result.effort[i].Commits > result.effort[j].Commits
+	})
+
+	return result
+}
+
+// applyBusFactorFragmentation fills the fragmentation metrics of every bus factor entry
+func applyBusFactorFragmentation(data []models.BusFactorData) {
+	for i := range data {
+		contributors := make([]fileContributor, 0, len(data[i].OwnershipDistribution))
+		for _, ownership := range data[i].OwnershipDistribution {
+			contributors = append(contributors, fileContributor{
+				author:     ownership.Author,
+				commits:    ownership.Commits,
+				linesAdded: ownership.LinesAdded,
+			})
+		}
+		metrics := computeFragmentation(contributors)
+		data[i].Fragmentation = metrics.fragmentation
+		data[i].MainDeveloper = metrics.mainDeveloper
+		data[i].MainDeveloperShare = metrics.mainDeveloperShare
+		data[i].Effort = metrics.effort
+	}
+}
+
+// applyOwnershipFragmentation fills the fragmentation metrics of every file ownership entry
+func applyOwnershipFragmentation(ownership []models.FileOwnership) {
+	for i := range ownership {
+		contributors := make([]fileContributor, 0, len(ownership[i].Contributors))
+		for _, contrib := range ownership[i].Contributors {
+			contributors = append(contributors, fileContributor{
+				author:     contrib.Author,
+				commits:    contrib.Commits,
+				linesAdded: contrib.LinesAdded,
+			})
+		}
+		metrics := computeFragmentation(contributors)
+		ownership[i].Fragmentation = metrics.fragmentation
+		ownership[i].MainDeveloper = metrics.mainDeveloper
+		ownership[i].MainDeveloperShare = metrics.mainDeveloperShare
+		ownership[i].Effort = metrics.effort
+	}
+}
+
+// GetFragmentation ranks the files of a project by fragmentation, most fragmented first.
+// Files with fewer than minAuthors contributors are skipped; limit <= 0 returns every file.
+func (uc *AnalyticsUseCase) GetFragmentation(projectID int, pathPrefix string, minAuthors, limit int) (*models.FragmentationReport, error) {
+	data, err := uc.GetBusFactorAnalysis(projectID, nil, nil, "", pathPrefix, values.OwnershipByCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.FragmentationReport{
+		ProjectID:  projectID,
+		Path:       pathPrefix,
+		MinAuthors: minAuthors,
+		Files:      make([]models.FileFragmentation, 0, len(data)),
+	}
+
+	total := 0.0
+	for _, file := range data {
+		if len(file.OwnershipDistribution) < minAuthors {
+			continue
+		}
+		report.Files = append(report.Files, models.FileFragmentation{
+			FilePath:           file.FilePath,
+			Fragmentation:      file.Fragmentation,
+			Authors:            len(file.OwnershipDistribution),
+			TotalCommits:       file.TotalCommits,
+			MainDeveloper:      file.MainDeveloper,
+			MainDeveloperShare: file.MainDeveloperShare,
+		})
+		total += file.Fragmentation
+	}
+	if len(report.Files) > 0 {
+		report.AverageFragmentation = total / float64(len(report.Files))
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		if report.Files[i].Fragmentation != report.Files[j].Fragmentation {
+			return report.Files[i].Fragmentation > report.Files[j].Fragmentation
+		}
+		return report.Files[i].FilePath < report.Files[j].FilePath
+	})
+	if limit > 0 && len(report.Files) > limit {
+		report.Files = report.Files[:limit]
+	}
+
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"codeecho/internal/models"
+)
+
+// ErrIssueNotFound is returned when no commit of the project references the requested issue
+var ErrIssueNotFound = errors.New("issue not found")
+
+// GetIssues lists the issues referenced by a project's commits, most referenced first.
+// issueType optionally restricts the list to imported issues of that type; limit <= 0
+// returns every issue.
+func (uc *AnalyticsUseCase) GetIssues(projectID int, issueType string, limit int) (*models.IssueReport, error) {
+	issues, err := uc.repo.GetIssues(projectID, issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Commits != issues[j].Commits {
+			return issues[i].Commits > issues[j].Commits
+		}
+		return issues[i].Key < issues[j].Key
+	})
+	if limit > 0 && len(issues) > limit {
+		issues = issues[:limit]
+	}
+
+	return &models.IssueReport{ProjectID: projectID, Type: issueType, Issues: issues}, nil
+}
+
+// GetIssueCommits returns an issue with the commits referencing it
+func (uc *AnalyticsUseCase) GetIssueCommits(projectID int, key string) (*models.IssueDetail, error) {
+	detail, err := uc.getIssueDetail(projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Commits, err = uc.repo.GetIssueCommits(projectID, detail.Issue.Key); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// GetIssueFiles returns an issue with the files its commits changed
+func (uc *AnalyticsUseCase) GetIssueFiles(projectID int, key string) (*models.IssueDetail, error) {
+	detail, err := uc.getIssueDetail(projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Files, err = uc.repo.GetIssueFiles(projectID, detail.Issue.Key); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// GetIssueAuthors returns an issue with the authors of its commits
+func (uc *AnalyticsUseCase) GetIssueAuthors(projectID int, key string) (*models.IssueDetail, error) {
+	detail, err := uc.getIssueDetail(projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Authors, err = uc.repo.GetIssueAuthors(projectID, detail.Issue.Key); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// getIssueDetail looks an issue up by key, which is matched case-insensitively as keys are
+// stored upper-cased
+func (uc *AnalyticsUseCase) getIssueDetail(projectID int, key string) (*models.IssueDetail, error) {
+	issue, err := uc.repo.GetIssue(projectID, strings.ToUpper(strings.TrimSpace(key)))
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, ErrIssueNotFound
+	}
+	return &models.IssueDetail{ProjectID: projectID, Issue: issue}, nil
+}
+
+// GetFileIssues returns the issues referenced by the commits that changed a file
+func (uc *AnalyticsUseCase) GetFileIssues(projectID int, filePath string) ([]models.FileIssue, error) {
+	return uc.repo.GetFileIssues(projectID, filePath)
+}
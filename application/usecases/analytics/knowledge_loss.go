@@ -0,0 +1,203 @@
+package analytics
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// Defaults for knowledge loss analysis
+const (
+	// DefaultInactiveAfterMonths is how long an author may go without committing before being treated as gone
+	DefaultInactiveAfterMonths = 6
+	// DefaultAbandonedThreshold is the inactive ownership share (percent) from which a file counts as abandoned
+	DefaultAbandonedThreshold = 50.0
+)
+
+// Author status values accepted when overriding an author's activity
+const (
+	AuthorStatusActive   = "active"
+	AuthorStatusInactive = "inactive"
+	AuthorStatusAuto     = "auto"
+)
+
+// KnowledgeLossUseCase classifies authors as active or inactive and measures how much
+// of the codebase is owned by authors who are gone
+type KnowledgeLossUseCase struct {
+	repo       ports.AnalyticsRepository
+	statusRepo repositories.AuthorStatusRepository
+}
+
+// NewKnowledgeLossUseCase creates a new knowledge loss use case
+func NewKnowledgeLossUseCase(repo ports.AnalyticsRepository, statusRepo repositories.AuthorStatusRepository) *KnowledgeLossUseCase {
+	return &KnowledgeLossUseCase{
+		repo:       repo,
+		statusRepo: statusRepo,
+	}
+}
+
+// GetAuthorActivity lists the authors of a project with their activity status. Manual
+// overrides win; other authors are inactive when their last commit is older than
+// inactiveAfterMonths.
+func (uc *KnowledgeLossUseCase) GetAuthorActivity(projectID int, inactiveAfterMonths int, now time.Time) ([]models.AuthorActivity, error) {
+	authors, err := uc.repo.GetAuthorActivity(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author activity: %w", err)
+	}
+
+	overrides, err := uc.statusRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author statuses: %w", err)
+	}
+	overrideByAuthor := make(map[string]*entities.AuthorStatus, len(overrides))
+	for _, o := range overrides {
+		overrideByAuthor[o.Author] = o
+	}
+
+	cutoff := now.AddDate(0, -inactiveAfterMonths, 0)
+	for i := range authors {
+		a := &authors[i]
+		if o, exists := overrideByAuthor[a.Author]; exists {
+			a.Active = o.Active
+			a.StatusSource = "manual"
+			a.Note = o.Note
+			continue
+		}
+		a.Active = a.LastCommit != nil && a.LastCommit.After(cutoff)
+		a.StatusSource = "auto"
+	}
+
+	return authors, nil
+}
+
+// SetAuthorStatus marks an author as active or inactive, or with AuthorStatusAuto
+// removes the override so the author is classified from their commit activity again
+func (uc *KnowledgeLossUseCase) SetAuthorStatus(projectID int, author, status, note string) error {
+	if author == "" {
+		return fmt.Errorf("author is required")
+	}
+
+	switch status {
+	case AuthorStatusAuto:
+		return uc.statusRepo.Delete(projectID, author)
+	case AuthorStatusActive, AuthorStatusInactive:
+		return uc.statusRepo.Save(&entities.AuthorStatus{
+			ProjectID: projectID,
+			Author:    author,
+			Active:    status == AuthorStatusActive,
+			Note:      note,
+			UpdatedAt: time.Now(),
+		})
+	default:
+		return fmt.Errorf("invalid status %q: must be one of active, inactive, auto", status)
+	}
+}
+
+// GetKnowledgeLoss reports, per file and per directory, the share of ownership held by
+// inactive authors. Ownership distributions come from the bus factor analysis so the
+// same ownership metrics are available.
+func (uc *KnowledgeLossUseCase) GetKnowledgeLoss(projectID int, pathPrefix string, metric values.OwnershipMetric, inactiveAfterMonths int, abandonedThreshold float64, now time.Time) (*models.KnowledgeLossReport, error) {
+	authors, err := uc.GetAuthorActivity(projectID, inactiveAfterMonths, now)
+	if err != nil {
+		return nil, err
+	}
+
+	distributions, err := uc.repo.GetBusFactorAnalysis(projectID, nil, nil, "", pathPrefix, metric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ownership distributions: %w", err)
+	}
+
+	report := &models.KnowledgeLossReport{
+		ProjectID:           projectID,
+		Ownership:           string(metric),
+		InactiveAfterMonths: inactiveAfterMonths,
+		AbandonedThreshold:  abandonedThreshold,
+		InactiveAuthors:     make([]models.AuthorActivity, 0),
+		Files:               make([]models.FileKnowledgeLoss, 0, len(distributions)),
+	}
+
+	inactive := make(map[string]bool)
+	for _, a := range authors {
+		if !a.Active {
+			inactive[a.Author] = true
+			report.InactiveAuthors = append(report.InactiveAuthors, a)
+		}
+	}
+
+	directories := make(map[string]*models.DirectoryKnowledgeLoss)
+	totalAbandoned := 0.0
+
+	for _, data := range distributions {
+		file := models.FileKnowledgeLoss{
+			FilePath:       data.FilePath,
+			InactiveOwners: make([]models.AuthorOwnership, 0),
+			ActiveOwners:   make([]models.AuthorOwnership, 0),
+		}
+
+		for i, owner := range data.OwnershipDistribution {
+			if i == 0 {
+				file.MainOwner = owner.Author
+				file.MainOwnerInactive = inactive[owner.Author]
+			}
+			if inactive[owner.Author] {
+				file.AbandonedPercent += owner.OwnershipPercent
+				file.InactiveOwners = append(file.InactiveOwners, owner)
+			} else {
+				file.ActiveOwners = append(file.ActiveOwners, owner)
+			}
+		}
+		file.Abandoned = file.AbandonedPercent >= abandonedThreshold
+
+		report.Files = append(report.Files, file)
+		totalAbandoned += file.AbandonedPercent
+		if file.Abandoned {
+			report.Summary.AbandonedFiles++
+		}
+
+		for dir := path.Dir(data.FilePath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			rollup, exists := directories[dir]
+			if !exists {
+				rollup = &models.DirectoryKnowledgeLoss{Path: dir}
+				directories[dir] = rollup
+			}
+			rollup.Files++
+			rollup.AbandonedPercent += file.AbandonedPercent
+			if file.Abandoned {
+				rollup.AbandonedFiles++
+			}
+		}
+	}
+
+	report.Summary.TotalFiles = len(report.Files)
+	if report.Summary.TotalFiles > 0 {
+		report.Summary.AbandonedPercent = totalAbandoned / float64(report.Summary.TotalFiles)
+	}
+
+	report.Directories = make([]models.DirectoryKnowledgeLoss, 0, len(directories))
+	for _, rollup := range directories {
+		rollup.AbandonedPercent /= float64(rollup.Files)
+		report.Directories = append(report.Directories, *rollup)
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		if report.Files[i].AbandonedPercent != report.Files[j].AbandonedPercent {
+			return report.Files[i].AbandonedPercent > report.Files[j].AbandonedPercent
+		}
+		return report.Files[i].FilePath < report.Files[j].FilePath
+	})
+	sort.Slice(report.Directories, func(i, j int) bool {
+		if report.Directories[i].AbandonedPercent != report.Directories[j].AbandonedPercent {
+			return report.Directories[i].AbandonedPercent > report.Directories[j].AbandonedPercent
+		}
+		return report.Directories[i].Path < report.Directories[j].Path
+	})
+
+	return report, nil
+}
@@ -0,0 +1,137 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+type knowledgeTestRepo struct {
+	ports.AnalyticsRepository
+	activity  []models.AuthorActivity
+	ownership []models.BusFactorData
+}
+
+func (r *knowledgeTestRepo) GetAuthorActivity(projectID int) ([]models.AuthorActivity, error) {
+	// The use case sets the status on the rows it gets, so every call gets its own copy
+	return append([]models.AuthorActivity{}, r.activity...), nil
+}
+
+func (r *knowledgeTestRepo) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string, metric values.OwnershipMetric) ([]models.BusFactorData, error) {
+	return r.ownership, nil
+}
+
+type knowledgeTestStatuses struct {
+	repositories.AuthorStatusRepository
+	overrides []*entities.AuthorStatus
+}
+
+func (r *knowledgeTestStatuses) GetByProjectID(projectID int) ([]*entities.AuthorStatus, error) {
+	return r.overrides, nil
+}
+
+func TestGetKnowledgeLoss(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent, old := now.AddDate(0, -1, 0), now.AddDate(-1, 0, 0)
+	owned := func(path string, owners ...models.AuthorOwnership) models.BusFactorData {
+		return models.BusFactorData{FilePath: path, OwnershipDistribution: owners}
+	}
+	owner := func(author string, percent float64) models.AuthorOwnership {
+		return models.AuthorOwnership{Author: author, OwnershipPercent: percent}
+	}
+	repo := &knowledgeTestRepo{
+		// bob left a year ago; cy too, but is marked active, and dan is marked inactive
+		activity: []models.AuthorActivity{
+			{Author: "ann", LastCommit: &recent},
+			{Author: "bob", LastCommit: &old},
+			{Author: "cy", LastCommit: &old},
+			{Author: "dan", LastCommit: &recent},
+		},
+		ownership: []models.BusFactorData{
+			owned("core/a.go", owner("bob", 60), owner("ann", 40)),
+			owned("core/b.go", owner("cy", 100)),
+			owned("core/sub/c.go", owner("ann", 60), owner("dan", 40)),
+			owned("web/x.js", owner("bob", 50), owner("dan", 50)),
+		},
+	}
+	statuses := &knowledgeTestStatuses{overrides: []*entities.AuthorStatus{
+		{Author: "cy", Active: true},
+		{Author: "dan", Active: false},
+	}}
+	uc := NewKnowledgeLossUseCase(repo, statuses)
+
+	report, err := uc.GetKnowledgeLoss(1, "", values.OwnershipByCommits, 6, 50, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.InactiveAuthors) != 2 || report.InactiveAuthors[0].Author != "bob" || report.InactiveAuthors[0].StatusSource != "auto" ||
+		report.InactiveAuthors[1].Author != "dan" || report.InactiveAuthors[1].StatusSource != "manual" {
+		t.Errorf("expected bob by the threshold and dan by override, got %+v", report.InactiveAuthors)
+	}
+
+	want := []struct {
+		path      string
+		percent   float64
+		abandoned bool
+	}{
+		{"web/x.js", 100, true},
+		{"core/a.go", 60, true},
+		{"core/sub/c.go", 40, false},
+		{"core/b.go", 0, false},
+	}
+	if len(report.Files) != len(want) {
+		t.Fatalf("expected %d files, got %+v", len(want), report.Files)
+	}
+	for i, w := range want {
+		if f := report.Files[i]; f.FilePath != w.path || f.AbandonedPercent != w.percent || f.Abandoned != w.abandoned {
+			t.Errorf("file %d: got %+v, want %+v", i, f, w)
+		}
+	}
+	if a := report.Files[1]; a.MainOwner != "bob" || !a.MainOwnerInactive || len(a.InactiveOwners) != 1 || len(a.ActiveOwners) != 1 {
+		t.Errorf("unexpected owners of core/a.go %+v", a)
+	}
+	if s := report.Summary; s.TotalFiles != 4 || s.AbandonedFiles != 2 || s.AbandonedPercent != 50 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+
+	// Directories roll up every file below them, nested directories included
+	wantDirs := []models.DirectoryKnowledgeLoss{
+		{Path: "web", Files: 1, AbandonedFiles: 1, AbandonedPercent: 100},
+		{Path: "core/sub", Files: 1, AbandonedFiles: 0, AbandonedPercent: 40},
+		{Path: "core", Files: 3, AbandonedFiles: 1, AbandonedPercent: 100.0 / 3},
+	}
+	if len(report.Directories) != len(wantDirs) {
+		t.Fatalf("expected %d directories, got %+v", len(wantDirs), report.Directories)
+	}
+	for i, w := range wantDirs {
+		d := report.Directories[i]
+		if d.Path != w.Path || d.Files != w.Files || d.AbandonedFiles != w.AbandonedFiles || math.Abs(d.AbandonedPercent-w.AbandonedPercent) > 1e-9 {
+			t.Errorf("directory %d: got %+v, want %+v", i, d, w)
+		}
+	}
+
+	// A lower threshold abandons core/sub/c.go as well
+	report, err = uc.GetKnowledgeLoss(1, "", values.OwnershipByCommits, 6, 40, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.AbandonedFiles != 3 || !report.Files[2].Abandoned {
+		t.Errorf("expected 3 abandoned files at 40%%, got %+v", report.Summary)
+	}
+
+	// With a two-year cutoff only the overrides decide
+	report, err = uc.GetKnowledgeLoss(1, "", values.OwnershipByCommits, 24, 50, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.InactiveAuthors) != 1 || report.InactiveAuthors[0].Author != "dan" {
+		t.Errorf("expected only dan to be inactive, got %+v", report.InactiveAuthors)
+	}
+}
@@ -0,0 +1,158 @@
+package analytics
+
+import (
+	"fmt"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// QualityGateUseCase measures the rules of a quality gate and evaluates them against a
+// baseline measured before an analysis
+type QualityGateUseCase struct {
+	repo       ports.AnalyticsRepository
+	changeRepo repositories.ChangeRepository
+	analytics  *AnalyticsUseCase
+}
+
+// NewQualityGateUseCase creates a new quality gate use case
+func NewQualityGateUseCase(repo ports.AnalyticsRepository, changeRepo repositories.ChangeRepository) *QualityGateUseCase {
+	return &QualityGateUseCase{
+		repo:       repo,
+		changeRepo: changeRepo,
+		analytics:  NewAnalyticsUseCase(repo),
+	}
+}
+
+// ResolveRules validates a gate configuration and fills the limits its rules leave at zero
+// from the project's risk policy and the gate defaults
+func (uc *QualityGateUseCase) ResolveRules(projectID int, config entities.GateConfig) ([]entities.GateRule, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	assessor, err := uc.analytics.GetRiskAssessor(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]entities.GateRule, 0, len(config.Rules))
+	for _, rule := range config.Rules {
+		rules = append(rules, services.ResolveGateRule(rule, assessor.Policy()))
+	}
+	return rules, nil
+}
+
+// Measure measures every resolved rule on the project's current data
+func (uc *QualityGateUseCase) Measure(projectID int, rules []entities.GateRule) ([]services.GateMeasurement, error) {
+	measurements := make([]services.GateMeasurement, 0, len(rules))
+	for _, rule := range rules {
+		measurement, err := uc.measure(projectID, rule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure rule %s: %w", rule.Name, err)
+		}
+		measurements = append(measurements, measurement)
+	}
+	return measurements, nil
+}
+
+// measure measures one rule: the bus factor of the files under its path, the change count of
+// the top hotspots, or the coupling between the components of its level
+func (uc *QualityGateUseCase) measure(projectID int, rule entities.GateRule) (services.GateMeasurement, error) {
+	var measurement services.GateMeasurement
+	switch rule.Type {
+	case values.GateBusFactor:
+		files, err := uc.repo.GetBusFactorAnalysis(projectID, nil, nil, "", rule.Path, values.OwnershipByCommits)
+		if err != nil {
+			return measurement, err
+		}
+		measurement.BusFactors = make(map[string]int, len(files))
+		for _, file := range files {
+			shares := make([]float64, 0, len(file.OwnershipDistribution))
+			for _, owner := range file.OwnershipDistribution {
+				shares = append(shares, owner.OwnershipPercent)
+			}
+			measurement.BusFactors[file.FilePath] = services.BusFactor(shares)
+		}
+
+	case values.GateHotspotGrowth:
+		hotspots, err := uc.changeRepo.GetHotspots(projectID, rule.Top)
+		if err != nil {
+			return measurement, err
+		}
+		measurement.HotspotScores = make(map[string]int, len(hotspots))
+		for _, hotspot := range hotspots {
+			measurement.HotspotScores[hotspot.FilePath] = hotspot.ChangeCount
+		}
+
+	case values.GateCoupling:
+		options := CouplingOptions{Level: rule.Level, Depth: rule.Depth}
+		for _, definition := range rule.Layers {
+			layer, err := services.ParseArchitectureLayer(definition)
+			if err != nil {
+				return measurement, err
+			}
+			options.Layers = append(options.Layers, layer)
+		}
+		pairs, err := uc.analytics.GetLevelTemporalCoupling(projectID, options, 0, "", "", rule.MinSharedCommits, rule.MinScore, "")
+		if err != nil {
+			return measurement, err
+		}
+		measurement.Couplings = make(map[services.FilePair]float64, len(pairs))
+		for _, pair := range pairs {
+			key := services.FilePair{A: pair.FileA, B: pair.FileB}
+			if key.B < key.A {
+				key.A, key.B = key.B, key.A
+			}
+			measurement.Couplings[key] = pair.CouplingScore
+		}
+	}
+	return measurement, nil
+}
+
+// Evaluate compares the current measurements of the rules with their baseline, which is nil
+// when the project had not been analysed before
+func (uc *QualityGateUseCase) Evaluate(rules []entities.GateRule, baseline, current []services.GateMeasurement) ([]models.QualityGateRuleResult, int) {
+	results := make([]models.QualityGateRuleResult, 0, len(rules))
+	violations := 0
+	for i, rule := range rules {
+		var before *services.GateMeasurement
+		if baseline != nil {
+			before = &baseline[i]
+		}
+		outcome := services.EvaluateGateRule(rule, before, current[i])
+
+		result := models.QualityGateRuleResult{
+			Name:       rule.Name,
+			Type:       string(rule.Type),
+			Limit:      gateRuleLimit(rule),
+			Passed:     outcome.Passed(),
+			Checked:    outcome.Checked,
+			Violations: make([]models.QualityGateViolation, 0, len(outcome.Violations)),
+		}
+		for _, violation := range outcome.Violations {
+			result.Violations = append(result.Violations, models.QualityGateViolation{
+				Subject: violation.Subject,
+				Message: violation.Message,
+			})
+		}
+		violations += len(outcome.Violations)
+		results = append(results, result)
+	}
+	return results, violations
+}
+
+// gateRuleLimit returns the limit a resolved rule is checked against
+func gateRuleLimit(rule entities.GateRule) float64 {
+	switch rule.Type {
+	case values.GateBusFactor:
+		return float64(rule.MaxBusFactor)
+	case values.GateHotspotGrowth:
+		return rule.MaxGrowthPercent
+	default:
+		return rule.MinScore
+	}
+}
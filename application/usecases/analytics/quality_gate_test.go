@@ -0,0 +1,103 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// gateTestRepo serves the ownership of the files under a path, with the default risk policy
+type gateTestRepo struct {
+	ports.AnalyticsRepository
+	path  string
+	files []models.BusFactorData
+}
+
+func (r *gateTestRepo) GetRiskPolicy(projectID int) (*entities.RiskPolicy, error) {
+	return nil, nil
+}
+
+func (r *gateTestRepo) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string, metric values.OwnershipMetric) ([]models.BusFactorData, error) {
+	r.path = path
+	return r.files, nil
+}
+
+type gateTestChanges struct {
+	repositories.ChangeRepository
+	limit    int
+	hotspots []*repositories.FileChangeFrequency
+}
+
+func (r *gateTestChanges) GetHotspots(projectID int, limit int) ([]*repositories.FileChangeFrequency, error) {
+	r.limit = limit
+	return r.hotspots, nil
+}
+
+func TestQualityGate(t *testing.T) {
+	owned := func(path string, shares ...float64) models.BusFactorData {
+		file := models.BusFactorData{FilePath: path}
+		for _, share := range shares {
+			file.OwnershipDistribution = append(file.OwnershipDistribution, models.AuthorOwnership{OwnershipPercent: share})
+		}
+		return file
+	}
+	repo := &gateTestRepo{files: []models.BusFactorData{owned("core/a.go", 40, 30, 30), owned("core/b.go", 90, 10)}}
+	changes := &gateTestChanges{hotspots: []*repositories.FileChangeFrequency{
+		{FilePath: "core/a.go", ChangeCount: 10},
+		{FilePath: "core/b.go", ChangeCount: 10},
+	}}
+	uc := NewQualityGateUseCase(repo, changes)
+
+	rules, err := uc.ResolveRules(1, entities.GateConfig{Rules: []entities.GateRule{
+		{Type: values.GateBusFactor, Path: "core/"},
+		{Name: "hotspots", Type: values.GateHotspotGrowth, Top: 2, MaxGrowthPercent: 10},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].Name != "bus_factor" || rules[0].MaxBusFactor != 1 {
+		t.Errorf("expected the rule to be named and limited by the risk policy, got %+v", rules[0])
+	}
+
+	baseline, err := uc.Measure(1, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.path != "core/" || changes.limit != 2 {
+		t.Errorf("measured path %q and %d hotspots, want core/ and 2", repo.path, changes.limit)
+	}
+	if baseline[0].BusFactors["core/a.go"] != 2 || baseline[0].BusFactors["core/b.go"] != 1 {
+		t.Errorf("unexpected bus factors %v", baseline[0].BusFactors)
+	}
+
+	// The analysed commits leave core/a.go to a single owner and grow the hotspots by 25%
+	repo.files[0] = owned("core/a.go", 80, 20)
+	changes.hotspots[0].ChangeCount = 15
+	current, err := uc.Measure(1, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, violations := uc.Evaluate(rules, baseline, current)
+	if violations != 2 {
+		t.Fatalf("expected 2 violations, got %d: %+v", violations, results)
+	}
+	if busFactor := results[0]; busFactor.Passed || busFactor.Limit != 1 || busFactor.Checked != 2 ||
+		len(busFactor.Violations) != 1 || busFactor.Violations[0].Subject != "core/a.go" {
+		t.Errorf("unexpected bus factor result %+v", busFactor)
+	}
+	if hotspots := results[1]; hotspots.Passed || hotspots.Name != "hotspots" || hotspots.Limit != 10 ||
+		len(hotspots.Violations) != 1 || hotspots.Violations[0].Subject != "top 2 hotspots" {
+		t.Errorf("unexpected hotspot result %+v", hotspots)
+	}
+
+	// The first analysis of a project has no baseline and passes
+	if results, violations := uc.Evaluate(rules, nil, current); violations != 0 || !results[0].Passed || !results[1].Passed {
+		t.Errorf("expected rules without a baseline to pass, got %+v", results)
+	}
+}
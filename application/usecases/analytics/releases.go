@@ -0,0 +1,267 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/internal/models"
+)
+
+// ErrReleaseNotFound is returned when a project has no release of the requested name
+var ErrReleaseNotFound = errors.New("release not found")
+
+// ReleaseUseCase lists the releases ingested from repository tags and compares the
+// development periods between them
+type ReleaseUseCase struct {
+	releaseRepo repositories.ReleaseRepository
+	repo        ports.AnalyticsRepository
+}
+
+// NewReleaseUseCase creates a new release use case
+func NewReleaseUseCase(releaseRepo repositories.ReleaseRepository, repo ports.AnalyticsRepository) *ReleaseUseCase {
+	return &ReleaseUseCase{
+		releaseRepo: releaseRepo,
+		repo:        repo,
+	}
+}
+
+// GetReleases lists a project's releases ordered by commit date, each with the release whose
+// commit starts its development period
+func (uc *ReleaseUseCase) GetReleases(projectID int) ([]models.Release, error) {
+	releases, err := uc.releaseRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Release, 0, len(releases))
+	previous := ""
+	for i, release := range releases {
+		if i > 0 && releases[i-1].CommitDate.Before(release.CommitDate) {
+			previous = releases[i-1].Name
+		}
+		r := releaseModel(release)
+		r.PreviousRelease = previous
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// CompareReleases compares the development period of release from with that of release to:
+// per-file commits and churn, per-author commits, and the file pairs whose coupling (at least
+// minSharedCommits shared commits and a score of minCouplingScore) appeared or disappeared.
+// The file, author and coupling lists are each capped at limit when it is positive.
+func (uc *ReleaseUseCase) CompareReleases(projectID int, from, to string, limit, minSharedCommits int, minCouplingScore float64) (*models.ReleaseComparison, error) {
+	if minSharedCommits <= 0 {
+		minSharedCommits = 2
+	}
+
+	releases, err := uc.releaseRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	fromPeriod, fromActivity, err := uc.releaseActivity(projectID, releases, from)
+	if err != nil {
+		return nil, err
+	}
+	toPeriod, toActivity, err := uc.releaseActivity(projectID, releases, to)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &models.ReleaseComparison{
+		ProjectID:        projectID,
+		From:             *fromPeriod,
+		To:               *toPeriod,
+		MinSharedCommits: minSharedCommits,
+		MinCouplingScore: minCouplingScore,
+		Files:            compareFiles(fromActivity, toActivity),
+		Authors:          compareAuthors(fromActivity, toActivity),
+		NewCoupling:      compareCoupling(fromActivity, toActivity, minSharedCommits, minCouplingScore, false),
+		RemovedCoupling:  compareCoupling(toActivity, fromActivity, minSharedCommits, minCouplingScore, true),
+	}
+	if limit > 0 {
+		comparison.Files = comparison.Files[:min(limit, len(comparison.Files))]
+		comparison.Authors = comparison.Authors[:min(limit, len(comparison.Authors))]
+		comparison.NewCoupling = comparison.NewCoupling[:min(limit, len(comparison.NewCoupling))]
+		comparison.RemovedCoupling = comparison.RemovedCoupling[:min(limit, len(comparison.RemovedCoupling))]
+	}
+	return comparison, nil
+}
+
+// releaseActivity loads and summarises the development period of a release
+func (uc *ReleaseUseCase) releaseActivity(projectID int, releases []entities.Release, name string) (*models.ReleasePeriod, *services.PeriodActivity, error) {
+	release, start, ok := services.ReleasePeriod(releases, name)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, name)
+	}
+
+	commits, err := uc.repo.GetPeriodCommits(projectID, start, release.CommitDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	activity := services.SummarizePeriod(commits)
+
+	period := &models.ReleasePeriod{
+		Release:      releaseModel(release),
+		Commits:      activity.Commits,
+		Authors:      len(activity.Authors),
+		FilesChanged: len(activity.Files),
+	}
+	if !start.IsZero() {
+		period.Start = start.Format(time.RFC3339)
+	}
+	for _, file := range activity.Files {
+		period.Churn += file.Churn
+	}
+	return period, activity, nil
+}
+
+// releaseModel converts a release to its API representation
+func releaseModel(release entities.Release) models.Release {
+	return models.Release{
+		Name:       release.Name,
+		CommitHash: release.CommitHash,
+		CommitDate: release.CommitDate.Format(time.RFC3339),
+		ReleasedAt: release.ReleasedAt.Format(time.RFC3339),
+	}
+}
+
+// compareFiles lists every file changed in either period, the files that became hotter first
+func compareFiles(from, to *services.PeriodActivity) []models.FileChurnDelta {
+	paths := make(map[string]bool, len(from.Files)+len(to.Files))
+	for path := range from.Files {
+		paths[path] = true
+	}
+	for path := range to.Files {
+		paths[path] = true
+	}
+
+	deltas := make([]models.FileChurnDelta, 0, len(paths))
+	for path := range paths {
+		delta := models.FileChurnDelta{FilePath: path}
+		if f := from.Files[path]; f != nil {
+			delta.FromCommits, delta.FromChurn = f.Commits, f.Churn
+		}
+		if t := to.Files[path]; t != nil {
+			delta.ToCommits, delta.ToChurn = t.Commits, t.Churn
+		}
+		delta.CommitDelta = delta.ToCommits - delta.FromCommits
+		delta.ChurnDelta = delta.ToChurn - delta.FromChurn
+
+		switch {
+		case delta.FromCommits == 0:
+			delta.Status = "new"
+		case delta.ToCommits == 0:
+			delta.Status = "inactive"
+		case delta.CommitDelta > 0 || (delta.CommitDelta == 0 && delta.ChurnDelta > 0):
+			delta.Status = "hotter"
+		case delta.CommitDelta < 0 || delta.ChurnDelta < 0:
+			delta.Status = "cooler"
+		default:
+			delta.Status = "unchanged"
+		}
+		deltas = append(deltas, delta)
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].CommitDelta != deltas[j].CommitDelta {
+			return deltas[i].CommitDelta > deltas[j].CommitDelta
+		}
+		if deltas[i].ChurnDelta != deltas[j].ChurnDelta {
+			return deltas[i].ChurnDelta > deltas[j].ChurnDelta
+		}
+		return deltas[i].FilePath < deltas[j].FilePath
+	})
+	return deltas
+}
+
+// compareAuthors lists every author active in either period, the most increased first
+func compareAuthors(from, to *services.PeriodActivity) []models.AuthorDelta {
+	authors := make(map[string]bool, len(from.Authors)+len(to.Authors))
+	for author := range from.Authors {
+		authors[author] = true
+	}
+	for author := range to.Authors {
+		authors[author] = true
+	}
+
+	deltas := make([]models.AuthorDelta, 0, len(authors))
+	for author := range authors {
+		delta := models.AuthorDelta{Author: author, FromCommits: from.Authors[author], ToCommits: to.Authors[author]}
+		delta.CommitDelta = delta.ToCommits - delta.FromCommits
+		switch {
+		case delta.FromCommits == 0:
+			delta.Status = "new"
+		case delta.ToCommits == 0:
+			delta.Status = "departed"
+		default:
+			delta.Status = "continuing"
+		}
+		deltas = append(deltas, delta)
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].CommitDelta != deltas[j].CommitDelta {
+			return deltas[i].CommitDelta > deltas[j].CommitDelta
+		}
+		return deltas[i].Author < deltas[j].Author
+	})
+	return deltas
+}
+
+// compareCoupling lists the file pairs coupled in the current period but not in the
+// baseline one, strongest first. reversed reports that current is the earlier period, so
+// the from and to sides of the deltas are swapped.
+func compareCoupling(baseline, current *services.PeriodActivity, minSharedCommits int, minCouplingScore float64, reversed bool) []models.CouplingDelta {
+	coupled := func(activity *services.PeriodActivity, pair services.FilePair) bool {
+		return activity.Pairs[pair] >= minSharedCommits && activity.CouplingScore(pair) >= minCouplingScore
+	}
+
+	deltas := make([]models.CouplingDelta, 0)
+	for pair := range current.Pairs {
+		if !coupled(current, pair) || coupled(baseline, pair) {
+			continue
+		}
+		delta := models.CouplingDelta{
+			FileA:             pair.A,
+			FileB:             pair.B,
+			FromSharedCommits: baseline.Pairs[pair],
+			ToSharedCommits:   current.Pairs[pair],
+			FromScore:         baseline.CouplingScore(pair),
+			ToScore:           current.CouplingScore(pair),
+		}
+		if reversed {
+			delta.FromSharedCommits, delta.ToSharedCommits = delta.ToSharedCommits, delta.FromSharedCommits
+			delta.FromScore, delta.ToScore = delta.ToScore, delta.FromScore
+		}
+		deltas = append(deltas, delta)
+	}
+
+	strength := func(d models.CouplingDelta) (float64, int) {
+		if reversed {
+			return d.FromScore, d.FromSharedCommits
+		}
+		return d.ToScore, d.ToSharedCommits
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		si, ci := strength(deltas[i])
+		sj, cj := strength(deltas[j])
+		if si != sj {
+			return si > sj
+		}
+		if ci != cj {
+			return ci > cj
+		}
+		if deltas[i].FileA != deltas[j].FileA {
+			return deltas[i].FileA < deltas[j].FileA
+		}
+		return deltas[i].FileB < deltas[j].FileB
+	})
+	return deltas
+}
@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+)
+
+type releaseTestReleases struct {
+	repositories.ReleaseRepository
+	releases []entities.Release
+}
+
+func (r *releaseTestReleases) GetByProjectID(projectID int) ([]entities.Release, error) {
+	return r.releases, nil
+}
+
+// releaseTestRepo serves the commits of each period by its start
+type releaseTestRepo struct {
+	ports.AnalyticsRepository
+	periods map[time.Time][]services.PeriodCommit
+}
+
+func (r *releaseTestRepo) GetPeriodCommits(projectID int, start, end time.Time) ([]services.PeriodCommit, error) {
+	return r.periods[start], nil
+}
+
+func TestCompareReleases(t *testing.T) {
+	v1, v2 := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+	releases := &releaseTestReleases{releases: []entities.Release{
+		{Name: "v1.0", CommitHash: "a1", CommitDate: v1, ReleasedAt: v1},
+		{Name: "v1.1", CommitHash: "b2", CommitDate: v2, ReleasedAt: v2},
+	}}
+	change := func(path string, added, deleted int) services.PeriodChange {
+		return services.PeriodChange{FilePath: path, LinesAdded: added, LinesDeleted: deleted}
+	}
+	repo := &releaseTestRepo{periods: map[time.Time][]services.PeriodCommit{
+		// ann works on the parser and lexer together until v1.0
+		{}: {
+			{Author: "ann", Changes: []services.PeriodChange{change("parser.go", 10, 2), change("lexer.go", 5, 1)}},
+			{Author: "ann", Changes: []services.PeriodChange{change("parser.go", 3, 0), change("lexer.go", 2, 2)}},
+		},
+		// bob joins and documents the parser for v1.1
+		v1: {
+			{Author: "bob", Changes: []services.PeriodChange{change("parser.go", 1, 1), change("docs/README.md", 4, 0)}},
+			{Author: "bob", Changes: []services.PeriodChange{change("parser.go", 2, 0), change("docs/README.md", 1, 0)}},
+			{Author: "ann", Changes: []services.PeriodChange{change("parser.go", 6, 0)}},
+		},
+	}}
+	uc := NewReleaseUseCase(releases, repo)
+
+	list, err := uc.GetReleases(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].PreviousRelease != "" || list[1].PreviousRelease != "v1.0" {
+		t.Errorf("expected v1.1 to follow v1.0, got %+v", list)
+	}
+
+	comparison, err := uc.CompareReleases(1, "v1.0", "v1.1", 0, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.From.Commits != 2 || comparison.From.Churn != 25 || comparison.From.Start != "" ||
+		comparison.To.Commits != 3 || comparison.To.Authors != 2 || comparison.To.FilesChanged != 2 || comparison.To.Start != v1.Format(time.RFC3339) {
+		t.Errorf("unexpected periods %+v and %+v", comparison.From, comparison.To)
+	}
+
+	var files []string
+	for _, f := range comparison.Files {
+		files = append(files, f.FilePath+":"+f.Status)
+	}
+	if len(files) != 3 || files[0] != "docs/README.md:new" || files[1] != "parser.go:hotter" || files[2] != "lexer.go:inactive" {
+		t.Errorf("unexpected files %v", files)
+	}
+	if parser := comparison.Files[1]; parser.FromChurn != 15 || parser.ToChurn != 10 || parser.ChurnDelta != -5 {
+		t.Errorf("unexpected parser.go churn %+v", parser)
+	}
+
+	authors := comparison.Authors
+	if len(authors) != 2 || authors[0].Author != "bob" || authors[0].Status != "new" || authors[1].Author != "ann" || authors[1].CommitDelta != -1 {
+		t.Errorf("unexpected authors %+v", authors)
+	}
+
+	if added := comparison.NewCoupling; len(added) != 1 || added[0].FileA != "docs/README.md" || added[0].FileB != "parser.go" ||
+		added[0].FromSharedCommits != 0 || added[0].ToSharedCommits != 2 || added[0].ToScore != 1 {
+		t.Errorf("unexpected new coupling %+v", added)
+	}
+	if removed := comparison.RemovedCoupling; len(removed) != 1 || removed[0].FileA != "lexer.go" ||
+		removed[0].FromSharedCommits != 2 || removed[0].ToSharedCommits != 0 {
+		t.Errorf("unexpected removed coupling %+v", removed)
+	}
+
+	if _, err := uc.CompareReleases(1, "v1.0", "v2.0", 0, 2, 0); !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected an unknown release to be missing, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// Reasons an author with expertise is not recommended as reviewer
+const (
+	ReviewerExcludedAuthor   = "author"
+	ReviewerExcludedManually = "excluded"
+	ReviewerExcludedInactive = "inactive"
+)
+
+// ReviewerRequest describes the change to recommend reviewers for
+type ReviewerRequest struct {
+	// Files are file paths; paths ending in "/" stand for every file below them
+	Files []string
+	// Author is the change's author, who cannot review it
+	Author string
+	// Exclude are further authors who must not be recommended
+	Exclude             []string
+	InactiveAfterMonths int
+	Options             services.ReviewerOptions
+}
+
+// ReviewerUseCase recommends reviewers for a set of files from the project's file ownership
+type ReviewerUseCase struct {
+	repo          ports.AnalyticsRepository
+	knowledgeLoss *KnowledgeLossUseCase
+}
+
+// NewReviewerUseCase creates a new reviewer recommendation use case
+func NewReviewerUseCase(repo ports.AnalyticsRepository, statusRepo repositories.AuthorStatusRepository) *ReviewerUseCase {
+	return &ReviewerUseCase{
+		repo:          repo,
+		knowledgeLoss: NewKnowledgeLossUseCase(repo, statusRepo),
+	}
+}
+
+// Recommend ranks the authors who own the requested files as reviewers. Ownership is weighted
+// by metric and decays with the time since each author last touched a file. The change's
+// author, the excluded authors and inactive authors are left out; the remaining candidates
+// are picked with services.RecommendReviewers.
+func (uc *ReviewerUseCase) Recommend(projectID int, req ReviewerRequest, metric values.OwnershipMetric, now time.Time) (*models.ReviewerRecommendation, error) {
+	if req.InactiveAfterMonths <= 0 {
+		req.InactiveAfterMonths = DefaultInactiveAfterMonths
+	}
+	if req.Options.HalfLifeDays <= 0 {
+		req.Options.HalfLifeDays = services.DefaultExpertiseHalfLifeDays
+	}
+
+	ownership, err := uc.repo.GetFileOwnership(projectID, metric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ownership: %w", err)
+	}
+	authors, err := uc.knowledgeLoss.GetAuthorActivity(projectID, req.InactiveAfterMonths, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Authors are matched case-insensitively, as identities are typed by hand
+	reasons := make(map[string]string)
+	for _, a := range authors {
+		if !a.Active {
+			reasons[strings.ToLower(a.Author)] = ReviewerExcludedInactive
+		}
+	}
+	for _, author := range req.Exclude {
+		reasons[strings.ToLower(author)] = ReviewerExcludedManually
+	}
+	if req.Author != "" {
+		reasons[strings.ToLower(req.Author)] = ReviewerExcludedAuthor
+	}
+
+	recommendation := &models.ReviewerRecommendation{
+		ProjectID:      projectID,
+		Files:          req.Files,
+		HalfLifeDays:   req.Options.HalfLifeDays,
+		Candidates:     []models.ReviewerCandidate{},
+		Excluded:       []models.ExcludedReviewer{},
+		UncoveredFiles: []string{},
+	}
+
+	var expertise []services.FileExpertise
+	excluded := make(map[string]bool)
+	covered := make(map[string]bool)
+	for _, file := range ownership {
+		requested := matchRequestedPath(file.FilePath, req.Files)
+		if requested == "" {
+			continue
+		}
+		for _, contrib := range file.Contributors {
+			if reason, ok := reasons[strings.ToLower(contrib.Author)]; ok {
+				if !excluded[contrib.Author] {
+					excluded[contrib.Author] = true
+					recommendation.Excluded = append(recommendation.Excluded, models.ExcludedReviewer{Author: contrib.Author, Reason: reason})
+				}
+				continue
+			}
+			lastTouched, _ := time.Parse(time.RFC3339Nano, contrib.LastModified)
+			expertise = append(expertise, services.FileExpertise{
+				FilePath:    file.FilePath,
+				Author:      contrib.Author,
+				Share:       contrib.Percentage,
+				LastTouched: lastTouched,
+			})
+			covered[requested] = true
+		}
+	}
+	for _, path := range req.Files {
+		if !covered[path] {
+			recommendation.UncoveredFiles = append(recommendation.UncoveredFiles, path)
+		}
+	}
+	sort.Slice(recommendation.Excluded, func(i, j int) bool {
+		return recommendation.Excluded[i].Author < recommendation.Excluded[j].Author
+	})
+
+	for _, c := range services.RecommendReviewers(expertise, now, req.Options) {
+		candidate := models.ReviewerCandidate{
+			Author:    c.Author,
+			Selected:  c.Selected,
+			Expertise: c.Expertise,
+			Score:     c.Score,
+			Load:      c.Load,
+			Files:     c.Files,
+		}
+		if !c.LastTouched.IsZero() {
+			lastTouched := c.LastTouched
+			candidate.LastTouched = &lastTouched
+		}
+		recommendation.Candidates = append(recommendation.Candidates, candidate)
+	}
+	return recommendation, nil
+}
+
+// matchRequestedPath returns the requested path that filePath matches, either exactly or as a
+// directory ending in "/", or "" when it matches none
+func matchRequestedPath(filePath string, requested []string) string {
+	for _, path := range requested {
+		if filePath == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(filePath, path)) {
+			return path
+		}
+	}
+	return ""
+}
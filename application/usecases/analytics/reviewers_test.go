@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+type reviewerTestRepo struct {
+	ports.AnalyticsRepository
+	ownership []models.FileOwnership
+	activity  []models.AuthorActivity
+}
+
+func (r *reviewerTestRepo) GetFileOwnership(projectID int, metric values.OwnershipMetric) ([]models.FileOwnership, error) {
+	return r.ownership, nil
+}
+
+func (r *reviewerTestRepo) GetAuthorActivity(projectID int) ([]models.AuthorActivity, error) {
+	return r.activity, nil
+}
+
+type reviewerTestStatuses struct {
+	repositories.AuthorStatusRepository
+}
+
+func (r *reviewerTestStatuses) GetByProjectID(projectID int) ([]*entities.AuthorStatus, error) {
+	return nil, nil
+}
+
+func TestRecommendReviewers(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent, old := now.AddDate(0, -1, 0), now.AddDate(-1, 0, 0)
+	owner := func(author string, percent float64) models.AuthorContribution {
+		return models.AuthorContribution{Author: author, Percentage: percent, LastModified: recent.Format(time.RFC3339Nano)}
+	}
+	repo := &reviewerTestRepo{
+		ownership: []models.FileOwnership{
+			{FilePath: "core/a.go", Contributors: []models.AuthorContribution{owner("ann", 60), owner("bob", 40)}},
+			{FilePath: "core/b.go", Contributors: []models.AuthorContribution{owner("cy", 100)}},
+			{FilePath: "docs/guide.md", Contributors: []models.AuthorContribution{owner("dan", 100)}},
+			{FilePath: "other/x.go", Contributors: []models.AuthorContribution{owner("eve", 100)}},
+		},
+		// cy has not committed for a year
+		activity: []models.AuthorActivity{
+			{Author: "ann", LastCommit: &recent},
+			{Author: "bob", LastCommit: &recent},
+			{Author: "cy", LastCommit: &old},
+			{Author: "dan", LastCommit: &recent},
+			{Author: "eve", LastCommit: &recent},
+		},
+	}
+	uc := NewReviewerUseCase(repo, &reviewerTestStatuses{})
+
+	recommendation, err := uc.Recommend(1, ReviewerRequest{
+		Files:   []string{"core/", "docs/guide.md", "web/app.js"},
+		Author:  "Bob",
+		Exclude: []string{"dan"},
+	}, values.OwnershipByCommits, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recommendation.Candidates) != 1 || recommendation.Candidates[0].Author != "ann" || !recommendation.Candidates[0].Selected ||
+		!slices.Equal(recommendation.Candidates[0].Files, []string{"core/a.go"}) {
+		t.Errorf("expected ann as the only reviewer of core/a.go, got %+v", recommendation.Candidates)
+	}
+	if recommendation.HalfLifeDays != 180 {
+		t.Errorf("expected the default half-life, got %d", recommendation.HalfLifeDays)
+	}
+
+	want := []models.ExcludedReviewer{
+		{Author: "bob", Reason: ReviewerExcludedAuthor},
+		{Author: "cy", Reason: ReviewerExcludedInactive},
+		{Author: "dan", Reason: ReviewerExcludedManually},
+	}
+	if !slices.Equal(recommendation.Excluded, want) {
+		t.Errorf("got excluded %+v, want %+v", recommendation.Excluded, want)
+	}
+	if !slices.Equal(recommendation.UncoveredFiles, []string{"docs/guide.md", "web/app.js"}) {
+		t.Errorf("unexpected uncovered files %v", recommendation.UncoveredFiles)
+	}
+}
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+)
+
+// RiskPolicyUseCase manages the versioned risk policies that rate a project's metrics
+type RiskPolicyUseCase struct {
+	policyRepo repositories.RiskPolicyRepository
+}
+
+// NewRiskPolicyUseCase creates a new risk policy use case
+func NewRiskPolicyUseCase(policyRepo repositories.RiskPolicyRepository) *RiskPolicyUseCase {
+	return &RiskPolicyUseCase{policyRepo: policyRepo}
+}
+
+// GetPolicy returns the policy applied to a project and whether it is the default
+func (uc *RiskPolicyUseCase) GetPolicy(projectID int) (entities.RiskPolicy, bool, error) {
+	stored, err := uc.policyRepo.GetCurrent(projectID)
+	if err != nil {
+		return entities.RiskPolicy{}, false, err
+	}
+	policy := services.EffectiveRiskPolicy(stored)
+	policy.ProjectID = projectID
+	return policy, stored == nil, nil
+}
+
+// UpdatePolicy validates a project's policy and stores it as its next version. Health snapshots
+// keep the version they were computed under until they are rebuilt.
+func (uc *RiskPolicyUseCase) UpdatePolicy(projectID int, policy entities.RiskPolicy) (entities.RiskPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return entities.RiskPolicy{}, err
+	}
+	policy.ProjectID = projectID
+	if err := uc.policyRepo.Create(&policy); err != nil {
+		return entities.RiskPolicy{}, err
+	}
+	return policy, nil
+}
+
+// GetVersions returns every stored version of a project's policy, newest first
+func (uc *RiskPolicyUseCase) GetVersions(projectID int) ([]entities.RiskPolicy, error) {
+	return uc.policyRepo.GetVersions(projectID)
+}
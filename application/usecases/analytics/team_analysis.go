@@ -0,0 +1,225 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"codeecho/application/ports"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// UnassignedTeam groups authors that are not a member of any team
+const UnassignedTeam = "Unassigned"
+
+// TeamAnalysisUseCase reports ownership and temporal coupling at team level
+type TeamAnalysisUseCase struct {
+	repo     ports.AnalyticsRepository
+	teamRepo repositories.TeamRepository
+}
+
+// NewTeamAnalysisUseCase creates a new team analysis use case
+func NewTeamAnalysisUseCase(repo ports.AnalyticsRepository, teamRepo repositories.TeamRepository) *TeamAnalysisUseCase {
+	return &TeamAnalysisUseCase{
+		repo:     repo,
+		teamRepo: teamRepo,
+	}
+}
+
+// GetTeamOwnership aggregates file ownership by team and rolls it up to directories at the given depth
+func (uc *TeamAnalysisUseCase) GetTeamOwnership(projectID int, metric values.OwnershipMetric, depth int) (*models.TeamOwnershipReport, error) {
+	teamOf, err := uc.teamIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	ownership, err := uc.repo.GetFileOwnership(projectID, metric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file ownership: %w", err)
+	}
+
+	report := &models.TeamOwnershipReport{
+		ProjectID:   projectID,
+		Ownership:   string(metric),
+		Files:       make([]models.FileTeamOwnership, 0, len(ownership)),
+		Directories: make([]models.DirectoryTeamOwnership, 0),
+	}
+
+	type directoryShares struct {
+		files  int
+		shares map[string]float64
+	}
+	directories := make(map[string]*directoryShares)
+
+	for _, fo := range ownership {
+		shares := make(map[string]*models.TeamShare)
+		for _, contrib := range fo.Contributors {
+			team := teamOf(contrib.Author)
+			if shares[team] == nil {
+				shares[team] = &models.TeamShare{Team: team}
+			}
+			shares[team].Percentage += contrib.Percentage
+			shares[team].Authors = append(shares[team].Authors, contrib.Author)
+		}
+
+		file := models.FileTeamOwnership{FilePath: fo.FilePath, Teams: sortedTeamShares(shares)}
+		if len(file.Teams) > 0 {
+			file.PrimaryTeam = file.Teams[0].Team
+			file.PrimaryTeamPercentage = file.Teams[0].Percentage
+		}
+		report.Files = append(report.Files, file)
+
+		dir := directoryAtDepth(fo.FilePath, depth)
+		if directories[dir] == nil {
+			directories[dir] = &directoryShares{shares: make(map[string]float64)}
+		}
+		directories[dir].files++
+		for _, share := range file.Teams {
+			directories[dir].shares[share.Team] += share.Percentage
+		}
+	}
+
+	for path, dir := range directories {
+		shares := make(map[string]*models.TeamShare, len(dir.shares))
+		for team, total := range dir.shares {
+			shares[team] = &models.TeamShare{Team: team, Percentage: total / float64(dir.files)}
+		}
+
+		rollup := models.DirectoryTeamOwnership{Path: path, Files: dir.files, Teams: sortedTeamShares(shares)}
+		if len(rollup.Teams) > 0 {
+			rollup.PrimaryTeam = rollup.Teams[0].Team
+			rollup.PrimaryTeamPercentage = rollup.Teams[0].Percentage
+		}
+		report.Directories = append(report.Directories, rollup)
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].FilePath < report.Files[j].FilePath })
+	sort.Slice(report.Directories, func(i, j int) bool { return report.Directories[i].Path < report.Directories[j].Path })
+
+	return report, nil
+}
+
+// GetTeamCoupling annotates temporally coupled file pairs with the primary team of each
+// file and aggregates them per team pair, exposing coordination needs across team boundaries.
+// The totals and team pairs cover every coupled file pair; the listed pairs are the limit
+// strongest (100 when limit is not between 1 and 200).
+func (uc *TeamAnalysisUseCase) GetTeamCoupling(projectID int, metric values.OwnershipMetric, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) (*models.TeamCouplingReport, error) {
+	ownership, err := uc.GetTeamOwnership(projectID, metric, 1)
+	if err != nil {
+		return nil, err
+	}
+	primaryTeam := make(map[string]string, len(ownership.Files))
+	for _, file := range ownership.Files {
+		primaryTeam[file.FilePath] = file.PrimaryTeam
+	}
+	teamOfFile := func(filePath string) string {
+		if team, exists := primaryTeam[filePath]; exists && team != "" {
+			return team
+		}
+		return UnassignedTeam
+	}
+
+	// Pairs come strongest first, as from GetTemporalCoupling
+	pairs, err := uc.repo.GetCouplingGraph(projectID, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get temporal coupling: %w", err)
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 100
+	}
+
+	report := &models.TeamCouplingReport{
+		ProjectID:  projectID,
+		Ownership:  string(metric),
+		TotalPairs: len(pairs),
+		Teams:      make([]models.TeamCouplingEdge, 0),
+		Pairs:      make([]models.TeamCouplingPair, 0, min(limit, len(pairs))),
+	}
+
+	edges := make(map[[2]string]*models.TeamCouplingEdge)
+	for _, pair := range pairs {
+		teamA, teamB := teamOfFile(pair.FileA), teamOfFile(pair.FileB)
+		annotated := models.TeamCouplingPair{
+			TemporalCoupling: pair,
+			TeamA:            teamA,
+			TeamB:            teamB,
+			CrossTeam:        teamA != teamB,
+		}
+		if len(report.Pairs) < limit {
+			report.Pairs = append(report.Pairs, annotated)
+		}
+		if annotated.CrossTeam {
+			report.CrossTeamPairs++
+		}
+
+		// Team pairs are undirected
+		if teamB < teamA {
+			teamA, teamB = teamB, teamA
+		}
+		key := [2]string{teamA, teamB}
+		if edges[key] == nil {
+			edges[key] = &models.TeamCouplingEdge{TeamA: teamA, TeamB: teamB}
+		}
+		edges[key].Pairs++
+		edges[key].SharedCommits += pair.SharedCommits
+	}
+
+	if report.TotalPairs > 0 {
+		report.CrossTeamPercent = float64(report.CrossTeamPairs) / float64(report.TotalPairs) * 100
+	}
+
+	for _, edge := range edges {
+		report.Teams = append(report.Teams, *edge)
+	}
+	sort.Slice(report.Teams, func(i, j int) bool {
+		if report.Teams[i].Pairs != report.Teams[j].Pairs {
+			return report.Teams[i].Pairs > report.Teams[j].Pairs
+		}
+		return report.Teams[i].TeamA+report.Teams[i].TeamB < report.Teams[j].TeamA+report.Teams[j].TeamB
+	})
+
+	return report, nil
+}
+
+// teamIndex returns a lookup from author identity to team name
+func (uc *TeamAnalysisUseCase) teamIndex() (func(author string) string, error) {
+	teams, err := uc.teamRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	teamByIdentity := make(map[string]string)
+	for _, team := range teams {
+		for _, member := range team.Members {
+			for _, identity := range member.Identities {
+				if _, exists := teamByIdentity[identity]; !exists {
+					teamByIdentity[identity] = team.Name
+				}
+			}
+		}
+	}
+
+	return func(author string) string {
+		if team, exists := teamByIdentity[author]; exists {
+			return team
+		}
+		return UnassignedTeam
+	}, nil
+}
+
+// sortedTeamShares orders team shares by percentage, largest first
+func sortedTeamShares(shares map[string]*models.TeamShare) []models.TeamShare {
+	sorted := make([]models.TeamShare, 0, len(shares))
+	for _, share := range shares {
+		sort.Strings(share.Authors)
+		sorted = append(sorted, *share)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Percentage != sorted[j].Percentage {
+			return sorted[i].Percentage > sorted[j].Percentage
+		}
+		return sorted[i].Team < sorted[j].Team
+	})
+	return sorted
+}
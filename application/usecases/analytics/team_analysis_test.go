@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"fmt"
+	"testing"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/internal/models"
+)
+
+// teamTestRepo serves fixed ownership and coupling; other queries are not used
+type teamTestRepo struct {
+	ports.AnalyticsRepository
+	ownership []models.FileOwnership
+	pairs     []models.TemporalCoupling
+}
+
+func (r *teamTestRepo) GetFileOwnership(projectID int, metric values.OwnershipMetric) ([]models.FileOwnership, error) {
+	return r.ownership, nil
+}
+
+func (r *teamTestRepo) GetCouplingGraph(projectID int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error) {
+	return r.pairs, nil
+}
+
+type teamTestTeams struct {
+	repositories.TeamRepository
+	teams []*entities.Team
+}
+
+func (r *teamTestTeams) GetAll() ([]*entities.Team, error) {
+	return r.teams, nil
+}
+
+func TestGetTeamCouplingCoversEveryPair(t *testing.T) {
+	// 300 api files owned by ann's team, each coupled with a core file owned by bob's team,
+	// and one pair within the core team
+	repo := &teamTestRepo{}
+	for _, file := range []struct{ path, author string }{{"core/a.go", "bob"}, {"core/b.go", "bob"}} {
+		repo.ownership = append(repo.ownership, models.FileOwnership{
+			FilePath:     file.path,
+			Contributors: []models.AuthorContribution{{Author: file.author, Percentage: 100}},
+		})
+	}
+	repo.pairs = append(repo.pairs, models.TemporalCoupling{FileA: "core/a.go", FileB: "core/b.go", SharedCommits: 9, CouplingScore: 1})
+	for i := 0; i < 300; i++ {
+		path := fmt.Sprintf("api/h%03d.go", i)
+		repo.ownership = append(repo.ownership, models.FileOwnership{
+			FilePath:     path,
+			Contributors: []models.AuthorContribution{{Author: "ann", Percentage: 100}},
+		})
+		repo.pairs = append(repo.pairs, models.TemporalCoupling{FileA: path, FileB: "core/a.go", SharedCommits: 2, CouplingScore: 0.5})
+	}
+	teams := &teamTestTeams{teams: []*entities.Team{
+		{Name: "api", Members: []entities.TeamMember{{Name: "Ann", Identities: []string{"ann"}}}},
+		{Name: "core", Members: []entities.TeamMember{{Name: "Bob", Identities: []string{"bob"}}}},
+	}}
+
+	report, err := NewTeamAnalysisUseCase(repo, teams).GetTeamCoupling(1, values.OwnershipByLinesChanged, 10, "", "", 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.TotalPairs != 301 || report.CrossTeamPairs != 300 || len(report.Pairs) != 10 {
+		t.Errorf("expected 301 pairs, 300 across teams and 10 listed, got %d, %d and %d", report.TotalPairs, report.CrossTeamPairs, len(report.Pairs))
+	}
+	if report.Pairs[0].FileA != "core/a.go" || report.Pairs[0].CrossTeam {
+		t.Errorf("expected the strongest pair first, got %+v", report.Pairs[0])
+	}
+	if len(report.Teams) != 2 ||
+		report.Teams[0] != (models.TeamCouplingEdge{TeamA: "api", TeamB: "core", Pairs: 300, SharedCommits: 600}) ||
+		report.Teams[1] != (models.TeamCouplingEdge{TeamA: "core", TeamB: "core", Pairs: 1, SharedCommits: 9}) {
+		t.Errorf("unexpected team pairs %+v", report.Teams)
+	}
+}
@@ -0,0 +1,103 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"codeecho/domain/services"
+	"codeecho/internal/models"
+)
+
+// GetTruckFactor computes the project truck factor with the greedy algorithm of Avelino et al.
+// over degree-of-authorship data, along with the same measure for every directory at the
+// given depth. coverage is the share (0-1) of files that must keep an author; pathPrefix
+// optionally restricts the analysis to a subdirectory.
+func (uc *AnalyticsUseCase) GetTruckFactor(projectID int, pathPrefix string, depth int, coverage float64) (*models.TruckFactorReport, error) {
+	authorship, err := uc.repo.GetFileAuthorship(projectID, pathPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]services.FileAuthorship, 0, len(authorship))
+	for _, fa := range authorship {
+		records = append(records, services.FileAuthorship{
+			FilePath: fa.FilePath,
+			Author:   fa.Author,
+			Commits:  fa.Commits,
+			Creator:  fa.Creator,
+		})
+	}
+	fileAuthors := services.FileAuthors(records)
+
+	project := services.ComputeTruckFactor(fileAuthors, coverage)
+	report := &models.TruckFactorReport{
+		ProjectID:       projectID,
+		Path:            pathPrefix,
+		Coverage:        coverage,
+		TruckFactor:     project.TruckFactor,
+		TotalFiles:      project.TotalFiles,
+		InitialCoverage: project.InitialCoverage,
+		KeyDevelopers:   make([]models.KeyDeveloper, 0, len(project.KeyDevelopers)),
+		CoverageCurve:   []models.CoveragePoint{{Removed: 0, Coverage: project.InitialCoverage}},
+		Directories:     make([]models.DirectoryTruckFactor, 0),
+	}
+	for i, dev := range project.KeyDevelopers {
+		report.KeyDevelopers = append(report.KeyDevelopers, models.KeyDeveloper{
+			Author:        dev.Author,
+			FilesAuthored: dev.FilesAuthored,
+			CoverageAfter: dev.CoverageAfter,
+		})
+		report.CoverageCurve = append(report.CoverageCurve, models.CoveragePoint{Removed: i + 1, Coverage: dev.CoverageAfter})
+	}
+
+	byDirectory := make(map[string]map[string][]string)
+	for file, authors := range fileAuthors {
+		dir := directoryAtDepth(file, depth)
+		if byDirectory[dir] == nil {
+			byDirectory[dir] = make(map[string][]string)
+		}
+		byDirectory[dir][file] = authors
+	}
+
+	for dir, files := range byDirectory {
+		result := services.ComputeTruckFactor(files, coverage)
+		keyDevelopers := make([]string, 0, len(result.KeyDevelopers))
+		for _, dev := range result.KeyDevelopers {
+			keyDevelopers = append(keyDevelopers, dev.Author)
+		}
+		report.Directories = append(report.Directories, models.DirectoryTruckFactor{
+			Path:          dir,
+			TruckFactor:   result.TruckFactor,
+			TotalFiles:    result.TotalFiles,
+			KeyDevelopers: keyDevelopers,
+		})
+	}
+
+	// Most fragile directories first
+	sort.Slice(report.Directories, func(i, j int) bool {
+		a, b := report.Directories[i], report.Directories[j]
+		if a.TruckFactor != b.TruckFactor {
+			return a.TruckFactor < b.TruckFactor
+		}
+		if a.TotalFiles != b.TotalFiles {
+			return a.TotalFiles > b.TotalFiles
+		}
+		return a.Path < b.Path
+	})
+
+	return report, nil
+}
+
+// directoryAtDepth returns the first depth directory components of a file path,
+// or "." for files at the repository root
+func directoryAtDepth(filePath string, depth int) string {
+	parts := strings.Split(filePath, "/")
+	dirs := parts[:len(parts)-1]
+	if len(dirs) == 0 {
+		return "."
+	}
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/")
+}
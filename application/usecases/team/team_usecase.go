@@ -0,0 +1,108 @@
+package team
+
+import (
+	"fmt"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+// TeamUseCase handles team management business logic
+type TeamUseCase struct {
+	teamRepo repositories.TeamRepository
+}
+
+// NewTeamUseCase creates a new team use case
+func NewTeamUseCase(teamRepo repositories.TeamRepository) *TeamUseCase {
+	return &TeamUseCase{
+		teamRepo: teamRepo,
+	}
+}
+
+// CreateTeam creates a new team with its members
+func (uc *TeamUseCase) CreateTeam(name, description string, members []entities.TeamMember) (*entities.Team, error) {
+	team := entities.NewTeam(name, description, members)
+	if err := team.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureIdentitiesUnassigned(team); err != nil {
+		return nil, err
+	}
+
+	if err := uc.teamRepo.Create(team); err != nil {
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+	return team, nil
+}
+
+// GetAllTeams retrieves all teams
+func (uc *TeamUseCase) GetAllTeams() ([]*entities.Team, error) {
+	teams, err := uc.teamRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	return teams, nil
+}
+
+// GetTeamByID retrieves a team by its ID
+func (uc *TeamUseCase) GetTeamByID(id int) (*entities.Team, error) {
+	return uc.teamRepo.GetByID(id)
+}
+
+// UpdateTeam replaces a team's details and members
+func (uc *TeamUseCase) UpdateTeam(id int, name, description string, members []entities.TeamMember) (*entities.Team, error) {
+	team, err := uc.teamRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	team.Name = name
+	team.Description = description
+	team.Members = members
+	if err := team.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureIdentitiesUnassigned(team); err != nil {
+		return nil, err
+	}
+
+	if err := uc.teamRepo.Update(team); err != nil {
+		return nil, fmt.Errorf("failed to update team: %w", err)
+	}
+	return team, nil
+}
+
+// DeleteTeam removes a team
+func (uc *TeamUseCase) DeleteTeam(id int) error {
+	return uc.teamRepo.Delete(id)
+}
+
+// ensureIdentitiesUnassigned rejects identities that already belong to another team,
+// so every author maps to at most one team
+func (uc *TeamUseCase) ensureIdentitiesUnassigned(team *entities.Team) error {
+	teams, err := uc.teamRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	owner := make(map[string]string)
+	for _, other := range teams {
+		if other.ID == team.ID {
+			continue
+		}
+		for _, member := range other.Members {
+			for _, identity := range member.Identities {
+				owner[identity] = other.Name
+			}
+		}
+	}
+
+	for _, member := range team.Members {
+		for _, identity := range member.Identities {
+			if otherTeam, taken := owner[identity]; taken {
+				return fmt.Errorf("author identity '%s' already belongs to team '%s'", identity, otherTeam)
+			}
+		}
+	}
+	return nil
+}
@@ -235,7 +235,7 @@ const HotspotTreemap = () => {
         level = folder.children;
       }
       const fileName = parts[parts.length - 1];
-      const riskScore = h.risk_level === 'High' ? 0.8 : h.risk_level === 'Medium' ? 0.5 : 0.2;
+      const riskScore = h.risk_level === 'Critical' ? 0.95 : h.risk_level === 'High' ? 0.8 : h.risk_level === 'Medium' ? 0.5 : 0.2;
       level.push({
         name: fileName,
         riskScore,
@@ -135,8 +135,8 @@ const KnowledgeOwnership = () => {
       setLoadingFileTypes(true);
       try {
         const response = await api.getProjectFileTypes(projectId);
-        // The API returns { project_id, file_types: [...] }
-        const fileTypesArray = Array.isArray(response?.file_types) ? response.file_types : [];
+        // The API returns { project_id, file_types: [{ extension, files, loc }] }
+        const fileTypesArray = Array.isArray(response?.file_types) ? response.file_types.map(ft => ft.extension) : [];
         setProjectFileTypes(fileTypesArray);
       } catch (e) {
         console.error('Failed to load file types:', e);
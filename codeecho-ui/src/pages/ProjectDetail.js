@@ -206,7 +206,7 @@ const ProjectDetail = () => {
                     </td>
                     <td className="px-6 py-4 whitespace-nowrap text-sm text-gray-900">
                       <span className={`inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium ${
-                        hotspot.riskLevel === 'High' ? 'bg-red-100 text-red-800' :
+                        (hotspot.riskLevel === 'High' || hotspot.riskLevel === 'Critical') ? 'bg-red-100 text-red-800' :
                         hotspot.riskLevel === 'Medium' ? 'bg-yellow-100 text-yellow-800' :
                         'bg-green-100 text-green-800'
                       }`}>
@@ -114,7 +114,7 @@ const TemporalCoupling = () => {
       try {
         setLoadingFileTypes(true);
         const data = await api.getProjectFileTypes(projectId);
-        setProjectFileTypes(data?.file_types || []);
+        setProjectFileTypes((data?.file_types || []).map(ft => ft.extension));
       } catch (e) {
         console.error('Failed to load file types:', e);
         setProjectFileTypes([]);
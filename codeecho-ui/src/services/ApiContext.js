@@ -418,7 +418,7 @@ export const ApiProvider = ({ children }) => {
     async getProjectFileTypes(projectId) {
       try {
         const response = await api.get(`/projects/${projectId}/file-types`);
-        return response.data; // { project_id, file_types: [...] }
+        return response.data; // { project_id, file_types: [{ extension, files, loc }] }
       } catch (error) {
         dispatch({ type: 'SET_ERROR', payload: error.message });
         throw error;
@@ -4,7 +4,7 @@
 USE codeecho_db;
 
 -- Default admin user (email: admin@codeecho.com, password: admin123)
--- This user is already created in schema.sql, this ensures it exists
+-- This user is already created by the initial schema migration, this ensures it exists
 INSERT INTO users (email, password_hash, first_name, last_name, role, is_active) 
 VALUES (
     'admin@codeecho.com', 
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// AuthorStatus is a manual override of whether an author is still active in a project.
+// Authors without an override are classified automatically from their last commit.
+type AuthorStatus struct {
+	ProjectID int
+	Author    string
+	Active    bool
+	Note      string
+	UpdatedAt time.Time
+}
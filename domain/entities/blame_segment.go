@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// BlameSegment records how many lines of a file at the analysed commit
+// originate from a single earlier commit
+type BlameSegment struct {
+	ProjectID   int
+	FilePath    string
+	CommitHash  string
+	Author      string
+	CommittedAt time.Time
+	Lines       int
+}
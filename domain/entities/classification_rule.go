@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"codeecho/domain/values"
+)
+
+// ClassificationRule assigns a category to commits whose message contains one of its
+// keywords (whole words, case-insensitive) or matches its regular expression
+type ClassificationRule struct {
+	ID        int
+	ProjectID int
+	Category  values.CommitCategory
+	Keywords  []string
+	Pattern   string
+}
+
+// Validate checks that the rule has a known category, at least one matcher, keywords
+// that can be stored and a pattern that compiles
+func (r *ClassificationRule) Validate() error {
+	if r.Category == "" {
+		return fmt.Errorf("classification rule category is required")
+	}
+	if _, err := values.ParseCommitCategory(string(r.Category), ""); err != nil {
+		return err
+	}
+
+	keywords := make([]string, 0, len(r.Keywords))
+	for _, keyword := range r.Keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" {
+			continue
+		}
+		if strings.Contains(keyword, ",") {
+			return fmt.Errorf("classification rule keyword %q must not contain a comma", keyword)
+		}
+		keywords = append(keywords, keyword)
+	}
+	r.Keywords = keywords
+
+	if len(r.Keywords) == 0 && r.Pattern == "" {
+		return fmt.Errorf("classification rule for %s requires keywords or a pattern", r.Category)
+	}
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid classification rule pattern %q: %w", r.Pattern, err)
+		}
+	}
+	return nil
+}
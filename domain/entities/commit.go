@@ -13,7 +13,10 @@ type Commit struct {
 	Author    string
 	Timestamp time.Time
 	Message   string
+	Category  values.CommitCategory
 	CreatedAt time.Time
+	// IssueKeys are the issue keys the message references, linked when the commit is stored
+	IssueKeys []string
 }
 
 // NewCommit creates a new commit entity
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// ComplexitySample captures the size and complexity of a file at a specific commit
+type ComplexitySample struct {
+	ID              int
+	ProjectID       int
+	CommitID        int
+	CommitHash      string
+	FilePath        string
+	Timestamp       time.Time
+	LinesOfCode     int
+	TotalComplexity int
+	MeanComplexity  float64
+	MaxComplexity   int
+}
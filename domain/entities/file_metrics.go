@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// FileMetrics captures the measured size of a file in the tree at the analysed commit
+type FileMetrics struct {
+	ProjectID    int
+	CommitHash   string
+	FilePath     string
+	Language     string
+	CodeLines    int
+	CommentLines int
+	BlankLines   int
+	IsBinary     bool
+	MeasuredAt   time.Time
+}
+
+// TotalLines returns the total number of lines in the file
+func (fm *FileMetrics) TotalLines() int {
+	return fm.CodeLines + fm.CommentLines + fm.BlankLines
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// HealthSnapshot is the debt score of a project at the end of a calendar month, measured on
+// the tree of the month's last commit with the debt weights of a risk policy version
+type HealthSnapshot struct {
+	ID                int
+	ProjectID         int
+	Month             time.Time
+	CommitHash        string
+	Score             float64
+	HotspotComplexity float64
+	Coupling          float64
+	BusFactorRisk     float64
+	StaleCode         float64
+	Files             int
+	ActiveFiles       int
+	PolicyVersion     int
+	ComputedAt        time.Time
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Issue is the metadata of an issue tracker entry referenced by commit messages, imported so
+// that analyses can use it without a live tracker
+type Issue struct {
+	ID         int
+	ProjectID  int
+	Key        string
+	Title      string
+	Type       string
+	Priority   string
+	Status     string
+	ImportedAt time.Time
+}
+
+// Validate checks that the issue has a key and that its fields fit their columns. The key is
+// upper-cased, as keys extracted from commit messages are, and long titles are truncated.
+func (i *Issue) Validate() error {
+	i.Key = strings.ToUpper(strings.TrimSpace(i.Key))
+	if i.Key == "" {
+		return fmt.Errorf("issue key is required")
+	}
+	if len(i.Key) > 100 {
+		return fmt.Errorf("issue key %q is longer than 100 characters", i.Key)
+	}
+	for name, value := range map[string]string{"type": i.Type, "priority": i.Priority, "status": i.Status} {
+		if len(value) > 50 {
+			return fmt.Errorf("issue %s %s is longer than 50 characters", i.Key, name)
+		}
+	}
+	if title := []rune(i.Title); len(title) > 500 {
+		i.Title = string(title[:500])
+	}
+	return nil
+}
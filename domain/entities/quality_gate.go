@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"fmt"
+
+	"codeecho/domain/values"
+)
+
+// GateConfig is the set of rules a quality gate evaluates, as read from its configuration file
+type GateConfig struct {
+	Rules []GateRule `json:"rules"`
+}
+
+// GateRule is one quality gate rule. Rules compare the project after an analysis with the
+// baseline measured before it, so they only fail on what the analysed commits changed. Limits
+// left at zero default to the project's risk policy.
+type GateRule struct {
+	Name string              `json:"name"`
+	Type values.GateRuleType `json:"type"`
+
+	// Path restricts bus_factor rules to the files under a prefix, e.g. core/
+	Path string `json:"path,omitempty"`
+	// MaxBusFactor fails bus_factor rules on files that newly have a bus factor at or below it
+	MaxBusFactor int `json:"max_bus_factor,omitempty"`
+
+	// Top is the number of hotspots whose total change count hotspot_growth rules compare
+	Top int `json:"top,omitempty"`
+	// MaxGrowthPercent fails hotspot_growth rules when that total grows by more than it
+	MaxGrowthPercent float64 `json:"max_growth_percent,omitempty"`
+
+	// Level, Depth and Layers select the components of coupling rules, as in the coupling command
+	Level  values.CouplingLevel `json:"level,omitempty"`
+	Depth  int                  `json:"depth,omitempty"`
+	Layers []string             `json:"layers,omitempty"`
+	// MinScore fails coupling rules on component pairs newly coupled above it
+	MinScore         float64 `json:"min_score,omitempty"`
+	MinSharedCommits int     `json:"min_shared_commits,omitempty"`
+}
+
+// Validate checks the rules' types and limits and names unnamed rules after their type
+func (c *GateConfig) Validate() error {
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("quality gate requires at least one rule")
+	}
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if _, err := values.ParseGateRuleType(string(rule.Type)); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if rule.Name == "" {
+			rule.Name = string(rule.Type)
+		}
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the limits of a rule's type
+func (r *GateRule) validate() error {
+	switch r.Type {
+	case values.GateBusFactor:
+		if r.MaxBusFactor < 0 {
+			return fmt.Errorf("max_bus_factor must not be negative")
+		}
+	case values.GateHotspotGrowth:
+		if r.Top < 0 {
+			return fmt.Errorf("top must not be negative")
+		}
+		if r.MaxGrowthPercent < 0 {
+			return fmt.Errorf("max_growth_percent must not be negative")
+		}
+	case values.GateCoupling:
+		level, err := values.ParseCouplingLevel(string(r.Level), values.CouplingByDirectory)
+		if err != nil {
+			return err
+		}
+		r.Level = level
+		if level == values.CouplingByLayer && len(r.Layers) == 0 {
+			return fmt.Errorf("layer coupling requires at least one layer")
+		}
+		if r.Depth < 0 || r.MinSharedCommits < 0 {
+			return fmt.Errorf("depth and min_shared_commits must not be negative")
+		}
+		if r.MinScore < 0 || r.MinScore > 1 {
+			return fmt.Errorf("min_score must be between 0 and 1")
+		}
+	}
+	return nil
+}
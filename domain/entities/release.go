@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// Release is a tag of a project's repository. Its development period runs from the commit of
+// the previous release to its own commit, ordered by commit date.
+type Release struct {
+	ID         int
+	ProjectID  int
+	Name       string
+	CommitHash string
+	CommitDate time.Time
+	ReleasedAt time.Time
+}
@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// RiskThresholds are the values at which a metric reaches the medium, high and critical risk
+// levels. A zero threshold disables its level.
+type RiskThresholds struct {
+	Medium   float64
+	High     float64
+	Critical float64
+}
+
+// DebtWeights are the relative weights of the debt score components
+type DebtWeights struct {
+	HotspotComplexity float64
+	Coupling          float64
+	BusFactorRisk     float64
+	StaleCode         float64
+}
+
+// RiskPolicy holds the thresholds that turn a project's metrics into risk levels and the
+// weights of its debt score. Every change is stored as a new version; version 0 is the
+// built-in default.
+type RiskPolicy struct {
+	ID        int
+	ProjectID int
+	Version   int
+	// ChangeFrequency rates files by the number of commits touching them; a level is reached
+	// above its threshold
+	ChangeFrequency RiskThresholds
+	// Ownership rates files by the share of their main owner, in percent; a level is reached
+	// above its threshold
+	Ownership RiskThresholds
+	// BusFactor rates files by their bus factor; lower is riskier, so a level is reached at or
+	// below its threshold
+	BusFactor RiskThresholds
+	// Coupling rates file pairs with at least CouplingMinSharedCommits shared commits by their
+	// coupling score; a level is reached above its threshold
+	Coupling                 RiskThresholds
+	CouplingMinSharedCommits int
+	DebtWeights              DebtWeights
+	CreatedAt                time.Time
+}
+
+// Validate checks that the thresholds of every metric are in range and ordered by risk and
+// that the debt weights are usable
+func (p *RiskPolicy) Validate() error {
+	checks := []struct {
+		name       string
+		thresholds RiskThresholds
+		max        float64
+		descending bool
+	}{
+		{"change_frequency", p.ChangeFrequency, 0, false},
+		{"ownership", p.Ownership, 100, false},
+		{"bus_factor", p.BusFactor, 0, true},
+		{"coupling", p.Coupling, 1, false},
+	}
+	for _, check := range checks {
+		if err := check.thresholds.validate(check.max, check.descending); err != nil {
+			return fmt.Errorf("invalid %s thresholds: %w", check.name, err)
+		}
+	}
+
+	if p.CouplingMinSharedCommits < 1 {
+		return fmt.Errorf("coupling_min_shared_commits must be at least 1")
+	}
+
+	weights := []float64{p.DebtWeights.HotspotComplexity, p.DebtWeights.Coupling, p.DebtWeights.BusFactorRisk, p.DebtWeights.StaleCode}
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("debt weights must not be negative")
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("at least one debt weight must be positive")
+	}
+	return nil
+}
+
+// validate checks that the enabled thresholds are within range and strictly ordered from
+// medium to critical: increasing, or decreasing for metrics where lower values are riskier
+func (t RiskThresholds) validate(max float64, descending bool) error {
+	previous := 0.0
+	for _, threshold := range []float64{t.Medium, t.High, t.Critical} {
+		if threshold < 0 {
+			return fmt.Errorf("thresholds must not be negative")
+		}
+		if max > 0 && threshold > max {
+			return fmt.Errorf("thresholds must not exceed %g", max)
+		}
+		if threshold == 0 {
+			continue
+		}
+		if previous != 0 && (descending && threshold >= previous || !descending && threshold <= previous) {
+			if descending {
+				return fmt.Errorf("thresholds must decrease from medium to critical")
+			}
+			return fmt.Errorf("thresholds must increase from medium to critical")
+		}
+		previous = threshold
+	}
+	return nil
+}
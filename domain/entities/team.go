@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Team is a group of developers whose commits are attributed to the team
+type Team struct {
+	ID          int
+	Name        string
+	Description string
+	Members     []TeamMember
+	CreatedAt   time.Time
+}
+
+// TeamMember is a developer in a team together with the author identities
+// (commit author names) they commit under
+type TeamMember struct {
+	Name       string
+	Identities []string
+}
+
+// NewTeam creates a new team entity
+func NewTeam(name, description string, members []TeamMember) *Team {
+	return &Team{
+		Name:        name,
+		Description: description,
+		Members:     members,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Validate checks that the team has a name and that members are named. Members
+// without explicit identities commit under their own name.
+func (t *Team) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("team name is required")
+	}
+	for i := range t.Members {
+		if strings.TrimSpace(t.Members[i].Name) == "" {
+			return fmt.Errorf("team member name is required")
+		}
+		if len(t.Members[i].Identities) == 0 {
+			t.Members[i].Identities = []string{t.Members[i].Name}
+		}
+	}
+	return nil
+}
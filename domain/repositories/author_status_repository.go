@@ -0,0 +1,15 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// AuthorStatusRepository defines the interface for persisting manual author activity overrides
+type AuthorStatusRepository interface {
+	// GetByProjectID retrieves all overrides of a project
+	GetByProjectID(projectID int) ([]*entities.AuthorStatus, error)
+
+	// Save creates or updates the override of an author
+	Save(status *entities.AuthorStatus) error
+
+	// Delete removes the override of an author so it is classified automatically again
+	Delete(projectID int, author string) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// BlameRepository defines the interface for persisting line-level authorship of the analysed tree
+type BlameRepository interface {
+	// ReplaceForProject replaces the stored blame of a project with a new snapshot
+	ReplaceForProject(projectID int, segments []*entities.BlameSegment) error
+
+	// ReplaceForFiles replaces the stored blame of the given files only; files without
+	// new segments are removed
+	ReplaceForFiles(projectID int, filePaths []string, segments []*entities.BlameSegment) error
+
+	// GetFilePaths retrieves the paths of all files that have stored blame
+	GetFilePaths(projectID int) ([]string, error)
+
+	// GetByProjectID retrieves the current blame segments of a project
+	GetByProjectID(projectID int) ([]*entities.BlameSegment, error)
+}
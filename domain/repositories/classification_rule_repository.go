@@ -0,0 +1,12 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// ClassificationRuleRepository defines the interface for the per-project commit classification rules
+type ClassificationRuleRepository interface {
+	// GetByProjectID retrieves a project's rules in the order they are applied
+	GetByProjectID(projectID int) ([]entities.ClassificationRule, error)
+
+	// ReplaceForProject replaces all rules of a project; an empty list restores the defaults
+	ReplaceForProject(projectID int, rules []entities.ClassificationRule) error
+}
@@ -1,12 +1,19 @@
 package repositories
 
-import "codeecho/domain/entities"
+import (
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
 
 // CommitRepository defines the interface for commit persistence operations
 type CommitRepository interface {
 	// Create creates a new commit
 	Create(commit *entities.Commit) error
 
+	// CreateWithChanges creates a commit and its changes in one transaction, together with their
+	// counts in the pre-computed coupling and daily file statistics and the commit's issue links
+	CreateWithChanges(commit *entities.Commit, changes []*entities.Change) error
+
 	// GetByID retrieves a commit by its ID
 	GetByID(id int) (*entities.Commit, error)
 
@@ -22,6 +29,12 @@ type CommitRepository interface {
 	// GetByAuthor retrieves commits by author for a project
 	GetByAuthor(projectID int, author string) ([]*entities.Commit, error)
 
+	// GetByFilePath retrieves the commits that touched a file, oldest first
+	GetByFilePath(projectID int, filePath string) ([]*entities.Commit, error)
+
 	// CreateBatch creates multiple commits in a batch operation
 	CreateBatch(commits []*entities.Commit) error
+
+	// UpdateCategories sets the category of the commits with the given IDs
+	UpdateCategories(categories map[int]values.CommitCategory) error
 }
@@ -0,0 +1,12 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// ComplexityRepository defines the interface for persisting file complexity samples
+type ComplexityRepository interface {
+	// GetByFilePath retrieves all stored samples for a file, oldest first
+	GetByFilePath(projectID int, filePath string) ([]*entities.ComplexitySample, error)
+
+	// Save stores a sample, replacing any existing sample for the same commit and file
+	Save(sample *entities.ComplexitySample) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import "time"
+
+// MaxCouplingCommitFiles is the largest number of files a commit may touch to count towards the
+// coupling of its file pairs; larger commits (mass renames, reformatting, vendoring) still count
+// towards each file's commits but would add quadratically many pairs that carry no coupling signal
+const MaxCouplingCommitFiles = 200
+
+// CouplingRepository defines the interface for the pre-computed temporal coupling store, which
+// keeps per-month counts of the commits each file and each pair of files changed in
+type CouplingRepository interface {
+	// RecordCommit adds a commit's changed files to the monthly buckets of their counts and pairs
+	RecordCommit(projectID int, committedAt time.Time, filePaths []string) error
+
+	// MarkComplete records that the stored coupling covers every stored commit of a project,
+	// which lets the coupling queries read it instead of the change history
+	MarkComplete(projectID int) error
+
+	// IsComplete reports whether the stored coupling covers every stored commit of a project
+	IsComplete(projectID int) (bool, error)
+
+	// Rebuild replaces the stored coupling of a project with counts recomputed from its stored commits and
+	// marks it complete in one transaction, which keeps the project's commits from being stored
+	// meanwhile; it returns the number of commits recorded
+	Rebuild(projectID int) (int, error)
+
+	// DeleteByProjectID removes all stored coupling of a project and its completeness mark
+	DeleteByProjectID(projectID int) error
+}
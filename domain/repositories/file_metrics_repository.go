@@ -0,0 +1,12 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// FileMetricsRepository defines the interface for persisting per-file size measurements
+type FileMetricsRepository interface {
+	// ReplaceForProject replaces the stored measurements of a project with a new snapshot
+	ReplaceForProject(projectID int, metrics []*entities.FileMetrics) error
+
+	// GetByProjectID retrieves the current measurements of a project
+	GetByProjectID(projectID int) ([]*entities.FileMetrics, error)
+}
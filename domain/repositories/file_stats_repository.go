@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"time"
+
+	"codeecho/domain/entities"
+)
+
+// FileStatsRepository defines the interface for the per-file daily statistics rollup, which
+// keeps the commits and lines added and deleted per file, author and day
+type FileStatsRepository interface {
+	// RecordCommit adds a commit's changes to the daily statistics of their files
+	RecordCommit(projectID int, author string, committedAt time.Time, changes []*entities.Change) error
+
+	// MarkComplete records that the daily statistics cover every stored commit of a project
+	MarkComplete(projectID int) error
+
+	// IsComplete reports whether the daily statistics cover every stored commit of a project
+	IsComplete(projectID int) (bool, error)
+
+	// Rebuild replaces the daily statistics of a project with ones recomputed from its stored commits and
+	// marks them complete in one transaction, which keeps the project's commits from being stored
+	// meanwhile; it returns the number of commits recorded
+	Rebuild(projectID int) (int, error)
+
+	// DeleteByProjectID removes all daily statistics of a project and their completeness mark
+	DeleteByProjectID(projectID int) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// HealthSnapshotRepository defines the interface for the monthly project health snapshots
+type HealthSnapshotRepository interface {
+	// GetByProjectID retrieves the most recent snapshots of a project, up to limit (all when
+	// limit is 0), ordered by month
+	GetByProjectID(projectID int, limit int) ([]entities.HealthSnapshot, error)
+
+	// GetLatest retrieves a project's most recent snapshot, or nil when it has none
+	GetLatest(projectID int) (*entities.HealthSnapshot, error)
+
+	// Save inserts or replaces the snapshots of their months
+	Save(snapshots []entities.HealthSnapshot) error
+
+	// DeleteByProjectID removes all snapshots of a project
+	DeleteByProjectID(projectID int) error
+}
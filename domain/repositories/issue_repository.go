@@ -0,0 +1,25 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// IssueRepository defines the interface for issue references extracted from commit messages,
+// the patterns that extract them and imported issue metadata
+type IssueRepository interface {
+	// GetPatterns retrieves the issue key patterns of a project in order
+	GetPatterns(projectID int) ([]string, error)
+
+	// ReplacePatterns replaces the issue key patterns of a project; an empty list restores the defaults
+	ReplacePatterns(projectID int, patterns []string) error
+
+	// LinkCommit records the issue keys a commit references
+	LinkCommit(projectID, commitID int, keys []string) error
+
+	// DeleteLinksByProjectID removes all commit-issue links of a project
+	DeleteLinksByProjectID(projectID int) error
+
+	// UpsertIssues creates or updates the metadata of issues by key
+	UpsertIssues(projectID int, issues []*entities.Issue) error
+
+	// GetIssueTypes maps the keys of a project's imported issues to their types
+	GetIssueTypes(projectID int) (map[string]string, error)
+}
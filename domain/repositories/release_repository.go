@@ -0,0 +1,12 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// ReleaseRepository defines the interface for the releases ingested from repository tags
+type ReleaseRepository interface {
+	// GetByProjectID retrieves a project's releases ordered by commit date
+	GetByProjectID(projectID int) ([]entities.Release, error)
+
+	// ReplaceForProject replaces all releases of a project with its current tags
+	ReplaceForProject(projectID int, releases []entities.Release) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// RiskPolicyRepository defines the interface for the versioned per-project risk policies
+type RiskPolicyRepository interface {
+	// GetCurrent retrieves the latest version of a project's policy, or nil when the project
+	// uses the default policy
+	GetCurrent(projectID int) (*entities.RiskPolicy, error)
+
+	// GetVersions retrieves every version of a project's policy, newest first
+	GetVersions(projectID int) ([]entities.RiskPolicy, error)
+
+	// Create stores a policy as the next version of its project's policy, setting its ID,
+	// version and creation time
+	Create(policy *entities.RiskPolicy) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import "codeecho/domain/entities"
+
+// TeamRepository defines the interface for team persistence operations
+type TeamRepository interface {
+	// Create saves a new team with its members
+	Create(team *entities.Team) error
+
+	// GetByID retrieves a team by its ID
+	GetByID(id int) (*entities.Team, error)
+
+	// GetAll retrieves all teams
+	GetAll() ([]*entities.Team, error)
+
+	// Update replaces a team's details and members
+	Update(team *entities.Team) error
+
+	// Delete removes a team
+	Delete(id int) error
+}
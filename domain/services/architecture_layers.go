@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ArchitectureLayer is a named group of files (a layer or component) defined by glob patterns
+type ArchitectureLayer struct {
+	Name     string
+	Patterns []string
+}
+
+// ParseArchitectureLayer parses a "name:pattern,pattern" layer definition. Patterns use
+// path.Match syntax with an additional "**" segment matching any number of directories.
+func ParseArchitectureLayer(definition string) (ArchitectureLayer, error) {
+	name, patterns, found := strings.Cut(definition, ":")
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return ArchitectureLayer{}, fmt.Errorf("invalid layer %q: expected name:pattern[,pattern]", definition)
+	}
+
+	layer := ArchitectureLayer{Name: name}
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return ArchitectureLayer{}, fmt.Errorf("invalid pattern %q in layer %q: %w", pattern, name, err)
+		}
+		layer.Patterns = append(layer.Patterns, pattern)
+	}
+	if len(layer.Patterns) == 0 {
+		return ArchitectureLayer{}, fmt.Errorf("layer %q has no patterns", name)
+	}
+	return layer, nil
+}
+
+// LayerOf returns the first layer whose patterns match the file path
+func LayerOf(layers []ArchitectureLayer, filePath string) (string, bool) {
+	for _, layer := range layers {
+		for _, pattern := range layer.Patterns {
+			if MatchGlob(pattern, filePath) {
+				return layer.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// MatchGlob reports whether a slash-separated file path matches a glob pattern. Segments
+// are matched with path.Match; a "**" segment matches zero or more whole directories.
+func MatchGlob(pattern, filePath string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,47 @@
+package services
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"interfaces/api/**", "interfaces/api/handlers/bus_factor.go", true},
+		{"interfaces/api/**", "interfaces/cli/main.go", false},
+		{"**/*_test.go", "domain/services/code_age_test.go", true},
+		{"**/*_test.go", "code_age_test.go", true},
+		{"domain/*/*.go", "domain/services/code_age.go", true},
+		{"domain/*.go", "domain/services/code_age.go", false},
+		{"src/**/*.js", "src/app.js", true},
+	}
+	for _, tc := range cases {
+		if got := MatchGlob(tc.pattern, tc.path); got != tc.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestLayerOf(t *testing.T) {
+	api, err := ParseArchitectureLayer("api:interfaces/api/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	persistence, err := ParseArchitectureLayer("persistence: infrastructure/persistence/**, infrastructure/repository/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers := []ArchitectureLayer{api, persistence}
+
+	if layer, ok := LayerOf(layers, "infrastructure/repository/analytics_repository_impl.go"); !ok || layer != "persistence" {
+		t.Errorf("expected persistence, got %q", layer)
+	}
+	if _, ok := LayerOf(layers, "domain/entities/team.go"); ok {
+		t.Error("expected files outside every layer to be unmatched")
+	}
+
+	if _, err := ParseArchitectureLayer("no-patterns"); err == nil {
+		t.Error("expected an error for a layer without patterns")
+	}
+}
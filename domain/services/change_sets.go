@@ -0,0 +1,124 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"codeecho/domain/values"
+)
+
+// DefaultChangeSetWindow is the gap under which commits of one author form a single change set
+const DefaultChangeSetWindow = 30 * time.Minute
+
+// DefaultIssueKeyPattern matches Jira-style issue keys such as PROJ-123
+var DefaultIssueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// ChangeSetCommit is the commit metadata needed to group commits into change sets
+type ChangeSetCommit struct {
+	ID        int
+	Author    string
+	Timestamp time.Time
+	Message   string
+}
+
+// ChangeSetGrouping configures how commits are grouped into logical change sets
+type ChangeSetGrouping struct {
+	Mode values.ChangeSetMode
+	// Window is the maximum gap between consecutive commits of an author in one change set
+	Window time.Duration
+	// IssuePattern extracts issue keys from commit messages; DefaultIssueKeyPattern when nil
+	IssuePattern *regexp.Regexp
+}
+
+// GroupChangeSets maps every commit ID to the ID of its change set, which is the smallest
+// commit ID in the set. In window mode each author's commits are chained while the gap to
+// the previous commit stays within the window; in ticket mode commits referencing a common
+// issue key are joined transitively and commits without a key stay on their own.
+func GroupChangeSets(commits []ChangeSetCommit, grouping ChangeSetGrouping) map[int]int {
+	sets := make(map[int]int, len(commits))
+	for _, commit := range commits {
+		sets[commit.ID] = commit.ID
+	}
+
+	// find returns the representative of a commit's set, compressing the path on the way
+	var find func(id int) int
+	find = func(id int) int {
+		if sets[id] != id {
+			sets[id] = find(sets[id])
+		}
+		return sets[id]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if ra < rb {
+			sets[rb] = ra
+		} else {
+			sets[ra] = rb
+		}
+	}
+
+	switch grouping.Mode {
+	case values.ChangeSetByWindow:
+		window := grouping.Window
+		if window <= 0 {
+			window = DefaultChangeSetWindow
+		}
+		byAuthor := make(map[string][]ChangeSetCommit)
+		for _, commit := range commits {
+			byAuthor[commit.Author] = append(byAuthor[commit.Author], commit)
+		}
+		for _, authored := range byAuthor {
+			sort.Slice(authored, func(i, j int) bool {
+				if !authored[i].Timestamp.Equal(authored[j].Timestamp) {
+					return authored[i].Timestamp.Before(authored[j].Timestamp)
+				}
+				return authored[i].ID < authored[j].ID
+			})
+			for i := 1; i < len(authored); i++ {
+				if authored[i].Timestamp.Sub(authored[i-1].Timestamp) <= window {
+					union(authored[i-1].ID, authored[i].ID)
+				}
+			}
+		}
+	case values.ChangeSetByTicket:
+		firstByKey := make(map[string]int)
+		for _, commit := range commits {
+			for _, key := range ExtractIssueKeys(commit.Message, grouping.IssuePattern) {
+				if first, ok := firstByKey[key]; ok {
+					union(first, commit.ID)
+				} else {
+					firstByKey[key] = commit.ID
+				}
+			}
+		}
+	}
+
+	for id := range sets {
+		find(id)
+	}
+	return sets
+}
+
+// ExtractIssueKeys returns the distinct issue keys referenced in a commit message, upper-cased,
+// in order of appearance. A nil pattern uses DefaultIssueKeyPattern.
+func ExtractIssueKeys(message string, pattern *regexp.Regexp) []string {
+	if pattern == nil {
+		pattern = DefaultIssueKeyPattern
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, match := range pattern.FindAllString(message, -1) {
+		key := strings.ToUpper(match)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"codeecho/domain/values"
+)
+
+func TestGroupChangeSetsByWindow(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	commits := []ChangeSetCommit{
+		{ID: 1, Author: "alice", Timestamp: base},
+		{ID: 2, Author: "alice", Timestamp: base.Add(20 * time.Minute)},
+		{ID: 3, Author: "bob", Timestamp: base.Add(25 * time.Minute)},
+		{ID: 4, Author: "alice", Timestamp: base.Add(45 * time.Minute)},
+		{ID: 5, Author: "alice", Timestamp: base.Add(3 * time.Hour)},
+	}
+
+	sets := GroupChangeSets(commits, ChangeSetGrouping{Mode: values.ChangeSetByWindow, Window: 30 * time.Minute})
+
+	// alice's first three commits chain within the window; bob and the late commit stay alone
+	expected := map[int]int{1: 1, 2: 1, 3: 3, 4: 1, 5: 5}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Errorf("expected %v, got %v", expected, sets)
+	}
+}
+
+func TestGroupChangeSetsByTicket(t *testing.T) {
+	commits := []ChangeSetCommit{
+		{ID: 1, Message: "PROJ-1: backend endpoint"},
+		{ID: 2, Message: "frontend for proj-1 and PROJ-2"},
+		{ID: 3, Message: "PROJ-2 docs"},
+		{ID: 4, Message: "unrelated cleanup"},
+	}
+
+	sets := GroupChangeSets(commits, ChangeSetGrouping{Mode: values.ChangeSetByTicket})
+
+	// The default pattern is case-sensitive, so "proj-1" does not match; PROJ-2 still links 2 and 3
+	expected := map[int]int{1: 1, 2: 2, 3: 2, 4: 4}
+	if !reflect.DeepEqual(sets, expected) {
+		t.Errorf("expected %v, got %v", expected, sets)
+	}
+}
+
+func TestGroupChangeSetsByCommit(t *testing.T) {
+	commits := []ChangeSetCommit{{ID: 7, Message: "PROJ-1"}, {ID: 8, Message: "PROJ-1"}}
+
+	sets := GroupChangeSets(commits, ChangeSetGrouping{Mode: values.ChangeSetByCommit})
+
+	if sets[7] != 7 || sets[8] != 8 {
+		t.Errorf("expected every commit in its own change set, got %v", sets)
+	}
+}
+
+func TestExtractIssueKeys(t *testing.T) {
+	keys := ExtractIssueKeys("Fix ABC-12 and DEF-3, follow-up to ABC-12", nil)
+	if !reflect.DeepEqual(keys, []string{"ABC-12", "DEF-3"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
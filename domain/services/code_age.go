@@ -0,0 +1,39 @@
+package services
+
+import "time"
+
+// AgeBucket is a half-open range of ages in days, [MinDays, MaxDays).
+// A MaxDays of zero means the bucket has no upper bound.
+type AgeBucket struct {
+	Label   string
+	MinDays int
+	MaxDays int
+}
+
+// CodeAgeBuckets are the age ranges used to classify files and lines by how long ago they last changed
+var CodeAgeBuckets = []AgeBucket{
+	{Label: "< 1 month", MinDays: 0, MaxDays: 30},
+	{Label: "1-3 months", MinDays: 30, MaxDays: 90},
+	{Label: "3-6 months", MinDays: 90, MaxDays: 180},
+	{Label: "6-12 months", MinDays: 180, MaxDays: 365},
+	{Label: "1-2 years", MinDays: 365, MaxDays: 730},
+	{Label: "> 2 years", MinDays: 730, MaxDays: 0},
+}
+
+// AgeInDays returns the number of whole days between then and now, never negative
+func AgeInDays(then, now time.Time) int {
+	if then.After(now) {
+		return 0
+	}
+	return int(now.Sub(then).Hours() / 24)
+}
+
+// AgeBucketIndex returns the index in CodeAgeBuckets of the bucket containing ageDays
+func AgeBucketIndex(ageDays int) int {
+	for i, bucket := range CodeAgeBuckets {
+		if bucket.MaxDays == 0 || ageDays < bucket.MaxDays {
+			return i
+		}
+	}
+	return len(CodeAgeBuckets) - 1
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgeInDays(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := AgeInDays(now.AddDate(0, 0, -45), now); got != 45 {
+		t.Errorf("expected 45 days, got %d", got)
+	}
+	if got := AgeInDays(now.Add(time.Hour), now); got != 0 {
+		t.Errorf("expected future timestamps to have age 0, got %d", got)
+	}
+}
+
+func TestAgeBucketIndex(t *testing.T) {
+	tests := []struct {
+		ageDays int
+		label   string
+	}{
+		{0, "< 1 month"},
+		{29, "< 1 month"},
+		{30, "1-3 months"},
+		{200, "6-12 months"},
+		{365, "1-2 years"},
+		{5000, "> 2 years"},
+	}
+
+	for _, tt := range tests {
+		if got := CodeAgeBuckets[AgeBucketIndex(tt.ageDays)].Label; got != tt.label {
+			t.Errorf("age %d: expected bucket %q, got %q", tt.ageDays, tt.label, got)
+		}
+	}
+}
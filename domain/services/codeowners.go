@@ -0,0 +1,387 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults of CODEOWNERS proposals whose options are not set
+const (
+	// DefaultCodeownersDepth is the directory depth entries are proposed at
+	DefaultCodeownersDepth = 1
+	// DefaultCodeownersMinShare is the ownership share (percent) of a directory an owner needs to be listed
+	DefaultCodeownersMinShare = 20.0
+	// DefaultCodeownersMaxOwners is the maximum number of owners listed per entry
+	DefaultCodeownersMaxOwners = 3
+)
+
+// CodeownersLocations are the paths a CODEOWNERS file is looked up at, in GitHub's order
+var CodeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is an entry of a CODEOWNERS file. A rule without owners removes ownership.
+type CodeownersRule struct {
+	Line    int
+	Pattern string
+	Owners  []string
+	pattern *regexp.Regexp
+}
+
+// Matches reports whether the rule's pattern matches a file path
+func (r CodeownersRule) Matches(filePath string) bool {
+	return r.pattern != nil && r.pattern.MatchString(filePath)
+}
+
+// NewCodeownersRule compiles a CODEOWNERS entry. Patterns follow gitignore rules, except that
+// a trailing "/*" does not match files in subdirectories, as on GitHub.
+func NewCodeownersRule(line int, pattern string, owners []string) (CodeownersRule, error) {
+	re, err := compileCodeownersPattern(pattern)
+	if err != nil {
+		return CodeownersRule{}, fmt.Errorf("line %d: %w", line, err)
+	}
+	return CodeownersRule{Line: line, Pattern: pattern, Owners: owners, pattern: re}, nil
+}
+
+// ParseCodeowners reads the rules of a CODEOWNERS file, skipping comments, blank lines and
+// GitLab section headers
+func ParseCodeowners(content string) ([]CodeownersRule, error) {
+	var rules []CodeownersRule
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if hash := strings.Index(line, " #"); hash >= 0 {
+			line = line[:hash]
+		}
+		fields := strings.Fields(line)
+		rule, err := NewCodeownersRule(i+1, fields[0], fields[1:])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// MatchCodeowners returns the index of the rule owning a file path, the last matching one, or
+// -1 when no rule matches
+func MatchCodeowners(rules []CodeownersRule, filePath string) int {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Matches(filePath) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compileCodeownersPattern converts a CODEOWNERS pattern to a regular expression over file paths
+func compileCodeownersPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("negated pattern %q is not supported in CODEOWNERS", pattern)
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("character ranges in pattern %q are not supported in CODEOWNERS", pattern)
+	}
+
+	body := strings.TrimSuffix(pattern, "/")
+	dirOnly := body != pattern
+	// Patterns with a slash other than a trailing one are relative to the repository root
+	anchored := strings.Contains(body, "/")
+	body = strings.TrimPrefix(body, "/")
+	if body == "" {
+		// "/" owns the whole repository
+		return regexp.Compile("^.*$")
+	}
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(body); i++ {
+		switch {
+		case strings.HasPrefix(body[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(body[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case body[i] == '*':
+			expr.WriteString("[^/]*")
+		case body[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(body[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case strings.HasSuffix(body, "/*"):
+		expr.WriteString("$")
+	default:
+		// A pattern naming a directory owns everything below it
+		expr.WriteString("(/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
+
+// CodeownersOptions tune which owners a proposed entry lists
+type CodeownersOptions struct {
+	Depth     int
+	MinShare  float64
+	MaxOwners int
+}
+
+// OwnedFile holds the ownership shares (percent) of one file by owner
+type OwnedFile struct {
+	FilePath string
+	Shares   map[string]float64
+}
+
+// OwnerShare is an owner's average ownership share (percent) of the files of a directory
+type OwnerShare struct {
+	Owner string
+	Share float64
+}
+
+// CodeownersProposal is a proposed CODEOWNERS entry for a directory, "*" for the root
+type CodeownersProposal struct {
+	Pattern string
+	Files   int
+	Owners  []OwnerShare
+}
+
+// ProposeCodeowners proposes a CODEOWNERS entry for every directory at opts.Depth, and the
+// root, holding files. An entry lists up to MaxOwners owners whose average share of the
+// directory's files reaches MinShare. Directories without such owners and directories owned
+// exactly like the entry enclosing them are left out. Entries are ordered so that more specific
+// ones come later, as the last matching entry wins.
+func ProposeCodeowners(files []OwnedFile, opts CodeownersOptions) []CodeownersProposal {
+	if opts.Depth <= 0 {
+		opts.Depth = DefaultCodeownersDepth
+	}
+	if opts.MinShare <= 0 {
+		opts.MinShare = DefaultCodeownersMinShare
+	}
+	if opts.MaxOwners <= 0 {
+		opts.MaxOwners = DefaultCodeownersMaxOwners
+	}
+
+	type directory struct {
+		files  int
+		shares map[string]float64
+	}
+	directories := make(map[string]*directory)
+	for _, file := range files {
+		dir := codeownersDirectory(file.FilePath, opts.Depth)
+		if directories[dir] == nil {
+			directories[dir] = &directory{shares: make(map[string]float64)}
+		}
+		directories[dir].files++
+		for owner, share := range file.Shares {
+			directories[dir].shares[owner] += share
+		}
+	}
+
+	paths := make([]string, 0, len(directories))
+	for path := range directories {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		// The root comes first, then parents before their subdirectories
+		if paths[i] == "" || paths[j] == "" {
+			return paths[i] == ""
+		}
+		return paths[i]+"/" < paths[j]+"/"
+	})
+
+	proposals := make([]CodeownersProposal, 0, len(paths))
+	ownersOf := make(map[string]string)
+	for _, path := range paths {
+		dir := directories[path]
+		owners := make([]OwnerShare, 0, len(dir.shares))
+		for owner, total := range dir.shares {
+			if share := total / float64(dir.files); share >= opts.MinShare {
+				owners = append(owners, OwnerShare{Owner: owner, Share: share})
+			}
+		}
+		if len(owners) == 0 {
+			continue
+		}
+		sort.Slice(owners, func(i, j int) bool {
+			if owners[i].Share != owners[j].Share {
+				return owners[i].Share > owners[j].Share
+			}
+			return owners[i].Owner < owners[j].Owner
+		})
+		if len(owners) > opts.MaxOwners {
+			owners = owners[:opts.MaxOwners]
+		}
+
+		names := make([]string, len(owners))
+		for i, owner := range owners {
+			names[i] = owner.Owner
+		}
+		key := strings.Join(names, " ")
+		ownersOf[path] = key
+		if path != "" && ownersOf[enclosingCodeownersEntry(path, ownersOf)] == key {
+			continue
+		}
+
+		pattern := "*"
+		if path != "" {
+			pattern = "/" + path + "/"
+		}
+		proposals = append(proposals, CodeownersProposal{Pattern: pattern, Files: dir.files, Owners: owners})
+	}
+	return proposals
+}
+
+// FormatCodeowners renders proposed entries as a CODEOWNERS file with aligned owners
+func FormatCodeowners(header string, proposals []CodeownersProposal) string {
+	width := 0
+	for _, p := range proposals {
+		width = max(width, len(p.Pattern))
+	}
+
+	var out strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+		if line != "" {
+			out.WriteString("# " + line + "\n")
+		}
+	}
+	if out.Len() > 0 {
+		out.WriteString("\n")
+	}
+	for _, p := range proposals {
+		owners := make([]string, len(p.Owners))
+		for i, owner := range p.Owners {
+			owners[i] = owner.Owner
+		}
+		fmt.Fprintf(&out, "%-*s %s\n", width, p.Pattern, strings.Join(owners, " "))
+	}
+	return out.String()
+}
+
+// codeownersDirectory returns the directory of a file cut to depth, "" for files at the root
+func codeownersDirectory(filePath string, depth int) string {
+	parts := strings.Split(filePath, "/")
+	dirs := parts[:len(parts)-1]
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/")
+}
+
+// enclosingCodeownersEntry returns the closest parent of a directory with an entry, "" for the root
+func enclosingCodeownersEntry(path string, entries map[string]string) string {
+	for {
+		slash := strings.LastIndex(path, "/")
+		if slash < 0 {
+			return ""
+		}
+		path = path[:slash]
+		if _, exists := entries[path]; exists {
+			return path
+		}
+	}
+}
+
+// CodeownersDrift is a CODEOWNERS rule whose declared owners no longer match who works on its files
+type CodeownersDrift struct {
+	Rule CodeownersRule
+	// Files is the number of files at HEAD the rule owns, as the last matching rule
+	Files int
+	// StaleOwners have not touched the rule's files since the cutoff; a zero LastTouched means never
+	StaleOwners []OwnerActivity
+	// UnknownOwners cannot be resolved to any author
+	UnknownOwners []string
+	LastChange    time.Time
+	// RecentAuthors touched the rule's files since the cutoff, most recent first
+	RecentAuthors []string
+}
+
+// OwnerActivity is when a declared owner last touched a rule's files
+type OwnerActivity struct {
+	Owner       string
+	LastTouched time.Time
+}
+
+// Drifted reports whether the rule has stale or unknown owners, or owns no files
+func (d CodeownersDrift) Drifted() bool {
+	return d.Files == 0 || len(d.StaleOwners) > 0 || len(d.UnknownOwners) > 0
+}
+
+// CheckCodeowners compares every rule with owners against the files it owns at HEAD. touches
+// maps files to the last time each author changed them; resolve maps an owner to the authors
+// it stands for and reports false for owners it does not know. Owners none of whose authors
+// touched the rule's files after cutoff are stale.
+func CheckCodeowners(rules []CodeownersRule, files []string, touches map[string]map[string]time.Time, resolve func(owner string) ([]string, bool), cutoff time.Time) []CodeownersDrift {
+	checks := make([]CodeownersDrift, len(rules))
+	lastTouch := make([]map[string]time.Time, len(rules))
+	for i, rule := range rules {
+		checks[i].Rule = rule
+		lastTouch[i] = make(map[string]time.Time)
+	}
+	for _, file := range files {
+		i := MatchCodeowners(rules, file)
+		if i < 0 {
+			continue
+		}
+		checks[i].Files++
+		for author, touched := range touches[file] {
+			if touched.After(lastTouch[i][author]) {
+				lastTouch[i][author] = touched
+			}
+		}
+	}
+
+	drifts := make([]CodeownersDrift, 0, len(rules))
+	for i := range checks {
+		check := &checks[i]
+		if len(check.Rule.Owners) == 0 {
+			continue
+		}
+
+		for author, touched := range lastTouch[i] {
+			if touched.After(check.LastChange) {
+				check.LastChange = touched
+			}
+			if touched.After(cutoff) {
+				check.RecentAuthors = append(check.RecentAuthors, author)
+			}
+		}
+		sort.Slice(check.RecentAuthors, func(a, b int) bool {
+			ta, tb := lastTouch[i][check.RecentAuthors[a]], lastTouch[i][check.RecentAuthors[b]]
+			if !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+			return check.RecentAuthors[a] < check.RecentAuthors[b]
+		})
+
+		for _, owner := range check.Rule.Owners {
+			authors, known := resolve(owner)
+			if !known {
+				check.UnknownOwners = append(check.UnknownOwners, owner)
+				continue
+			}
+			var last time.Time
+			for _, author := range authors {
+				if touched := lastTouch[i][author]; touched.After(last) {
+					last = touched
+				}
+			}
+			// Owners of a rule without files are not stale; the rule itself is
+			if !last.After(cutoff) && check.Files > 0 {
+				check.StaleOwners = append(check.StaleOwners, OwnerActivity{Owner: owner, LastTouched: last})
+			}
+		}
+		drifts = append(drifts, *check)
+	}
+	return drifts
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCodeownersRule_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "core/a.go", true},
+		{"*.js", "web/app/main.js", true},
+		{"*.js", "web/app/main.go", false},
+		{"/build/logs/", "build/logs/x/y.log", true},
+		{"/build/logs/", "src/build/logs/y.log", false},
+		{"apps/", "src/apps/main.go", true},
+		{"docs/*", "docs/intro.md", true},
+		{"docs/*", "docs/guide/setup.md", false},
+		{"**/logs", "deep/nested/logs/out.txt", true},
+		{"/core", "core/a.go", true},
+		{"/core", "lib/core/a.go", false},
+		{"core", "lib/core/a.go", true},
+		{"core/a?.go", "core/ab.go", true},
+		{"/", "anything/at/all", true},
+	}
+
+	for _, tt := range tests {
+		rule, err := NewCodeownersRule(1, tt.pattern, []string{"@ann"})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.pattern, err)
+		}
+		if got := rule.Matches(tt.path); got != tt.want {
+			t.Errorf("%q matching %q: expected %v, got %v", tt.pattern, tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# Default owners
+*           @acme/core
+
+[Docs]
+/docs/      @bob docs@example.com   # writers
+/generated/
+`
+	rules, err := ParseCodeowners(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %+v", rules)
+	}
+	if rules[1].Line != 5 || rules[1].Pattern != "/docs/" || !reflect.DeepEqual(rules[1].Owners, []string{"@bob", "docs@example.com"}) {
+		t.Errorf("unexpected docs rule %+v", rules[1])
+	}
+	if len(rules[2].Owners) != 0 {
+		t.Errorf("expected an ownerless rule, got %+v", rules[2])
+	}
+
+	if got := MatchCodeowners(rules, "docs/intro.md"); got != 1 {
+		t.Errorf("expected the last matching rule to win, got %d", got)
+	}
+	if got := MatchCodeowners(rules[1:], "core/a.go"); got != -1 {
+		t.Errorf("expected no rule, got %d", got)
+	}
+
+	if _, err := ParseCodeowners("!vendor/ @ann"); err == nil {
+		t.Error("expected negated patterns to be rejected")
+	}
+}
+
+func TestProposeCodeowners(t *testing.T) {
+	files := []OwnedFile{
+		{FilePath: "main.go", Shares: map[string]float64{"@dan": 100}},
+		{FilePath: "core/a.go", Shares: map[string]float64{"@ann": 80, "@bob": 20}},
+		{FilePath: "core/b.go", Shares: map[string]float64{"@ann": 60, "@bob": 30, "@cy": 10}},
+		{FilePath: "core/sub/c.go", Shares: map[string]float64{"@ann": 100}},
+		{FilePath: "web/w.go", Shares: map[string]float64{"@cy": 90, "@dan": 10}},
+		{FilePath: "tmp/t.go", Shares: map[string]float64{"@a": 10, "@b": 10, "@c": 10}},
+	}
+
+	got := ProposeCodeowners(files, CodeownersOptions{Depth: 2, MinShare: 20, MaxOwners: 1})
+	want := []CodeownersProposal{
+		{Pattern: "*", Files: 1, Owners: []OwnerShare{{"@dan", 100}}},
+		{Pattern: "/core/", Files: 2, Owners: []OwnerShare{{"@ann", 70}}},
+		{Pattern: "/web/", Files: 1, Owners: []OwnerShare{{"@cy", 90}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	got = ProposeCodeowners(files[1:3], CodeownersOptions{MinShare: 20})
+	if len(got) != 1 || len(got[0].Owners) != 2 || got[0].Owners[1] != (OwnerShare{"@bob", 25}) {
+		t.Errorf("expected ann and bob for core, got %+v", got)
+	}
+
+	content := FormatCodeowners("Generated", want)
+	wantContent := "# Generated\n\n*      @dan\n/core/ @ann\n/web/  @cy\n"
+	if content != wantContent {
+		t.Errorf("expected\n%s\ngot\n%s", wantContent, content)
+	}
+}
+
+func TestCheckCodeowners(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := now.AddDate(0, -6, 0)
+	rules, err := ParseCodeowners("* @acme/core\n/web/ @cy @ghost\n/old/ @ann\n/docs/\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{"main.go", "web/w.go", "web/x.go", "docs/a.md"}
+	touches := map[string]map[string]time.Time{
+		"main.go":  {"ann": now.AddDate(0, -1, 0)},
+		"web/w.go": {"cy": now.AddDate(-1, 0, 0), "dan": now.AddDate(0, 0, -3)},
+		"web/x.go": {"eve": now.AddDate(0, -2, 0)},
+	}
+	resolve := func(owner string) ([]string, bool) {
+		switch owner {
+		case "@acme/core":
+			return []string{"ann", "bob"}, true
+		case "@cy":
+			return []string{"cy"}, true
+		case "@ann":
+			return []string{"ann"}, true
+		}
+		return nil, false
+	}
+
+	got := CheckCodeowners(rules, files, touches, resolve, cutoff)
+	if len(got) != 3 {
+		t.Fatalf("expected the three rules with owners, got %+v", got)
+	}
+	if got[0].Drifted() || got[0].Files != 1 {
+		t.Errorf("expected the root rule to be current, got %+v", got[0])
+	}
+
+	web := got[1]
+	if !web.Drifted() || web.Files != 2 {
+		t.Fatalf("expected the web rule to drift, got %+v", web)
+	}
+	if len(web.StaleOwners) != 1 || web.StaleOwners[0].Owner != "@cy" || !web.StaleOwners[0].LastTouched.Equal(now.AddDate(-1, 0, 0)) {
+		t.Errorf("expected cy to be stale, got %+v", web.StaleOwners)
+	}
+	if !reflect.DeepEqual(web.UnknownOwners, []string{"@ghost"}) || !reflect.DeepEqual(web.RecentAuthors, []string{"dan", "eve"}) {
+		t.Errorf("unexpected unknown owners or recent authors: %+v", web)
+	}
+
+	old := got[2]
+	if old.Files != 0 || !old.Drifted() || len(old.StaleOwners) != 0 {
+		t.Errorf("expected the rule without files to drift, got %+v", old)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+// conventionalCommitPattern matches a Conventional Commits header such as "fix(parser)!: ..."
+var conventionalCommitPattern = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?!?:\s`)
+
+// conventionalCommitTypes maps Conventional Commits types to commit categories
+var conventionalCommitTypes = map[string]values.CommitCategory{
+	"fix":      values.CategoryBugfix,
+	"bugfix":   values.CategoryBugfix,
+	"hotfix":   values.CategoryBugfix,
+	"feat":     values.CategoryFeature,
+	"feature":  values.CategoryFeature,
+	"refactor": values.CategoryRefactor,
+	"perf":     values.CategoryRefactor,
+	"style":    values.CategoryRefactor,
+	"docs":     values.CategoryDocs,
+	"doc":      values.CategoryDocs,
+	"test":     values.CategoryTest,
+	"tests":    values.CategoryTest,
+	"chore":    values.CategoryChore,
+	"build":    values.CategoryChore,
+	"ci":       values.CategoryChore,
+	"release":  values.CategoryChore,
+	"revert":   values.CategoryChore,
+	"deps":     values.CategoryChore,
+}
+
+// issueTypeCategories maps issue tracker types (lower-cased) to commit categories
+var issueTypeCategories = map[string]values.CommitCategory{
+	"bug":           values.CategoryBugfix,
+	"defect":        values.CategoryBugfix,
+	"incident":      values.CategoryBugfix,
+	"problem":       values.CategoryBugfix,
+	"story":         values.CategoryFeature,
+	"feature":       values.CategoryFeature,
+	"epic":          values.CategoryFeature,
+	"improvement":   values.CategoryFeature,
+	"enhancement":   values.CategoryFeature,
+	"new feature":   values.CategoryFeature,
+	"documentation": values.CategoryDocs,
+	"test":          values.CategoryTest,
+	"task":          values.CategoryChore,
+	"sub-task":      values.CategoryChore,
+	"chore":         values.CategoryChore,
+}
+
+// DefaultClassificationRules are the keyword rules applied to projects without rules of their
+// own. Rules are tried in order, so bug-fix wording wins over the rest.
+var DefaultClassificationRules = []entities.ClassificationRule{
+	{Category: values.CategoryBugfix, Keywords: []string{"fix", "fixes", "fixed", "fixing", "bug", "bugfix", "hotfix", "defect", "regression", "crash", "broken"}},
+	{Category: values.CategoryTest, Keywords: []string{"test", "tests", "testing", "spec", "specs", "coverage"}},
+	{Category: values.CategoryDocs, Keywords: []string{"doc", "docs", "documentation", "readme", "changelog", "typo"}},
+	{Category: values.CategoryRefactor, Keywords: []string{"refactor", "refactoring", "refactored", "cleanup", "clean up", "restructure", "rename", "simplify"}},
+	{Category: values.CategoryChore, Keywords: []string{"bump", "upgrade", "release", "merge", "dependency", "dependencies", "version"}},
+	{Category: values.CategoryFeature, Keywords: []string{"add", "adds", "added", "implement", "implements", "implemented", "introduce", "support", "feature", "new"}},
+}
+
+// CommitClassifier assigns a category to commit messages. The Conventional Commits type of
+// the first line decides first, then the type of a linked issue, then the first matching
+// rule; commits matching nothing are chores.
+type CommitClassifier struct {
+	rules        []classifierRule
+	issueTypes   map[string]string
+	issuePattern *regexp.Regexp
+}
+
+type classifierRule struct {
+	category values.CommitCategory
+	matcher  *regexp.Regexp
+}
+
+// NewCommitClassifier builds a classifier from validated rules, using DefaultClassificationRules
+// when there are none. issueTypes maps issue keys to their tracker types and issuePattern
+// extracts the keys from messages (DefaultIssueKeyPattern when nil); both may be empty.
+func NewCommitClassifier(rules []entities.ClassificationRule, issueTypes map[string]string, issuePattern *regexp.Regexp) *CommitClassifier {
+	if len(rules) == 0 {
+		rules = DefaultClassificationRules
+	}
+
+	classifier := &CommitClassifier{issueTypes: issueTypes, issuePattern: issuePattern}
+	for _, rule := range rules {
+		if len(rule.Keywords) > 0 {
+			quoted := make([]string, 0, len(rule.Keywords))
+			for _, keyword := range rule.Keywords {
+				quoted = append(quoted, regexp.QuoteMeta(strings.TrimSpace(keyword)))
+			}
+			classifier.rules = append(classifier.rules, classifierRule{
+				category: rule.Category,
+				matcher:  regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`),
+			})
+		}
+		if rule.Pattern != "" {
+			if matcher, err := regexp.Compile(rule.Pattern); err == nil {
+				classifier.rules = append(classifier.rules, classifierRule{category: rule.Category, matcher: matcher})
+			}
+		}
+	}
+	return classifier
+}
+
+// Classify returns the category of a commit message
+func (c *CommitClassifier) Classify(message string) values.CommitCategory {
+	firstLine := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	if match := conventionalCommitPattern.FindStringSubmatch(firstLine); match != nil {
+		if category, ok := conventionalCommitTypes[strings.ToLower(match[1])]; ok {
+			return category
+		}
+	}
+
+	if len(c.issueTypes) > 0 {
+		for _, key := range ExtractIssueKeys(message, c.issuePattern) {
+			if category, ok := issueTypeCategories[strings.ToLower(c.issueTypes[key])]; ok {
+				return category
+			}
+		}
+	}
+
+	for _, rule := range c.rules {
+		if rule.matcher.MatchString(message) {
+			return rule.category
+		}
+	}
+	return values.CategoryChore
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+func TestClassifyCommitMessages(t *testing.T) {
+	classifier := NewCommitClassifier(nil, nil, nil)
+
+	cases := map[string]values.CommitCategory{
+		"fix(parser): handle empty input":       values.CategoryBugfix,
+		"feat!: drop the v1 API":                values.CategoryFeature,
+		"refactor: extract scanner":             values.CategoryRefactor,
+		"docs: fix typo in README":              values.CategoryDocs,
+		"test(api): cover pagination":           values.CategoryTest,
+		"chore(deps): bump gin to 1.9.1":        values.CategoryChore,
+		"Fix crash when the repository is bare": values.CategoryBugfix,
+		"Add tests for the bug in ownership":    values.CategoryBugfix,
+		"Add coverage for ownership":            values.CategoryTest,
+		"Implement truck factor endpoint":       values.CategoryFeature,
+		"Prefix in middle fix: not a header":    values.CategoryBugfix,
+		"wip":                                   values.CategoryChore,
+	}
+	for message, expected := range cases {
+		if got := classifier.Classify(message); got != expected {
+			t.Errorf("Classify(%q) = %s, expected %s", message, got, expected)
+		}
+	}
+}
+
+func TestClassifyLinkedIssueTypes(t *testing.T) {
+	issueTypes := map[string]string{"PROJ-1": "Bug", "PROJ-2": "Story"}
+	classifier := NewCommitClassifier(nil, issueTypes, nil)
+
+	if got := classifier.Classify("PROJ-1 adjust the retry loop"); got != values.CategoryBugfix {
+		t.Errorf("expected the linked bug to classify as bugfix, got %s", got)
+	}
+	if got := classifier.Classify("PROJ-2 handle fixed-width columns"); got != values.CategoryFeature {
+		t.Errorf("expected the linked story to win over keywords, got %s", got)
+	}
+	if got := classifier.Classify("feat: PROJ-1 new retry loop"); got != values.CategoryFeature {
+		t.Errorf("expected the Conventional Commits prefix to win over the issue type, got %s", got)
+	}
+}
+
+func TestClassifyCustomRules(t *testing.T) {
+	rules := []entities.ClassificationRule{
+		{Category: values.CategoryBugfix, Pattern: `^Revert "`},
+		{Category: values.CategoryDocs, Keywords: []string{"manual"}},
+	}
+	classifier := NewCommitClassifier(rules, nil, nil)
+
+	if got := classifier.Classify(`Revert "Add cache"`); got != values.CategoryBugfix {
+		t.Errorf("expected the pattern rule to match, got %s", got)
+	}
+	if got := classifier.Classify("Update the MANUAL"); got != values.CategoryDocs {
+		t.Errorf("expected the keyword rule to match case-insensitively, got %s", got)
+	}
+	// Custom rules replace the defaults
+	if got := classifier.Classify("Fix crash"); got != values.CategoryChore {
+		t.Errorf("expected no default rules to apply, got %s", got)
+	}
+}
+
+func TestClassificationRuleValidate(t *testing.T) {
+	invalid := []entities.ClassificationRule{
+		{Category: "cleanup", Keywords: []string{"tidy"}},
+		{Category: values.CategoryBugfix},
+		{Category: values.CategoryBugfix, Keywords: []string{"a,b"}},
+		{Category: values.CategoryBugfix, Pattern: "("},
+	}
+	for _, rule := range invalid {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("expected rule %+v to be invalid", rule)
+		}
+	}
+
+	rule := entities.ClassificationRule{Category: values.CategoryTest, Keywords: []string{" spec ", ""}}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rule.Keywords) != 1 || rule.Keywords[0] != "spec" {
+		t.Errorf("expected keywords to be trimmed, got %q", rule.Keywords)
+	}
+}
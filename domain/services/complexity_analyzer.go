@@ -0,0 +1,58 @@
+package services
+
+import (
+	"strings"
+)
+
+// indentationWidth is the number of spaces treated as one logical indentation level
+const indentationWidth = 4
+
+// ComplexityMetrics holds indentation-based complexity figures for a single file revision
+type ComplexityMetrics struct {
+	LinesOfCode     int
+	TotalComplexity int
+	MeanComplexity  float64
+	MaxComplexity   int
+}
+
+// MeasureComplexity calculates whitespace complexity for the given file contents.
+// Each non-blank line contributes its logical indentation depth, which is a
+// language-neutral proxy for nesting (and therefore cognitive) complexity.
+func MeasureComplexity(content string) ComplexityMetrics {
+	var metrics ComplexityMetrics
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		depth := indentationDepth(line)
+		metrics.LinesOfCode++
+		metrics.TotalComplexity += depth
+		if depth > metrics.MaxComplexity {
+			metrics.MaxComplexity = depth
+		}
+	}
+
+	if metrics.LinesOfCode > 0 {
+		metrics.MeanComplexity = float64(metrics.TotalComplexity) / float64(metrics.LinesOfCode)
+	}
+
+	return metrics
+}
+
+// indentationDepth returns the logical indentation level of a line (tabs count as one level each)
+func indentationDepth(line string) int {
+	spaces, tabs := 0, 0
+	for _, char := range line {
+		switch char {
+		case ' ':
+			spaces++
+		case '\t':
+			tabs++
+		default:
+			return tabs + spaces/indentationWidth
+		}
+	}
+	return tabs + spaces/indentationWidth
+}
@@ -0,0 +1,27 @@
+package services
+
+import "testing"
+
+func TestMeasureComplexity(t *testing.T) {
+	content := "func main() {\n\tif ok {\n\t\treturn\n\t}\n\n    x := 1\n}\n"
+
+	metrics := MeasureComplexity(content)
+
+	if metrics.LinesOfCode != 6 {
+		t.Errorf("expected 6 lines of code, got %d", metrics.LinesOfCode)
+	}
+	// Depths: 0, 1, 2, 1, 1 (four spaces), 0
+	if metrics.TotalComplexity != 5 {
+		t.Errorf("expected total complexity 5, got %d", metrics.TotalComplexity)
+	}
+	if metrics.MaxComplexity != 2 {
+		t.Errorf("expected max complexity 2, got %d", metrics.MaxComplexity)
+	}
+}
+
+func TestMeasureComplexity_Empty(t *testing.T) {
+	metrics := MeasureComplexity("")
+	if metrics.LinesOfCode != 0 || metrics.MeanComplexity != 0 {
+		t.Errorf("expected zero metrics for empty content, got %+v", metrics)
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDetectCouplingCommunities(t *testing.T) {
+	// Two tightly coupled triangles joined by a single weak edge
+	edges := []CouplingEdge{
+		{FileA: "a.go", FileB: "b.go", Weight: 1},
+		{FileA: "b.go", FileB: "c.go", Weight: 1},
+		{FileA: "a.go", FileB: "c.go", Weight: 1},
+		{FileA: "x.go", FileB: "y.go", Weight: 0.9},
+		{FileA: "y.go", FileB: "z.go", Weight: 0.9},
+		{FileA: "x.go", FileB: "z.go", Weight: 0.9},
+		{FileA: "c.go", FileB: "x.go", Weight: 0.1},
+	}
+
+	communities := DetectCouplingCommunities(edges)
+
+	expected := [][]string{{"a.go", "b.go", "c.go"}, {"x.go", "y.go", "z.go"}}
+	if !reflect.DeepEqual(communities, expected) {
+		t.Fatalf("expected %v, got %v", expected, communities)
+	}
+
+	if q := CouplingModularity(edges, communities); q <= 0.3 {
+		t.Errorf("expected a clearly positive modularity for the split, got %f", q)
+	}
+	if q := CouplingModularity(edges, [][]string{{"a.go", "b.go", "c.go", "x.go", "y.go", "z.go"}}); math.Abs(q) > 1e-9 {
+		t.Errorf("expected zero modularity for a single community, got %f", q)
+	}
+}
+
+func TestDetectCouplingCommunitiesEmpty(t *testing.T) {
+	if communities := DetectCouplingCommunities(nil); len(communities) != 0 {
+		t.Errorf("expected no communities, got %v", communities)
+	}
+}
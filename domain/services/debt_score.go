@@ -0,0 +1,199 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+// The debt score rates the technical debt of a project at a point in its history between 0
+// and 100. It combines four components, each a share between 0 and 1:
+//
+//   - hotspot complexity: the mean indentation complexity (see MeasureComplexity) of the
+//     DebtHotspotFiles files changed most often in the trailing DebtWindowMonths, each capped
+//     at DebtComplexityCeiling and divided by it
+//   - coupling: the share of the files changed in the window that form a pair of at least
+//     DebtCouplingLevel coupling risk with another file over the window's commits
+//   - bus-factor risk: the share of files whose most active author's share of their commits
+//     since the beginning of history is of at least DebtOwnershipLevel ownership risk
+//   - stale code: the share of files not changed in the window
+//
+// Shares of files are taken over the files in the tree at the time of the snapshot. The score
+// is the weighted mean of the components multiplied by 100, so higher scores mean more debt.
+// The risk levels and the weights come from the project's risk policy.
+const (
+	DebtWindowMonths      = 12
+	DebtHotspotFiles      = 10
+	DebtComplexityCeiling = 4.0
+	DebtCouplingLevel     = values.RiskMedium
+	DebtOwnershipLevel    = values.RiskHigh
+)
+
+// DefaultDebtWeights are the weights of the default risk policy
+var DefaultDebtWeights = entities.DebtWeights{
+	HotspotComplexity: 0.3,
+	Coupling:          0.2,
+	BusFactorRisk:     0.25,
+	StaleCode:         0.25,
+}
+
+// DebtInputs are the measurements a debt score is computed from
+type DebtInputs struct {
+	Files            int
+	ActiveFiles      int
+	CoupledFiles     int
+	SingleOwnerFiles int
+	StaleFiles       int
+	// Hotspots lists the most changed files of the window, most changed first
+	Hotspots []string
+	// HotspotComplexity holds the mean complexity of each hotspot that could be measured
+	HotspotComplexity []float64
+}
+
+// DebtScore is a debt score with its components
+type DebtScore struct {
+	Score             float64
+	HotspotComplexity float64
+	Coupling          float64
+	BusFactorRisk     float64
+	StaleCode         float64
+}
+
+// ScoreDebt computes the debt score of the given measurements with the weights of the
+// assessor's policy. Components without data (no files, no active files or no measured
+// hotspots) count as 0.
+func ScoreDebt(inputs DebtInputs, assessor *RiskAssessor) DebtScore {
+	var score DebtScore
+	weights := assessor.DebtWeights()
+
+	if len(inputs.HotspotComplexity) > 0 {
+		total := 0.0
+		for _, complexity := range inputs.HotspotComplexity {
+			total += math.Min(complexity, DebtComplexityCeiling) / DebtComplexityCeiling
+		}
+		score.HotspotComplexity = total / float64(len(inputs.HotspotComplexity))
+	}
+	if inputs.ActiveFiles > 0 {
+		score.Coupling = float64(inputs.CoupledFiles) / float64(inputs.ActiveFiles)
+	}
+	if inputs.Files > 0 {
+		score.BusFactorRisk = float64(inputs.SingleOwnerFiles) / float64(inputs.Files)
+		score.StaleCode = float64(inputs.StaleFiles) / float64(inputs.Files)
+	}
+
+	totalWeight := weights.HotspotComplexity + weights.Coupling + weights.BusFactorRisk + weights.StaleCode
+	if totalWeight > 0 {
+		weighted := weights.HotspotComplexity*score.HotspotComplexity +
+			weights.Coupling*score.Coupling +
+			weights.BusFactorRisk*score.BusFactorRisk +
+			weights.StaleCode*score.StaleCode
+		score.Score = 100 * weighted / totalWeight
+	}
+
+	return score
+}
+
+// DebtTracker replays a project's history in chronological order and measures the debt
+// inputs at points along it
+type DebtTracker struct {
+	lastModified map[string]time.Time
+	authors      map[string]map[string]int
+	timestamps   []time.Time
+	commits      []PeriodCommit
+}
+
+// NewDebtTracker creates an empty debt tracker
+func NewDebtTracker() *DebtTracker {
+	return &DebtTracker{
+		lastModified: make(map[string]time.Time),
+		authors:      make(map[string]map[string]int),
+	}
+}
+
+// Add records a commit; commits must be added in chronological order
+func (t *DebtTracker) Add(timestamp time.Time, commit PeriodCommit) {
+	t.timestamps = append(t.timestamps, timestamp)
+	t.commits = append(t.commits, commit)
+
+	seen := make(map[string]bool, len(commit.Changes))
+	for _, change := range commit.Changes {
+		if seen[change.FilePath] {
+			continue
+		}
+		seen[change.FilePath] = true
+		t.lastModified[change.FilePath] = timestamp
+		authors := t.authors[change.FilePath]
+		if authors == nil {
+			authors = make(map[string]int)
+			t.authors[change.FilePath] = authors
+		}
+		authors[commit.Author]++
+	}
+}
+
+// Measure returns the debt inputs, without hotspot complexity, of the history added so far
+// for the given files of the tree, with the activity window ending at end (exclusive) and
+// coupling and ownership rated by the assessor
+func (t *DebtTracker) Measure(files []string, end time.Time, assessor *RiskAssessor) DebtInputs {
+	windowStart := end.AddDate(0, -DebtWindowMonths, 0)
+	first := sort.Search(len(t.timestamps), func(i int) bool { return !t.timestamps[i].Before(windowStart) })
+	last := sort.Search(len(t.timestamps), func(i int) bool { return !t.timestamps[i].Before(end) })
+	activity := SummarizePeriod(t.commits[first:last])
+
+	coupled := make(map[string]bool)
+	for pair, shared := range activity.Pairs {
+		if assessor.CouplingLevel(shared, activity.CouplingScore(pair)).AtLeast(DebtCouplingLevel) {
+			coupled[pair.A] = true
+			coupled[pair.B] = true
+		}
+	}
+
+	inputs := DebtInputs{Files: len(files)}
+	active := make([]string, 0)
+	for _, file := range files {
+		if activity.Files[file] != nil {
+			inputs.ActiveFiles++
+			active = append(active, file)
+			if coupled[file] {
+				inputs.CoupledFiles++
+			}
+		} else {
+			inputs.StaleFiles++
+		}
+
+		total, top := 0, 0
+		for _, commits := range t.authors[file] {
+			total += commits
+			top = max(top, commits)
+		}
+		if total > 0 && assessor.OwnershipLevel(100*float64(top)/float64(total)).AtLeast(DebtOwnershipLevel) {
+			inputs.SingleOwnerFiles++
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		a, b := activity.Files[active[i]], activity.Files[active[j]]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		if a.Churn != b.Churn {
+			return a.Churn > b.Churn
+		}
+		return active[i] < active[j]
+	})
+	if len(active) > DebtHotspotFiles {
+		active = active[:DebtHotspotFiles]
+	}
+	inputs.Hotspots = active
+
+	return inputs
+}
+
+// LastModified returns when a file was last changed in the history added so far
+func (t *DebtTracker) LastModified(file string) (time.Time, bool) {
+	modified, ok := t.lastModified[file]
+	return modified, ok
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"codeecho/domain/entities"
+)
+
+func TestScoreDebt(t *testing.T) {
+	inputs := DebtInputs{
+		Files:             10,
+		ActiveFiles:       4,
+		CoupledFiles:      2,
+		SingleOwnerFiles:  5,
+		StaleFiles:        6,
+		HotspotComplexity: []float64{2, 8},
+	}
+
+	assessor := NewRiskAssessor(DefaultRiskPolicy)
+	score := ScoreDebt(inputs, assessor)
+	if score.HotspotComplexity != 0.75 {
+		t.Errorf("expected hotspot complexity 0.75 with the second file capped, got %v", score.HotspotComplexity)
+	}
+	if score.Coupling != 0.5 || score.BusFactorRisk != 0.5 || score.StaleCode != 0.6 {
+		t.Errorf("unexpected components %+v", score)
+	}
+	expected := 100 * (0.3*0.75 + 0.2*0.5 + 0.25*0.5 + 0.25*0.6)
+	if math.Abs(score.Score-expected) > 1e-9 {
+		t.Errorf("expected score %v, got %v", expected, score.Score)
+	}
+
+	if empty := ScoreDebt(DebtInputs{}, assessor); empty.Score != 0 {
+		t.Errorf("expected an empty project to score 0, got %v", empty.Score)
+	}
+
+	staleOnlyPolicy := DefaultRiskPolicy
+	staleOnlyPolicy.DebtWeights = entities.DebtWeights{StaleCode: 2}
+	staleOnly := ScoreDebt(inputs, NewRiskAssessor(staleOnlyPolicy))
+	if math.Abs(staleOnly.Score-60) > 1e-9 {
+		t.Errorf("expected weights to be normalised, got %v", staleOnly.Score)
+	}
+}
+
+func TestDebtTrackerMeasure(t *testing.T) {
+	tracker := NewDebtTracker()
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	commit := func(author string, files ...string) PeriodCommit {
+		changes := make([]PeriodChange, 0, len(files))
+		for _, file := range files {
+			changes = append(changes, PeriodChange{FilePath: file, LinesAdded: 1})
+		}
+		return PeriodCommit{Author: author, Changes: changes}
+	}
+
+	// old.go is only changed before the window ending in March 2025
+	tracker.Add(day(2023, 1, 1), commit("ann", "old.go", "a.go"))
+	tracker.Add(day(2024, 6, 1), commit("bob", "a.go", "b.go"))
+	tracker.Add(day(2024, 7, 1), commit("ann", "a.go", "b.go"))
+	tracker.Add(day(2024, 8, 1), commit("bob", "a.go", "b.go", "c.go"))
+	tracker.Add(day(2025, 2, 1), commit("ann", "a.go"))
+
+	files := []string{"a.go", "b.go", "c.go", "old.go", "untracked.go"}
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	assessor := NewRiskAssessor(DefaultRiskPolicy)
+	inputs := tracker.Measure(files, march, assessor)
+
+	if inputs.Files != 5 || inputs.ActiveFiles != 3 || inputs.StaleFiles != 2 {
+		t.Errorf("unexpected file counts %+v", inputs)
+	}
+	// a.go and b.go share all 3 of b.go's commits
+	if inputs.CoupledFiles != 2 {
+		t.Errorf("expected 2 coupled files, got %d", inputs.CoupledFiles)
+	}
+	// a.go has a 3-2 split and b.go a 2-1 split; c.go and old.go have a single author
+	if inputs.SingleOwnerFiles != 2 {
+		t.Errorf("expected 2 single-owner files, got %d", inputs.SingleOwnerFiles)
+	}
+
+	// The policy's thresholds decide: with 4 shared commits required nothing is coupled, and a
+	// 60% share is high ownership risk
+	strict := DefaultRiskPolicy
+	strict.CouplingMinSharedCommits = 4
+	strict.Ownership = entities.RiskThresholds{Medium: 40, High: 55}
+	if strictInputs := tracker.Measure(files, march, NewRiskAssessor(strict)); strictInputs.CoupledFiles != 0 || strictInputs.SingleOwnerFiles != 4 {
+		t.Errorf("expected no coupled and 4 single-owner files under the strict policy, got %+v", strictInputs)
+	}
+	if len(inputs.Hotspots) != 3 || inputs.Hotspots[0] != "a.go" || inputs.Hotspots[1] != "b.go" || inputs.Hotspots[2] != "c.go" {
+		t.Errorf("unexpected hotspots %v", inputs.Hotspots)
+	}
+
+	tracker.Add(day(2025, 3, 1), commit("bob", "c.go", "old.go"))
+	inputs = tracker.Measure(files, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), assessor)
+	if inputs.SingleOwnerFiles != 1 || inputs.StaleFiles != 1 {
+		t.Errorf("expected old.go to be shared and active again, got %+v", inputs)
+	}
+	if modified, ok := tracker.LastModified("old.go"); !ok || !modified.Equal(day(2025, 3, 1)) {
+		t.Errorf("unexpected last modification %v", modified)
+	}
+}
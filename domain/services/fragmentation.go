@@ -0,0 +1,21 @@
+package services
+
+// FractalValue measures how fragmented the development of a file is among its authors:
+// 1 - Σ (commits_a / total)². It is 0 when a single author made every commit and
+// approaches 1 as work spreads evenly over many authors (D'Ambros et al.).
+func FractalValue(commitsPerAuthor []int) float64 {
+	total := 0
+	for _, commits := range commitsPerAuthor {
+		total += commits
+	}
+	if total == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, commits := range commitsPerAuthor {
+		share := float64(commits) / float64(total)
+		sum += share * share
+	}
+	return 1 - sum
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFractalValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		commits  []int
+		expected float64
+	}{
+		{"no commits", nil, 0},
+		{"single author", []int{12}, 0},
+		{"two equal authors", []int{5, 5}, 0.5},
+		{"dominant author", []int{9, 1}, 0.18},
+		{"four equal authors", []int{2, 2, 2, 2}, 0.75},
+	}
+
+	for _, tt := range tests {
+		if got := FractalValue(tt.commits); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected %f, got %f", tt.name, tt.expected, got)
+		}
+	}
+}
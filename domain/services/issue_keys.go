@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultIssuePatterns extract Jira-style keys such as PROJ-123 and GitHub-style references
+// such as #456 for projects without patterns of their own
+var DefaultIssuePatterns = []string{
+	DefaultIssueKeyPattern.String(),
+	`\B#[0-9]+\b`,
+}
+
+// CompileIssuePatterns combines a project's issue key patterns into one expression for
+// ExtractIssueKeys, using DefaultIssuePatterns when there are none
+func CompileIssuePatterns(patterns []string) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultIssuePatterns
+	}
+
+	alternatives := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("issue pattern must not be empty")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid issue pattern %q: %w", pattern, err)
+		}
+		alternatives = append(alternatives, "(?:"+pattern+")")
+	}
+	return regexp.Compile(strings.Join(alternatives, "|"))
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompileIssuePatternsDefaults(t *testing.T) {
+	pattern, err := CompileIssuePatterns(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := ExtractIssueKeys("PROJ-12: fix #456, see abc#7 and PROJ-12 again (#8)", pattern)
+	expected := []string{"PROJ-12", "#456", "#8"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+}
+
+func TestCompileIssuePatternsCustom(t *testing.T) {
+	pattern, err := CompileIssuePatterns([]string{`(?i)\bbug-[0-9]+\b`, `\bGH[0-9]+\b`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := ExtractIssueKeys("bug-3 and GH12, not PROJ-1 or #4", pattern)
+	expected := []string{"BUG-3", "GH12"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	for _, invalid := range [][]string{{"("}, {" "}} {
+		if _, err := CompileIssuePatterns(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"strings"
+
+	"codeecho/domain/values"
+)
+
+// LineCounts classifies the lines of a single file
+type LineCounts struct {
+	Code    int
+	Comment int
+	Blank   int
+}
+
+// Total returns the total number of lines
+func (lc LineCounts) Total() int {
+	return lc.Code + lc.Comment + lc.Blank
+}
+
+// IsBinaryContent reports whether content looks like a binary blob rather than text
+func IsBinaryContent(content string) bool {
+	return strings.Contains(content, "\x00")
+}
+
+// CountLines classifies each line of content as code, comment or blank using the
+// comment syntax of the given language. When the language is unknown (nil) every
+// non-blank line is counted as code.
+func CountLines(content string, lang *values.Language) LineCounts {
+	var counts LineCounts
+	if content == "" {
+		return counts
+	}
+
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			counts.Blank++
+		case lang == nil:
+			counts.Code++
+		case inBlock:
+			counts.Comment++
+			if end := strings.Index(trimmed, lang.BlockEnd); end >= 0 {
+				inBlock = opensBlock(trimmed[end+len(lang.BlockEnd):], lang)
+			}
+		case hasAnyPrefix(trimmed, lang.LineComments):
+			counts.Comment++
+		case lang.HasBlockComments() && strings.HasPrefix(trimmed, lang.BlockStart):
+			counts.Comment++
+			inBlock = opensBlock(trimmed, lang)
+		default:
+			counts.Code++
+			// A block comment opened after code on the same line continues onto the next lines
+			if lang.HasBlockComments() {
+				inBlock = opensBlock(trimmed, lang)
+			}
+		}
+	}
+
+	return counts
+}
+
+// opensBlock reports whether a block comment is left open at the end of line. Start and
+// end delimiters are matched in pairs from the left, so that a block opened and closed
+// on the same line (such as a one-line Python """docstring""") does not continue.
+func opensBlock(line string, lang *values.Language) bool {
+	for {
+		start := strings.Index(line, lang.BlockStart)
+		if start < 0 {
+			return false
+		}
+		line = line[start+len(lang.BlockStart):]
+		end := strings.Index(line, lang.BlockEnd)
+		if end < 0 {
+			return true
+		}
+		line = line[end+len(lang.BlockEnd):]
+	}
+}
+
+// hasAnyPrefix reports whether s starts with any of the given prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"codeecho/domain/values"
+)
+
+func TestCountLines_Go(t *testing.T) {
+	content := "package main\n\n// comment\n/* block\n   still block */\nfunc main() {} /* trailing\n*/\n"
+
+	counts := CountLines(content, values.LanguageForPath("main.go"))
+
+	if counts.Code != 2 || counts.Comment != 4 || counts.Blank != 1 {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+}
+
+func TestCountLines_UnknownLanguage(t *testing.T) {
+	counts := CountLines("a\n\n# not a comment\n", nil)
+
+	if counts.Code != 2 || counts.Comment != 0 || counts.Blank != 1 {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+}
+
+func TestCountLines_PythonDocstrings(t *testing.T) {
+	python := values.LanguageForPath("app.py")
+
+	// A docstring opened and closed on one line does not swallow the following code
+	counts := CountLines("x = \"\"\"one line\"\"\"\ny = 1\nz = 2", python)
+	if counts.Code != 3 || counts.Comment != 0 {
+		t.Errorf("unexpected counts for a one-line string: %+v", counts)
+	}
+
+	counts = CountLines("\"\"\"Module docstring\"\"\"\n\"\"\"\nLonger\ndocstring\n\"\"\"\nx = 1 # note\n", python)
+	if counts.Code != 1 || counts.Comment != 5 {
+		t.Errorf("unexpected counts for docstrings: %+v", counts)
+	}
+
+	// A docstring closed and reopened on one line stays open
+	counts = CountLines("\"\"\"a\n\"\"\" + \"\"\"b\nc\n\"\"\"\ny = 2\n", python)
+	if counts.Code != 1 || counts.Comment != 4 {
+		t.Errorf("unexpected counts for a reopened docstring: %+v", counts)
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+// Defaults of quality gate rules whose limits are not configured
+const (
+	DefaultGateHotspotTop       = 10
+	DefaultGateMaxGrowthPercent = 20.0
+	DefaultGateCouplingDepth    = 1
+)
+
+// DefaultGateConfig is evaluated when no rules are configured: the top hotspots must not grow
+// by more than DefaultGateMaxGrowthPercent and no top-level directories may become coupled
+// above the risk policy's high coupling threshold. Bus factor rules need a path and are not
+// part of the defaults.
+var DefaultGateConfig = entities.GateConfig{
+	Rules: []entities.GateRule{
+		{Name: "hotspot_growth", Type: values.GateHotspotGrowth},
+		{Name: "coupling", Type: values.GateCoupling, Level: values.CouplingByDirectory},
+	},
+}
+
+// ResolveGateRule fills the limits a rule leaves at zero: the bus factor and coupling limits
+// come from the risk policy's high thresholds, the others from the gate defaults
+func ResolveGateRule(rule entities.GateRule, policy entities.RiskPolicy) entities.GateRule {
+	switch rule.Type {
+	case values.GateBusFactor:
+		if rule.MaxBusFactor == 0 {
+			rule.MaxBusFactor = max(int(policy.BusFactor.High), 1)
+		}
+	case values.GateHotspotGrowth:
+		if rule.Top == 0 {
+			rule.Top = DefaultGateHotspotTop
+		}
+		if rule.MaxGrowthPercent == 0 {
+			rule.MaxGrowthPercent = DefaultGateMaxGrowthPercent
+		}
+	case values.GateCoupling:
+		if rule.Level == "" {
+			rule.Level = values.CouplingByDirectory
+		}
+		if rule.Depth == 0 {
+			rule.Depth = DefaultGateCouplingDepth
+		}
+		if rule.MinScore == 0 {
+			rule.MinScore = policy.Coupling.High
+		}
+		if rule.MinSharedCommits == 0 {
+			rule.MinSharedCommits = policy.CouplingMinSharedCommits
+		}
+	}
+	return rule
+}
+
+// GateMeasurement holds what one rule measures of a project at one point in time
+type GateMeasurement struct {
+	// BusFactors maps the files of a bus_factor rule to their bus factor
+	BusFactors map[string]int
+	// HotspotScores maps the top hotspots of a hotspot_growth rule to their change count
+	HotspotScores map[string]int
+	// Couplings maps the component pairs of a coupling rule to their coupling score
+	Couplings map[FilePair]float64
+}
+
+// GateViolation is a file, component pair or hotspot set that fails a rule
+type GateViolation struct {
+	Subject string
+	Message string
+}
+
+// GateResult is the outcome of one rule
+type GateResult struct {
+	// Checked is the number of files, hotspots or component pairs the rule looked at
+	Checked    int
+	Violations []GateViolation
+}
+
+// Passed reports whether the rule found no violations
+func (r GateResult) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// EvaluateGateRule compares the current measurement of a resolved rule with its baseline. A
+// nil baseline (the project's first analysis) has nothing to compare against and passes.
+func EvaluateGateRule(rule entities.GateRule, baseline *GateMeasurement, current GateMeasurement) GateResult {
+	var result GateResult
+	switch rule.Type {
+	case values.GateBusFactor:
+		result.Checked = len(current.BusFactors)
+		if baseline == nil {
+			break
+		}
+		for file, busFactor := range current.BusFactors {
+			if busFactor == 0 || busFactor > rule.MaxBusFactor {
+				continue
+			}
+			before, existed := baseline.BusFactors[file]
+			switch {
+			case !existed:
+				result.Violations = append(result.Violations, GateViolation{
+					Subject: file,
+					Message: fmt.Sprintf("new file with bus factor %d", busFactor),
+				})
+			case before == 0 || before > rule.MaxBusFactor:
+				result.Violations = append(result.Violations, GateViolation{
+					Subject: file,
+					Message: fmt.Sprintf("bus factor dropped from %d to %d", before, busFactor),
+				})
+			}
+		}
+
+	case values.GateHotspotGrowth:
+		result.Checked = len(current.HotspotScores)
+		if baseline == nil {
+			break
+		}
+		before, after := 0, 0
+		for _, score := range baseline.HotspotScores {
+			before += score
+		}
+		for _, score := range current.HotspotScores {
+			after += score
+		}
+		if before == 0 {
+			break
+		}
+		growth := 100 * float64(after-before) / float64(before)
+		if growth > rule.MaxGrowthPercent {
+			result.Violations = append(result.Violations, GateViolation{
+				Subject: fmt.Sprintf("top %d hotspots", rule.Top),
+				Message: fmt.Sprintf("change count grew by %.1f%% (%d to %d), above %.1f%%", growth, before, after, rule.MaxGrowthPercent),
+			})
+		}
+
+	case values.GateCoupling:
+		result.Checked = len(current.Couplings)
+		if baseline == nil {
+			break
+		}
+		for pair, score := range current.Couplings {
+			if score <= rule.MinScore || baseline.Couplings[pair] > rule.MinScore {
+				continue
+			}
+			result.Violations = append(result.Violations, GateViolation{
+				Subject: pair.A + " <-> " + pair.B,
+				Message: fmt.Sprintf("new coupling of %.2f, above %.2f", score, rule.MinScore),
+			})
+		}
+	}
+
+	sort.Slice(result.Violations, func(i, j int) bool {
+		return result.Violations[i].Subject < result.Violations[j].Subject
+	})
+	return result
+}
+
+// BusFactor returns the number of main owners whose ownership shares, in percent and sorted in
+// descending order, cover at least half of a file; 0 for a file without owners
+func BusFactor(shares []float64) int {
+	cumulative := 0.0
+	for i, share := range shares {
+		cumulative += share
+		if cumulative >= 50.0 {
+			return i + 1
+		}
+	}
+	return len(shares)
+}
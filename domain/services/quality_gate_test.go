@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+func TestResolveGateRule(t *testing.T) {
+	coupling := ResolveGateRule(entities.GateRule{Type: values.GateCoupling}, DefaultRiskPolicy)
+	if coupling.Level != values.CouplingByDirectory || coupling.Depth != 1 || coupling.MinScore != 0.7 || coupling.MinSharedCommits != 3 {
+		t.Errorf("unexpected coupling defaults %+v", coupling)
+	}
+	busFactor := ResolveGateRule(entities.GateRule{Type: values.GateBusFactor, Path: "core/"}, DefaultRiskPolicy)
+	if busFactor.MaxBusFactor != 1 {
+		t.Errorf("expected the policy's high bus factor threshold, got %d", busFactor.MaxBusFactor)
+	}
+	hotspots := ResolveGateRule(entities.GateRule{Type: values.GateHotspotGrowth, MaxGrowthPercent: 5}, DefaultRiskPolicy)
+	if hotspots.Top != DefaultGateHotspotTop || hotspots.MaxGrowthPercent != 5 {
+		t.Errorf("expected configured limits to be kept, got %+v", hotspots)
+	}
+}
+
+func TestEvaluateGateRuleBusFactor(t *testing.T) {
+	rule := entities.GateRule{Type: values.GateBusFactor, MaxBusFactor: 1}
+	baseline := &GateMeasurement{BusFactors: map[string]int{"core/a.go": 1, "core/b.go": 2}}
+	current := GateMeasurement{BusFactors: map[string]int{"core/a.go": 1, "core/b.go": 1, "core/c.go": 1, "core/d.go": 2}}
+
+	result := EvaluateGateRule(rule, baseline, current)
+	if len(result.Violations) != 2 || result.Violations[0].Subject != "core/b.go" || result.Violations[1].Subject != "core/c.go" {
+		t.Fatalf("expected the degraded and the new file to fail, got %+v", result.Violations)
+	}
+	if result.Checked != 4 {
+		t.Errorf("expected 4 checked files, got %d", result.Checked)
+	}
+
+	if first := EvaluateGateRule(rule, nil, current); !first.Passed() {
+		t.Errorf("expected rules without a baseline to pass, got %+v", first.Violations)
+	}
+}
+
+func TestEvaluateGateRuleHotspotGrowth(t *testing.T) {
+	rule := entities.GateRule{Type: values.GateHotspotGrowth, Top: 2, MaxGrowthPercent: 20}
+	baseline := &GateMeasurement{HotspotScores: map[string]int{"a.go": 10, "b.go": 10}}
+
+	within := EvaluateGateRule(rule, baseline, GateMeasurement{HotspotScores: map[string]int{"a.go": 12, "b.go": 12}})
+	if !within.Passed() {
+		t.Errorf("expected 20%% growth to pass, got %+v", within.Violations)
+	}
+	above := EvaluateGateRule(rule, baseline, GateMeasurement{HotspotScores: map[string]int{"a.go": 15, "c.go": 10}})
+	if above.Passed() {
+		t.Error("expected 25% growth to fail")
+	}
+}
+
+func TestEvaluateGateRuleCoupling(t *testing.T) {
+	rule := entities.GateRule{Type: values.GateCoupling, MinScore: 0.7}
+	api, core, web := FilePair{A: "api", B: "core"}, FilePair{A: "core", B: "web"}, FilePair{A: "api", B: "web"}
+	baseline := &GateMeasurement{Couplings: map[FilePair]float64{api: 0.8, core: 0.6}}
+	current := GateMeasurement{Couplings: map[FilePair]float64{api: 0.9, core: 0.75, web: 0.7}}
+
+	result := EvaluateGateRule(rule, baseline, current)
+	if len(result.Violations) != 1 || result.Violations[0].Subject != "core <-> web" {
+		t.Errorf("expected only the newly coupled pair to fail, got %+v", result.Violations)
+	}
+}
+
+func TestBusFactor(t *testing.T) {
+	cases := []struct {
+		shares   []float64
+		expected int
+	}{
+		{nil, 0},
+		{[]float64{80, 20}, 1},
+		{[]float64{40, 30, 30}, 2},
+		{[]float64{20, 20}, 2},
+	}
+	for _, tc := range cases {
+		if got := BusFactor(tc.shares); got != tc.expected {
+			t.Errorf("BusFactor(%v) = %d, expected %d", tc.shares, got, tc.expected)
+		}
+	}
+}
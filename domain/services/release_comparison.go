@@ -0,0 +1,118 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"codeecho/domain/entities"
+)
+
+// maxPeriodPairFiles is the largest number of files a commit may touch for its file pairs to
+// count towards a period's coupling, as for the pre-computed coupling
+const maxPeriodPairFiles = 200
+
+// PeriodCommit is a commit of a development period with the files it changed
+type PeriodCommit struct {
+	Author  string
+	Changes []PeriodChange
+}
+
+// PeriodChange is one file changed by a PeriodCommit
+type PeriodChange struct {
+	FilePath     string
+	LinesAdded   int
+	LinesDeleted int
+}
+
+// FileActivity is the number of commits touching a file in a period and its churn (lines
+// added plus lines deleted)
+type FileActivity struct {
+	Commits int
+	Churn   int
+}
+
+// FilePair is an unordered pair of files, stored with A < B
+type FilePair struct {
+	A string
+	B string
+}
+
+// PeriodActivity summarises the commits of a development period per file, per author and
+// per co-changed file pair
+type PeriodActivity struct {
+	Commits int
+	Files   map[string]*FileActivity
+	Authors map[string]int
+	Pairs   map[FilePair]int
+}
+
+// SummarizePeriod aggregates the commits of a period. Commits touching more than
+// maxPeriodPairFiles files count for their files but not for file pairs.
+func SummarizePeriod(commits []PeriodCommit) *PeriodActivity {
+	activity := &PeriodActivity{
+		Files:   make(map[string]*FileActivity),
+		Authors: make(map[string]int),
+		Pairs:   make(map[FilePair]int),
+	}
+
+	for _, commit := range commits {
+		activity.Commits++
+		activity.Authors[commit.Author]++
+
+		files := make([]string, 0, len(commit.Changes))
+		seen := make(map[string]bool, len(commit.Changes))
+		for _, change := range commit.Changes {
+			file := activity.Files[change.FilePath]
+			if file == nil {
+				file = &FileActivity{}
+				activity.Files[change.FilePath] = file
+			}
+			file.Churn += change.LinesAdded + change.LinesDeleted
+			if !seen[change.FilePath] {
+				seen[change.FilePath] = true
+				file.Commits++
+				files = append(files, change.FilePath)
+			}
+		}
+
+		if len(files) > maxPeriodPairFiles {
+			continue
+		}
+		sort.Strings(files)
+		for i, a := range files {
+			for _, b := range files[i+1:] {
+				activity.Pairs[FilePair{A: a, B: b}]++
+			}
+		}
+	}
+
+	return activity
+}
+
+// CouplingScore returns the shared commits of a pair over the commits of its less active file
+func (p *PeriodActivity) CouplingScore(pair FilePair) float64 {
+	a, b := p.Files[pair.A], p.Files[pair.B]
+	if a == nil || b == nil {
+		return 0
+	}
+	return float64(p.Pairs[pair]) / float64(min(a.Commits, b.Commits))
+}
+
+// ReleasePeriod finds a release by name among releases ordered by commit date and returns it
+// with the start of its development period: the commit date of the closest earlier release,
+// or the zero time for the first release. The period covers commits after the start up to
+// and including the release's commit date.
+func ReleasePeriod(releases []entities.Release, name string) (entities.Release, time.Time, bool) {
+	for i, release := range releases {
+		if release.Name != name {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if releases[j].CommitDate.Before(release.CommitDate) {
+				return release, releases[j].CommitDate, true
+			}
+		}
+		return release, time.Time{}, true
+	}
+	return entities.Release{}, time.Time{}, false
+}
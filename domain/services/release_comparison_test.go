@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"codeecho/domain/entities"
+)
+
+func TestSummarizePeriod(t *testing.T) {
+	activity := SummarizePeriod([]PeriodCommit{
+		{Author: "alice", Changes: []PeriodChange{{"a.go", 10, 2}, {"b.go", 1, 1}}},
+		{Author: "bob", Changes: []PeriodChange{{"b.go", 3, 0}, {"a.go", 0, 4}}},
+		{Author: "alice", Changes: []PeriodChange{{"c.go", 5, 0}, {"b.go", 1, 0}}},
+	})
+
+	if activity.Commits != 3 || activity.Authors["alice"] != 2 || activity.Authors["bob"] != 1 {
+		t.Errorf("unexpected commit or author counts: %d %v", activity.Commits, activity.Authors)
+	}
+	if a := activity.Files["a.go"]; a.Commits != 2 || a.Churn != 16 {
+		t.Errorf("unexpected activity for a.go: %+v", *a)
+	}
+	if b := activity.Files["b.go"]; b.Commits != 3 || b.Churn != 6 {
+		t.Errorf("unexpected activity for b.go: %+v", *b)
+	}
+
+	pair := FilePair{A: "a.go", B: "b.go"}
+	if activity.Pairs[pair] != 2 {
+		t.Errorf("expected a.go and b.go to share 2 commits, got %d", activity.Pairs[pair])
+	}
+	if score := activity.CouplingScore(pair); score != 1 {
+		t.Errorf("expected a coupling score of 1, got %f", score)
+	}
+	if score := activity.CouplingScore(FilePair{A: "a.go", B: "c.go"}); score != 0 {
+		t.Errorf("expected files never changed together to score 0, got %f", score)
+	}
+}
+
+func TestSummarizePeriodSkipsLargeCommitPairs(t *testing.T) {
+	changes := make([]PeriodChange, maxPeriodPairFiles+1)
+	for i := range changes {
+		changes[i] = PeriodChange{FilePath: fmt.Sprintf("f%d.go", i)}
+	}
+	activity := SummarizePeriod([]PeriodCommit{{Author: "bot", Changes: changes}})
+
+	if len(activity.Files) != len(changes) || len(activity.Pairs) != 0 {
+		t.Errorf("expected %d files and no pairs, got %d files and %d pairs", len(changes), len(activity.Files), len(activity.Pairs))
+	}
+}
+
+func TestReleasePeriod(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	releases := []entities.Release{
+		{Name: "v1.0", CommitDate: day(1)},
+		{Name: "v1.1", CommitDate: day(10)},
+		{Name: "v1.1-final", CommitDate: day(10)},
+		{Name: "v1.2", CommitDate: day(20)},
+	}
+
+	if _, start, ok := ReleasePeriod(releases, "v1.0"); !ok || !start.IsZero() {
+		t.Errorf("expected the first release to start at the beginning of history, got %v", start)
+	}
+	if _, start, ok := ReleasePeriod(releases, "v1.2"); !ok || !start.Equal(day(10)) {
+		t.Errorf("expected v1.2 to start at v1.1, got %v", start)
+	}
+	if _, start, ok := ReleasePeriod(releases, "v1.1-final"); !ok || !start.Equal(day(1)) {
+		t.Errorf("expected a tag on the same commit to share its period, got %v", start)
+	}
+	if _, _, ok := ReleasePeriod(releases, "v2.0"); ok {
+		t.Error("expected an unknown release not to be found")
+	}
+}
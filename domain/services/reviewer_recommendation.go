@@ -0,0 +1,172 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Defaults of reviewer recommendations whose options are not set
+const (
+	// DefaultReviewerCount is the number of reviewers picked
+	DefaultReviewerCount = 2
+	// DefaultExpertiseHalfLifeDays is the age after which an author's expertise with a file counts half
+	DefaultExpertiseHalfLifeDays = 180
+	// DefaultReviewLoadPenalty is how much each open review lowers a candidate's score
+	DefaultReviewLoadPenalty = 0.5
+)
+
+// FileExpertise holds one author's ownership of one file
+type FileExpertise struct {
+	FilePath string
+	Author   string
+	// Share is the author's ownership of the file in percent
+	Share float64
+	// LastTouched is the author's last change to the file
+	LastTouched time.Time
+}
+
+// ReviewerOptions tune how reviewers are scored and picked
+type ReviewerOptions struct {
+	Count        int
+	HalfLifeDays int
+	// LoadPenalty divides a candidate's score by 1 + LoadPenalty × their open reviews
+	LoadPenalty float64
+	// Load is the number of open reviews per author
+	Load map[string]int
+}
+
+// ReviewerCandidate is an author ranked as reviewer of a set of files
+type ReviewerCandidate struct {
+	Author string
+	// Expertise is the sum over the files of the author's ownership share (0-1), halved for
+	// every HalfLifeDays since they last touched the file
+	Expertise float64
+	// Score is the expertise lowered by the author's review load
+	Score float64
+	Load  int
+	// Files are the files the author has expertise in, by decreasing expertise
+	Files       []string
+	LastTouched time.Time
+	Selected    bool
+}
+
+// DecayedExpertise returns an ownership share in percent as a 0-1 expertise, halved for every
+// halfLifeDays between lastTouched and now
+func DecayedExpertise(share float64, lastTouched, now time.Time, halfLifeDays int) float64 {
+	if halfLifeDays <= 0 {
+		halfLifeDays = DefaultExpertiseHalfLifeDays
+	}
+	age := float64(AgeInDays(lastTouched, now))
+	return share / 100 * math.Pow(0.5, age/float64(halfLifeDays))
+}
+
+// RecommendReviewers ranks the authors of the given expertise as reviewers. Reviewers are picked
+// greedily: each pick is the candidate with the highest load-adjusted expertise in the files
+// the reviewers picked so far know least about, so that the same expert is not picked for
+// files already covered and busy reviewers give way to others. The picked reviewers come first
+// in pick order, followed by the other candidates by score.
+func RecommendReviewers(expertise []FileExpertise, now time.Time, opts ReviewerOptions) []ReviewerCandidate {
+	if opts.Count <= 0 {
+		opts.Count = DefaultReviewerCount
+	}
+
+	type fileScore struct {
+		file  string
+		score float64
+	}
+	byAuthor := make(map[string][]fileScore)
+	candidates := make(map[string]*ReviewerCandidate)
+	best := make(map[string]float64)
+	for _, e := range expertise {
+		score := DecayedExpertise(e.Share, e.LastTouched, now, opts.HalfLifeDays)
+		if score <= 0 {
+			continue
+		}
+		c := candidates[e.Author]
+		if c == nil {
+			c = &ReviewerCandidate{Author: e.Author, Load: opts.Load[e.Author]}
+			candidates[e.Author] = c
+		}
+		c.Expertise += score
+		if e.LastTouched.After(c.LastTouched) {
+			c.LastTouched = e.LastTouched
+		}
+		byAuthor[e.Author] = append(byAuthor[e.Author], fileScore{e.FilePath, score})
+		best[e.FilePath] = math.Max(best[e.FilePath], score)
+	}
+
+	loadFactor := func(c *ReviewerCandidate) float64 {
+		return 1 + opts.LoadPenalty*float64(c.Load)
+	}
+	ranked := make([]*ReviewerCandidate, 0, len(candidates))
+	for author, c := range candidates {
+		scores := byAuthor[author]
+		sort.Slice(scores, func(i, j int) bool {
+			if scores[i].score != scores[j].score {
+				return scores[i].score > scores[j].score
+			}
+			return scores[i].file < scores[j].file
+		})
+		for _, s := range scores {
+			c.Files = append(c.Files, s.file)
+		}
+		c.Score = c.Expertise / loadFactor(c)
+		ranked = append(ranked, c)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Author < ranked[j].Author
+	})
+
+	// uncovered is how much of each file is not yet known by the picked reviewers, relative
+	// to its best expert
+	uncovered := make(map[string]float64, len(best))
+	for file := range best {
+		uncovered[file] = 1
+	}
+	picked := make([]ReviewerCandidate, 0, len(ranked))
+	for len(picked) < opts.Count {
+		var pick *ReviewerCandidate
+		pickGain := 0.0
+		for _, c := range ranked {
+			if c.Selected {
+				continue
+			}
+			gain := 0.0
+			for _, s := range byAuthor[c.Author] {
+				gain += s.score * uncovered[s.file]
+			}
+			gain /= loadFactor(c)
+			if gain > pickGain {
+				pick, pickGain = c, gain
+			}
+		}
+		if pick == nil {
+			// The remaining candidates only know files that are fully covered
+			for _, c := range ranked {
+				if !c.Selected {
+					pick = c
+					break
+				}
+			}
+			if pick == nil {
+				break
+			}
+		}
+		pick.Selected = true
+		for _, s := range byAuthor[pick.Author] {
+			uncovered[s.file] *= 1 - s.score/best[s.file]
+		}
+		picked = append(picked, *pick)
+	}
+
+	for _, c := range ranked {
+		if !c.Selected {
+			picked = append(picked, *c)
+		}
+	}
+	return picked
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecayedExpertise(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := DecayedExpertise(80, now, now, 180); got != 0.8 {
+		t.Errorf("expected undecayed expertise 0.8, got %f", got)
+	}
+	if got := DecayedExpertise(80, now.AddDate(0, 0, -180), now, 180); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("expected expertise halved after one half-life, got %f", got)
+	}
+	if got := DecayedExpertise(80, now.AddDate(0, 0, -360), now, 0); math.Abs(got-0.2) > 1e-9 {
+		t.Errorf("expected the default half-life to apply, got %f", got)
+	}
+}
+
+func TestRecommendReviewers_DecayFavoursRecentExperts(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expertise := []FileExpertise{
+		{FilePath: "core/a.go", Author: "ann", Share: 70, LastTouched: now.AddDate(-2, 0, 0)},
+		{FilePath: "core/a.go", Author: "bob", Share: 30, LastTouched: now.AddDate(0, 0, -7)},
+	}
+
+	got := RecommendReviewers(expertise, now, ReviewerOptions{Count: 1, HalfLifeDays: 180})
+	if len(got) != 2 || got[0].Author != "bob" || !got[0].Selected || got[1].Selected {
+		t.Fatalf("expected bob picked over the long-gone owner, got %+v", got)
+	}
+}
+
+func TestRecommendReviewers_SpreadsAcrossFiles(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expertise := []FileExpertise{
+		{FilePath: "core/a.go", Author: "ann", Share: 90, LastTouched: now},
+		{FilePath: "core/b.go", Author: "ann", Share: 90, LastTouched: now},
+		{FilePath: "core/a.go", Author: "bob", Share: 10, LastTouched: now},
+		{FilePath: "core/b.go", Author: "bob", Share: 10, LastTouched: now},
+		{FilePath: "api/h.go", Author: "cat", Share: 100, LastTouched: now},
+	}
+
+	// bob scores higher than cat on expertise alone, but only knows files ann already covers
+	got := RecommendReviewers(expertise, now, ReviewerOptions{Count: 2})
+	if got[0].Author != "ann" || got[1].Author != "cat" || !got[1].Selected {
+		t.Fatalf("expected ann then cat, got %+v", got)
+	}
+	if got[2].Author != "bob" || got[2].Selected {
+		t.Errorf("expected bob ranked last and not picked, got %+v", got[2])
+	}
+	if len(got[0].Files) != 2 || got[0].Expertise != 1.8 {
+		t.Errorf("expected ann's expertise over both files, got %+v", got[0])
+	}
+}
+
+func TestRecommendReviewers_LoadBalancing(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expertise := []FileExpertise{
+		{FilePath: "core/a.go", Author: "ann", Share: 60, LastTouched: now},
+		{FilePath: "core/a.go", Author: "bob", Share: 40, LastTouched: now},
+	}
+
+	got := RecommendReviewers(expertise, now, ReviewerOptions{Count: 1})
+	if got[0].Author != "ann" {
+		t.Fatalf("expected ann without load, got %s", got[0].Author)
+	}
+
+	got = RecommendReviewers(expertise, now, ReviewerOptions{Count: 1, LoadPenalty: 0.5, Load: map[string]int{"ann": 2}})
+	if got[0].Author != "bob" || got[1].Load != 2 || got[1].Score != 0.3 {
+		t.Fatalf("expected busy ann to give way to bob, got %+v", got)
+	}
+}
+
+func TestRecommendReviewers_FewerCandidatesThanCount(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expertise := []FileExpertise{{FilePath: "core/a.go", Author: "ann", Share: 100, LastTouched: now}}
+
+	got := RecommendReviewers(expertise, now, ReviewerOptions{Count: 3})
+	if len(got) != 1 || !got[0].Selected {
+		t.Fatalf("expected the only candidate picked, got %+v", got)
+	}
+	if got := RecommendReviewers(nil, now, ReviewerOptions{}); len(got) != 0 {
+		t.Errorf("expected no candidates, got %+v", got)
+	}
+}
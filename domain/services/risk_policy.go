@@ -0,0 +1,107 @@
+package services
+
+import (
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+// DefaultRiskPolicy is the policy of projects that have not configured their own. Files are
+// medium risk above 5 commits, high above 10 and critical above 20; above 50%, 70% and 90%
+// main-owner share; and at a bus factor of 2 (medium) or 1 (high). File pairs with at least
+// 3 shared commits are medium risk above a coupling score of 0.5, high above 0.7 and
+// critical above 0.9.
+var DefaultRiskPolicy = entities.RiskPolicy{
+	ChangeFrequency:          entities.RiskThresholds{Medium: 5, High: 10, Critical: 20},
+	Ownership:                entities.RiskThresholds{Medium: 50, High: 70, Critical: 90},
+	BusFactor:                entities.RiskThresholds{Medium: 2, High: 1},
+	Coupling:                 entities.RiskThresholds{Medium: 0.5, High: 0.7, Critical: 0.9},
+	CouplingMinSharedCommits: 3,
+	DebtWeights:              DefaultDebtWeights,
+}
+
+// EffectiveRiskPolicy returns a project's stored policy, or DefaultRiskPolicy when it has none
+func EffectiveRiskPolicy(stored *entities.RiskPolicy) entities.RiskPolicy {
+	if stored == nil {
+		return DefaultRiskPolicy
+	}
+	return *stored
+}
+
+// RiskAssessor assigns risk levels under a project's risk policy, so that every analysis
+// rates the same metric the same way
+type RiskAssessor struct {
+	policy entities.RiskPolicy
+}
+
+// NewRiskAssessor creates an assessor for a validated policy
+func NewRiskAssessor(policy entities.RiskPolicy) *RiskAssessor {
+	return &RiskAssessor{policy: policy}
+}
+
+// Policy returns the policy the assessor applies
+func (a *RiskAssessor) Policy() entities.RiskPolicy {
+	return a.policy
+}
+
+// Version returns the version of the policy the assessor applies, 0 for the default policy
+func (a *RiskAssessor) Version() int {
+	return a.policy.Version
+}
+
+// ChangeFrequencyLevel rates a file by the number of commits touching it
+func (a *RiskAssessor) ChangeFrequencyLevel(commits int) values.RiskLevel {
+	return levelAbove(float64(commits), a.policy.ChangeFrequency)
+}
+
+// OwnershipLevel rates a file by the share of its main owner, in percent
+func (a *RiskAssessor) OwnershipLevel(percent float64) values.RiskLevel {
+	return levelAbove(percent, a.policy.Ownership)
+}
+
+// BusFactorLevel rates a file by its bus factor. Files without a bus factor (no
+// contributions) are low risk.
+func (a *RiskAssessor) BusFactorLevel(busFactor int) values.RiskLevel {
+	if busFactor <= 0 {
+		return values.RiskLow
+	}
+	thresholds := a.policy.BusFactor
+	value := float64(busFactor)
+	switch {
+	case thresholds.Critical > 0 && value <= thresholds.Critical:
+		return values.RiskCritical
+	case thresholds.High > 0 && value <= thresholds.High:
+		return values.RiskHigh
+	case thresholds.Medium > 0 && value <= thresholds.Medium:
+		return values.RiskMedium
+	default:
+		return values.RiskLow
+	}
+}
+
+// CouplingLevel rates a file pair by its shared commits and coupling score; pairs with fewer
+// shared commits than the policy's minimum are low risk
+func (a *RiskAssessor) CouplingLevel(sharedCommits int, score float64) values.RiskLevel {
+	if sharedCommits < a.policy.CouplingMinSharedCommits {
+		return values.RiskLow
+	}
+	return levelAbove(score, a.policy.Coupling)
+}
+
+// DebtWeights returns the weights of the debt score components
+func (a *RiskAssessor) DebtWeights() entities.DebtWeights {
+	return a.policy.DebtWeights
+}
+
+// levelAbove returns the highest enabled level whose threshold the value exceeds
+func levelAbove(value float64, thresholds entities.RiskThresholds) values.RiskLevel {
+	switch {
+	case thresholds.Critical > 0 && value > thresholds.Critical:
+		return values.RiskCritical
+	case thresholds.High > 0 && value > thresholds.High:
+		return values.RiskHigh
+	case thresholds.Medium > 0 && value > thresholds.Medium:
+		return values.RiskMedium
+	default:
+		return values.RiskLow
+	}
+}
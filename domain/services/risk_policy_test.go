@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+)
+
+func TestRiskAssessorDefaultPolicy(t *testing.T) {
+	assessor := NewRiskAssessor(DefaultRiskPolicy)
+
+	changes := map[int]values.RiskLevel{0: values.RiskLow, 5: values.RiskLow, 6: values.RiskMedium, 11: values.RiskHigh, 21: values.RiskCritical}
+	for commits, expected := range changes {
+		if got := assessor.ChangeFrequencyLevel(commits); got != expected {
+			t.Errorf("ChangeFrequencyLevel(%d) = %s, expected %s", commits, got, expected)
+		}
+	}
+
+	busFactors := map[int]values.RiskLevel{0: values.RiskLow, 1: values.RiskHigh, 2: values.RiskMedium, 3: values.RiskLow}
+	for busFactor, expected := range busFactors {
+		if got := assessor.BusFactorLevel(busFactor); got != expected {
+			t.Errorf("BusFactorLevel(%d) = %s, expected %s", busFactor, got, expected)
+		}
+	}
+
+	if got := assessor.OwnershipLevel(95); got != values.RiskCritical {
+		t.Errorf("expected 95%% ownership to be critical, got %s", got)
+	}
+	if got := assessor.CouplingLevel(2, 1); got != values.RiskLow {
+		t.Errorf("expected pairs below the shared commit minimum to be low risk, got %s", got)
+	}
+	if got := assessor.CouplingLevel(3, 0.8); got != values.RiskHigh {
+		t.Errorf("expected a 0.8 coupling score to be high risk, got %s", got)
+	}
+}
+
+func TestRiskAssessorDisabledLevels(t *testing.T) {
+	policy := DefaultRiskPolicy
+	policy.ChangeFrequency = entities.RiskThresholds{High: 3}
+	assessor := NewRiskAssessor(policy)
+
+	if got := assessor.ChangeFrequencyLevel(100); got != values.RiskHigh {
+		t.Errorf("expected the critical level to be disabled, got %s", got)
+	}
+	if got := assessor.ChangeFrequencyLevel(2); got != values.RiskLow {
+		t.Errorf("expected the medium level to be disabled, got %s", got)
+	}
+}
+
+func TestRiskPolicyValidate(t *testing.T) {
+	if err := DefaultRiskPolicy.Validate(); err != nil {
+		t.Fatalf("expected the default policy to be valid: %v", err)
+	}
+
+	invalid := map[string]func(p *entities.RiskPolicy){
+		"unordered":        func(p *entities.RiskPolicy) { p.ChangeFrequency = entities.RiskThresholds{Medium: 10, High: 5} },
+		"ascending bus":    func(p *entities.RiskPolicy) { p.BusFactor = entities.RiskThresholds{Medium: 1, High: 2} },
+		"ownership range":  func(p *entities.RiskPolicy) { p.Ownership.Critical = 120 },
+		"coupling range":   func(p *entities.RiskPolicy) { p.Coupling.Critical = 1.5 },
+		"negative":         func(p *entities.RiskPolicy) { p.ChangeFrequency.Medium = -1 },
+		"min shared":       func(p *entities.RiskPolicy) { p.CouplingMinSharedCommits = 0 },
+		"negative weight":  func(p *entities.RiskPolicy) { p.DebtWeights.Coupling = -0.1 },
+		"all zero weights": func(p *entities.RiskPolicy) { p.DebtWeights = entities.DebtWeights{} },
+	}
+	for name, mutate := range invalid {
+		policy := DefaultRiskPolicy
+		mutate(&policy)
+		if err := policy.Validate(); err == nil {
+			t.Errorf("%s: expected the policy to be invalid", name)
+		}
+	}
+}
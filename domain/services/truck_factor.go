@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math"
+	"sort"
+)
+
+// Degree-of-authorship model coefficients from Fritz et al., as used by Avelino et al.
+// to estimate truck factors
+const (
+	doaIntercept         = 3.293
+	doaFirstAuthorWeight = 1.098
+	doaDeliveriesWeight  = 0.164
+	doaAcceptancesWeight = 0.321
+
+	// authorshipNormalizedThreshold is the minimum DOA relative to a file's top author for authorship
+	authorshipNormalizedThreshold = 0.75
+)
+
+// DefaultTruckFactorCoverage is the share of files that must keep at least one author
+const DefaultTruckFactorCoverage = 0.5
+
+// FileAuthorship holds one author's history with one file
+type FileAuthorship struct {
+	FilePath string
+	Author   string
+	// Commits is the number of commits by the author touching the file
+	Commits int
+	// Creator reports whether the author made the file's first commit
+	Creator bool
+}
+
+// KeyDeveloper is an author removed by the truck factor algorithm
+type KeyDeveloper struct {
+	Author string
+	// FilesAuthored is the number of files the author was an author of when removed
+	FilesAuthored int
+	// CoverageAfter is the share of files still having an author after the removal
+	CoverageAfter float64
+}
+
+// TruckFactorResult is the outcome of the greedy truck factor algorithm
+type TruckFactorResult struct {
+	TruckFactor int
+	TotalFiles  int
+	// InitialCoverage is the share of files with at least one author before any removal
+	InitialCoverage float64
+	// KeyDevelopers lists removed authors in removal order
+	KeyDevelopers []KeyDeveloper
+}
+
+// DegreeOfAuthorship computes the absolute DOA of an author for a file given whether they
+// created it, their own commit count and the commit count of everyone else
+func DegreeOfAuthorship(creator bool, deliveries, acceptances int) float64 {
+	fa := 0.0
+	if creator {
+		fa = 1
+	}
+	return doaIntercept + doaFirstAuthorWeight*fa + doaDeliveriesWeight*float64(deliveries) -
+		doaAcceptancesWeight*math.Log(1+float64(acceptances))
+}
+
+// FileAuthors determines the authors of each file: authors whose DOA is at least the model
+// intercept and at least 75% of the highest DOA on the file. Every file in records is
+// present in the result, possibly with no authors.
+func FileAuthors(records []FileAuthorship) map[string][]string {
+	commitsByFile := make(map[string]int)
+	for _, r := range records {
+		commitsByFile[r.FilePath] += r.Commits
+	}
+
+	doas := make(map[string]map[string]float64)
+	maxDOA := make(map[string]float64)
+	for _, r := range records {
+		doa := DegreeOfAuthorship(r.Creator, r.Commits, commitsByFile[r.FilePath]-r.Commits)
+		if doas[r.FilePath] == nil {
+			doas[r.FilePath] = make(map[string]float64)
+		}
+		doas[r.FilePath][r.Author] = doa
+		if doa > maxDOA[r.FilePath] {
+			maxDOA[r.FilePath] = doa
+		}
+	}
+
+	authors := make(map[string][]string, len(doas))
+	for file, byAuthor := range doas {
+		authors[file] = []string{}
+		for author, doa := range byAuthor {
+			if doa >= doaIntercept && maxDOA[file] > 0 && doa/maxDOA[file] > authorshipNormalizedThreshold {
+				authors[file] = append(authors[file], author)
+			}
+		}
+		sort.Strings(authors[file])
+	}
+	return authors
+}
+
+// ComputeTruckFactor greedily removes the author of the most files until fewer than
+// coverage (0-1) of the files still have an author. The truck factor is the number of
+// authors removed.
+func ComputeTruckFactor(fileAuthors map[string][]string, coverage float64) TruckFactorResult {
+	result := TruckFactorResult{TotalFiles: len(fileAuthors)}
+	if result.TotalFiles == 0 {
+		return result
+	}
+
+	filesByAuthor := make(map[string]map[string]bool)
+	remaining := make(map[string]int, len(fileAuthors))
+	covered := 0
+	for file, authors := range fileAuthors {
+		remaining[file] = len(authors)
+		if len(authors) > 0 {
+			covered++
+		}
+		for _, author := range authors {
+			if filesByAuthor[author] == nil {
+				filesByAuthor[author] = make(map[string]bool)
+			}
+			filesByAuthor[author][file] = true
+		}
+	}
+
+	share := func() float64 { return float64(covered) / float64(result.TotalFiles) }
+	result.InitialCoverage = share()
+
+	for share() >= coverage && len(filesByAuthor) > 0 {
+		top, topFiles := "", -1
+		for author, files := range filesByAuthor {
+			if len(files) > topFiles || (len(files) == topFiles && author < top) {
+				top, topFiles = author, len(files)
+			}
+		}
+
+		for file := range filesByAuthor[top] {
+			remaining[file]--
+			if remaining[file] == 0 {
+				covered--
+			}
+		}
+		delete(filesByAuthor, top)
+
+		result.TruckFactor++
+		result.KeyDevelopers = append(result.KeyDevelopers, KeyDeveloper{
+			Author:        top,
+			FilesAuthored: topFiles,
+			CoverageAfter: share(),
+		})
+	}
+
+	return result
+}
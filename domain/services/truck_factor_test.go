@@ -0,0 +1,49 @@
+package services
+
+import "testing"
+
+func TestFileAuthors(t *testing.T) {
+	records := []FileAuthorship{
+		{FilePath: "a.go", Author: "alice", Commits: 10, Creator: true},
+		{FilePath: "a.go", Author: "bob", Commits: 1},
+		{FilePath: "b.go", Author: "alice", Commits: 3, Creator: true},
+		{FilePath: "b.go", Author: "bob", Commits: 3},
+	}
+
+	authors := FileAuthors(records)
+
+	if got := authors["a.go"]; len(got) != 1 || got[0] != "alice" {
+		t.Errorf("expected alice to be the only author of a.go, got %v", got)
+	}
+	if got := authors["b.go"]; len(got) != 2 {
+		t.Errorf("expected alice and bob to author b.go, got %v", got)
+	}
+}
+
+func TestComputeTruckFactor(t *testing.T) {
+	fileAuthors := map[string][]string{
+		"a.go": {"alice"},
+		"b.go": {"alice"},
+		"c.go": {"alice", "bob"},
+		"d.go": {"carol"},
+	}
+
+	result := ComputeTruckFactor(fileAuthors, DefaultTruckFactorCoverage)
+
+	// Removing alice orphans a.go and b.go (coverage 50%), removing bob then orphans c.go (25%)
+	if result.TruckFactor != 2 {
+		t.Fatalf("expected truck factor 2, got %d", result.TruckFactor)
+	}
+	if result.KeyDevelopers[0].Author != "alice" || result.KeyDevelopers[0].FilesAuthored != 3 {
+		t.Errorf("expected alice with 3 files first, got %+v", result.KeyDevelopers[0])
+	}
+	if result.KeyDevelopers[1].CoverageAfter != 0.25 {
+		t.Errorf("expected coverage 0.25 after second removal, got %f", result.KeyDevelopers[1].CoverageAfter)
+	}
+}
+
+func TestComputeTruckFactorEmpty(t *testing.T) {
+	if result := ComputeTruckFactor(nil, DefaultTruckFactorCoverage); result.TruckFactor != 0 {
+		t.Errorf("expected truck factor 0 for no files, got %d", result.TruckFactor)
+	}
+}
@@ -0,0 +1,28 @@
+package values
+
+import "fmt"
+
+// ChangeSetMode selects which commits are treated as one logical change set when measuring
+// temporal coupling
+type ChangeSetMode string
+
+const (
+	// ChangeSetByCommit treats every commit as its own change set
+	ChangeSetByCommit ChangeSetMode = "commit"
+	// ChangeSetByWindow joins commits of the same author made within a time window of each other
+	ChangeSetByWindow ChangeSetMode = "window"
+	// ChangeSetByTicket joins commits whose messages reference the same issue key
+	ChangeSetByTicket ChangeSetMode = "ticket"
+)
+
+// ParseChangeSetMode validates a change set mode, returning fallback for an empty value
+func ParseChangeSetMode(value string, fallback ChangeSetMode) (ChangeSetMode, error) {
+	switch mode := ChangeSetMode(value); mode {
+	case "":
+		return fallback, nil
+	case ChangeSetByCommit, ChangeSetByWindow, ChangeSetByTicket:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid change set grouping %q: must be one of commit, window, ticket", value)
+	}
+}
@@ -0,0 +1,36 @@
+package values
+
+import "fmt"
+
+// CommitCategory classifies the intent of a commit
+type CommitCategory string
+
+const (
+	// CategoryBugfix marks commits that fix defects
+	CategoryBugfix CommitCategory = "bugfix"
+	// CategoryFeature marks commits that add or extend functionality
+	CategoryFeature CommitCategory = "feature"
+	// CategoryRefactor marks commits that restructure code without changing behaviour
+	CategoryRefactor CommitCategory = "refactor"
+	// CategoryDocs marks commits that only touch documentation
+	CategoryDocs CommitCategory = "docs"
+	// CategoryTest marks commits that add or change tests
+	CategoryTest CommitCategory = "test"
+	// CategoryChore marks maintenance commits such as builds, releases and dependency bumps
+	CategoryChore CommitCategory = "chore"
+)
+
+// CommitCategories lists every commit category
+var CommitCategories = []CommitCategory{CategoryBugfix, CategoryFeature, CategoryRefactor, CategoryDocs, CategoryTest, CategoryChore}
+
+// ParseCommitCategory validates a commit category, returning fallback for an empty value
+func ParseCommitCategory(value string, fallback CommitCategory) (CommitCategory, error) {
+	switch category := CommitCategory(value); category {
+	case "":
+		return fallback, nil
+	case CategoryBugfix, CategoryFeature, CategoryRefactor, CategoryDocs, CategoryTest, CategoryChore:
+		return category, nil
+	default:
+		return "", fmt.Errorf("invalid commit category %q: must be one of bugfix, feature, refactor, docs, test, chore", value)
+	}
+}
@@ -0,0 +1,27 @@
+package values
+
+import "fmt"
+
+// CouplingLevel selects the granularity at which temporal coupling is measured
+type CouplingLevel string
+
+const (
+	// CouplingByFile measures coupling between individual files
+	CouplingByFile CouplingLevel = "file"
+	// CouplingByDirectory aggregates files into their directory at a configurable depth
+	CouplingByDirectory CouplingLevel = "directory"
+	// CouplingByLayer aggregates files into architectural layers defined by glob patterns
+	CouplingByLayer CouplingLevel = "layer"
+)
+
+// ParseCouplingLevel validates a coupling level, returning fallback for an empty value
+func ParseCouplingLevel(value string, fallback CouplingLevel) (CouplingLevel, error) {
+	switch level := CouplingLevel(value); level {
+	case "":
+		return fallback, nil
+	case CouplingByFile, CouplingByDirectory, CouplingByLayer:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid coupling level %q: must be one of file, directory, layer", value)
+	}
+}
@@ -48,18 +48,15 @@ func (fp *FilePath) GetDirectory() string {
 	return filepath.Dir(fp.value)
 }
 
-// IsCodeFile checks if the file is a code file based on extension
+// IsCodeFile checks if the file is written in a programming language, based on its extension
 func (fp *FilePath) IsCodeFile() bool {
-	codeExtensions := []string{".go", ".js", ".ts", ".py", ".java", ".cpp", ".c", ".h", ".cs", ".php", ".rb", ".swift", ".kt", ".rs"}
-	ext := strings.ToLower(fp.GetExtension())
-
-	for _, codeExt := range codeExtensions {
-		if ext == codeExt {
-			return true
-		}
-	}
+	lang := languagesByExtension[strings.ToLower(fp.GetExtension())]
+	return lang != nil && lang.Code
+}
 
-	return false
+// GetLanguage returns the language of the file, or nil when it is not recognised
+func (fp *FilePath) GetLanguage() *Language {
+	return LanguageForPath(fp.value)
 }
 
 // Equals compares two FilePath objects
@@ -0,0 +1,25 @@
+package values
+
+import "fmt"
+
+// GateRuleType selects what a quality gate rule checks
+type GateRuleType string
+
+const (
+	// GateBusFactor fails when files drop to a low bus factor
+	GateBusFactor GateRuleType = "bus_factor"
+	// GateHotspotGrowth fails when the change count of the top hotspots grows too fast
+	GateHotspotGrowth GateRuleType = "hotspot_growth"
+	// GateCoupling fails when components become strongly coupled
+	GateCoupling GateRuleType = "coupling"
+)
+
+// ParseGateRuleType validates a quality gate rule type
+func ParseGateRuleType(value string) (GateRuleType, error) {
+	switch ruleType := GateRuleType(value); ruleType {
+	case GateBusFactor, GateHotspotGrowth, GateCoupling:
+		return ruleType, nil
+	default:
+		return "", fmt.Errorf("invalid gate rule type %q: must be one of bus_factor, hotspot_growth, coupling", value)
+	}
+}
@@ -0,0 +1,79 @@
+package values
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Language describes a programming language and its comment syntax
+type Language struct {
+	Name         string
+	LineComments []string
+	BlockStart   string
+	BlockEnd     string
+	// Code is set for programming languages, as opposed to markup, styling and configuration
+	Code bool
+}
+
+// HasBlockComments reports whether the language supports block comments
+func (l *Language) HasBlockComments() bool {
+	return l.BlockStart != "" && l.BlockEnd != ""
+}
+
+// cStyle creates a programming language using // line comments and /* */ block comments
+func cStyle(name string) *Language {
+	return &Language{Name: name, LineComments: []string{"//"}, BlockStart: "/*", BlockEnd: "*/", Code: true}
+}
+
+// hashStyle creates a language using # line comments
+func hashStyle(name string) *Language {
+	return &Language{Name: name, LineComments: []string{"#"}}
+}
+
+var (
+	// languagesByExtension maps lower-case file extensions to their language
+	languagesByExtension = map[string]*Language{
+		".go":    cStyle("Go"),
+		".js":    cStyle("JavaScript"),
+		".jsx":   cStyle("JavaScript"),
+		".ts":    cStyle("TypeScript"),
+		".tsx":   cStyle("TypeScript"),
+		".java":  cStyle("Java"),
+		".c":     cStyle("C"),
+		".h":     cStyle("C"),
+		".cpp":   cStyle("C++"),
+		".hpp":   cStyle("C++"),
+		".cs":    cStyle("C#"),
+		".php":   {Name: "PHP", LineComments: []string{"//", "#"}, BlockStart: "/*", BlockEnd: "*/", Code: true},
+		".swift": cStyle("Swift"),
+		".kt":    cStyle("Kotlin"),
+		".rs":    cStyle("Rust"),
+		".scss":  {Name: "SCSS", LineComments: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"},
+		".css":   {Name: "CSS", BlockStart: "/*", BlockEnd: "*/"},
+		".py":    {Name: "Python", LineComments: []string{"#"}, BlockStart: `"""`, BlockEnd: `"""`, Code: true},
+		".rb":    {Name: "Ruby", LineComments: []string{"#"}, BlockStart: "=begin", BlockEnd: "=end", Code: true},
+		".sh":    hashStyle("Shell"),
+		".yml":   hashStyle("YAML"),
+		".yaml":  hashStyle("YAML"),
+		".toml":  hashStyle("TOML"),
+		".sql":   {Name: "SQL", LineComments: []string{"--"}, BlockStart: "/*", BlockEnd: "*/"},
+		".html":  {Name: "HTML", BlockStart: "<!--", BlockEnd: "-->"},
+		".xml":   {Name: "XML", BlockStart: "<!--", BlockEnd: "-->"},
+		".md":    {Name: "Markdown"},
+		".json":  {Name: "JSON"},
+	}
+
+	// languagesByFileName maps well-known extension-less file names to their language
+	languagesByFileName = map[string]*Language{
+		"Makefile":   hashStyle("Makefile"),
+		"Dockerfile": hashStyle("Dockerfile"),
+	}
+)
+
+// LanguageForPath returns the language of a file path, or nil when it is not recognised
+func LanguageForPath(path string) *Language {
+	if lang, exists := languagesByFileName[filepath.Base(path)]; exists {
+		return lang
+	}
+	return languagesByExtension[strings.ToLower(filepath.Ext(path))]
+}
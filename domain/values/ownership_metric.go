@@ -0,0 +1,27 @@
+package values
+
+import "fmt"
+
+// OwnershipMetric selects how a file's ownership is attributed to its authors
+type OwnershipMetric string
+
+const (
+	// OwnershipByCommits weights authors by the number of commits touching the file
+	OwnershipByCommits OwnershipMetric = "commits"
+	// OwnershipByLinesChanged weights authors by the lines they added and deleted in the file
+	OwnershipByLinesChanged OwnershipMetric = "lines_changed"
+	// OwnershipByBlame weights authors by the lines they wrote that survive at the analysed commit
+	OwnershipByBlame OwnershipMetric = "blame"
+)
+
+// ParseOwnershipMetric validates an ownership metric, returning fallback for an empty value
+func ParseOwnershipMetric(value string, fallback OwnershipMetric) (OwnershipMetric, error) {
+	switch metric := OwnershipMetric(value); metric {
+	case "":
+		return fallback, nil
+	case OwnershipByCommits, OwnershipByLinesChanged, OwnershipByBlame:
+		return metric, nil
+	default:
+		return "", fmt.Errorf("invalid ownership metric %q: must be one of commits, lines_changed, blame", value)
+	}
+}
@@ -0,0 +1,32 @@
+package values
+
+import "strings"
+
+// RiskLevel rates how risky a file or component is under a project's risk policy
+type RiskLevel string
+
+const (
+	// RiskLow is the level of values below every threshold
+	RiskLow RiskLevel = "low"
+	// RiskMedium is the level of values past the medium threshold
+	RiskMedium RiskLevel = "medium"
+	// RiskHigh is the level of values past the high threshold
+	RiskHigh RiskLevel = "high"
+	// RiskCritical is the level of values past the critical threshold
+	RiskCritical RiskLevel = "critical"
+)
+
+// Title returns the level capitalised ("High"), as reported by the hotspot endpoints
+func (l RiskLevel) Title() string {
+	if l == "" {
+		return ""
+	}
+	return strings.ToUpper(string(l[:1])) + string(l[1:])
+}
+
+// AtLeast reports whether the level is as risky as other or riskier
+func (l RiskLevel) AtLeast(other RiskLevel) bool {
+	return riskLevelRank[l] >= riskLevelRank[other]
+}
+
+var riskLevelRank = map[RiskLevel]int{RiskLow: 0, RiskMedium: 1, RiskHigh: 2, RiskCritical: 3}
@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+// SetBlameRepository sets the repository used to store line-level authorship
+func (ra *RepositoryAnalyzer) SetBlameRepository(repo repositories.BlameRepository) {
+	ra.blameRepo = repo
+}
+
+// blameFiles attributes every line of the measured text files to the commit that last changed it.
+// Lines are aggregated per originating commit so a file produces one segment per contributing commit.
+//
+// When changedFiles is nil the whole tree is blamed. Otherwise only the changed files are
+// re-blamed: the blame of an untouched file cannot change, so its stored segments are kept,
+// while files that are no longer in the tree (deleted or renamed) are dropped.
+// Projects without any stored blame always get a full pass.
+func (ra *RepositoryAnalyzer) blameFiles(projectID int, repoPath string, commitHash string, metrics []*entities.FileMetrics, changedFiles map[string]bool) error {
+	if ra.blameRepo == nil {
+		return nil
+	}
+
+	var stored []string
+	if changedFiles != nil {
+		var err error
+		stored, err = ra.blameRepo.GetFilePaths(projectID)
+		if err != nil {
+			return fmt.Errorf("failed to load stored blame: %w", err)
+		}
+		// Projects analysed before blame was recorded need a full pass first
+		if len(stored) == 0 {
+			changedFiles = nil
+		}
+	}
+
+	var segments []*entities.BlameSegment
+	failed, blamed := 0, 0
+	inTree := make(map[string]bool, len(metrics))
+
+	for _, fm := range metrics {
+		inTree[fm.FilePath] = true
+		if changedFiles != nil && !changedFiles[fm.FilePath] {
+			continue
+		}
+		if fm.IsBinary || fm.TotalLines() == 0 {
+			continue
+		}
+
+		lines, err := ra.gitService.BlameFile(repoPath, commitHash, fm.FilePath)
+		if err != nil {
+			failed++
+			continue
+		}
+		blamed++
+
+		byCommit := make(map[string]*entities.BlameSegment)
+		for _, line := range lines {
+			segment, exists := byCommit[line.CommitHash]
+			if !exists {
+				segment = &entities.BlameSegment{
+					ProjectID:   projectID,
+					FilePath:    fm.FilePath,
+					CommitHash:  line.CommitHash,
+					Author:      line.Author,
+					CommittedAt: line.Date,
+				}
+				byCommit[line.CommitHash] = segment
+				segments = append(segments, segment)
+			}
+			segment.Lines++
+		}
+	}
+
+	if failed > 0 {
+		log.Printf("Could not blame %d files at commit %s for project %d", failed, commitHash, projectID)
+	}
+
+	if changedFiles == nil {
+		if err := ra.blameRepo.ReplaceForProject(projectID, segments); err != nil {
+			return fmt.Errorf("failed to store blame: %w", err)
+		}
+		log.Printf("Blamed %d files (%d segments) at commit %s for project %d", blamed, len(segments), commitHash, projectID)
+		return nil
+	}
+
+	replaced := make([]string, 0, len(changedFiles))
+	for path := range changedFiles {
+		replaced = append(replaced, path)
+	}
+	for _, path := range stored {
+		if !inTree[path] && !changedFiles[path] {
+			replaced = append(replaced, path)
+		}
+	}
+
+	if err := ra.blameRepo.ReplaceForFiles(projectID, replaced, segments); err != nil {
+		return fmt.Errorf("failed to store blame: %w", err)
+	}
+
+	log.Printf("Re-blamed %d changed files (%d segments) at commit %s for project %d", blamed, len(segments), commitHash, projectID)
+	return nil
+}
@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"log"
+
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+)
+
+// SetClassificationRuleRepository sets the repository of the per-project rules used to
+// classify commits as they are ingested
+func (ra *RepositoryAnalyzer) SetClassificationRuleRepository(repo repositories.ClassificationRuleRepository) {
+	ra.classificationRuleRepo = repo
+}
+
+// classifyCommit returns the category of a commit message under its project's rules and
+// imported issue types, which are loaded once per analyzer
+func (ra *RepositoryAnalyzer) classifyCommit(projectID int, message string) values.CommitCategory {
+	if ra.classificationRuleRepo == nil {
+		return ""
+	}
+
+	classifier, ok := ra.classifiers[projectID]
+	if !ok {
+		rules, err := ra.classificationRuleRepo.GetByProjectID(projectID)
+		if err != nil {
+			log.Printf("Failed to load classification rules for project %d, using defaults: %v", projectID, err)
+		}
+		var issueTypes map[string]string
+		if ra.issueRepo != nil {
+			if issueTypes, err = ra.issueRepo.GetIssueTypes(projectID); err != nil {
+				log.Printf("Failed to load issue types for project %d: %v", projectID, err)
+			}
+		}
+		classifier = services.NewCommitClassifier(rules, issueTypes, ra.issuePattern(projectID))
+		if ra.classifiers == nil {
+			ra.classifiers = make(map[int]*services.CommitClassifier)
+		}
+		ra.classifiers[projectID] = classifier
+	}
+	return classifier.Classify(message)
+}
@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"log"
+
+	"codeecho/domain/repositories"
+)
+
+// SetCouplingRepository sets the repository used to mark the pre-computed temporal coupling of
+// fully analysed projects as complete
+func (ra *RepositoryAnalyzer) SetCouplingRepository(repo repositories.CouplingRepository) {
+	ra.couplingRepo = repo
+}
+
+// hasStoredCommits reports whether a project has commits from an earlier analysis
+func (ra *RepositoryAnalyzer) hasStoredCommits(projectID int) (bool, error) {
+	if ra.commitRepo == nil {
+		return false, nil
+	}
+	commits, err := ra.commitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return false, err
+	}
+	return len(commits) > 0, nil
+}
+
+// markCouplingComplete records that the coupling of every stored commit of a project is stored,
+// which holds after an analysis that started without stored commits: each commit is saved in
+// the same transaction as its coupling.
+func (ra *RepositoryAnalyzer) markCouplingComplete(projectID int) {
+	if ra.couplingRepo == nil {
+		return
+	}
+	if err := ra.couplingRepo.MarkComplete(projectID); err != nil {
+		log.Printf("Error marking the coupling of project %d complete: %v", projectID, err)
+	}
+}
@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"codeecho/application/ports"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+)
+
+// unknownLanguage is the language recorded for text files with no recognised extension
+const unknownLanguage = "Other"
+
+// SetFileMetricsRepository sets the repository used to store per-file LOC measurements
+func (ra *RepositoryAnalyzer) SetFileMetricsRepository(repo repositories.FileMetricsRepository) {
+	ra.fileMetricsRepo = repo
+}
+
+// snapshotTree records per-file measurements and line-level authorship of the tree at the analysed commit.
+// changedFiles lists the paths touched since the previous snapshot; nil re-blames the whole tree.
+func (ra *RepositoryAnalyzer) snapshotTree(projectID int, repoPath string, commitHash string, changedFiles map[string]bool) {
+	metrics, err := ra.measureFiles(projectID, repoPath, commitHash)
+	if err != nil {
+		log.Printf("Error measuring files for project %d: %v", projectID, err)
+		return
+	}
+
+	if err := ra.blameFiles(projectID, repoPath, commitHash, metrics, changedFiles); err != nil {
+		log.Printf("Error computing blame for project %d: %v", projectID, err)
+	}
+}
+
+// measureFiles counts code, comment and blank lines for every file in the tree at the analysed commit
+func (ra *RepositoryAnalyzer) measureFiles(projectID int, repoPath string, commitHash string) ([]*entities.FileMetrics, error) {
+	if ra.fileMetricsRepo == nil {
+		return nil, nil
+	}
+
+	measuredAt := time.Now()
+	var metrics []*entities.FileMetrics
+
+	err := ra.gitService.WalkFilesAtCommit(repoPath, commitHash, func(file *ports.GitFile) error {
+		fm := &entities.FileMetrics{
+			ProjectID:  projectID,
+			CommitHash: commitHash,
+			FilePath:   file.Path,
+			Language:   unknownLanguage,
+			MeasuredAt: measuredAt,
+		}
+
+		if file.IsBinary {
+			fm.IsBinary = true
+			metrics = append(metrics, fm)
+			return nil
+		}
+
+		lang := values.LanguageForPath(file.Path)
+		if lang != nil {
+			fm.Language = lang.Name
+		}
+
+		counts := services.CountLines(file.Content, lang)
+		fm.CodeLines = counts.Code
+		fm.CommentLines = counts.Comment
+		fm.BlankLines = counts.Blank
+
+		metrics = append(metrics, fm)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk files at %s: %w", commitHash, err)
+	}
+
+	if err := ra.fileMetricsRepo.ReplaceForProject(projectID, metrics); err != nil {
+		return nil, fmt.Errorf("failed to store file metrics: %w", err)
+	}
+
+	log.Printf("Measured %d files at commit %s for project %d", len(metrics), commitHash, projectID)
+	return metrics, nil
+}
@@ -0,0 +1,24 @@
+package analyzer
+
+import (
+	"log"
+
+	"codeecho/domain/repositories"
+)
+
+// SetFileStatsRepository sets the repository used to mark the per-file daily statistics of
+// fully analysed projects as complete
+func (ra *RepositoryAnalyzer) SetFileStatsRepository(repo repositories.FileStatsRepository) {
+	ra.fileStatsRepo = repo
+}
+
+// markFileStatsComplete records that the daily statistics of every stored commit of a project
+// are stored; like the coupling, they are saved in the same transaction as their commit
+func (ra *RepositoryAnalyzer) markFileStatsComplete(projectID int) {
+	if ra.fileStatsRepo == nil {
+		return
+	}
+	if err := ra.fileStatsRepo.MarkComplete(projectID); err != nil {
+		log.Printf("Error marking the file statistics of project %d complete: %v", projectID, err)
+	}
+}
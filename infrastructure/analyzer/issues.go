@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"log"
+	"regexp"
+
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+)
+
+// SetIssueRepository sets the repository that stores the issue references extracted from
+// commit messages as they are ingested
+func (ra *RepositoryAnalyzer) SetIssueRepository(repo repositories.IssueRepository) {
+	ra.issueRepo = repo
+}
+
+// issuePattern returns the compiled issue key patterns of a project, which are loaded once
+// per analyzer. Invalid stored patterns fall back to the defaults.
+func (ra *RepositoryAnalyzer) issuePattern(projectID int) *regexp.Regexp {
+	if ra.issueRepo == nil {
+		return nil
+	}
+
+	pattern, ok := ra.issuePatterns[projectID]
+	if !ok {
+		patterns, err := ra.issueRepo.GetPatterns(projectID)
+		if err != nil {
+			log.Printf("Failed to load issue patterns for project %d, using defaults: %v", projectID, err)
+		}
+		if pattern, err = services.CompileIssuePatterns(patterns); err != nil {
+			log.Printf("Invalid issue patterns for project %d, using defaults: %v", projectID, err)
+			pattern, _ = services.CompileIssuePatterns(nil)
+		}
+		if ra.issuePatterns == nil {
+			ra.issuePatterns = make(map[int]*regexp.Regexp)
+		}
+		ra.issuePatterns[projectID] = pattern
+	}
+	return pattern
+}
+
+// issueKeys returns the issue keys a processed commit's message references, which are linked
+// in the transaction that stores the commit
+func (ra *RepositoryAnalyzer) issueKeys(projectID int, message string) []string {
+	if ra.issueRepo == nil {
+		return nil
+	}
+	return services.ExtractIssueKeys(message, ra.issuePattern(projectID))
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"log"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+// SetReleaseRepository sets the repository that records the repository's tags as releases
+func (ra *RepositoryAnalyzer) SetReleaseRepository(repo repositories.ReleaseRepository) {
+	ra.releaseRepo = repo
+}
+
+// recordReleases replaces a project's releases with the current tags of its repository.
+// Tags are re-read on every analysis as they can be moved or deleted; failures are logged
+// and do not fail the analysis.
+func (ra *RepositoryAnalyzer) recordReleases(projectID int, repoPath string) {
+	if ra.releaseRepo == nil {
+		return
+	}
+
+	tags, err := ra.gitService.GetTags(repoPath)
+	if err != nil {
+		log.Printf("Failed to read tags for project %d: %v", projectID, err)
+		return
+	}
+
+	releases := make([]entities.Release, 0, len(tags))
+	for _, tag := range tags {
+		releases = append(releases, entities.Release{
+			ProjectID:  projectID,
+			Name:       tag.Name,
+			CommitHash: tag.CommitHash,
+			CommitDate: tag.CommitDate,
+			ReleasedAt: tag.TaggedAt,
+		})
+	}
+
+	if err := ra.releaseRepo.ReplaceForProject(projectID, releases); err != nil {
+		log.Printf("Failed to record releases for project %d: %v", projectID, err)
+		return
+	}
+	log.Printf("Recorded %d releases for project %d", len(releases), projectID)
+}
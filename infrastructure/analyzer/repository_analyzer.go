@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"regexp"
 	"time"
 
 	"codeecho/application/ports"
 	"codeecho/domain/entities"
 	"codeecho/domain/repositories"
+	"codeecho/domain/services"
 	"codeecho/domain/values"
 )
 
@@ -17,12 +19,24 @@ type AnalysisCancelChecker func(projectID int) (bool, error)
 
 // RepositoryAnalyzer performs comprehensive analysis of Git repositories
 type RepositoryAnalyzer struct {
-	gitService    ports.GitService
-	projectRepo   repositories.ProjectRepository
-	commitRepo    repositories.CommitRepository
-	changeRepo    repositories.ChangeRepository
-	db            *sql.DB
-	cancelChecker AnalysisCancelChecker
+	gitService      ports.GitService
+	projectRepo     repositories.ProjectRepository
+	commitRepo      repositories.CommitRepository
+	changeRepo      repositories.ChangeRepository
+	fileMetricsRepo repositories.FileMetricsRepository
+	blameRepo       repositories.BlameRepository
+	couplingRepo    repositories.CouplingRepository
+	fileStatsRepo   repositories.FileStatsRepository
+	db              *sql.DB
+	cancelChecker   AnalysisCancelChecker
+
+	classificationRuleRepo repositories.ClassificationRuleRepository
+	classifiers            map[int]*services.CommitClassifier
+
+	issueRepo     repositories.IssueRepository
+	issuePatterns map[int]*regexp.Regexp
+
+	releaseRepo repositories.ReleaseRepository
 }
 
 // NewRepositoryAnalyzer creates a new repository analyzer instance
@@ -146,35 +160,31 @@ func (ra *RepositoryAnalyzer) processGitCommit(projectID int, gitCommit *ports.G
 		return fmt.Errorf("invalid git hash: %w", err)
 	}
 
-	// Parse timestamp if needed
-	timestamp := time.Now() // You might want to parse gitCommit.Timestamp properly
+	// Use the author date from git so time-based analytics reflect real history
+	timestamp, err := time.Parse(time.RFC3339, gitCommit.Timestamp)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", gitCommit.Timestamp, err)
+	}
 
 	commit := entities.NewCommit(projectID, hashValue, gitCommit.Author, timestamp, gitCommit.Message)
-
-	// Save commit to database
-	if ra.commitRepo != nil {
-		err := ra.commitRepo.Create(commit)
-		if err != nil {
-			return fmt.Errorf("failed to save commit: %w", err)
-		}
-	}
+	commit.Category = ra.classifyCommit(projectID, gitCommit.Message)
+	commit.IssueKeys = ra.issueKeys(projectID, gitCommit.Message)
 
 	// Process changes
+	changes := make([]*entities.Change, 0, len(gitCommit.Changes))
 	for _, gitChange := range gitCommit.Changes {
 		filePath, err := values.NewFilePath(gitChange.FilePath)
 		if err != nil {
 			log.Printf("Invalid file path %s: %v", gitChange.FilePath, err)
 			continue
 		}
+		changes = append(changes, entities.NewChange(commit.ID, filePath, gitChange.LinesAdded, gitChange.LinesDeleted))
+	}
 
-		change := entities.NewChange(commit.ID, filePath, gitChange.LinesAdded, gitChange.LinesDeleted)
-
-		// Save change to database
-		if ra.changeRepo != nil {
-			err := ra.changeRepo.Create(change)
-			if err != nil {
-				return fmt.Errorf("failed to save change: %w", err)
-			}
+	// Save the commit and its changes, along with their coupling, file statistics and issue links
+	if ra.commitRepo != nil {
+		if err := ra.commitRepo.CreateWithChanges(commit, changes); err != nil {
+			return fmt.Errorf("failed to save commit: %w", err)
 		}
 	}
 
@@ -282,10 +292,21 @@ func (ra *RepositoryAnalyzer) AnalyzeProject(projectID int, repoPath string) err
 		return fmt.Errorf("failed to get project: %w", err)
 	}
 
+	// Starting without stored commits, the analysis stores the coupling and file statistics of
+	// the whole history
+	hadCommits, err := ra.hasStoredCommits(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to check for stored commits: %w", err)
+	}
+
 	_, err = ra.AnalyzeRepository(project.Name, repoPath)
 	if err != nil {
 		return err
 	}
+	if !hadCommits {
+		ra.markCouplingComplete(projectID)
+		ra.markFileStatsComplete(projectID)
+	}
 
 	// Update project's last analyzed hash with the latest commit
 	commits, err := ra.gitService.GetCommits(repoPath)
@@ -309,7 +330,10 @@ func (ra *RepositoryAnalyzer) AnalyzeProject(projectID int, repoPath string) err
 		}
 
 		log.Printf("Updated project %d with latest commit hash: %s", projectID, latestCommit.Hash)
+
+		ra.snapshotTree(projectID, repoPath, latestCommit.Hash, nil)
 	}
+	ra.recordReleases(projectID, repoPath)
 
 	return nil
 }
@@ -324,8 +348,14 @@ func (ra *RepositoryAnalyzer) AnalyzeProjectSince(projectID int, repoPath string
 
 	log.Printf("Found %d new commits to process", len(commits))
 
-	// Process new commits
-	for _, gitCommit := range commits {
+	// Commits arrive newest first; process them in chronological order
+	changedFiles := make(map[string]bool)
+	for i := len(commits) - 1; i >= 0; i-- {
+		gitCommit := commits[i]
+		for _, change := range gitCommit.Changes {
+			changedFiles[change.FilePath] = true
+		}
+
 		err := ra.processGitCommit(projectID, gitCommit)
 		if err != nil {
 			log.Printf("Error processing commit %s: %v", gitCommit.Hash, err)
@@ -335,8 +365,8 @@ func (ra *RepositoryAnalyzer) AnalyzeProjectSince(projectID int, repoPath string
 
 	// Update project's last analyzed hash
 	if len(commits) > 0 {
-		lastCommit := commits[len(commits)-1]
-		hashValue, err := values.NewGitHash(lastCommit.Hash)
+		latestCommit := commits[0]
+		hashValue, err := values.NewGitHash(latestCommit.Hash)
 		if err == nil {
 			project, err := ra.projectRepo.GetByID(projectID)
 			if err == nil {
@@ -344,7 +374,10 @@ func (ra *RepositoryAnalyzer) AnalyzeProjectSince(projectID int, repoPath string
 				ra.projectRepo.Update(project)
 			}
 		}
+
+		ra.snapshotTree(projectID, repoPath, latestCommit.Hash, changedFiles)
 	}
+	ra.recordReleases(projectID, repoPath)
 
 	return nil
 }
@@ -12,7 +12,7 @@ import (
 // DB holds the database connection
 var DB *sql.DB
 
-// InitDB initializes the database connection
+// InitDB initializes the database connection to DB_DSN; see Open for the supported DSNs
 func InitDB() error {
 	dsn := os.Getenv("DB_DSN")
 	if dsn == "" {
@@ -20,7 +20,7 @@ func InitDB() error {
 	}
 
 	var err error
-	DB, err = sql.Open("mysql", dsn)
+	DB, err = Open(dsn)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -0,0 +1,378 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"codeecho/migrations"
+)
+
+// Migration is one version of a backend's schema: the script applying it and the one
+// reverting it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA-256 of Up, recorded when the migration is applied
+	Checksum string
+}
+
+// MigrationStatus is a migration together with whether and when it was applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	// Modified is set when the applied script differs from the one in this build
+	Modified bool
+	// Unknown is set for applied versions this build has no migration for, e.g. after a
+	// downgrade; only their version, name and checksum are known
+	Unknown bool
+}
+
+// ErrMigrationsDiverged is returned when the applied migrations are not those of this build:
+// a script was edited after it was applied, or the database has a version this build lacks
+var ErrMigrationsDiverged = errors.New("the applied migrations differ from this build")
+
+// migrationFile matches the names of migration scripts: version, name and direction
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// baselineFile is the optional script of a dialect that upgrades a database created before
+// migrations were tracked to the schema of the first migration
+const baselineFile = "baseline.sql"
+
+// migrationsTable records the applied migrations in SQL every dialect accepts
+const migrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`
+
+// Migrator applies and reverts the embedded migrations of a database's dialect and records
+// the applied versions in the schema_migrations table. Each migration runs in a transaction;
+// MySQL commits schema changes as it runs them, so a failed MySQL migration may stay partly
+// applied. Only one migrator should run against a database at a time.
+//
+// A database whose tables predate the schema_migrations table is adopted: the dialect's
+// baseline.sql runs after the first migration, in its transaction, to add what the first
+// migration's CREATE TABLE IF NOT EXISTS statements skip over in existing tables.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	baseline   string
+}
+
+// appliedMigration is a row of the schema_migrations table
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// NewMigrator loads the migrations of the dialect of a database opened with Open
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	dialect := DialectOf(db)
+	dir, err := fs.Sub(migrations.FS, string(dialect))
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db, dialect, dir)
+}
+
+// newMigrator loads the migrations and the baseline script of a dialect's directory
+func newMigrator(db *sql.DB, dialect Dialect, dir fs.FS) (*Migrator, error) {
+	loaded, err := loadMigrations(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s migrations: %w", dialect, err)
+	}
+	baseline, err := fs.ReadFile(dir, baselineFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: loaded, baseline: string(baseline)}, nil
+}
+
+// loadMigrations reads the migration scripts of a directory, ordered by version
+func loadMigrations(dir fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(dir, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	type scripts struct {
+		migration Migration
+		up, down  bool
+	}
+	byVersion := make(map[int]*scripts)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			if strings.HasSuffix(entry.Name(), ".sql") && entry.Name() != baselineFile {
+				return nil, fmt.Errorf("%s is not named NNN_name.up.sql or NNN_name.down.sql", entry.Name())
+			}
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%s: versions are numbered from 1", entry.Name())
+		}
+		content, err := fs.ReadFile(dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		s := byVersion[version]
+		if s == nil {
+			s = &scripts{migration: Migration{Version: version, Name: match[2]}}
+			byVersion[version] = s
+		} else if s.migration.Name != match[2] {
+			return nil, fmt.Errorf("version %d is used by %s and %s", version, s.migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			s.migration.Up, s.up = string(content), true
+		} else {
+			s.migration.Down, s.down = string(content), true
+		}
+	}
+
+	loaded := make([]Migration, 0, len(byVersion))
+	for version, s := range byVersion {
+		if !s.up || !s.down {
+			return nil, fmt.Errorf("migration %d (%s) needs both an up and a down script", version, s.migration.Name)
+		}
+		sum := sha256.Sum256([]byte(s.migration.Up))
+		s.migration.Checksum = hex.EncodeToString(sum[:])
+		loaded = append(loaded, s.migration)
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Version < loaded[j].Version })
+	return loaded, nil
+}
+
+// Up applies the pending migrations in version order and returns them
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.verifiedApplied()
+	if err != nil {
+		return nil, err
+	}
+
+	// Tables from before migrations were tracked are only recognisable until the first
+	// migration has created the rest
+	adopt := false
+	if len(applied) == 0 && m.baseline != "" {
+		if adopt, err = m.tableExists("projects"); err != nil {
+			return nil, err
+		}
+	}
+
+	var done []Migration
+	for i, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		script := migration.Up
+		if i == 0 && adopt {
+			script += "\n;\n" + m.baseline
+		}
+		if err := m.run(migration, script, true); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts up to steps of the applied migrations, newest first, and returns them
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.verifiedApplied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := m.run(migration, migration.Down, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status lists the migrations of this build and the applied ones it does not know, by version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]bool, len(m.migrations))
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = record.checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := record.appliedAt
+		statuses = append(statuses, MigrationStatus{
+			Migration: Migration{Version: version, Name: record.name, Checksum: record.checksum},
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// applied reads the applied migrations by version, creating the schema_migrations table when
+// it is missing
+func (m *Migrator) applied() (map[int]appliedMigration, error) {
+	if _, err := m.db.Exec(migrationsTable); err != nil {
+		return nil, fmt.Errorf("failed to create the schema_migrations table: %w", err)
+	}
+
+	rows, err := m.db.Query(`SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+		}
+		applied[version] = record
+	}
+	return applied, rows.Err()
+}
+
+// verifiedApplied reads the applied migrations and checks that this build has each of them
+// unchanged, as migrating on top of other scripts would leave the schema in an unknown state
+func (m *Migrator) verifiedApplied() (map[int]appliedMigration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if record, ok := applied[migration.Version]; ok && record.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: migration %d (%s) was changed after it was applied: its checksum is %s, the applied one %s",
+				ErrMigrationsDiverged, migration.Version, migration.Name, migration.Checksum, record.checksum)
+		}
+	}
+	for version, record := range applied {
+		if !m.has(version) {
+			return nil, fmt.Errorf("%w: migration %d (%s) is applied but not part of this build", ErrMigrationsDiverged, version, record.name)
+		}
+	}
+	return applied, nil
+}
+
+// has reports whether this build has a migration of version
+func (m *Migrator) has(version int) bool {
+	i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= version })
+	return i < len(m.migrations) && m.migrations[i].Version == version
+}
+
+// tableExists reports whether the database has a table
+func (m *Migrator) tableExists(name string) (bool, error) {
+	query := `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?`
+	switch m.dialect {
+	case SQLite:
+		query = `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`
+	case Postgres:
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?`
+	}
+	var count int
+	if err := m.db.QueryRow(query, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to look up the %s table: %w", name, err)
+	}
+	return count > 0, nil
+}
+
+// run applies or reverts a migration with script in a transaction that also records it
+func (m *Migrator) run(migration Migration, script string, up bool) error {
+	direction := "revert"
+	if up {
+		direction = "apply"
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, statement := range splitStatements(script) {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to %s migration %d (%s) at statement %d: %w", direction, migration.Version, migration.Name, i+1, err)
+		}
+	}
+	if up {
+		_, err = tx.Exec(`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			migration.Version, migration.Name, migration.Checksum, time.Now().UTC().Truncate(time.Second))
+	} else {
+		_, err = tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+	return tx.Commit()
+}
+
+// splitStatements splits a script at the semicolons outside string literals, quoted
+// identifiers and comments, dropping statements without any SQL
+func splitStatements(script string) []string {
+	var statements []string
+	start, hasSQL := 0, false
+	for i := 0; i < len(script); {
+		end := i + 1
+		switch c := script[i]; {
+		case c == ';':
+			if hasSQL {
+				statements = append(statements, strings.TrimSpace(script[start:i]))
+			}
+			start, hasSQL = end, false
+		case c == '\'' || c == '"' || c == '`':
+			end = skipPast(script, i+1, script[i:i+1])
+			hasSQL = true
+		case strings.HasPrefix(script[i:], "--"):
+			end = skipPast(script, i+2, "\n")
+		case strings.HasPrefix(script[i:], "/*"):
+			end = skipPast(script, i+2, "*/")
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			hasSQL = true
+		}
+		i = end
+	}
+	if hasSQL {
+		statements = append(statements, strings.TrimSpace(script[start:]))
+	}
+	return statements
+}
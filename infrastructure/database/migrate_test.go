@@ -0,0 +1,202 @@
+package database
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"codeecho/migrations"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- Leading comment; with a semicolon
+CREATE TABLE a (id INT);
+INSERT INTO a VALUES (1); /* trailing; comment */
+SET @s = 'ALTER TABLE a ADD COLUMN b VARCHAR(10) DEFAULT ''x;y''';
+SELECT "odd;name", ` + "`odd;too`" + ` FROM a
+-- only a comment;
+`
+	want := []string{
+		"-- Leading comment; with a semicolon\nCREATE TABLE a (id INT)",
+		"INSERT INTO a VALUES (1)",
+		"/* trailing; comment */\nSET @s = 'ALTER TABLE a ADD COLUMN b VARCHAR(10) DEFAULT ''x;y'''",
+		"SELECT \"odd;name\", `odd;too` FROM a\n-- only a comment;",
+	}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	dir := fstest.MapFS{
+		"002_add_b.up.sql":            {Data: []byte("ALTER TABLE a ADD COLUMN b INT;")},
+		"002_add_b.down.sql":          {Data: []byte("ALTER TABLE a DROP COLUMN b;")},
+		"001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"001_initial_schema.down.sql": {Data: []byte("DROP TABLE a;")},
+		"README.md":                   {Data: []byte("not a migration")},
+		"baseline.sql":                {Data: []byte("ALTER TABLE a ADD COLUMN legacy INT;")},
+	}
+	loaded, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0].Version != 1 || loaded[0].Name != "initial_schema" || loaded[1].Down != "ALTER TABLE a DROP COLUMN b;" {
+		t.Fatalf("unexpected migrations %+v", loaded)
+	}
+	if len(loaded[0].Checksum) != 64 || loaded[0].Checksum == loaded[1].Checksum {
+		t.Errorf("expected distinct SHA-256 checksums, got %q and %q", loaded[0].Checksum, loaded[1].Checksum)
+	}
+
+	for name, invalid := range map[string]fstest.MapFS{
+		"missing down": {"001_a.up.sql": {}},
+		"two names":    {"001_a.up.sql": {}, "001_a.down.sql": {}, "001_b.up.sql": {}, "001_b.down.sql": {}},
+		"version zero": {"000_a.up.sql": {}, "000_a.down.sql": {}},
+		"misnamed":     {"001_a.sql": {}},
+	} {
+		if _, err := loadMigrations(invalid); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	// Every dialect has the same versions, so that no backend misses a schema change
+	var versions []int
+	for _, dialect := range []Dialect{MySQL, Postgres, SQLite} {
+		dir, err := fs.Sub(migrations.FS, string(dialect))
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := loadMigrations(dir)
+		if err != nil {
+			t.Fatalf("%s: %v", dialect, err)
+		}
+
+		var got []int
+		for _, migration := range loaded {
+			got = append(got, migration.Version)
+		}
+		if len(got) == 0 || (versions != nil && !reflect.DeepEqual(got, versions)) {
+			t.Errorf("%s: expected the versions %v, got %v", dialect, versions, got)
+		}
+		versions = got
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	// New SQLite files are migrated when they are opened
+	db, err := Open("sqlite://" + filepath.Join(t.TempDir(), "codeecho.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil || status.Modified || status.Unknown {
+			t.Errorf("expected migration %d to be applied as it is, got %+v", status.Version, status)
+		}
+	}
+	if applied, err := migrator.Up(); err != nil || len(applied) != 0 {
+		t.Errorf("expected nothing to apply, got %v (%v)", applied, err)
+	}
+
+	reverted, err := migrator.Down(len(statuses) + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(statuses) || reverted[0].Version != statuses[len(statuses)-1].Version {
+		t.Errorf("expected every migration to be reverted newest first, got %+v", reverted)
+	}
+	var tables int
+	db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'projects'`).Scan(&tables)
+	if tables != 0 {
+		t.Error("expected the projects table to be dropped")
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(statuses) {
+		t.Errorf("expected every migration to be applied again, got %+v", applied)
+	}
+	if _, err := db.Exec(`INSERT INTO projects (name, repo_path) VALUES ('p', '/tmp/p')`); err != nil {
+		t.Errorf("expected the schema to be back: %v", err)
+	}
+
+	// An applied script that changed, or one this build does not have, stops every migration
+	db.Exec(`UPDATE schema_migrations SET checksum = 'edited' WHERE version = 1`)
+	if _, err := migrator.Up(); !errors.Is(err, ErrMigrationsDiverged) || !strings.Contains(err.Error(), "changed after it was applied") {
+		t.Errorf("expected the checksum mismatch to fail, got %v", err)
+	}
+	if statuses, _ := migrator.Status(); !statuses[0].Modified {
+		t.Errorf("expected the status to report the change, got %+v", statuses[0])
+	}
+	db.Exec(`UPDATE schema_migrations SET checksum = ? WHERE version = 1`, migrator.migrations[0].Checksum)
+	db.Exec(`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (999, 'from_the_future', 'x', '2025-01-01 00:00:00')`)
+	if _, err := migrator.Down(1); !errors.Is(err, ErrMigrationsDiverged) || !strings.Contains(err.Error(), "not part of this build") {
+		t.Errorf("expected the unknown migration to fail, got %v", err)
+	}
+	if statuses, _ := migrator.Status(); !statuses[len(statuses)-1].Unknown {
+		t.Errorf("expected the status to list the unknown migration, got %+v", statuses[len(statuses)-1])
+	}
+}
+
+func TestMigratorBaseline(t *testing.T) {
+	db, err := Open("sqlite://:memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	embedded, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := embedded.Down(len(embedded.migrations)); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := newMigrator(db, SQLite, fstest.MapFS{
+		"001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE IF NOT EXISTS projects (id INTEGER PRIMARY KEY);")},
+		"001_initial_schema.down.sql": {Data: []byte("DROP TABLE projects;")},
+		"baseline.sql":                {Data: []byte("CREATE TABLE adopted (id INTEGER);")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adopted := func() bool {
+		var tables int
+		db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'adopted'`).Scan(&tables)
+		return tables > 0
+	}
+
+	// A new database only gets the first migration
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if adopted() {
+		t.Error("expected the baseline to be left out for a new database")
+	}
+
+	// Tables from before migrations were tracked get the baseline together with the first migration
+	db.Exec(`DELETE FROM schema_migrations`)
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || !adopted() {
+		t.Errorf("expected the first migration to adopt the existing tables, got %+v", applied)
+	}
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// openPostgres opens the PostgreSQL database of a postgres:// URL. Sessions use UTC unless the
+// DSN sets a timezone, so that CURRENT_TIMESTAMP defaults match the UTC timestamps written by
+// the repositories.
+func openPostgres(dsn string) (*sql.DB, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres DSN: %w", err)
+	}
+	params := u.Query()
+	hasTimezone := false
+	for key := range params {
+		if strings.EqualFold(key, "timezone") {
+			hasTimezone = true
+		}
+	}
+	if !hasTimezone {
+		params.Set("timezone", "UTC")
+	}
+	u.RawQuery = params.Encode()
+
+	return sql.OpenDB(postgresConnector{dsn: u.String()}), nil
+}
+
+// postgresDriver wraps the pgx driver so that it runs the repositories' MySQL style queries:
+// '?' placeholders are rewritten to PostgreSQL's numbered ones, and time.Time arguments are
+// converted to UTC at second precision before they are stored in TIMESTAMP columns, as the
+// MySQL driver does
+type postgresDriver struct{}
+
+// Open opens a connection to a PostgreSQL DSN
+func (postgresDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := stdlib.GetDefaultDriver().Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresConn{conn: conn}, nil
+}
+
+// postgresConnector opens connections to one DSN for sql.OpenDB
+type postgresConnector struct {
+	dsn string
+}
+
+func (c postgresConnector) Connect(context.Context) (driver.Conn, error) {
+	return postgresDriver{}.Open(c.dsn)
+}
+
+func (c postgresConnector) Driver() driver.Driver {
+	return &postgresDriver{}
+}
+
+// postgresConn is a connection of postgresDriver; it forwards to the wrapped pgx connection
+type postgresConn struct {
+	conn driver.Conn
+}
+
+func (c *postgresConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *postgresConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.conn.(driver.ConnPrepareContext).PrepareContext(ctx, rebindPostgres(query))
+}
+
+func (c *postgresConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *postgresConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *postgresConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *postgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.conn.(driver.ExecerContext).ExecContext(ctx, rebindPostgres(query), args)
+}
+
+func (c *postgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.conn.(driver.QueryerContext).QueryContext(ctx, rebindPostgres(query), args)
+}
+
+func (c *postgresConn) Ping(ctx context.Context) error {
+	return c.conn.(driver.Pinger).Ping(ctx)
+}
+
+func (c *postgresConn) ResetSession(ctx context.Context) error {
+	return c.conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+// CheckNamedValue converts time.Time arguments to UTC at second precision; pgx stores the
+// wall clock of a time in TIMESTAMP columns and accepts every other argument as it is
+func (c *postgresConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok {
+		nv.Value = t.UTC().Truncate(time.Second)
+	}
+	return nil
+}
+
+// rebindPostgres replaces the '?' placeholders of a query with $1, $2, ..., leaving string
+// literals, quoted identifiers and comments alone
+func rebindPostgres(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+	n := 0
+	for i := 0; i < len(query); {
+		if query[i] == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			i++
+			continue
+		}
+		end := i + 1
+		switch {
+		case query[i] == '\'' || query[i] == '"':
+			// A doubled quote inside a literal closes and reopens it, which copies as is
+			end = skipPast(query, i+1, query[i:i+1])
+		case strings.HasPrefix(query[i:], "--"):
+			end = skipPast(query, i+2, "\n")
+		case strings.HasPrefix(query[i:], "/*"):
+			end = skipPast(query, i+2, "*/")
+		}
+		b.WriteString(query[i:end])
+		i = end
+	}
+	return b.String()
+}
+
+// skipPast returns the index after the first terminator in query from start on, or the end
+// of the query when it is not terminated
+func skipPast(query string, start int, terminator string) int {
+	if at := strings.Index(query[start:], terminator); at >= 0 {
+		return start + at + len(terminator)
+	}
+	return len(query)
+}
@@ -0,0 +1,42 @@
+package database
+
+import "testing"
+
+func TestRebindPostgres(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT * FROM commits WHERE project_id = ? AND hash = ?", "SELECT * FROM commits WHERE project_id = $1 AND hash = $2"},
+		{"INSERT INTO t VALUES (?, ?), (?, ?)", "INSERT INTO t VALUES ($1, $2), ($3, $4)"},
+		{"SELECT '?', 'it''s ?' FROM t WHERE a = ?", "SELECT '?', 'it''s ?' FROM t WHERE a = $1"},
+		{`SELECT "odd?column" FROM t WHERE a = ?`, `SELECT "odd?column" FROM t WHERE a = $1`},
+		{"SELECT a - ? -- why?\nFROM t WHERE b = ?", "SELECT a - $1 -- why?\nFROM t WHERE b = $2"},
+		{"SELECT a / ? /* why? */ FROM t WHERE b = ?", "SELECT a / $1 /* why? */ FROM t WHERE b = $2"},
+		{"SELECT 'unterminated ?", "SELECT 'unterminated ?"},
+		{"SELECT ? -- trailing ?", "SELECT $1 -- trailing ?"},
+	}
+	for _, tt := range tests {
+		if got := rebindPostgres(tt.query); got != tt.want {
+			t.Errorf("rebindPostgres(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestOpenPostgres(t *testing.T) {
+	for _, dsn := range []string{"postgres://codeecho@localhost/codeecho_db", "postgresql://codeecho@localhost/codeecho_db?timezone=UTC"} {
+		db, err := Open(dsn)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", dsn, err)
+		}
+		if dialect := DialectOf(db); dialect != Postgres {
+			t.Errorf("expected the postgres dialect for %q, got %q", dsn, dialect)
+		}
+		db.Close()
+	}
+
+	if _, err := Open("postgres://codeecho@localhost:port/codeecho_db"); err == nil {
+		t.Error("expected an invalid URL to fail")
+	}
+}
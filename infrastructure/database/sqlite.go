@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteTimeLayout is how timestamps are stored in SQLite: in UTC at second precision, as MySQL
+// stores TIMESTAMP columns, so that they compare as text with each other and with the
+// "2006-01-02" and "2006-01-02 15:04:05" strings the queries filter on
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// sqliteDefaults are the connection parameters a SQLite DSN gets unless it sets them itself:
+// enforced foreign keys for the cascading deletes, a write-ahead log and a busy timeout so the
+// API can read while an analysis writes, and immediate transactions so that two writers wait
+// for each other instead of failing on a lock upgrade
+var sqliteDefaults = []struct{ key, value string }{
+	{"_pragma", "foreign_keys(1)"},
+	{"_pragma", "journal_mode(WAL)"},
+	{"_pragma", "busy_timeout(10000)"},
+	{"_txlock", "immediate"},
+}
+
+// openSQLite opens the SQLite database file at path, which may carry driver parameters after
+// a '?', and migrates it when it has never been migrated, which creates the schema of new
+// files. ":memory:" opens a private in-memory database on a single connection.
+func openSQLite(path string) (*sql.DB, error) {
+	file, rawQuery, _ := strings.Cut(path, "?")
+	if file == "" {
+		return nil, fmt.Errorf("the sqlite DSN needs a database file, e.g. sqlite:///var/lib/codeecho.db")
+	}
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sqlite DSN parameters: %w", err)
+	}
+	for _, def := range sqliteDefaults {
+		if !hasSQLiteParam(params, def.key, def.value) {
+			params.Add(def.key, def.value)
+		}
+	}
+
+	db := sql.OpenDB(sqliteConnector{dsn: file + "?" + params.Encode()})
+	if file == ":memory:" {
+		// Every connection would get its own empty database
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxIdleTime(0)
+		db.SetConnMaxLifetime(0)
+	}
+	if err := migrateNewSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the sqlite schema: %w", err)
+	}
+	return db, nil
+}
+
+// migrateNewSQLite applies the migrations to a database without a schema_migrations table;
+// databases migrated before only change with Migrator.Up and Down
+func migrateNewSQLite(db *sql.DB) error {
+	var tracked int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&tracked)
+	if err != nil || tracked > 0 {
+		return err
+	}
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up()
+	return err
+}
+
+// hasSQLiteParam reports whether the DSN parameters already set key, or for pragmas the
+// pragma named in value
+func hasSQLiteParam(params url.Values, key, value string) bool {
+	if key != "_pragma" {
+		return params.Has(key)
+	}
+	name, _, _ := strings.Cut(value, "(")
+	for _, pragma := range params[key] {
+		if strings.EqualFold(strings.TrimSpace(strings.SplitN(pragma, "(", 2)[0]), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqliteDriver wraps the pure-Go SQLite driver so that it stores time.Time arguments in
+// sqliteTimeLayout. Columns declared as timestamps or dates are read back as time.Time by the
+// driver; timestamps computed by expressions such as MAX(timestamp) come back as text and are
+// scanned with Timestamp.
+type sqliteDriver struct{}
+
+// Open opens a connection to a SQLite DSN
+func (sqliteDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := (&sqlite.Driver{}).Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteConn{conn: conn}, nil
+}
+
+// sqliteConnector opens connections to one DSN for sql.OpenDB
+type sqliteConnector struct {
+	dsn string
+}
+
+func (c sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	return sqliteDriver{}.Open(c.dsn)
+}
+
+func (c sqliteConnector) Driver() driver.Driver {
+	return &sqliteDriver{}
+}
+
+// sqliteConn is a connection of sqliteDriver; it forwards to the wrapped connection, which
+// implements every optional interface used here
+type sqliteConn struct {
+	conn driver.Conn
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteStmt{stmt: stmt}, nil
+}
+
+func (c *sqliteConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *sqliteConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqliteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (c *sqliteConn) Ping(ctx context.Context) error {
+	return c.conn.(driver.Pinger).Ping(ctx)
+}
+
+func (c *sqliteConn) ResetSession(ctx context.Context) error {
+	return c.conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+func (c *sqliteConn) IsValid() bool {
+	return c.conn.(driver.Validator).IsValid()
+}
+
+// CheckNamedValue stores time.Time arguments in sqliteTimeLayout and leaves other arguments to
+// the default conversion
+func (c *sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok {
+		nv.Value = t.UTC().Format(sqliteTimeLayout)
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+// sqliteStmt is a prepared statement of sqliteConn
+type sqliteStmt struct {
+	stmt driver.Stmt
+}
+
+func (s *sqliteStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *sqliteStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *sqliteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+}
+
+func (s *sqliteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// namedValues converts positional arguments to ordinal named values
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// parseSQLiteTime parses a timestamp stored in sqliteTimeLayout or a date
+func parseSQLiteTime(text string) (time.Time, bool) {
+	var layout string
+	switch len(text) {
+	case len(sqliteTimeLayout):
+		layout = sqliteTimeLayout
+	case len("2006-01-02"):
+		layout = "2006-01-02"
+	default:
+		return time.Time{}, false
+	}
+	t, err := time.Parse(layout, text)
+	return t, err == nil
+}
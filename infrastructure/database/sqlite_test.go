@@ -0,0 +1,48 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSQLiteTimestamps(t *testing.T) {
+	db, err := Open("sqlite://:memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	committed := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	if _, err := db.Exec(`CREATE TABLE events (path TEXT NOT NULL, happened_at TIMESTAMP NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO events VALUES (?, ?)`, "2024-01-02", committed); err != nil {
+		t.Fatal(err)
+	}
+
+	// Declared timestamps are read as time.Time; text that merely looks like a date, whether
+	// computed or literal, stays text
+	var happenedAt time.Time
+	var maxPath, literal string
+	err = db.QueryRow(`SELECT happened_at, MAX(path), '2024-01-02 00:00:00' FROM events`).Scan(&happenedAt, &maxPath, &literal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !happenedAt.Equal(committed) || maxPath != "2024-01-02" || literal != "2024-01-02 00:00:00" {
+		t.Errorf("got %v, %q and %q", happenedAt, maxPath, literal)
+	}
+
+	// Computed timestamps are parsed by Timestamp, NULL being the zero time
+	var latest, day, none time.Time
+	err = db.QueryRow(`SELECT MAX(happened_at), DATE(happened_at), MAX(NULL) FROM events`).
+		Scan(Timestamp(&latest), Timestamp(&day), Timestamp(&none))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !latest.Equal(committed) || !day.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || !none.IsZero() {
+		t.Errorf("got %v, %v and %v", latest, day, none)
+	}
+	if err := db.QueryRow(`SELECT MAX(path) || 'x' FROM events`).Scan(Timestamp(&latest)); err == nil {
+		t.Error("expected text that is no timestamp to fail")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -138,6 +139,242 @@ func (gs *GitServiceImpl) CloneRepository(repoURL string) (string, error) {
 	return tempDir, nil
 }
 
+// openRepository opens a local repository, reusing an existing clone for remote URLs
+func (gs *GitServiceImpl) openRepository(repoPath string) (*git.Repository, error) {
+	localPath := repoPath
+	if gs.isRemoteURL(repoPath) {
+		localPath = filepath.Join("/tmp", "codeecho-repos", gs.getRepoNameFromURL(repoPath))
+		if _, err := os.Stat(localPath); err != nil {
+			if localPath, err = gs.CloneRepository(repoPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	repo, err := git.PlainOpen(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository at %s: %w", localPath, err)
+	}
+	return repo, nil
+}
+
+// resolveCommit resolves a hash or revision (e.g. HEAD, tag name) to a commit object
+func (gs *GitServiceImpl) resolveCommit(repo *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %s: %w", revision, err)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commit %s: %w", hash.String(), err)
+	}
+	return commit, nil
+}
+
+// GetFileContentAtCommit reads the blob of a file as it was at the given commit
+func (gs *GitServiceImpl) GetFileContentAtCommit(repoPath string, commitHash string, filePath string) (string, error) {
+	repo, err := gs.openRepository(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := gs.resolveCommit(repo, commitHash)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := commit.File(filePath)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", fmt.Errorf("%w: %s at %s", ports.ErrFileNotFound, filePath, commitHash)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", filePath, commitHash, err)
+	}
+
+	return file.Contents()
+}
+
+// WalkFilesAtCommit calls visit for every file in the tree of the given commit
+func (gs *GitServiceImpl) WalkFilesAtCommit(repoPath string, commitHash string, visit func(file *ports.GitFile) error) error {
+	repo, err := gs.openRepository(repoPath)
+	if err != nil {
+		return err
+	}
+
+	commit, err := gs.resolveCommit(repo, commitHash)
+	if err != nil {
+		return err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	return tree.Files().ForEach(func(file *object.File) error {
+		gitFile := &ports.GitFile{Path: file.Name, Size: file.Size}
+
+		// Only the first bytes are read to tell binaries apart, so their blobs are never loaded
+		isBinary, err := file.IsBinary()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		if isBinary {
+			gitFile.IsBinary = true
+			return visit(gitFile)
+		}
+
+		gitFile.Content, err = file.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		return visit(gitFile)
+	})
+}
+
+// ListFilesAtCommit lists the paths of the files in the tree of the given commit without reading them
+func (gs *GitServiceImpl) ListFilesAtCommit(repoPath string, commitHash string) ([]string, error) {
+	repo, err := gs.openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := gs.resolveCommit(repo, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
+	var files []string
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk tree: %w", err)
+		}
+		if entry.Mode.IsFile() {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// DiffRefs lists the files changed on head since it diverged from base, like a pull request
+// diff: the comparison starts at the merge base of the two, or at base when they share none
+func (gs *GitServiceImpl) DiffRefs(repoPath string, baseRef string, headRef string) ([]*ports.GitChange, error) {
+	repo, err := gs.openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := gs.resolveCommit(repo, baseRef)
+	if err != nil {
+		return nil, err
+	}
+	head, err := gs.resolveCommit(repo, headRef)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := base.MergeBase(head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base of %s and %s: %w", baseRef, headRef, err)
+	}
+	if len(bases) > 0 {
+		base = bases[0]
+	}
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base tree: %w", err)
+	}
+	headTree, err := head.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head tree: %w", err)
+	}
+	return gs.diffTrees(baseTree, headTree)
+}
+
+// BlameFile attributes every line of a file at the given commit to the commit that last changed it
+func (gs *GitServiceImpl) BlameFile(repoPath string, commitHash string, filePath string) ([]*ports.GitBlameLine, error) {
+	repo, err := gs.openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := gs.resolveCommit(repo, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := git.Blame(commit, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", filePath, err)
+	}
+
+	lines := make([]*ports.GitBlameLine, 0, len(result.Lines))
+	for _, line := range result.Lines {
+		lines = append(lines, &ports.GitBlameLine{
+			Author:     line.AuthorName,
+			CommitHash: line.Hash.String(),
+			Date:       line.Date,
+		})
+	}
+	return lines, nil
+}
+
+// GetTags lists the tags of a repository that point at commits, resolving annotated tags to
+// their target commit; tags of trees or blobs are skipped
+func (gs *GitServiceImpl) GetTags(repoPath string) ([]*ports.GitTag, error) {
+	repo, err := gs.openRepository(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer refs.Close()
+
+	var tags []*ports.GitTag
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag := &ports.GitTag{Name: ref.Name().Short()}
+
+		var commit *object.Commit
+		if annotated, err := repo.TagObject(ref.Hash()); err == nil {
+			if commit, err = annotated.Commit(); err != nil {
+				return nil
+			}
+			tag.TaggedAt = annotated.Tagger.When
+		} else if commit, err = repo.CommitObject(ref.Hash()); err != nil {
+			return nil
+		}
+
+		tag.CommitHash = commit.Hash.String()
+		tag.CommitDate = commit.Author.When
+		if tag.TaggedAt.IsZero() {
+			tag.TaggedAt = commit.Author.When
+		}
+		tags = append(tags, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+	return tags, nil
+}
+
 // getRepoNameFromURL extracts repository name from URL
 func (gs *GitServiceImpl) getRepoNameFromURL(url string) string {
 	// Extract repo name from URL like https://github.com/user/repo.git -> repo
@@ -191,40 +428,41 @@ func (gs *GitServiceImpl) getCommitsFromHash(repoPath string, fromHash string) (
 		return nil, fmt.Errorf("failed to open repository at %s: %w", repoPath, err)
 	}
 
-	// Get commit iterator
-	var commitIter object.CommitIter
-	if fromHash == "" {
-		// Get all commits from HEAD
-		ref, err := repo.Head()
+	// Commits reachable from the given hash were analysed before; walking from HEAD
+	// while treating them as seen returns every newer commit, including those of
+	// side branches merged since (newest first)
+	seen := make(map[plumbing.Hash]bool)
+	if fromHash != "" {
+		from, err := repo.CommitObject(plumbing.NewHash(fromHash))
 		if err != nil {
-			return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+			return nil, fmt.Errorf("failed to resolve commit %s: %w", fromHash, err)
 		}
-		log.Printf("[git] Walking commits from HEAD: %s", ref.Hash())
-
-		commitIter, err = repo.Log(&git.LogOptions{From: ref.Hash()})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get commit logs: %w", err)
-		}
-	} else {
-		// Get commits starting from specific hash
-		hash := plumbing.NewHash(fromHash)
-		commitIter, err = repo.Log(&git.LogOptions{From: hash})
+		err = object.NewCommitPreorderIter(from, nil, nil).ForEach(func(commit *object.Commit) error {
+			seen[commit.Hash] = true
+			return nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get commit logs from %s: %w", fromHash, err)
+			return nil, fmt.Errorf("failed to walk the history of %s: %w", fromHash, err)
 		}
 	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	log.Printf("[git] Walking commits from HEAD: %s", ref.Hash())
+
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	commitIter := object.NewCommitPreorderIter(head, seen, nil)
 	defer commitIter.Close()
 
 	var gitCommits []*ports.GitCommit
-	var skipFirst bool = fromHash != "" // Skip the first commit if we're getting commits since a hash
 
 	commitCounter := 0
 	err = commitIter.ForEach(func(commit *object.Commit) error {
-		if skipFirst {
-			skipFirst = false
-			return nil
-		}
-
 		// Get file changes for this commit
 		changes, err := gs.getCommitChanges(commit)
 		if err != nil {
@@ -282,7 +520,23 @@ func (gs *GitServiceImpl) getCommitChanges(commit *object.Commit) ([]*ports.GitC
 		return nil, fmt.Errorf("failed to get current tree: %w", err)
 	}
 
-	changelist, err := parentTree.Diff(currentTree)
+	changes, err = gs.diffTrees(parentTree, currentTree)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		log.Printf("[git] Commit %s produced zero file changes (possibly merge or empty commit?)", commit.Hash.String())
+	}
+	return changes, nil
+}
+
+// diffTrees lists the files changed between two trees with their added and deleted lines;
+// renamed files are reported under their new path
+func (gs *GitServiceImpl) diffTrees(fromTree, toTree *object.Tree) ([]*ports.GitChange, error) {
+	var changes []*ports.GitChange
+
+	changelist, err := fromTree.Diff(toTree)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get diff: %w", err)
 	}
@@ -320,9 +574,6 @@ func (gs *GitServiceImpl) getCommitChanges(commit *object.Commit) ([]*ports.GitC
 		}
 	}
 
-	if len(changes) == 0 {
-		log.Printf("[git] Commit %s produced zero file changes (possibly merge or empty commit?)", commit.Hash.String())
-	}
 	return changes, nil
 }
 
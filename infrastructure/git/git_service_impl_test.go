@@ -0,0 +1,135 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"codeecho/application/ports"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGetCommitsFromHash(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	commit := func(file string, parents ...plumbing.Hash) plumbing.Hash {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tree.Add(file); err != nil {
+			t.Fatal(err)
+		}
+		at = at.Add(time.Hour)
+		hash, err := tree.Commit(file, &git.CommitOptions{
+			Author:  &object.Signature{Name: "alice", Email: "alice@example.com", When: at},
+			Parents: parents,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	// root - side, merged after main had moved on to analysed and main
+	root := commit("root.go")
+	side := commit("side.go")
+	if err := tree.Reset(&git.ResetOptions{Commit: root, Mode: git.HardReset}); err != nil {
+		t.Fatal(err)
+	}
+	analysed := commit("analysed.go")
+	main := commit("main.go")
+	merge := commit("merge.go", main, side)
+
+	gs := &GitServiceImpl{}
+	commits, err := gs.getCommitsFromHash(dir, analysed.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range commits {
+		got = append(got, c.Hash)
+	}
+	want := []string{merge.String(), main.String(), side.String()}
+	if !slices.Equal(got, want) {
+		t.Errorf("got commits %v, want %v", got, want)
+	}
+
+	all, err := gs.getCommitsFromHash(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Errorf("got %d commits from the beginning, want 5", len(all))
+	}
+
+	if _, err := gs.getCommitsFromHash(dir, "0123456789012345678901234567890123456789"); err == nil {
+		t.Error("expected an unknown hash to fail")
+	}
+}
+
+func TestWalkFilesAtCommitSkipsBinaryContent(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"main.go":   "package main\n",
+		"image.png": "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash, err := tree.Commit("files", &git.CommitOptions{
+		Author: &object.Signature{Name: "alice", Email: "alice@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walked := make(map[string]*ports.GitFile)
+	err = (&GitServiceImpl{}).WalkFilesAtCommit(dir, hash.String(), func(file *ports.GitFile) error {
+		walked[file.Path] = file
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f := walked["main.go"]; f == nil || f.IsBinary || f.Content != files["main.go"] {
+		t.Errorf("unexpected text file: %+v", f)
+	}
+	if f := walked["image.png"]; f == nil || !f.IsBinary || f.Content != "" || f.Size != int64(len(files["image.png"])) {
+		t.Errorf("unexpected binary file: %+v", f)
+	}
+
+	if _, err := (&GitServiceImpl{}).GetFileContentAtCommit(dir, hash.String(), "missing.go"); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("expected a missing file to fail with ErrFileNotFound, got %v", err)
+	}
+}
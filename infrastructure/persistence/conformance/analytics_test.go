@@ -0,0 +1,468 @@
+package conformance
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+	"codeecho/internal/models"
+)
+
+// analyticsTestStart is when the history seeded by seedAnalyticsProject begins
+var analyticsTestStart = time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+// seedAnalyticsProject stores a project with three daily commits the way an analysis does:
+//
+//	day 0  ann  parser.go lexer.go
+//	day 1  bob  parser.go lexer.go
+//	day 2  ann  parser.go docs/README.md
+func seedAnalyticsProject(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	return seedTestHistory(t, db, []testHistoryEntry{
+		{"ann", []string{"parser.go", "lexer.go"}, ""},
+		{"bob", []string{"parser.go", "lexer.go"}, ""},
+		{"ann", []string{"parser.go", "docs/README.md"}, ""},
+	})
+}
+
+// testHistoryEntry is a commit of a seeded history; the message defaults to "Change the parser"
+type testHistoryEntry struct {
+	author  string
+	files   []string
+	message string
+}
+
+// seedTestHistory stores a project with one commit a day from analyticsTestStart, with its file
+// statistics marked complete
+func seedTestHistory(t *testing.T, db *sql.DB, history []testHistoryEntry) int {
+	t.Helper()
+	projects := sqlstore.NewProjectRepository(db)
+	project := entities.NewProject(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano()), "/tmp/repo")
+	if err := projects.Create(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { projects.Delete(project.ID) })
+
+	commits := sqlstore.NewCommitRepository(db)
+	for day, entry := range history {
+		message := entry.message
+		if message == "" {
+			message = "Change the parser"
+		}
+		storeTestCommit(t, commits, project.ID, day+1, entry.author, analyticsTestStart.AddDate(0, 0, day), message, entry.files)
+	}
+	if err := sqlstore.NewFileStatsRepository(db).MarkComplete(project.ID); err != nil {
+		t.Fatal(err)
+	}
+	return project.ID
+}
+
+// storeTestCommit stores a commit changing files the way an analysis does, with its coupling
+// and file statistics
+func storeTestCommit(t *testing.T, commits repositories.CommitRepository, projectID, n int, author string, at time.Time, message string, files []string) {
+	t.Helper()
+	hash, err := values.NewGitHash(fmt.Sprintf("%040x", n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []*entities.Change
+	for _, file := range files {
+		path, err := values.NewFilePath(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		changes = append(changes, entities.NewChange(0, path, 10, 2))
+	}
+	commit := entities.NewCommit(projectID, hash, author, at, message)
+	if err := commits.CreateWithChanges(commit, changes); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testAnalyticsTemporalCoupling(t *testing.T, db *sql.DB) {
+	projectID := seedAnalyticsProject(t, db)
+	repo := repository.NewAnalyticsRepository(db)
+
+	// A commit touching more files than MaxCouplingCommitFiles counts for each file, not for pairs
+	files := []string{"parser.go", "lexer.go"}
+	for len(files) <= repositories.MaxCouplingCommitFiles {
+		files = append(files, fmt.Sprintf("gen/file%03d.go", len(files)))
+	}
+	storeTestCommit(t, sqlstore.NewCommitRepository(db), projectID, 4, "bob", analyticsTestStart.AddDate(0, 0, 3), "Regenerate", files)
+
+	// The change history and, once marked complete, the stored coupling give the same pairs
+	check := func(t *testing.T) {
+		pairs, err := repo.GetTemporalCoupling(projectID, 10, "", "", 2, 0.5, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pairs) != 1 {
+			t.Fatalf("expected one coupled pair, got %+v", pairs)
+		}
+		pair := pairs[0]
+		if pair.FileA != "lexer.go" || pair.FileB != "parser.go" || pair.SharedCommits != 2 ||
+			pair.TotalCommitsA != 3 || pair.TotalCommitsB != 4 || pair.CouplingScore < 0.66 || pair.CouplingScore > 0.67 {
+			t.Errorf("unexpected pair %+v", pair)
+		}
+
+		pairs, err = repo.GetTemporalCoupling(projectID, 10, "", "", 1, 0.5, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pairs) != 2 || pairs[0].FileA != "docs/README.md" || pairs[1].FileA != "lexer.go" || pairs[0].LastModified == "" {
+			t.Errorf("expected the README pair before the lexer pair, got %+v", pairs)
+		}
+
+		// Whole months come from the stored coupling; other ranges still count by day
+		for _, r := range []struct {
+			start, end string
+			shared     int
+		}{{"2025-03-01", "2025-03-31", 2}, {"2025-03-04", "", 1}, {"", "2025-03-03", 1}} {
+			pairs, err = repo.GetTemporalCoupling(projectID, 10, r.start, r.end, 1, 0, "go")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pairs) != 1 || pairs[0].FileA != "lexer.go" || pairs[0].SharedCommits != r.shared {
+				t.Errorf("expected the lexer pair with %d shared commits from %q to %q, got %+v", r.shared, r.start, r.end, pairs)
+			}
+		}
+	}
+	t.Run("ChangeHistory", check)
+
+	coupling := sqlstore.NewCouplingRepository(db)
+	if err := coupling.MarkComplete(projectID); err != nil {
+		t.Fatal(err)
+	}
+	if complete, err := coupling.IsComplete(projectID); err != nil || !complete {
+		t.Fatalf("expected the coupling to be complete, got %v, %v", complete, err)
+	}
+	t.Run("StoredCoupling", check)
+
+	// A rebuild replaces counts that drifted from the change history
+	if err := coupling.RecordCommit(projectID, analyticsTestStart, []string{"parser.go", "lexer.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if recorded, err := coupling.Rebuild(projectID); err != nil || recorded != 4 {
+		t.Fatalf("expected the four commits to be recorded, got %d, %v", recorded, err)
+	}
+	if complete, err := coupling.IsComplete(projectID); err != nil || !complete {
+		t.Fatalf("expected the rebuilt coupling to be complete, got %v, %v", complete, err)
+	}
+	t.Run("RebuiltCoupling", check)
+}
+
+func testAnalyticsLevelTemporalCoupling(t *testing.T, db *sql.DB) {
+	projectID := seedTestHistory(t, db, []testHistoryEntry{
+		{"ann", []string{"api/users.go", "core/store.go"}, ""},
+		{"bob", []string{"api/orders.go", "core/store.go", "docs/api.md"}, ""},
+		{"ann", []string{"api/users.go", "core/auth/token.go"}, ""},
+		{"bob", []string{"core/store.go", "core/auth/token.go"}, ""},
+	})
+	uc := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(db))
+
+	web, err := services.ParseArchitectureLayer("web:api/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain, err := services.ParseArchitectureLayer("domain:core/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A commit counts once for each group it touches, however many of its files change
+	for _, tc := range []struct {
+		name               string
+		options            analytics.CouplingOptions
+		a, b               string
+		shared, totA, totB int
+		score              float64
+	}{
+		{"TopDirectories", analytics.CouplingOptions{Level: values.CouplingByDirectory, Depth: 1}, "api", "core", 3, 3, 4, 1},
+		{"NestedDirectories", analytics.CouplingOptions{Level: values.CouplingByDirectory, Depth: 2}, "api", "core", 2, 3, 3, 2.0 / 3},
+		{"Layers", analytics.CouplingOptions{Level: values.CouplingByLayer, Layers: []services.ArchitectureLayer{web, domain}}, "domain", "web", 3, 4, 3, 1},
+	} {
+		pairs, err := uc.GetLevelTemporalCoupling(projectID, tc.options, 10, "", "", 2, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pairs) != 1 {
+			t.Errorf("%s: expected one coupled pair, got %+v", tc.name, pairs)
+			continue
+		}
+		pair := pairs[0]
+		if pair.FileA != tc.a || pair.FileB != tc.b || pair.SharedCommits != tc.shared ||
+			pair.TotalCommitsA != tc.totA || pair.TotalCommitsB != tc.totB || math.Abs(pair.CouplingScore-tc.score) > 1e-9 {
+			t.Errorf("%s: unexpected pair %+v", tc.name, pair)
+		}
+	}
+
+	// A commit above the file cap counts for every directory it touches but couples none, as
+	// for file pairs; otherwise it would also couple docs with api and core
+	files := []string{"docs/generated.md"}
+	for i := 0; len(files) <= repositories.MaxCouplingCommitFiles; i++ {
+		files = append(files, fmt.Sprintf("api/gen%03d.go", i), fmt.Sprintf("core/gen%03d.go", i))
+	}
+	storeTestCommit(t, sqlstore.NewCommitRepository(db), projectID, 5, "bob", analyticsTestStart.AddDate(0, 0, 4), "Regenerate", files)
+
+	pairs, err := uc.GetLevelTemporalCoupling(projectID, analytics.CouplingOptions{Level: values.CouplingByDirectory, Depth: 1}, 10, "", "", 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 {
+		t.Fatalf("expected the large commit to couple nothing, got %+v", pairs)
+	}
+	if pair := pairs[0]; pair.FileA != "api" || pair.FileB != "core" || pair.SharedCommits != 3 ||
+		pair.TotalCommitsA != 4 || pair.TotalCommitsB != 5 || pair.CouplingScore != 0.75 {
+		t.Errorf("unexpected pair after the large commit %+v", pair)
+	}
+}
+
+func testAnalyticsIssues(t *testing.T, db *sql.DB) {
+	projectID := seedTestHistory(t, db, []testHistoryEntry{
+		{"ann", []string{"parser.go", "lexer.go"}, "PROJ-1: tokenize keywords"},
+		{"bob", []string{"parser.go"}, "Fix PROJ-1 and #7"},
+		{"ann", []string{"docs/README.md"}, "Document the grammar (#7)"},
+		{"bob", []string{"lexer.go"}, "Tidy up"},
+	})
+	commits := sqlstore.NewCommitRepository(db)
+	issues := analysis.NewIssueUseCase(commits, sqlstore.NewClassificationRuleRepository(db), sqlstore.NewIssueRepository(db))
+	uc := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(db))
+
+	// The default patterns link Jira keys and GitHub references
+	linked, err := issues.Relink(projectID)
+	if err != nil || linked != 4 {
+		t.Fatalf("expected 4 links, got %d (%v)", linked, err)
+	}
+	if _, err := issues.ImportIssues(projectID, []*entities.Issue{{Key: "proj-1", Title: "Keywords", Type: "bug"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := uc.GetIssues(projectID, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 2 {
+		t.Fatalf("expected two issues, got %+v", report.Issues)
+	}
+	for _, issue := range report.Issues {
+		if issue.Commits != 2 || issue.Files != 2 || issue.Authors != 2 {
+			t.Errorf("expected two commits, files and authors, got %+v", issue)
+		}
+	}
+	report, err = uc.GetIssues(projectID, "bug", 0)
+	if err != nil || len(report.Issues) != 1 || report.Issues[0].Key != "PROJ-1" || report.Issues[0].Title != "Keywords" {
+		t.Errorf("expected only the imported bug, got %+v (%v)", report, err)
+	}
+
+	detail, err := uc.GetIssueFiles(projectID, "proj-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.Files) != 2 || detail.Files[0].FilePath != "parser.go" || detail.Files[0].Commits != 2 || detail.Files[1].FilePath != "lexer.go" {
+		t.Errorf("expected parser.go then lexer.go, got %+v", detail.Files)
+	}
+	detail, err = uc.GetIssueCommits(projectID, "#7")
+	if err != nil || len(detail.Commits) != 2 || detail.Commits[0].Author != "ann" {
+		t.Errorf("expected the two commits of #7, newest first, got %+v (%v)", detail, err)
+	}
+	if _, err := uc.GetIssueAuthors(projectID, "PROJ-2"); !errors.Is(err, analytics.ErrIssueNotFound) {
+		t.Errorf("expected an unreferenced issue to be missing, got %v", err)
+	}
+
+	fileIssues, err := uc.GetFileIssues(projectID, "lexer.go")
+	if err != nil || len(fileIssues) != 1 || fileIssues[0].Key != "PROJ-1" || fileIssues[0].Type != "bug" {
+		t.Errorf("expected lexer.go to reference PROJ-1 only, got %+v (%v)", fileIssues, err)
+	}
+
+	// Replacing the patterns relinks the history
+	linked, err = issues.ReplacePatterns(projectID, []string{`PROJ-[0-9]+`})
+	if err != nil || linked != 2 {
+		t.Fatalf("expected 2 links, got %d (%v)", linked, err)
+	}
+	report, err = uc.GetIssues(projectID, "", 0)
+	if err != nil || len(report.Issues) != 1 || report.Issues[0].Key != "PROJ-1" {
+		t.Errorf("expected only PROJ-1, got %+v (%v)", report, err)
+	}
+
+	// Storing a commit links the issue keys it carries in the same transaction
+	hash, err := values.NewGitHash(fmt.Sprintf("%040x", 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := entities.NewCommit(projectID, hash, "cy", analyticsTestStart.AddDate(0, 0, 4), "PROJ-9: parse comments")
+	commit.IssueKeys = []string{"PROJ-9"}
+	if err := commits.CreateWithChanges(commit, []*entities.Change{entities.NewChange(0, mustPath(t, "parser.go"), 4, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	detail, err = uc.GetIssueCommits(projectID, "PROJ-9")
+	if err != nil || len(detail.Commits) != 1 || detail.Commits[0].Author != "cy" {
+		t.Errorf("expected the stored commit to reference PROJ-9, got %+v (%v)", detail, err)
+	}
+}
+
+func testAnalyticsDebtTrend(t *testing.T, db *sql.DB) {
+	projectID := seedAnalyticsProject(t, db)
+	repo := repository.NewAnalyticsRepository(db)
+
+	var snapshots []entities.HealthSnapshot
+	for i, score := range []float64{20.4, 35.6, 41} {
+		snapshots = append(snapshots, entities.HealthSnapshot{
+			ProjectID:     projectID,
+			Month:         time.Date(2025, time.Month(1+i), 1, 0, 0, 0, 0, time.UTC),
+			CommitHash:    fmt.Sprintf("%040x", i+1),
+			Score:         score,
+			Coupling:      0.25,
+			PolicyVersion: 1,
+			ComputedAt:    analyticsTestStart,
+		})
+	}
+	if err := sqlstore.NewHealthSnapshotRepository(db).Save(snapshots); err != nil {
+		t.Fatal(err)
+	}
+
+	// The trend holds the newest months, oldest first
+	overview, err := repo.GetProjectOverview(projectID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trend := overview.TechnicalDebtTrend
+	if len(trend) != 2 || trend[0].Date != "2025-02-01" || trend[0].Month != "Feb 2025" || trend[0].Score != 36 ||
+		trend[1].Date != "2025-03-01" || trend[1].Value != 41 || trend[1].Coupling != 0.25 || trend[1].PolicyVersion != 1 {
+		t.Errorf("expected February and March, got %+v", trend)
+	}
+	if overview.TotalCommits != 3 || overview.Contributors != 2 || overview.TotalFiles != 3 {
+		t.Errorf("unexpected totals %+v", overview)
+	}
+}
+
+func testAnalyticsProjectFileTypes(t *testing.T, db *sql.DB) {
+	projectID := seedAnalyticsProject(t, db)
+
+	fileTypes, err := repository.NewAnalyticsRepository(db).GetProjectFileTypes(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var extensions []string
+	for _, fileType := range fileTypes {
+		extensions = append(extensions, fileType.Extension)
+	}
+	if !reflect.DeepEqual(extensions, []string{"go", "md"}) {
+		t.Errorf("expected the go and md extensions, got %v", extensions)
+	}
+}
+
+func testAnalyticsFileOwnership(t *testing.T, db *sql.DB) {
+	projectID := seedAnalyticsProject(t, db)
+
+	ownership, err := repository.NewAnalyticsRepository(db).GetFileOwnership(projectID, values.OwnershipByCommits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parser *models.FileOwnership
+	for i := range ownership {
+		if ownership[i].FilePath == "parser.go" {
+			parser = &ownership[i]
+		}
+	}
+	if len(ownership) != 3 || parser == nil {
+		t.Fatalf("expected the three files, got %+v", ownership)
+	}
+	if parser.PrimaryOwner != "ann" || parser.TotalContributors != 2 {
+		t.Errorf("expected ann to own parser.go with bob, got %+v", parser)
+	}
+	lastModified, err := time.Parse(time.RFC3339Nano, parser.Contributors[0].LastModified)
+	if err != nil || !lastModified.Equal(analyticsTestStart.AddDate(0, 0, 2)) {
+		t.Errorf("expected ann's last change on day 2, got %q (%v)", parser.Contributors[0].LastModified, err)
+	}
+}
+
+// testAnalyticsIncompleteFileStats reads a project analysed before the daily file statistics were
+// kept from its change history, and from the statistics once they are rebuilt
+func testAnalyticsIncompleteFileStats(t *testing.T, db *sql.DB) {
+	projectID := seedAnalyticsProject(t, db)
+	stats := sqlstore.NewFileStatsRepository(db)
+	if err := stats.DeleteByProjectID(projectID); err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewAnalyticsRepository(db)
+
+	check := func(t *testing.T) {
+		overview, err := repo.GetProjectOverview(projectID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if overview.TotalFiles != 3 || overview.TotalLOC != 48 || len(overview.RiskSnapshots) != 3 ||
+			overview.RiskSnapshots[0].Component != "parser.go" || overview.RiskSnapshots[0].Changes != 3 {
+			t.Errorf("expected the totals of the change history, got %+v", overview)
+		}
+
+		ownership, err := repo.GetFileOwnership(projectID, values.OwnershipByCommits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parser *models.FileOwnership
+		for i := range ownership {
+			if ownership[i].FilePath == "parser.go" {
+				parser = &ownership[i]
+			}
+		}
+		if len(ownership) != 3 || parser == nil || parser.PrimaryOwner != "ann" || parser.Contributors[0].Commits != 2 {
+			t.Fatalf("expected ann to own parser.go with two of its three commits, got %+v", ownership)
+		}
+
+		// The end date takes in the whole day, as it does on the daily statistics
+		end := analyticsTestStart.AddDate(0, 0, 1).Truncate(24 * time.Hour)
+		busFactor, err := repo.GetBusFactorAnalysis(projectID, nil, &end, "", "parser.go", values.OwnershipByCommits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(busFactor) != 1 || len(busFactor[0].OwnershipDistribution) != 2 {
+			t.Errorf("expected ann and bob to share parser.go up to day 1, got %+v", busFactor)
+		}
+	}
+	t.Run("ChangeHistory", check)
+
+	if recorded, err := stats.Rebuild(projectID); err != nil || recorded != 3 {
+		t.Fatalf("expected the three commits to be recorded, got %d, %v", recorded, err)
+	}
+	if complete, err := stats.IsComplete(projectID); err != nil || !complete {
+		t.Fatalf("expected the rebuilt statistics to be complete, got %v, %v", complete, err)
+	}
+	t.Run("RebuiltStats", check)
+}
+
+func testAnalyticsAuthorActivity(t *testing.T, db *sql.DB) {
+	projectID := seedAnalyticsProject(t, db)
+	repo := repository.NewAnalyticsRepository(db)
+
+	activity, err := repo.GetAuthorActivity(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 2 || activity[0].Author != "ann" || activity[0].Commits != 2 {
+		t.Fatalf("expected ann, the latest author, first, got %+v", activity)
+	}
+	if !activity[0].FirstCommit.Equal(analyticsTestStart) || !activity[0].LastCommit.Equal(analyticsTestStart.AddDate(0, 0, 2)) {
+		t.Errorf("unexpected activity range %v - %v", activity[0].FirstCommit, activity[0].LastCommit)
+	}
+
+	period, err := repo.GetPeriodCommits(projectID, analyticsTestStart, analyticsTestStart.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(period) != 1 || period[0].Author != "bob" || len(period[0].Changes) != 2 {
+		t.Errorf("expected only bob's commit after the start, got %+v", period)
+	}
+}
@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codeecho/infrastructure/database"
+)
+
+// conformanceTests are the checks each backend runs, by name
+var conformanceTests = []struct {
+	name string
+	run  func(t *testing.T, db *sql.DB)
+}{
+	{"ProjectRepository", testProjectRepository},
+	{"CommitAndChangeRepositories", testCommitAndChangeRepositories},
+	{"AuthRepository", testAuthRepository},
+	{"TeamRepository", testTeamRepository},
+	{"RiskPolicyRepository", testRiskPolicyRepository},
+	{"FileStatsRepository_RecordCommit", testFileStatsRecordCommit},
+	{"AnalyticsRepository_GetTemporalCoupling", testAnalyticsTemporalCoupling},
+	{"AnalyticsRepository_GetAggregatedTemporalCoupling", testAnalyticsLevelTemporalCoupling},
+	{"AnalyticsRepository_Issues", testAnalyticsIssues},
+	{"AnalyticsRepository_GetProjectOverview", testAnalyticsDebtTrend},
+	{"AnalyticsRepository_GetProjectFileTypes", testAnalyticsProjectFileTypes},
+	{"AnalyticsRepository_GetFileOwnership", testAnalyticsFileOwnership},
+	{"AnalyticsRepository_IncompleteFileStats", testAnalyticsIncompleteFileStats},
+	{"AnalyticsRepository_GetAuthorActivity", testAnalyticsAuthorActivity},
+	{"AnalyticsRepository_GetAuthorHotspots", testAnalyticsAuthorHotspots},
+	{"AnalyticsRepository_GetBusFactorAnalysis", testAnalyticsBusFactor},
+	{"AnalyticsRepository_CodeAge", testAnalyticsCodeAge},
+	{"AnalyticsRepository_DefectHotspots", testAnalyticsDefectHotspots},
+	{"ReleaseRepository", testReleases},
+	{"AnalyticsRepository_GetCouplingGraph", testAnalyticsCouplingGraph},
+	{"AnalyticsRepository_KnowledgeLoss", testAnalyticsKnowledgeLoss},
+	{"AnalyticsRepository_TeamCoupling", testAnalyticsTeamCoupling},
+	{"AnalyticsRepository_GetFileAuthorship", testAnalyticsAuthorship},
+}
+
+func TestSQLite(t *testing.T) {
+	runConformance(t, "sqlite://"+filepath.Join(t.TempDir(), "codeecho.db"))
+}
+
+func TestMySQL(t *testing.T) {
+	runConformance(t, backendDSN(t, "CODEECHO_MYSQL_TEST_DSN"))
+}
+
+func TestPostgres(t *testing.T) {
+	runConformance(t, backendDSN(t, "CODEECHO_POSTGRES_TEST_DSN"))
+}
+
+// backendDSN returns the DSN in the environment variable, skipping the test when it is not set
+func backendDSN(t *testing.T, variable string) string {
+	t.Helper()
+	dsn := os.Getenv(variable)
+	if dsn == "" {
+		t.Skipf("%s is not set", variable)
+	}
+	return dsn
+}
+
+// runConformance runs every conformance test against the database of the DSN
+func runConformance(t *testing.T, dsn string) {
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range conformanceTests {
+		t.Run(tt.name, func(t *testing.T) { tt.run(t, db) })
+	}
+}
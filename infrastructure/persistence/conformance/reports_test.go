@@ -0,0 +1,443 @@
+package conformance
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+	"codeecho/internal/models"
+)
+
+// seedReportProject stores a project with five daily commits, the analysed tree and its blame:
+//
+//	day 0  ann  core/parser.go core/lexer.go               feature
+//	day 1  bob  core/parser.go core/lexer.go               bugfix
+//	day 2  ann  core/parser.go docs/README.md
+//	day 3  cy   web/app.js web/app.css
+//	day 4  cy   web/app.js web/app.css core/parser.go      bugfix
+//
+// web/app.css is no longer in the analysed tree.
+func seedReportProject(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	projectID := seedTestHistory(t, db, []testHistoryEntry{
+		{"ann", []string{"core/parser.go", "core/lexer.go"}, "Add the parser"},
+		{"bob", []string{"core/parser.go", "core/lexer.go"}, "Fix the lexer"},
+		{"ann", []string{"core/parser.go", "docs/README.md"}, "Document the parser"},
+		{"cy", []string{"web/app.js", "web/app.css"}, "Add the web app"},
+		{"cy", []string{"web/app.js", "web/app.css", "core/parser.go"}, "Fix the web app"},
+	})
+
+	commits := sqlstore.NewCommitRepository(db)
+	stored, err := commits.GetByProjectID(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	categories := map[string]values.CommitCategory{
+		fmt.Sprintf("%040x", 1): values.CategoryFeature,
+		fmt.Sprintf("%040x", 2): values.CategoryBugfix,
+		fmt.Sprintf("%040x", 5): values.CategoryBugfix,
+	}
+	updates := make(map[int]values.CommitCategory)
+	for _, commit := range stored {
+		if category, ok := categories[commit.Hash.String()]; ok {
+			updates[commit.ID] = category
+		}
+	}
+	if err := commits.UpdateCategories(updates); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := []*entities.FileMetrics{
+		{FilePath: "core/parser.go", Language: "Go", CodeLines: 160, CommentLines: 20, BlankLines: 20},
+		{FilePath: "core/lexer.go", Language: "Go", CodeLines: 100},
+		{FilePath: "docs/README.md", Language: "Markdown", CodeLines: 20},
+		{FilePath: "web/app.js", Language: "JavaScript", CodeLines: 50},
+	}
+	for _, m := range metrics {
+		m.ProjectID = projectID
+		m.CommitHash = fmt.Sprintf("%040x", 5)
+		m.MeasuredAt = analyticsTestStart.AddDate(0, 0, 4)
+	}
+	if err := sqlstore.NewFileMetricsRepository(db).ReplaceForProject(projectID, metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	segments := []*entities.BlameSegment{
+		{ProjectID: projectID, FilePath: "core/parser.go", CommitHash: fmt.Sprintf("%040x", 1), Author: "ann", CommittedAt: analyticsTestStart, Lines: 150},
+		{ProjectID: projectID, FilePath: "core/parser.go", CommitHash: fmt.Sprintf("%040x", 2), Author: "bob", CommittedAt: analyticsTestStart.AddDate(0, 0, 1), Lines: 50},
+	}
+	if err := sqlstore.NewBlameRepository(db).ReplaceForProject(projectID, segments); err != nil {
+		t.Fatal(err)
+	}
+	return projectID
+}
+
+func testAnalyticsAuthorHotspots(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+
+	hotspots, err := repository.NewAnalyticsRepository(db).GetAuthorHotspots(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hotspots) != 3 || hotspots[0].Author != "cy" || hotspots[1].Author != "ann" || hotspots[2].Author != "bob" {
+		t.Fatalf("expected cy, ann and bob by changes, got %+v", hotspots)
+	}
+	cy := hotspots[0]
+	if cy.FilesTouched != 3 || cy.TotalCommits != 5 || cy.LinesAdded != 50 || cy.LinesDeleted != 10 {
+		t.Errorf("unexpected totals for cy %+v", cy)
+	}
+	lastActivity, err := time.Parse(time.RFC3339Nano, cy.LastActivity)
+	if err != nil || !lastActivity.Equal(analyticsTestStart.AddDate(0, 0, 4)) {
+		t.Errorf("expected cy's last activity on day 4, got %q (%v)", cy.LastActivity, err)
+	}
+}
+
+func testAnalyticsBusFactor(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	repo := repository.NewAnalyticsRepository(db)
+
+	byFile := func(t *testing.T, data []models.BusFactorData) map[string]models.BusFactorData {
+		t.Helper()
+		files := make(map[string]models.BusFactorData)
+		for _, d := range data {
+			files[d.FilePath] = d
+		}
+		return files
+	}
+
+	data, err := repo.GetBusFactorAnalysis(projectID, nil, nil, "", "core/", values.OwnershipByCommits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := byFile(t, data)
+	parser := files["core/parser.go"]
+	if len(files) != 2 || parser.TotalCommits != 4 || len(parser.OwnershipDistribution) != 3 ||
+		parser.OwnershipDistribution[0].Author != "ann" || parser.OwnershipDistribution[0].OwnershipPercent != 50 {
+		t.Errorf("expected ann to own half of core/parser.go, got %+v", data)
+	}
+	if parser.LastModified == nil || !parser.LastModified.Equal(analyticsTestStart.AddDate(0, 0, 4)) {
+		t.Errorf("expected core/parser.go to be last modified on day 4, got %v", parser.LastModified)
+	}
+
+	// The date range keeps whole days
+	start, end := analyticsTestStart.AddDate(0, 0, 1).Truncate(24*time.Hour), analyticsTestStart.AddDate(0, 0, 3).Truncate(24*time.Hour)
+	data, err = repo.GetBusFactorAnalysis(projectID, &start, &end, "", "", values.OwnershipByLinesChanged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = byFile(t, data)
+	if len(files) != 5 || files["core/parser.go"].TotalCommits != 2 || files["web/app.js"].OwnershipDistribution[0].LinesChanged != 12 {
+		t.Errorf("expected the changes of days 1 to 3, got %+v", data)
+	}
+
+	// Blame weighs authors by their surviving lines
+	data, err = repo.GetBusFactorAnalysis(projectID, nil, nil, "", "core/parser.go", values.OwnershipByBlame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || len(data[0].OwnershipDistribution) != 2 || data[0].OwnershipDistribution[0].Author != "ann" ||
+		data[0].OwnershipDistribution[0].Lines != 150 || data[0].OwnershipDistribution[0].OwnershipPercent != 75 {
+		t.Errorf("expected ann to own three quarters of core/parser.go by blame, got %+v", data)
+	}
+}
+
+func testAnalyticsCodeAge(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	repo := repository.NewAnalyticsRepository(db)
+
+	segments, err := repo.GetLineAges(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].Author < segments[j].Author })
+	if len(segments) != 2 || segments[0].Author != "ann" || segments[0].Lines != 150 || !segments[0].CommittedAt.Equal(analyticsTestStart) {
+		t.Errorf("expected ann's and bob's blame of core/parser.go, got %+v", segments)
+	}
+
+	breakdown, err := repo.GetLanguageBreakdown(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breakdown) != 3 || breakdown[0].Language != "Go" || breakdown[0].Files != 2 || breakdown[0].CodeLines != 260 ||
+		breakdown[0].CommentLines != 20 || breakdown[2].Language != "Markdown" {
+		t.Errorf("unexpected language breakdown %+v", breakdown)
+	}
+
+	now := analyticsTestStart.AddDate(0, 0, 400)
+	report, err := analytics.NewAnalyticsUseCase(repo).GetCodeAge(projectID, "", 397, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, file := range report.Files {
+		paths = append(paths, file.FilePath)
+	}
+	if !reflect.DeepEqual(paths, []string{"core/lexer.go", "docs/README.md", "core/parser.go", "web/app.js"}) {
+		t.Fatalf("expected the measured files, oldest first, got %v", paths)
+	}
+	parser := report.Files[2]
+	if parser.AgeDays != 396 || parser.Lines != 200 || parser.LastModified == nil || !parser.LastModified.Equal(analyticsTestStart.AddDate(0, 0, 4)) {
+		t.Errorf("expected core/parser.go to be 396 days old with its blamed lines, got %+v", parser)
+	}
+	if report.TotalLines != 370 || report.StaleFiles != 2 || report.StaleLines != 320 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+
+	report, err = analytics.NewAnalyticsUseCase(repo).GetCodeAge(projectID, "core/", 397, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalFiles != 2 {
+		t.Errorf("expected the two core files, got %+v", report.Files)
+	}
+}
+
+func testAnalyticsDefectHotspots(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	uc := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(db))
+
+	report, err := uc.GetDefectHotspots(projectID, "", "", "", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Categories, map[string]int{"bugfix": 2, "feature": 1}) || report.Unclassified != 2 {
+		t.Errorf("unexpected categories %v with %d unclassified", report.Categories, report.Unclassified)
+	}
+	// web/app.css is no longer in the analysed tree
+	if len(report.Files) != 3 {
+		t.Fatalf("expected three files fixed, got %+v", report.Files)
+	}
+	parser, app, lexer := report.Files[0], report.Files[1], report.Files[2]
+	if parser.FilePath != "core/parser.go" || parser.BugfixCommits != 2 || parser.TotalCommits != 4 || parser.Authors != 2 ||
+		parser.CodeLines != 160 || parser.BugfixRatio != 0.5 {
+		t.Errorf("unexpected core/parser.go %+v", parser)
+	}
+	if app.FilePath != "web/app.js" || app.DefectDensity != 20 || lexer.FilePath != "core/lexer.go" || lexer.DefectDensity != 10 {
+		t.Errorf("expected web/app.js before core/lexer.go by density, got %+v and %+v", app, lexer)
+	}
+	lastBugfix, err := time.Parse(time.RFC3339, parser.LastBugfix)
+	if err != nil || !lastBugfix.Equal(analyticsTestStart.AddDate(0, 0, 4)) {
+		t.Errorf("expected the last fix of core/parser.go on day 4, got %q (%v)", parser.LastBugfix, err)
+	}
+
+	// Dates are whole days: day 1 ends on March 4
+	report, err = uc.GetDefectHotspots(projectID, "", "2025-03-04", "core/", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 2 || report.Files[0].BugfixCommits != 1 || report.Files[0].TotalCommits != 2 ||
+		!reflect.DeepEqual(report.Categories, map[string]int{"bugfix": 1, "feature": 1}) {
+		t.Errorf("expected bob's fix of the core files, got %+v", report)
+	}
+	report, err = uc.GetDefectHotspots(projectID, "2025-03-05", "", "", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 2 || report.Files[0].FilePath != "web/app.js" || report.Files[1].TotalCommits != 2 {
+		t.Errorf("expected cy's fix only, got %+v", report.Files)
+	}
+}
+
+func testReleases(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	releaseRepo := sqlstore.NewReleaseRepository(db)
+	release := func(name string, n int) entities.Release {
+		at := analyticsTestStart.AddDate(0, 0, n-1)
+		return entities.Release{ProjectID: projectID, Name: name, CommitHash: fmt.Sprintf("%040x", n), CommitDate: at, ReleasedAt: at}
+	}
+	if err := releaseRepo.ReplaceForProject(projectID, []entities.Release{release("v1.1", 5), release("v1.0", 2)}); err != nil {
+		t.Fatal(err)
+	}
+	uc := analytics.NewReleaseUseCase(releaseRepo, repository.NewAnalyticsRepository(db))
+
+	releases, err := uc.GetReleases(projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 || releases[0].Name != "v1.0" || releases[1].Name != "v1.1" || releases[1].PreviousRelease != "v1.0" {
+		t.Fatalf("expected v1.0 then v1.1, got %+v", releases)
+	}
+	commitDate, err := time.Parse(time.RFC3339, releases[0].CommitDate)
+	if err != nil || !commitDate.Equal(analyticsTestStart.AddDate(0, 0, 1)) {
+		t.Errorf("expected v1.0 on day 1, got %q (%v)", releases[0].CommitDate, err)
+	}
+
+	comparison, err := uc.CompareReleases(projectID, "v1.0", "v1.1", 0, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := comparison.From, comparison.To
+	if from.Commits != 2 || from.Authors != 2 || from.FilesChanged != 2 || from.Churn != 48 {
+		t.Errorf("unexpected v1.0 period %+v", from)
+	}
+	if to.Commits != 3 || to.Authors != 2 || to.FilesChanged != 4 {
+		t.Errorf("expected the commits after v1.0 up to v1.1, got %+v", to)
+	}
+	if len(comparison.NewCoupling) != 1 || comparison.NewCoupling[0].FileA != "web/app.css" ||
+		len(comparison.RemovedCoupling) != 1 || comparison.RemovedCoupling[0].FileA != "core/lexer.go" {
+		t.Errorf("expected the web coupling to appear and the core one to go, got %+v and %+v", comparison.NewCoupling, comparison.RemovedCoupling)
+	}
+}
+
+func testAnalyticsCouplingGraph(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	uc := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(db))
+
+	// The whole history and whole months, which read the stored coupling, agree
+	for _, r := range []struct{ start, end string }{{"", ""}, {"2025-03-01", "2025-03-31"}} {
+		sums, err := uc.GetCouplingSum(projectID, 0, r.start, r.end, 2, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, sum := range sums {
+			paths = append(paths, sum.FilePath)
+			if sum.CoupledFiles != 1 || sum.SumOfCoupling != 2 {
+				t.Errorf("expected %s to share two commits with one file, got %+v", sum.FilePath, sum)
+			}
+		}
+		if !reflect.DeepEqual(paths, []string{"core/lexer.go", "core/parser.go", "web/app.css", "web/app.js"}) {
+			t.Errorf("unexpected coupled files from %q to %q: %v", r.start, r.end, paths)
+		}
+
+		clusters, err := uc.GetCouplingClusters(projectID, 0, r.start, r.end, 2, 0, "", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var members []string
+		for _, cluster := range clusters.Clusters {
+			members = append(members, strings.Join(cluster.Files, " "))
+			if cluster.InternalPairs != 1 || cluster.Density != 1 {
+				t.Errorf("expected a fully connected pair, got %+v", cluster)
+			}
+		}
+		sort.Strings(members)
+		if clusters.TotalPairs != 2 || !reflect.DeepEqual(members, []string{"core/lexer.go core/parser.go", "web/app.css web/app.js"}) {
+			t.Errorf("expected the core and web clusters, got %+v", clusters)
+		}
+	}
+}
+
+func testAnalyticsKnowledgeLoss(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	statuses := sqlstore.NewAuthorStatusRepository(db)
+	if err := statuses.Save(&entities.AuthorStatus{ProjectID: projectID, Author: "bob", Active: false, UpdatedAt: analyticsTestStart}); err != nil {
+		t.Fatal(err)
+	}
+	uc := analytics.NewKnowledgeLossUseCase(repository.NewAnalyticsRepository(db), statuses)
+
+	report, err := uc.GetKnowledgeLoss(projectID, "core/", values.OwnershipByCommits, 6, 50, analyticsTestStart.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.InactiveAuthors) != 1 || report.InactiveAuthors[0].Author != "bob" {
+		t.Errorf("expected only bob to be inactive, got %+v", report.InactiveAuthors)
+	}
+	if len(report.Files) != 2 || report.Files[0].FilePath != "core/lexer.go" || report.Files[0].AbandonedPercent != 50 ||
+		!report.Files[0].Abandoned || report.Files[1].AbandonedPercent != 25 || report.Summary.AbandonedFiles != 1 {
+		t.Errorf("expected bob's half of core/lexer.go to be abandoned, got %+v", report.Files)
+	}
+
+	// Without the override everyone is inactive a year later
+	if err := statuses.Delete(projectID, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	report, err = uc.GetKnowledgeLoss(projectID, "", values.OwnershipByCommits, 6, 50, analyticsTestStart.AddDate(1, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.InactiveAuthors) != 3 || report.Summary.AbandonedPercent != 100 {
+		t.Errorf("expected every file to be abandoned, got %+v", report.Summary)
+	}
+}
+
+func testAnalyticsTeamCoupling(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	teams := sqlstore.NewTeamRepository(db)
+	for _, team := range []*entities.Team{
+		entities.NewTeam("Core", "", []entities.TeamMember{{Name: "Ann", Identities: []string{"ann"}}, {Name: "Bob", Identities: []string{"bob"}}}),
+		entities.NewTeam("Web", "", []entities.TeamMember{{Name: "Cy", Identities: []string{"cy"}}}),
+	} {
+		if err := teams.Create(team); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { teams.Delete(team.ID) })
+	}
+	uc := analytics.NewTeamAnalysisUseCase(repository.NewAnalyticsRepository(db), teams)
+
+	report, err := uc.GetTeamCoupling(projectID, values.OwnershipByCommits, 0, "", "", 1, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalPairs != 5 || report.CrossTeamPairs != 2 || report.CrossTeamPercent != 40 {
+		t.Errorf("expected two of five pairs to cross teams, got %+v", report)
+	}
+	want := []models.TeamCouplingEdge{
+		{TeamA: "Core", TeamB: "Core", Pairs: 2, SharedCommits: 3},
+		{TeamA: "Core", TeamB: "Web", Pairs: 2, SharedCommits: 2},
+		{TeamA: "Web", TeamB: "Web", Pairs: 1, SharedCommits: 2},
+	}
+	if !reflect.DeepEqual(report.Teams, want) {
+		t.Errorf("got team pairs %+v, want %+v", report.Teams, want)
+	}
+}
+
+func testAnalyticsAuthorship(t *testing.T, db *sql.DB) {
+	projectID := seedReportProject(t, db)
+	repo := repository.NewAnalyticsRepository(db)
+
+	authorship, err := repo.GetFileAuthorship(projectID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []string
+	for _, fa := range authorship {
+		records = append(records, fmt.Sprintf("%s %s %d %v", fa.FilePath, fa.Author, fa.Commits, fa.Creator))
+	}
+	sort.Strings(records)
+	// web/app.css is no longer in the analysed tree
+	want := []string{
+		"core/lexer.go ann 1 true",
+		"core/lexer.go bob 1 false",
+		"core/parser.go ann 2 true",
+		"core/parser.go bob 1 false",
+		"core/parser.go cy 1 false",
+		"docs/README.md ann 1 true",
+		"web/app.js cy 2 true",
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got authorship %v, want %v", records, want)
+	}
+
+	uc := analytics.NewAnalyticsUseCase(repo)
+	truckFactor, err := uc.GetTruckFactor(projectID, "", 1, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truckFactor.TotalFiles != 4 || len(truckFactor.KeyDevelopers) == 0 || truckFactor.KeyDevelopers[0].Author != "ann" || len(truckFactor.Directories) != 3 {
+		t.Errorf("expected ann as the first key developer of four files, got %+v", truckFactor)
+	}
+
+	fragmentation, err := uc.GetFragmentation(projectID, "", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fragmentation.Files) != 2 {
+		t.Fatalf("expected the two files with several authors, got %+v", fragmentation.Files)
+	}
+	parser, lexer := fragmentation.Files[0], fragmentation.Files[1]
+	if parser.FilePath != "core/parser.go" || parser.Authors != 3 || parser.MainDeveloper != "ann" ||
+		lexer.FilePath != "core/lexer.go" || math.Abs(lexer.Fragmentation-0.5) > 1e-9 || parser.Fragmentation <= lexer.Fragmentation {
+		t.Errorf("expected core/parser.go to be more fragmented than core/lexer.go, got %+v", fragmentation.Files)
+	}
+}
@@ -0,0 +1,361 @@
+package conformance
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/persistence/sqlstore"
+)
+
+// createTestProject creates a project with a unique name, deleted with its data after the test
+func createTestProject(t *testing.T, db *sql.DB) *entities.Project {
+	t.Helper()
+	repo := sqlstore.NewProjectRepository(db)
+	project := entities.NewProject(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano()), "/tmp/repo")
+	if err := repo.Create(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Delete(project.ID) })
+	return project
+}
+
+// mustHash returns a 40 character git hash derived from n
+func mustHash(t *testing.T, n int) *values.GitHash {
+	t.Helper()
+	hash, err := values.NewGitHash(fmt.Sprintf("%040x", n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+// mustPath returns a file path value
+func mustPath(t *testing.T, path string) *values.FilePath {
+	t.Helper()
+	filePath, err := values.NewFilePath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
+func testProjectRepository(t *testing.T, db *sql.DB) {
+	repo := sqlstore.NewProjectRepository(db)
+
+	project := entities.NewProjectWithAuth(fmt.Sprintf("project-%d", time.Now().UnixNano()), "https://example.com/repo.git",
+		entities.RepoTypePrivateGit, &entities.GitAuthConfig{Username: "ann", Token: "secret"})
+	if err := repo.Create(project); err != nil {
+		t.Fatal(err)
+	}
+	if project.ID == 0 {
+		t.Fatal("expected the project ID to be set")
+	}
+
+	got, err := repo.GetByName(project.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != project.ID || got.RepoType != entities.RepoTypePrivateGit || got.AuthConfig == nil || got.AuthConfig.Token != "secret" {
+		t.Errorf("unexpected project %+v", got)
+	}
+	// Stored at second precision, which MySQL rounds to
+	if diff := got.CreatedAt.Sub(project.CreatedAt); diff <= -time.Second || diff >= time.Second {
+		t.Errorf("expected created at %v, got %v", project.CreatedAt, got.CreatedAt)
+	}
+
+	hash := mustHash(t, 1)
+	if err := repo.UpdateLastAnalyzedHash(project.ID, hash.String()); err != nil {
+		t.Fatal(err)
+	}
+	got, err = repo.GetByID(project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastAnalyzedHash == nil || got.LastAnalyzedHash.String() != hash.String() {
+		t.Errorf("expected last analyzed hash %s, got %v", hash, got.LastAnalyzedHash)
+	}
+
+	if err := repo.Delete(project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(project.ID); err == nil {
+		t.Error("expected the deleted project to be gone")
+	}
+	if err := repo.Delete(project.ID); err == nil {
+		t.Error("expected deleting a missing project to fail")
+	}
+}
+
+func testCommitAndChangeRepositories(t *testing.T, db *sql.DB) {
+	project := createTestProject(t, db)
+	commitRepo := sqlstore.NewCommitRepository(db)
+	changeRepo := sqlstore.NewChangeRepository(db)
+
+	// Timestamps in another zone are stored as the same instant
+	zone := time.FixedZone("CEST", 2*60*60)
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, zone)
+	commits := []*entities.Commit{
+		entities.NewCommit(project.ID, mustHash(t, 1), "ann", start, "Add parser"),
+		entities.NewCommit(project.ID, mustHash(t, 2), "bob", start.Add(time.Hour), "Fix parser crash"),
+	}
+	if err := commitRepo.CreateBatch(commits); err != nil {
+		t.Fatal(err)
+	}
+	for i, commit := range commits {
+		stored, err := commitRepo.GetByHash(project.ID, commit.Hash.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits[i] = stored
+	}
+	third := entities.NewCommit(project.ID, mustHash(t, 3), "ann", start.Add(2*time.Hour), "Document parser")
+	if err := commitRepo.Create(third); err != nil {
+		t.Fatal(err)
+	}
+	commits = append(commits, third)
+
+	got, err := commitRepo.GetByHash(project.ID, mustHash(t, 2).String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Author != "bob" || !got.Timestamp.Equal(start.Add(time.Hour)) {
+		t.Errorf("unexpected commit %+v", got)
+	}
+
+	changes := []*entities.Change{
+		entities.NewChange(commits[0].ID, mustPath(t, "parser.go"), 100, 0),
+		entities.NewChange(commits[0].ID, mustPath(t, "README.md"), 10, 0),
+		entities.NewChange(commits[1].ID, mustPath(t, "parser.go"), 5, 3),
+	}
+	if err := changeRepo.CreateBatch(changes); err != nil {
+		t.Fatal(err)
+	}
+	docs := entities.NewChange(commits[2].ID, mustPath(t, "README.md"), 20, 2)
+	if err := changeRepo.Create(docs); err != nil {
+		t.Fatal(err)
+	}
+	if docs.ID == 0 {
+		t.Error("expected the change ID to be set")
+	}
+
+	touched, err := commitRepo.GetByFilePath(project.ID, "parser.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(touched) != 2 || touched[0].ID != commits[0].ID || touched[1].ID != commits[1].ID {
+		t.Errorf("expected the two parser commits oldest first, got %+v", touched)
+	}
+
+	hotspots, err := changeRepo.GetHotspots(project.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hotspots) != 2 || hotspots[0].ChangeCount != 2 || hotspots[0].TotalAdded+hotspots[1].TotalAdded != 135 {
+		t.Errorf("unexpected hotspots %+v", hotspots)
+	}
+
+	if err := commitRepo.UpdateCategories(map[int]values.CommitCategory{commits[1].ID: values.CategoryBugfix}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = commitRepo.GetByID(commits[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Category != values.CategoryBugfix {
+		t.Errorf("expected the bugfix category, got %q", got.Category)
+	}
+
+	all, err := changeRepo.GetByProjectID(project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 {
+		t.Errorf("expected 4 changes, got %d", len(all))
+	}
+}
+
+func testAuthRepository(t *testing.T, db *sql.DB) {
+	repo := sqlstore.NewAuthRepository(db)
+
+	user := &entities.User{
+		Email:        fmt.Sprintf("user-%d@example.com", time.Now().UnixNano()),
+		PasswordHash: "hash",
+		FirstName:    "Ann",
+		LastName:     "Lee",
+		Role:         "user",
+		IsActive:     true,
+	}
+	if err := repo.CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Exec("DELETE FROM users WHERE id = ?", user.ID) })
+
+	got, err := repo.GetUserByEmail(user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != user.ID || got.FirstName != "Ann" || !got.IsActive || got.EmailVerifiedAt != nil {
+		t.Errorf("unexpected user %+v", got)
+	}
+
+	user.Role = "admin"
+	if err := repo.UpdateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = repo.GetUserByID(user.ID); err != nil || got.Role != "admin" {
+		t.Errorf("expected the updated role, got %+v (%v)", got, err)
+	}
+
+	valid := &entities.RefreshToken{UserID: user.ID, TokenHash: user.Email + "-valid", ExpiresAt: time.Now().Add(time.Hour)}
+	expired := &entities.RefreshToken{UserID: user.ID, TokenHash: user.Email + "-expired", ExpiresAt: time.Now().Add(-time.Hour)}
+	for _, token := range []*entities.RefreshToken{valid, expired} {
+		if err := repo.CreateRefreshToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if token, err := repo.GetRefreshToken(valid.TokenHash); err != nil || token.ID != valid.ID {
+		t.Errorf("expected the valid token, got %+v (%v)", token, err)
+	}
+	if _, err := repo.GetRefreshToken(expired.TokenHash); err == nil {
+		t.Error("expected the expired token to be ignored")
+	}
+
+	if err := repo.DeleteUserRefreshTokens(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetRefreshToken(valid.TokenHash); err == nil {
+		t.Error("expected the user's tokens to be deleted")
+	}
+}
+
+func testFileStatsRecordCommit(t *testing.T, db *sql.DB) {
+	project := createTestProject(t, db)
+	repo := sqlstore.NewFileStatsRepository(db)
+
+	morning := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{morning.Add(3 * time.Hour), morning} {
+		changes := []*entities.Change{entities.NewChange(0, mustPath(t, "parser.go"), 10, 1)}
+		if err := repo.RecordCommit(project.ID, "ann", at, changes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var commits, added, deleted int
+	var lastCommit time.Time
+	err := db.QueryRow(`
+		SELECT commits, lines_added, lines_deleted, last_commit_at
+		FROM file_daily_stats WHERE project_id = ? AND file_path = ?
+	`, project.ID, "parser.go").Scan(&commits, &added, &deleted, &lastCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commits != 2 || added != 20 || deleted != 2 || !lastCommit.Equal(morning.Add(3*time.Hour)) {
+		t.Errorf("expected the day's two commits to add up, got %d commits, +%d -%d, last at %v", commits, added, deleted, lastCommit)
+	}
+
+	// A file listed twice in one commit counts once
+	changes := []*entities.Change{
+		entities.NewChange(0, mustPath(t, "lexer.go"), 3, 0),
+		entities.NewChange(0, mustPath(t, "lexer.go"), 4, 1),
+	}
+	if err := repo.RecordCommit(project.ID, "ann", morning, changes); err != nil {
+		t.Fatal(err)
+	}
+	err = db.QueryRow(`
+		SELECT commits, lines_added, lines_deleted
+		FROM file_daily_stats WHERE project_id = ? AND file_path = ?
+	`, project.ID, "lexer.go").Scan(&commits, &added, &deleted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commits != 1 || added != 7 || deleted != 1 {
+		t.Errorf("expected one commit, +7 -1, got %d commits, +%d -%d", commits, added, deleted)
+	}
+
+	// The completeness mark goes with the statistics
+	if err := repo.MarkComplete(project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MarkComplete(project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if complete, err := repo.IsComplete(project.ID); err != nil || !complete {
+		t.Fatalf("expected the statistics to be complete, got %v, %v", complete, err)
+	}
+	if err := repo.DeleteByProjectID(project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if complete, err := repo.IsComplete(project.ID); err != nil || complete {
+		t.Errorf("expected the deleted statistics to be incomplete, got %v, %v", complete, err)
+	}
+}
+
+func testTeamRepository(t *testing.T, db *sql.DB) {
+	repo := sqlstore.NewTeamRepository(db)
+
+	team := entities.NewTeam(fmt.Sprintf("team-%d", time.Now().UnixNano()), "Parser owners", []entities.TeamMember{
+		{Name: "Ann Lee", Identities: []string{"ann", "ann@example.com"}},
+	})
+	if err := repo.Create(team); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Delete(team.ID) })
+	if team.ID == 0 {
+		t.Fatal("expected the team ID to be set")
+	}
+
+	team.Members = append(team.Members, entities.TeamMember{Name: "Bob Ray", Identities: []string{"bob"}})
+	if err := repo.Update(team); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetByID(team.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != team.Name || len(got.Members) != 2 || len(got.Members[0].Identities) != 2 || got.Members[1].Name != "Bob Ray" {
+		t.Errorf("unexpected team %+v", got)
+	}
+
+	if err := repo.Delete(team.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(team.ID); err == nil {
+		t.Error("expected the deleted team to be gone")
+	}
+}
+
+func testRiskPolicyRepository(t *testing.T, db *sql.DB) {
+	project := createTestProject(t, db)
+	repo := sqlstore.NewRiskPolicyRepository(db)
+
+	if policy, err := repo.GetCurrent(project.ID); err != nil || policy != nil {
+		t.Fatalf("expected no policy, got %+v (%v)", policy, err)
+	}
+
+	first := services.EffectiveRiskPolicy(nil)
+	first.ProjectID = project.ID
+	if err := repo.Create(&first); err != nil {
+		t.Fatal(err)
+	}
+	second := first
+	second.CouplingMinSharedCommits = 5
+	if err := repo.Create(&second); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == 0 || second.ID == first.ID || first.Version != 1 || second.Version != 2 {
+		t.Errorf("expected versions 1 and 2 with their IDs, got %d/%d and %d/%d", first.ID, first.Version, second.ID, second.Version)
+	}
+
+	current, err := repo.GetCurrent(project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current == nil || current.Version != 2 || current.CouplingMinSharedCommits != 5 || current.Ownership != first.Ownership {
+		t.Errorf("expected the second version, got %+v", current)
+	}
+}
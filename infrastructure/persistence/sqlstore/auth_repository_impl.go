@@ -1,10 +1,12 @@
-package mysql
+package sqlstore
 
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"codeecho/domain/entities"
+	"codeecho/infrastructure/database"
 )
 
 // AuthRepositoryImpl implements the AuthRepository interface
@@ -23,7 +25,7 @@ func (ar *AuthRepositoryImpl) GetUserByEmail(email string) (*entities.User, erro
 		SELECT id, email, password_hash, first_name, last_name, role, is_active, 
 			   email_verified_at, created_at, updated_at 
 		FROM users 
-		WHERE email = ? AND is_active = 1
+		WHERE email = ? AND is_active = TRUE
 	`
 
 	var user entities.User
@@ -56,7 +58,7 @@ func (ar *AuthRepositoryImpl) GetUserByID(id int) (*entities.User, error) {
 		SELECT id, email, password_hash, first_name, last_name, role, is_active, 
 			   email_verified_at, created_at, updated_at 
 		FROM users 
-		WHERE id = ? AND is_active = 1
+		WHERE id = ? AND is_active = TRUE
 	`
 
 	var user entities.User
@@ -90,7 +92,7 @@ func (ar *AuthRepositoryImpl) CreateUser(user *entities.User) error {
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := ar.db.Exec(query,
+	id, err := database.DialectOf(ar.db).InsertID(ar.db, query,
 		user.Email,
 		user.PasswordHash,
 		user.FirstName,
@@ -103,11 +105,6 @@ func (ar *AuthRepositoryImpl) CreateUser(user *entities.User) error {
 		return err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
-	}
-
 	user.ID = int(id)
 	return nil
 }
@@ -139,12 +136,7 @@ func (ar *AuthRepositoryImpl) CreateRefreshToken(token *entities.RefreshToken) e
 		VALUES (?, ?, ?)
 	`
 
-	result, err := ar.db.Exec(query, token.UserID, token.TokenHash, token.ExpiresAt)
-	if err != nil {
-		return err
-	}
-
-	id, err := result.LastInsertId()
+	id, err := database.DialectOf(ar.db).InsertID(ar.db, query, token.UserID, token.TokenHash, token.ExpiresAt)
 	if err != nil {
 		return err
 	}
@@ -158,11 +150,11 @@ func (ar *AuthRepositoryImpl) GetRefreshToken(tokenHash string) (*entities.Refre
 	query := `
 		SELECT id, user_id, token_hash, expires_at, created_at
 		FROM refresh_tokens 
-		WHERE token_hash = ? AND expires_at > NOW()
+		WHERE token_hash = ? AND expires_at > ?
 	`
 
 	var token entities.RefreshToken
-	err := ar.db.QueryRow(query, tokenHash).Scan(
+	err := ar.db.QueryRow(query, tokenHash, time.Now()).Scan(
 		&token.ID,
 		&token.UserID,
 		&token.TokenHash,
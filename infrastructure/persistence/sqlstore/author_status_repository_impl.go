@@ -0,0 +1,68 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// AuthorStatusRepository implements the author status repository interface on a SQL database
+type AuthorStatusRepository struct {
+	db *sql.DB
+}
+
+// NewAuthorStatusRepository creates a new author status repository
+func NewAuthorStatusRepository(db *sql.DB) repositories.AuthorStatusRepository {
+	return &AuthorStatusRepository{db: db}
+}
+
+// GetByProjectID retrieves all overrides of a project
+func (r *AuthorStatusRepository) GetByProjectID(projectID int) ([]*entities.AuthorStatus, error) {
+	query := `
+		SELECT project_id, author, active, note, updated_at
+		FROM author_statuses
+		WHERE project_id = ?
+		ORDER BY author
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statuses []*entities.AuthorStatus
+
+	for rows.Next() {
+		status := &entities.AuthorStatus{}
+
+		err := rows.Scan(&status.ProjectID, &status.Author, &status.Active, &status.Note, &status.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, rows.Err()
+}
+
+// Save creates or updates the override of an author
+func (r *AuthorStatusRepository) Save(status *entities.AuthorStatus) error {
+	dialect := database.DialectOf(r.db)
+	query := `
+		INSERT INTO author_statuses (project_id, author, active, note, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		` + dialect.Upsert("project_id", "author") + ` ` + dialect.SetInserted("active", "note", "updated_at")
+
+	_, err := r.db.Exec(query, status.ProjectID, status.Author, status.Active, status.Note, status.UpdatedAt)
+	return err
+}
+
+// Delete removes the override of an author so it is classified automatically again
+func (r *AuthorStatusRepository) Delete(projectID int, author string) error {
+	_, err := r.db.Exec("DELETE FROM author_statuses WHERE project_id = ? AND author = ?", projectID, author)
+	return err
+}
@@ -0,0 +1,135 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+// BlameRepository implements the blame repository interface on a SQL database
+type BlameRepository struct {
+	db *sql.DB
+}
+
+// NewBlameRepository creates a new blame repository
+func NewBlameRepository(db *sql.DB) repositories.BlameRepository {
+	return &BlameRepository{db: db}
+}
+
+// ReplaceForProject replaces the stored blame of a project with a new snapshot
+func (r *BlameRepository) ReplaceForProject(projectID int, segments []*entities.BlameSegment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM file_blame_segments WHERE project_id = ?", projectID); err != nil {
+		return err
+	}
+
+	if err := insertBlameSegments(tx, projectID, segments); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceForFiles replaces the stored blame of the given files only; files without
+// new segments are removed
+func (r *BlameRepository) ReplaceForFiles(projectID int, filePaths []string, segments []*entities.BlameSegment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("DELETE FROM file_blame_segments WHERE project_id = ? AND file_path = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, filePath := range filePaths {
+		if _, err := stmt.Exec(projectID, filePath); err != nil {
+			return err
+		}
+	}
+
+	if err := insertBlameSegments(tx, projectID, segments); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertBlameSegments inserts segments within an open transaction
+func insertBlameSegments(tx *sql.Tx, projectID int, segments []*entities.BlameSegment) error {
+	stmt, err := tx.Prepare(`
+		INSERT INTO file_blame_segments (project_id, file_path, commit_hash, author, committed_at, line_count)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, s := range segments {
+		if _, err := stmt.Exec(projectID, s.FilePath, s.CommitHash, s.Author, s.CommittedAt, s.Lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFilePaths retrieves the paths of all files that have stored blame
+func (r *BlameRepository) GetFilePaths(projectID int) ([]string, error) {
+	rows, err := r.db.Query("SELECT DISTINCT file_path FROM file_blame_segments WHERE project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, rows.Err()
+}
+
+// GetByProjectID retrieves the current blame segments of a project
+func (r *BlameRepository) GetByProjectID(projectID int) ([]*entities.BlameSegment, error) {
+	query := `
+		SELECT project_id, file_path, commit_hash, author, committed_at, line_count
+		FROM file_blame_segments
+		WHERE project_id = ?
+		ORDER BY file_path, committed_at
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []*entities.BlameSegment
+
+	for rows.Next() {
+		s := &entities.BlameSegment{}
+
+		err := rows.Scan(&s.ProjectID, &s.FilePath, &s.CommitHash, &s.Author, &s.CommittedAt, &s.Lines)
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, s)
+	}
+
+	return segments, rows.Err()
+}
@@ -1,4 +1,4 @@
-package mysql
+package sqlstore
 
 import (
 	"database/sql"
@@ -7,9 +7,10 @@ import (
 	"codeecho/domain/entities"
 	"codeecho/domain/repositories"
 	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
 )
 
-// ChangeRepository implements the change repository interface with MySQL
+// ChangeRepository implements the change repository interface on a SQL database
 type ChangeRepository struct {
 	db *sql.DB
 }
@@ -26,7 +27,7 @@ func (r *ChangeRepository) Create(change *entities.Change) error {
 		VALUES (?, ?, ?, ?)
 	`
 
-	result, err := r.db.Exec(query,
+	id, err := database.DialectOf(r.db).InsertID(r.db, query,
 		change.CommitID,
 		change.FilePath.String(),
 		change.LinesAdded,
@@ -37,11 +38,6 @@ func (r *ChangeRepository) Create(change *entities.Change) error {
 		return err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
-	}
-
 	change.ID = int(id)
 	return nil
 }
@@ -0,0 +1,81 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+)
+
+// ClassificationRuleRepository implements the classification rule repository interface on a SQL database
+type ClassificationRuleRepository struct {
+	db *sql.DB
+}
+
+// NewClassificationRuleRepository creates a new classification rule repository
+func NewClassificationRuleRepository(db *sql.DB) repositories.ClassificationRuleRepository {
+	return &ClassificationRuleRepository{db: db}
+}
+
+// GetByProjectID retrieves a project's rules in the order they are applied
+func (r *ClassificationRuleRepository) GetByProjectID(projectID int) ([]entities.ClassificationRule, error) {
+	rows, err := r.db.Query(`
+		SELECT id, project_id, category, keywords, pattern
+		FROM commit_classification_rules
+		WHERE project_id = ?
+		ORDER BY position, id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get classification rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]entities.ClassificationRule, 0)
+	for rows.Next() {
+		var rule entities.ClassificationRule
+		var category, keywords string
+		if err := rows.Scan(&rule.ID, &rule.ProjectID, &category, &keywords, &rule.Pattern); err != nil {
+			return nil, err
+		}
+		rule.Category = values.CommitCategory(category)
+		if keywords != "" {
+			rule.Keywords = strings.Split(keywords, ",")
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// ReplaceForProject replaces all rules of a project; an empty list restores the defaults
+func (r *ClassificationRuleRepository) ReplaceForProject(projectID int, rules []entities.ClassificationRule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM commit_classification_rules WHERE project_id = ?", projectID); err != nil {
+		return fmt.Errorf("failed to clear classification rules: %w", err)
+	}
+
+	dialect := database.DialectOf(r.db)
+	for i := range rules {
+		rule := &rules[i]
+		id, err := dialect.InsertID(tx, `
+			INSERT INTO commit_classification_rules (project_id, position, category, keywords, pattern)
+			VALUES (?, ?, ?, ?, ?)
+		`, projectID, i, string(rule.Category), strings.Join(rule.Keywords, ","), rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("failed to save classification rule: %w", err)
+		}
+		rule.ID = int(id)
+		rule.ProjectID = projectID
+	}
+
+	return tx.Commit()
+}
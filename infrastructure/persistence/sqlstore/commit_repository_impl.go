@@ -0,0 +1,473 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+)
+
+// CommitRepository implements the commit repository interface on a SQL database
+type CommitRepository struct {
+	db *sql.DB
+}
+
+// NewCommitRepository creates a new commit repository
+func NewCommitRepository(db *sql.DB) repositories.CommitRepository {
+	return &CommitRepository{db: db}
+}
+
+// Create creates a new commit
+func (r *CommitRepository) Create(commit *entities.Commit) error {
+	query := `
+		INSERT INTO commits (project_id, hash, author, timestamp, message, category, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id, err := database.DialectOf(r.db).InsertID(r.db, query,
+		commit.ProjectID,
+		commit.Hash.String(),
+		commit.Author,
+		commit.Timestamp,
+		commit.Message,
+		nullableCategory(commit.Category),
+		commit.CreatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	commit.ID = int(id)
+	return nil
+}
+
+// CreateWithChanges creates a commit and its changes in one transaction, together with their
+// counts in the pre-computed coupling and daily file statistics, so that those never count a
+// commit that is not stored or miss one that is
+func (r *CommitRepository) CreateWithChanges(commit *entities.Commit, changes []*entities.Change) error {
+	dialect := database.DialectOf(r.db)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	commitID, err := dialect.InsertID(tx, `
+		INSERT INTO commits (project_id, hash, author, timestamp, message, category, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`,
+		commit.ProjectID,
+		commit.Hash.String(),
+		commit.Author,
+		commit.Timestamp,
+		commit.Message,
+		nullableCategory(commit.Category),
+		commit.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save commit: %w", err)
+	}
+
+	filePaths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		change.CommitID = int(commitID)
+		changeID, err := dialect.InsertID(tx, `
+			INSERT INTO changes (commit_id, file_path, lines_added, lines_deleted)
+			VALUES (?, ?, ?, ?)
+		`,
+			change.CommitID,
+			change.FilePath.String(),
+			change.LinesAdded,
+			change.LinesDeleted,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save change: %w", err)
+		}
+		change.ID = int(changeID)
+		filePaths = append(filePaths, change.FilePath.String())
+	}
+
+	if err := recordCoupling(tx, dialect, commit.ProjectID, commit.Timestamp, filePaths); err != nil {
+		return err
+	}
+	if err := recordFileStats(tx, dialect, commit.ProjectID, commit.Author, commit.Timestamp, changes); err != nil {
+		return err
+	}
+	if err := linkCommitIssues(tx, dialect, commit.ProjectID, int(commitID), commit.IssueKeys); err != nil {
+		return fmt.Errorf("failed to link issues: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	commit.ID = int(commitID)
+	return nil
+}
+
+// GetByID retrieves a commit by its ID
+func (r *CommitRepository) GetByID(id int) (*entities.Commit, error) {
+	query := `
+		SELECT id, project_id, hash, author, timestamp, message, category, created_at
+		FROM commits WHERE id = ?
+	`
+
+	var hashStr string
+	var category sql.NullString
+	commit := &entities.Commit{}
+
+	err := r.db.QueryRow(query, id).Scan(
+		&commit.ID,
+		&commit.ProjectID,
+		&hashStr,
+		&commit.Author,
+		&commit.Timestamp,
+		&commit.Message,
+		&category,
+		&commit.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := values.NewGitHash(hashStr)
+	if err != nil {
+		return nil, err
+	}
+	commit.Hash = hash
+	commit.Category = values.CommitCategory(category.String)
+
+	return commit, nil
+}
+
+// GetByProjectID retrieves all commits for a specific project
+func (r *CommitRepository) GetByProjectID(projectID int) ([]*entities.Commit, error) {
+	query := `
+		SELECT id, project_id, hash, author, timestamp, message, category, created_at
+		FROM commits WHERE project_id = ?
+		ORDER BY timestamp DESC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*entities.Commit
+
+	for rows.Next() {
+		var hashStr string
+		var category sql.NullString
+		commit := &entities.Commit{}
+
+		err := rows.Scan(
+			&commit.ID,
+			&commit.ProjectID,
+			&hashStr,
+			&commit.Author,
+			&commit.Timestamp,
+			&commit.Message,
+			&category,
+			&commit.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := values.NewGitHash(hashStr)
+		if err != nil {
+			continue // Skip invalid hashes
+		}
+		commit.Hash = hash
+		commit.Category = values.CommitCategory(category.String)
+
+		commits = append(commits, commit)
+	}
+
+	return commits, rows.Err()
+}
+
+// GetByHash retrieves a commit by its git hash
+func (r *CommitRepository) GetByHash(projectID int, hash string) (*entities.Commit, error) {
+	query := `
+		SELECT id, project_id, hash, author, timestamp, message, category, created_at
+		FROM commits WHERE project_id = ? AND hash = ?
+	`
+
+	var hashStr string
+	var category sql.NullString
+	commit := &entities.Commit{}
+
+	err := r.db.QueryRow(query, projectID, hash).Scan(
+		&commit.ID,
+		&commit.ProjectID,
+		&hashStr,
+		&commit.Author,
+		&commit.Timestamp,
+		&commit.Message,
+		&category,
+		&commit.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	retrievedHash, err := values.NewGitHash(hashStr)
+	if err != nil {
+		return nil, err
+	}
+	commit.Hash = retrievedHash
+	commit.Category = values.CommitCategory(category.String)
+
+	return commit, nil
+}
+
+// GetByProjectIDSinceHash retrieves commits since a specific hash
+func (r *CommitRepository) GetByProjectIDSinceHash(projectID int, sinceHash string) ([]*entities.Commit, error) {
+	// For simplicity, we'll get all commits and filter.
+	// In a real implementation, you'd want to use git log --since functionality
+	query := `
+		SELECT id, project_id, hash, author, timestamp, message, category, created_at
+		FROM commits WHERE project_id = ?
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*entities.Commit
+	foundSinceHash := false
+
+	for rows.Next() {
+		var hashStr string
+		var category sql.NullString
+		commit := &entities.Commit{}
+
+		err := rows.Scan(
+			&commit.ID,
+			&commit.ProjectID,
+			&hashStr,
+			&commit.Author,
+			&commit.Timestamp,
+			&commit.Message,
+			&category,
+			&commit.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hashStr == sinceHash {
+			foundSinceHash = true
+			continue // Skip the sinceHash commit itself
+		}
+
+		if foundSinceHash {
+			hash, err := values.NewGitHash(hashStr)
+			if err != nil {
+				continue // Skip invalid hashes
+			}
+			commit.Hash = hash
+			commit.Category = values.CommitCategory(category.String)
+			commits = append(commits, commit)
+		}
+	}
+
+	return commits, rows.Err()
+}
+
+// GetByAuthor retrieves commits by author for a project
+func (r *CommitRepository) GetByAuthor(projectID int, author string) ([]*entities.Commit, error) {
+	query := `
+		SELECT id, project_id, hash, author, timestamp, message, category, created_at
+		FROM commits WHERE project_id = ? AND author = ?
+		ORDER BY timestamp DESC
+	`
+
+	rows, err := r.db.Query(query, projectID, author)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*entities.Commit
+
+	for rows.Next() {
+		var hashStr string
+		var category sql.NullString
+		commit := &entities.Commit{}
+
+		err := rows.Scan(
+			&commit.ID,
+			&commit.ProjectID,
+			&hashStr,
+			&commit.Author,
+			&commit.Timestamp,
+			&commit.Message,
+			&category,
+			&commit.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := values.NewGitHash(hashStr)
+		if err != nil {
+			continue // Skip invalid hashes
+		}
+		commit.Hash = hash
+		commit.Category = values.CommitCategory(category.String)
+
+		commits = append(commits, commit)
+	}
+
+	return commits, rows.Err()
+}
+
+// GetByFilePath retrieves the commits that touched a file, oldest first
+func (r *CommitRepository) GetByFilePath(projectID int, filePath string) ([]*entities.Commit, error) {
+	query := `
+		SELECT DISTINCT cm.id, cm.project_id, cm.hash, cm.author, cm.timestamp, cm.message, cm.category, cm.created_at
+		FROM commits cm
+		JOIN changes c ON c.commit_id = cm.id
+		WHERE cm.project_id = ? AND c.file_path = ?
+		ORDER BY cm.timestamp ASC, cm.id ASC
+	`
+
+	rows, err := r.db.Query(query, projectID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*entities.Commit
+
+	for rows.Next() {
+		var hashStr string
+		var category sql.NullString
+		commit := &entities.Commit{}
+
+		err := rows.Scan(
+			&commit.ID,
+			&commit.ProjectID,
+			&hashStr,
+			&commit.Author,
+			&commit.Timestamp,
+			&commit.Message,
+			&category,
+			&commit.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := values.NewGitHash(hashStr)
+		if err != nil {
+			continue // Skip invalid hashes
+		}
+		commit.Hash = hash
+		commit.Category = values.CommitCategory(category.String)
+
+		commits = append(commits, commit)
+	}
+
+	return commits, rows.Err()
+}
+
+// CreateBatch creates multiple commits in a batch operation
+func (r *CommitRepository) CreateBatch(commits []*entities.Commit) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO commits (project_id, hash, author, timestamp, message, category, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, commit := range commits {
+		_, err := stmt.Exec(
+			commit.ProjectID,
+			commit.Hash.String(),
+			commit.Author,
+			commit.Timestamp,
+			commit.Message,
+			nullableCategory(commit.Category),
+			time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// commitCategoryBatchSize caps the commit IDs updated by one statement
+const commitCategoryBatchSize = 1000
+
+// UpdateCategories sets the category of the commits with the given IDs
+func (r *CommitRepository) UpdateCategories(categories map[int]values.CommitCategory) error {
+	idsByCategory := make(map[values.CommitCategory][]interface{})
+	for id, category := range categories {
+		idsByCategory[category] = append(idsByCategory[category], id)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for category, ids := range idsByCategory {
+		for start := 0; start < len(ids); start += commitCategoryBatchSize {
+			end := start + commitCategoryBatchSize
+			if end > len(ids) {
+				end = len(ids)
+			}
+			batch := ids[start:end]
+			query := `UPDATE commits SET category = ? WHERE id IN (?` + strings.Repeat(", ?", len(batch)-1) + `)`
+			args := append([]interface{}{nullableCategory(category)}, batch...)
+			if _, err := tx.Exec(query, args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// nullableCategory stores unclassified commits as NULL
+func nullableCategory(category values.CommitCategory) interface{} {
+	if category == "" {
+		return nil
+	}
+	return string(category)
+}
@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// ComplexityRepository implements the complexity repository interface on a SQL database
+type ComplexityRepository struct {
+	db *sql.DB
+}
+
+// NewComplexityRepository creates a new complexity repository
+func NewComplexityRepository(db *sql.DB) repositories.ComplexityRepository {
+	return &ComplexityRepository{db: db}
+}
+
+// GetByFilePath retrieves all stored samples for a file, oldest first
+func (r *ComplexityRepository) GetByFilePath(projectID int, filePath string) ([]*entities.ComplexitySample, error) {
+	query := `
+		SELECT s.id, s.project_id, s.commit_id, cm.hash, s.file_path, cm.timestamp,
+		       s.lines_of_code, s.total_complexity, s.mean_complexity, s.max_complexity
+		FROM file_complexity_samples s
+		JOIN commits cm ON s.commit_id = cm.id
+		WHERE s.project_id = ? AND s.file_path = ?
+		ORDER BY cm.timestamp ASC, cm.id ASC
+	`
+
+	rows, err := r.db.Query(query, projectID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []*entities.ComplexitySample
+
+	for rows.Next() {
+		sample := &entities.ComplexitySample{}
+
+		err := rows.Scan(
+			&sample.ID,
+			&sample.ProjectID,
+			&sample.CommitID,
+			&sample.CommitHash,
+			&sample.FilePath,
+			&sample.Timestamp,
+			&sample.LinesOfCode,
+			&sample.TotalComplexity,
+			&sample.MeanComplexity,
+			&sample.MaxComplexity,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return samples, rows.Err()
+}
+
+// Save stores a sample, replacing any existing sample for the same commit and file
+func (r *ComplexityRepository) Save(sample *entities.ComplexitySample) error {
+	dialect := database.DialectOf(r.db)
+	query := `
+		INSERT INTO file_complexity_samples
+			(project_id, commit_id, file_path, lines_of_code, total_complexity, mean_complexity, max_complexity)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		` + dialect.Upsert("commit_id", "file_path") + `
+		` + dialect.SetInserted("lines_of_code", "total_complexity", "mean_complexity", "max_complexity")
+
+	id, err := dialect.InsertID(r.db, query,
+		sample.ProjectID,
+		sample.CommitID,
+		sample.FilePath,
+		sample.LinesOfCode,
+		sample.TotalComplexity,
+		sample.MeanComplexity,
+		sample.MaxComplexity,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if id > 0 {
+		sample.ID = int(id)
+	}
+	return nil
+}
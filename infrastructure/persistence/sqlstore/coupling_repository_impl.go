@@ -0,0 +1,141 @@
+package sqlstore
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// couplingRollup names the pre-computed coupling in project_rollups
+const couplingRollup = "coupling"
+
+// couplingInsertBatch bounds the number of rows written per INSERT statement
+const couplingInsertBatch = 500
+
+// CouplingRepository implements the coupling repository interface on a SQL database
+type CouplingRepository struct {
+	db *sql.DB
+}
+
+// NewCouplingRepository creates a new coupling repository
+func NewCouplingRepository(db *sql.DB) repositories.CouplingRepository {
+	return &CouplingRepository{db: db}
+}
+
+// couplingPairHash identifies a file pair in file_pair_coupling; the paths are too long to be
+// indexed in full. A file's own commit count is stored as the pair of the file with itself.
+func couplingPairHash(fileA, fileB string) string {
+	sum := sha1.Sum([]byte(fileA + "\x00" + fileB))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordCommit adds a commit's changed files to the monthly buckets of their counts and pairs
+func (r *CouplingRepository) RecordCommit(projectID int, committedAt time.Time, filePaths []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := recordCoupling(tx, database.DialectOf(r.db), projectID, committedAt, filePaths); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// recordCoupling writes RecordCommit's counts with db, which may be a transaction that stores
+// the commit as well
+func recordCoupling(db database.Execer, dialect database.Dialect, projectID int, committedAt time.Time, filePaths []string) error {
+	seen := make(map[string]bool, len(filePaths))
+	files := make([]string, 0, len(filePaths))
+	for _, path := range filePaths {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	// Pairs are stored with file_a < file_b, as in the live coupling query
+	sort.Strings(files)
+
+	type pair struct{ a, b string }
+	pairs := make([]pair, 0, len(files))
+	for _, file := range files {
+		pairs = append(pairs, pair{file, file})
+	}
+	if len(files) <= repositories.MaxCouplingCommitFiles {
+		for i, a := range files {
+			for _, b := range files[i+1:] {
+				pairs = append(pairs, pair{a, b})
+			}
+		}
+	}
+
+	utc := committedAt.UTC()
+	month := time.Date(utc.Year(), utc.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+
+	for start := 0; start < len(pairs); start += couplingInsertBatch {
+		end := start + couplingInsertBatch
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+
+		placeholders := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*6)
+		for _, p := range pairs[start:end] {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, 1, ?)")
+			args = append(args, projectID, couplingPairHash(p.a, p.b), p.a, p.b, month, committedAt)
+		}
+
+		query := `
+			INSERT INTO file_pair_coupling (project_id, pair_hash, file_a, file_b, bucket_month, shared_commits, last_commit_at)
+			VALUES ` + strings.Join(placeholders, ", ") + `
+			` + dialect.Upsert("project_id", "pair_hash", "bucket_month") + `
+				shared_commits = file_pair_coupling.shared_commits + 1,
+				last_commit_at = ` + dialect.Greatest("file_pair_coupling.last_commit_at", dialect.Inserted("last_commit_at"))
+		if _, err := db.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to record coupling: %w", err)
+		}
+	}
+	return nil
+}
+
+// MarkComplete records that the stored coupling covers every stored commit of a project
+func (r *CouplingRepository) MarkComplete(projectID int) error {
+	return markRollupComplete(r.db, database.DialectOf(r.db), projectID, couplingRollup)
+}
+
+// IsComplete reports whether the stored coupling covers every stored commit of a project
+func (r *CouplingRepository) IsComplete(projectID int) (bool, error) {
+	return isRollupComplete(r.db, projectID, couplingRollup)
+}
+
+// Rebuild replaces the stored coupling of a project with counts recomputed from its stored
+// commits and marks it complete, keeping the project's commits from being stored meanwhile
+func (r *CouplingRepository) Rebuild(projectID int) (int, error) {
+	return rebuildRollup(r.db, projectID, couplingRollup, "file_pair_coupling", func(tx *sql.Tx, dialect database.Dialect, commit storedCommit) error {
+		files := make([]string, 0, len(commit.changes))
+		for _, change := range commit.changes {
+			files = append(files, change.FilePath.String())
+		}
+		return recordCoupling(tx, dialect, projectID, commit.committedAt, files)
+	})
+}
+
+// DeleteByProjectID removes all stored coupling of a project and its completeness mark
+func (r *CouplingRepository) DeleteByProjectID(projectID int) error {
+	if err := clearRollup(r.db, projectID, couplingRollup); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM file_pair_coupling WHERE project_id = ?`, projectID)
+	return err
+}
@@ -0,0 +1,4 @@
+// Package sqlstore implements the domain repositories on the databases database.Open opens:
+// MySQL, PostgreSQL and SQLite. The queries are MySQL SQL restricted to what the backends share,
+// with the clauses that differ taken from the database's dialect.
+package sqlstore
@@ -0,0 +1,102 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+// FileMetricsRepository implements the file metrics repository interface on a SQL database
+type FileMetricsRepository struct {
+	db *sql.DB
+}
+
+// NewFileMetricsRepository creates a new file metrics repository
+func NewFileMetricsRepository(db *sql.DB) repositories.FileMetricsRepository {
+	return &FileMetricsRepository{db: db}
+}
+
+// ReplaceForProject replaces the stored measurements of a project with a new snapshot
+func (r *FileMetricsRepository) ReplaceForProject(projectID int, metrics []*entities.FileMetrics) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM file_metrics WHERE project_id = ?", projectID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO file_metrics
+			(project_id, commit_hash, file_path, language, code_lines, comment_lines, blank_lines, is_binary, measured_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, m := range metrics {
+		_, err := stmt.Exec(
+			projectID,
+			m.CommitHash,
+			m.FilePath,
+			m.Language,
+			m.CodeLines,
+			m.CommentLines,
+			m.BlankLines,
+			m.IsBinary,
+			m.MeasuredAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByProjectID retrieves the current measurements of a project
+func (r *FileMetricsRepository) GetByProjectID(projectID int) ([]*entities.FileMetrics, error) {
+	query := `
+		SELECT project_id, commit_hash, file_path, language, code_lines, comment_lines, blank_lines, is_binary, measured_at
+		FROM file_metrics
+		WHERE project_id = ?
+		ORDER BY file_path
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metrics []*entities.FileMetrics
+
+	for rows.Next() {
+		m := &entities.FileMetrics{}
+
+		err := rows.Scan(
+			&m.ProjectID,
+			&m.CommitHash,
+			&m.FilePath,
+			&m.Language,
+			&m.CodeLines,
+			&m.CommentLines,
+			&m.BlankLines,
+			&m.IsBinary,
+			&m.MeasuredAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
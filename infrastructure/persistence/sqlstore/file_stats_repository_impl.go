@@ -0,0 +1,137 @@
+package sqlstore
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// FileStatsRepository implements the file stats repository interface on a SQL database
+type FileStatsRepository struct {
+	db *sql.DB
+}
+
+// NewFileStatsRepository creates a new file stats repository
+func NewFileStatsRepository(db *sql.DB) repositories.FileStatsRepository {
+	return &FileStatsRepository{db: db}
+}
+
+// fileStatsRollup names the daily file statistics in project_rollups
+const fileStatsRollup = "file_stats"
+
+// filePathHash identifies a file in file_daily_stats, as the paths are too long to be indexed
+// in full; it matches MySQL's SHA1(file_path)
+func filePathHash(filePath string) string {
+	sum := sha1.Sum([]byte(filePath))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordCommit adds a commit's changes to the daily statistics of their files
+func (r *FileStatsRepository) RecordCommit(projectID int, author string, committedAt time.Time, changes []*entities.Change) error {
+	return recordFileStats(r.db, database.DialectOf(r.db), projectID, author, committedAt, changes)
+}
+
+// recordFileStats writes RecordCommit's statistics with db, which may be a transaction that
+// stores the commit as well
+func recordFileStats(db database.Execer, dialect database.Dialect, projectID int, author string, committedAt time.Time, changes []*entities.Change) error {
+	// A file listed twice is merged into one row, as PostgreSQL rejects an upsert
+	// that touches the same row twice
+	type fileLines struct{ added, deleted int }
+	var paths []string
+	lines := make(map[string]*fileLines, len(changes))
+	for _, change := range changes {
+		if change.FilePath == nil {
+			continue
+		}
+		filePath := change.FilePath.String()
+		if lines[filePath] == nil {
+			paths = append(paths, filePath)
+			lines[filePath] = &fileLines{}
+		}
+		lines[filePath].added += change.LinesAdded
+		lines[filePath].deleted += change.LinesDeleted
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(paths))
+	args := make([]interface{}, 0, len(paths)*8)
+	day := committedAt.UTC().Format("2006-01-02")
+	for _, filePath := range paths {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, 1, ?, ?, ?)")
+		args = append(args, projectID, filePathHash(filePath), filePath, author, day, lines[filePath].added, lines[filePath].deleted, committedAt)
+	}
+
+	query := `
+		INSERT INTO file_daily_stats (project_id, path_hash, file_path, author, day, commits, lines_added, lines_deleted, last_commit_at)
+		VALUES ` + strings.Join(placeholders, ", ") + `
+		` + dialect.Upsert("project_id", "path_hash", "author", "day") + `
+			commits = file_daily_stats.commits + 1,
+			lines_added = file_daily_stats.lines_added + ` + dialect.Inserted("lines_added") + `,
+			lines_deleted = file_daily_stats.lines_deleted + ` + dialect.Inserted("lines_deleted") + `,
+			last_commit_at = ` + dialect.Greatest("file_daily_stats.last_commit_at", dialect.Inserted("last_commit_at"))
+	if _, err := db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to record file statistics: %w", err)
+	}
+	return nil
+}
+
+// MarkComplete records that the daily statistics cover every stored commit of a project
+func (r *FileStatsRepository) MarkComplete(projectID int) error {
+	return markRollupComplete(r.db, database.DialectOf(r.db), projectID, fileStatsRollup)
+}
+
+// IsComplete reports whether the daily statistics cover every stored commit of a project
+func (r *FileStatsRepository) IsComplete(projectID int) (bool, error) {
+	return isRollupComplete(r.db, projectID, fileStatsRollup)
+}
+
+// Rebuild replaces the daily statistics of a project with ones recomputed from its stored
+// commits and marks them complete, keeping the project's commits from being stored meanwhile
+func (r *FileStatsRepository) Rebuild(projectID int) (int, error) {
+	return rebuildRollup(r.db, projectID, fileStatsRollup, "file_daily_stats", func(tx *sql.Tx, dialect database.Dialect, commit storedCommit) error {
+		return recordFileStats(tx, dialect, projectID, commit.author, commit.committedAt, commit.changes)
+	})
+}
+
+// DeleteByProjectID removes all daily statistics of a project and their completeness mark
+func (r *FileStatsRepository) DeleteByProjectID(projectID int) error {
+	if err := clearRollup(r.db, projectID, fileStatsRollup); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM file_daily_stats WHERE project_id = ?`, projectID)
+	return err
+}
+
+// changeStatsRelation derives the columns of file_daily_stats from the change history, one row
+// per change
+const changeStatsRelation = `(
+	SELECT c.project_id, ch.file_path AS path_hash, ch.file_path, c.author, DATE(c.timestamp) AS day,
+	       1 AS commits, ch.lines_added, ch.lines_deleted, c.timestamp AS last_commit_at
+	FROM changes ch
+	JOIN commits c ON ch.commit_id = c.id
+	WHERE ch.file_path IS NOT NULL
+)`
+
+// FileStatsRelation returns the relation a project's daily file statistics are read from:
+// file_daily_stats once they cover every stored commit, otherwise the same columns derived from
+// the change history, so that projects analysed before the statistics were kept still report
+// their whole history. Queries give it an alias, as in "FROM " + relation + " file_stats".
+func FileStatsRelation(db *sql.DB, projectID int) (string, error) {
+	complete, err := isRollupComplete(db, projectID, fileStatsRollup)
+	if err != nil {
+		return "", err
+	}
+	if complete {
+		return "file_daily_stats", nil
+	}
+	return changeStatsRelation, nil
+}
@@ -0,0 +1,145 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+const healthSnapshotColumns = `
+	id, project_id, month, commit_hash, score, hotspot_complexity, coupling,
+	bus_factor_risk, stale_code, files, active_files, policy_version, computed_at`
+
+// HealthSnapshotRepository implements the health snapshot repository interface on a SQL database
+type HealthSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewHealthSnapshotRepository creates a new health snapshot repository
+func NewHealthSnapshotRepository(db *sql.DB) repositories.HealthSnapshotRepository {
+	return &HealthSnapshotRepository{db: db}
+}
+
+// GetByProjectID retrieves the most recent snapshots of a project, up to limit (all when
+// limit is 0), ordered by month
+func (r *HealthSnapshotRepository) GetByProjectID(projectID int, limit int) ([]entities.HealthSnapshot, error) {
+	query := `SELECT ` + healthSnapshotColumns + `
+		FROM project_health_snapshots
+		WHERE project_id = ?
+		ORDER BY month DESC`
+	args := []interface{}{projectID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots, err := scanHealthSnapshots(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first for the limit, oldest first for the caller
+	for i, j := 0, len(snapshots)-1; i < j; i, j = i+1, j-1 {
+		snapshots[i], snapshots[j] = snapshots[j], snapshots[i]
+	}
+	return snapshots, nil
+}
+
+// GetLatest retrieves a project's most recent snapshot, or nil when it has none
+func (r *HealthSnapshotRepository) GetLatest(projectID int) (*entities.HealthSnapshot, error) {
+	snapshots, err := r.GetByProjectID(projectID, 1)
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return &snapshots[0], nil
+}
+
+// Save inserts or replaces the snapshots of their months
+func (r *HealthSnapshotRepository) Save(snapshots []entities.HealthSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	dialect := database.DialectOf(r.db)
+	stmt, err := tx.Prepare(`
+		INSERT INTO project_health_snapshots
+			(project_id, month, commit_hash, score, hotspot_complexity, coupling, bus_factor_risk,
+			 stale_code, files, active_files, policy_version, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		` + dialect.Upsert("project_id", "month") + `
+		` + dialect.SetInserted("commit_hash", "score", "hotspot_complexity", "coupling", "bus_factor_risk",
+		"stale_code", "files", "active_files", "policy_version", "computed_at"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, snapshot := range snapshots {
+		if _, err := stmt.Exec(
+			snapshot.ProjectID,
+			snapshot.Month.Format("2006-01-02"),
+			snapshot.CommitHash,
+			snapshot.Score,
+			snapshot.HotspotComplexity,
+			snapshot.Coupling,
+			snapshot.BusFactorRisk,
+			snapshot.StaleCode,
+			snapshot.Files,
+			snapshot.ActiveFiles,
+			snapshot.PolicyVersion,
+			snapshot.ComputedAt.UTC(),
+		); err != nil {
+			return fmt.Errorf("failed to save health snapshot for %s: %w", snapshot.Month.Format("2006-01"), err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteByProjectID removes all snapshots of a project
+func (r *HealthSnapshotRepository) DeleteByProjectID(projectID int) error {
+	_, err := r.db.Exec("DELETE FROM project_health_snapshots WHERE project_id = ?", projectID)
+	return err
+}
+
+// scanHealthSnapshots reads rows selected with healthSnapshotColumns
+func scanHealthSnapshots(rows *sql.Rows) ([]entities.HealthSnapshot, error) {
+	snapshots := make([]entities.HealthSnapshot, 0)
+	for rows.Next() {
+		var snapshot entities.HealthSnapshot
+		if err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.ProjectID,
+			&snapshot.Month,
+			&snapshot.CommitHash,
+			&snapshot.Score,
+			&snapshot.HotspotComplexity,
+			&snapshot.Coupling,
+			&snapshot.BusFactorRisk,
+			&snapshot.StaleCode,
+			&snapshot.Files,
+			&snapshot.ActiveFiles,
+			&snapshot.PolicyVersion,
+			&snapshot.ComputedAt,
+		); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
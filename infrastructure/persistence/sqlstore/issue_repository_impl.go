@@ -0,0 +1,145 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// IssueRepository implements the issue repository interface on a SQL database
+type IssueRepository struct {
+	db *sql.DB
+}
+
+// NewIssueRepository creates a new issue repository
+func NewIssueRepository(db *sql.DB) repositories.IssueRepository {
+	return &IssueRepository{db: db}
+}
+
+// GetPatterns retrieves the issue key patterns of a project in order
+func (r *IssueRepository) GetPatterns(projectID int) ([]string, error) {
+	rows, err := r.db.Query("SELECT pattern FROM issue_patterns WHERE project_id = ? ORDER BY position, id", projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue patterns: %w", err)
+	}
+	defer rows.Close()
+
+	patterns := make([]string, 0)
+	for rows.Next() {
+		var pattern string
+		if err := rows.Scan(&pattern); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, rows.Err()
+}
+
+// ReplacePatterns replaces the issue key patterns of a project; an empty list restores the defaults
+func (r *IssueRepository) ReplacePatterns(projectID int, patterns []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM issue_patterns WHERE project_id = ?", projectID); err != nil {
+		return fmt.Errorf("failed to clear issue patterns: %w", err)
+	}
+	for i, pattern := range patterns {
+		if _, err := tx.Exec("INSERT INTO issue_patterns (project_id, position, pattern) VALUES (?, ?, ?)", projectID, i, pattern); err != nil {
+			return fmt.Errorf("failed to save issue pattern: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LinkCommit records the issue keys a commit references
+func (r *IssueRepository) LinkCommit(projectID, commitID int, keys []string) error {
+	return linkCommitIssues(r.db, database.DialectOf(r.db), projectID, commitID, keys)
+}
+
+// linkCommitIssues writes LinkCommit's links with db, which may be a transaction that stores
+// the commit as well
+func linkCommitIssues(db database.Execer, dialect database.Dialect, projectID, commitID int, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys)*3)
+	for _, key := range keys {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		args = append(args, projectID, commitID, key)
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO commit_issues (project_id, commit_id, issue_key)
+		VALUES `+strings.Join(placeholders, ", ")+`
+		`+dialect.Upsert("commit_id", "issue_key")+` `+dialect.SetInserted("issue_key"), args...)
+	return err
+}
+
+// DeleteLinksByProjectID removes all commit-issue links of a project
+func (r *IssueRepository) DeleteLinksByProjectID(projectID int) error {
+	_, err := r.db.Exec("DELETE FROM commit_issues WHERE project_id = ?", projectID)
+	return err
+}
+
+// UpsertIssues creates or updates the metadata of issues by key
+func (r *IssueRepository) UpsertIssues(projectID int, issues []*entities.Issue) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	dialect := database.DialectOf(r.db)
+	stmt, err := tx.Prepare(`
+		INSERT INTO issues (project_id, issue_key, title, issue_type, priority, status, imported_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		` + dialect.Upsert("project_id", "issue_key") + `
+		` + dialect.SetInserted("title", "issue_type", "priority", "status", "imported_at"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, issue := range issues {
+		if _, err := stmt.Exec(projectID, issue.Key, issue.Title, issue.Type, issue.Priority, issue.Status, now); err != nil {
+			return fmt.Errorf("failed to import issue %s: %w", issue.Key, err)
+		}
+		issue.ProjectID = projectID
+		issue.ImportedAt = now
+	}
+
+	return tx.Commit()
+}
+
+// GetIssueTypes maps the keys of a project's imported issues to their types
+func (r *IssueRepository) GetIssueTypes(projectID int) (map[string]string, error) {
+	rows, err := r.db.Query("SELECT issue_key, issue_type FROM issues WHERE project_id = ? AND issue_type <> ''", projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue types: %w", err)
+	}
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var key, issueType string
+		if err := rows.Scan(&key, &issueType); err != nil {
+			return nil, err
+		}
+		types[key] = issueType
+	}
+
+	return types, rows.Err()
+}
@@ -1,4 +1,4 @@
-package mysql
+package sqlstore
 
 import (
 	"database/sql"
@@ -8,6 +8,7 @@ import (
 	"codeecho/domain/entities"
 	"codeecho/domain/repositories"
 	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
 	"codeecho/infrastructure/persistence/models"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -49,7 +50,7 @@ func (r *ProjectRepositoryImpl) Create(project *entities.Project) error {
 		}
 	}
 
-	result, err := r.db.Exec(query,
+	id, err := database.DialectOf(r.db).InsertID(r.db, query,
 		project.Name,
 		project.RepoPath,
 		string(project.RepoType),
@@ -62,11 +63,6 @@ func (r *ProjectRepositoryImpl) Create(project *entities.Project) error {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
-	}
-
 	project.ID = int(id)
 	return nil
 }
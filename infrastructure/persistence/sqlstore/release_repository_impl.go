@@ -0,0 +1,74 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+)
+
+// ReleaseRepository implements the release repository interface on a SQL database
+type ReleaseRepository struct {
+	db *sql.DB
+}
+
+// NewReleaseRepository creates a new release repository
+func NewReleaseRepository(db *sql.DB) repositories.ReleaseRepository {
+	return &ReleaseRepository{db: db}
+}
+
+// GetByProjectID retrieves a project's releases ordered by commit date
+func (r *ReleaseRepository) GetByProjectID(projectID int) ([]entities.Release, error) {
+	rows, err := r.db.Query(`
+		SELECT id, project_id, name, commit_hash, commit_date, released_at
+		FROM releases
+		WHERE project_id = ?
+		ORDER BY commit_date, released_at, name
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get releases: %w", err)
+	}
+	defer rows.Close()
+
+	releases := make([]entities.Release, 0)
+	for rows.Next() {
+		var release entities.Release
+		if err := rows.Scan(&release.ID, &release.ProjectID, &release.Name, &release.CommitHash, &release.CommitDate, &release.ReleasedAt); err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, rows.Err()
+}
+
+// ReplaceForProject replaces all releases of a project with its current tags
+func (r *ReleaseRepository) ReplaceForProject(projectID int, releases []entities.Release) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM releases WHERE project_id = ?", projectID); err != nil {
+		return fmt.Errorf("failed to clear releases: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO releases (project_id, name, commit_hash, commit_date, released_at)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, release := range releases {
+		if _, err := stmt.Exec(projectID, release.Name, release.CommitHash, release.CommitDate.UTC(), release.ReleasedAt.UTC()); err != nil {
+			return fmt.Errorf("failed to save release %s: %w", release.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
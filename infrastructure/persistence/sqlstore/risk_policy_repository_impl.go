@@ -0,0 +1,134 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// RiskPolicyRepository implements the risk policy repository interface on a SQL database
+type RiskPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewRiskPolicyRepository creates a new risk policy repository
+func NewRiskPolicyRepository(db *sql.DB) repositories.RiskPolicyRepository {
+	return &RiskPolicyRepository{db: db}
+}
+
+const riskPolicyColumns = `
+	id, project_id, version,
+	change_frequency_medium, change_frequency_high, change_frequency_critical,
+	ownership_medium, ownership_high, ownership_critical,
+	bus_factor_medium, bus_factor_high, bus_factor_critical,
+	coupling_medium, coupling_high, coupling_critical, coupling_min_shared_commits,
+	weight_hotspot_complexity, weight_coupling, weight_bus_factor_risk, weight_stale_code,
+	created_at`
+
+// GetCurrent retrieves the latest version of a project's policy, or nil when the project uses
+// the default policy
+func (r *RiskPolicyRepository) GetCurrent(projectID int) (*entities.RiskPolicy, error) {
+	rows, err := r.db.Query(`SELECT `+riskPolicyColumns+`
+		FROM risk_policies
+		WHERE project_id = ?
+		ORDER BY version DESC
+		LIMIT 1
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk policy: %w", err)
+	}
+	defer rows.Close()
+
+	policies, err := scanRiskPolicies(rows)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	return &policies[0], nil
+}
+
+// GetVersions retrieves every version of a project's policy, newest first
+func (r *RiskPolicyRepository) GetVersions(projectID int) ([]entities.RiskPolicy, error) {
+	rows, err := r.db.Query(`SELECT `+riskPolicyColumns+`
+		FROM risk_policies
+		WHERE project_id = ?
+		ORDER BY version DESC
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk policy versions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRiskPolicies(rows)
+}
+
+// Create stores a policy as the next version of its project's policy, setting its ID, version
+// and creation time. Concurrent updates of the same project fail on the unique version key.
+func (r *RiskPolicyRepository) Create(policy *entities.RiskPolicy) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var latest int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM risk_policies WHERE project_id = ?", policy.ProjectID).Scan(&latest); err != nil {
+		return fmt.Errorf("failed to get risk policy version: %w", err)
+	}
+
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	id, err := database.DialectOf(r.db).InsertID(tx, `
+		INSERT INTO risk_policies (
+			project_id, version,
+			change_frequency_medium, change_frequency_high, change_frequency_critical,
+			ownership_medium, ownership_high, ownership_critical,
+			bus_factor_medium, bus_factor_high, bus_factor_critical,
+			coupling_medium, coupling_high, coupling_critical, coupling_min_shared_commits,
+			weight_hotspot_complexity, weight_coupling, weight_bus_factor_risk, weight_stale_code,
+			created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		policy.ProjectID, latest+1,
+		policy.ChangeFrequency.Medium, policy.ChangeFrequency.High, policy.ChangeFrequency.Critical,
+		policy.Ownership.Medium, policy.Ownership.High, policy.Ownership.Critical,
+		policy.BusFactor.Medium, policy.BusFactor.High, policy.BusFactor.Critical,
+		policy.Coupling.Medium, policy.Coupling.High, policy.Coupling.Critical, policy.CouplingMinSharedCommits,
+		policy.DebtWeights.HotspotComplexity, policy.DebtWeights.Coupling, policy.DebtWeights.BusFactorRisk, policy.DebtWeights.StaleCode,
+		createdAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save risk policy: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	policy.ID = int(id)
+	policy.Version = latest + 1
+	policy.CreatedAt = createdAt
+	return nil
+}
+
+// scanRiskPolicies reads rows selected with riskPolicyColumns
+func scanRiskPolicies(rows *sql.Rows) ([]entities.RiskPolicy, error) {
+	policies := make([]entities.RiskPolicy, 0)
+	for rows.Next() {
+		var p entities.RiskPolicy
+		if err := rows.Scan(
+			&p.ID, &p.ProjectID, &p.Version,
+			&p.ChangeFrequency.Medium, &p.ChangeFrequency.High, &p.ChangeFrequency.Critical,
+			&p.Ownership.Medium, &p.Ownership.High, &p.Ownership.Critical,
+			&p.BusFactor.Medium, &p.BusFactor.High, &p.BusFactor.Critical,
+			&p.Coupling.Medium, &p.Coupling.High, &p.Coupling.Critical, &p.CouplingMinSharedCommits,
+			&p.DebtWeights.HotspotComplexity, &p.DebtWeights.Coupling, &p.DebtWeights.BusFactorRisk, &p.DebtWeights.StaleCode,
+			&p.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
@@ -0,0 +1,133 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+)
+
+// markRollupComplete records in project_rollups that a derived table covers every stored commit
+// of a project. Ingestion keeps a complete rollup complete, as it stores each commit in the same
+// transaction as the commit's rollup counts.
+func markRollupComplete(db database.Execer, dialect database.Dialect, projectID int, rollup string) error {
+	_, err := db.Exec(`
+		INSERT INTO project_rollups (project_id, rollup, completed_at)
+		VALUES (?, ?, ?)
+		`+dialect.Upsert("project_id", "rollup")+` `+dialect.SetInserted("completed_at"),
+		projectID, rollup, time.Now().UTC())
+	return err
+}
+
+// isRollupComplete reports whether markRollupComplete marked a project's rollup
+func isRollupComplete(db *sql.DB, projectID int, rollup string) (bool, error) {
+	var marked int
+	err := db.QueryRow(`SELECT 1 FROM project_rollups WHERE project_id = ? AND rollup = ?`, projectID, rollup).Scan(&marked)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// clearRollup removes a project's rollup mark, before the rollup's rows are deleted
+func clearRollup(db database.Execer, projectID int, rollup string) error {
+	_, err := db.Exec(`DELETE FROM project_rollups WHERE project_id = ? AND rollup = ?`, projectID, rollup)
+	return err
+}
+
+// storedCommit is a stored commit with its changes, as a rollup rebuild replays it
+type storedCommit struct {
+	author      string
+	committedAt time.Time
+	changes     []*entities.Change
+}
+
+// rebuildRollup replaces a project's rows of a rollup table with ones record computes from the
+// stored commits, and marks the rollup complete, returning the number of commits recorded. It
+// runs in one transaction that first locks the project's row: storing a commit waits for that
+// lock through the commit's foreign key, so no commit is stored while the rollup is replaced
+// and none is counted twice or left out. SQLite has no row locks, but lets a single transaction
+// write at a time.
+func rebuildRollup(db *sql.DB, projectID int, rollup, table string, record func(tx *sql.Tx, dialect database.Dialect, commit storedCommit) error) (int, error) {
+	dialect := database.DialectOf(db)
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	if err := tx.QueryRow(`SELECT id FROM projects WHERE id = ? `+dialect.ForUpdate(), projectID).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock project %d: %w", projectID, err)
+	}
+	if err := clearRollup(tx, projectID, rollup); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`DELETE FROM `+table+` WHERE project_id = ?`, projectID); err != nil {
+		return 0, err
+	}
+
+	commits, err := loadStoredCommits(tx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	for _, commit := range commits {
+		if err := record(tx, dialect, commit); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := markRollupComplete(tx, dialect, projectID, rollup); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(commits), nil
+}
+
+// loadStoredCommits reads the commits of a project that changed files, in the order they were
+// stored. They are read in full before the rebuild writes, as a transaction runs one statement
+// at a time.
+func loadStoredCommits(tx *sql.Tx, projectID int) ([]storedCommit, error) {
+	rows, err := tx.Query(`
+		SELECT c.id, c.author, c.timestamp, ch.file_path, ch.lines_added, ch.lines_deleted
+		FROM commits c
+		JOIN changes ch ON ch.commit_id = c.id
+		WHERE c.project_id = ?
+		ORDER BY c.id, ch.id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commits: %w", err)
+	}
+	defer rows.Close()
+
+	var commits []storedCommit
+	lastID := 0
+	for rows.Next() {
+		var commitID, linesAdded, linesDeleted int
+		var author, filePathStr string
+		var committedAt time.Time
+		if err := rows.Scan(&commitID, &author, database.Timestamp(&committedAt), &filePathStr, &linesAdded, &linesDeleted); err != nil {
+			return nil, err
+		}
+
+		filePath, err := values.NewFilePath(filePathStr)
+		if err != nil {
+			continue // Skip invalid file paths, as the change repository does
+		}
+		if commitID != lastID {
+			commits = append(commits, storedCommit{author: author, committedAt: committedAt})
+			lastID = commitID
+		}
+		last := &commits[len(commits)-1]
+		last.changes = append(last.changes, entities.NewChange(commitID, filePath, linesAdded, linesDeleted))
+	}
+	return commits, rows.Err()
+}
@@ -0,0 +1,207 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/infrastructure/database"
+)
+
+// TeamRepository implements the team repository interface on a SQL database
+type TeamRepository struct {
+	db *sql.DB
+}
+
+// NewTeamRepository creates a new team repository
+func NewTeamRepository(db *sql.DB) repositories.TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+// Create saves a new team with its members
+func (r *TeamRepository) Create(team *entities.Team) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, err := database.DialectOf(r.db).InsertID(tx,
+		"INSERT INTO teams (name, description, created_at) VALUES (?, ?, ?)",
+		team.Name, team.Description, team.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create team: %w", err)
+	}
+	team.ID = int(id)
+
+	if err := insertTeamMembers(tx, team); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a team by its ID
+func (r *TeamRepository) GetByID(id int) (*entities.Team, error) {
+	team := &entities.Team{}
+	err := r.db.QueryRow(
+		"SELECT id, name, description, created_at FROM teams WHERE id = ?", id,
+	).Scan(&team.ID, &team.Name, &team.Description, &team.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("team with id %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get team by id: %w", err)
+	}
+
+	members, err := r.getMembers([]int{team.ID})
+	if err != nil {
+		return nil, err
+	}
+	team.Members = members[team.ID]
+
+	return team, nil
+}
+
+// GetAll retrieves all teams
+func (r *TeamRepository) GetAll() ([]*entities.Team, error) {
+	rows, err := r.db.Query("SELECT id, name, description, created_at FROM teams ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []*entities.Team
+	var ids []int
+	for rows.Next() {
+		team := &entities.Team{}
+		if err := rows.Scan(&team.ID, &team.Name, &team.Description, &team.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+		ids = append(ids, team.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	members, err := r.getMembers(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		team.Members = members[team.ID]
+	}
+
+	return teams, nil
+}
+
+// Update replaces a team's details and members
+func (r *TeamRepository) Update(team *entities.Team) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE teams SET name = ?, description = ? WHERE id = ?",
+		team.Name, team.Description, team.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update team: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		var exists int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM teams WHERE id = ?", team.ID).Scan(&exists); err != nil || exists == 0 {
+			return fmt.Errorf("team with id %d not found", team.ID)
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM team_members WHERE team_id = ?", team.ID); err != nil {
+		return err
+	}
+	if err := insertTeamMembers(tx, team); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes a team
+func (r *TeamRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM teams WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("team with id %d not found", id)
+	}
+
+	return nil
+}
+
+// insertTeamMembers stores one row per member identity within an open transaction
+func insertTeamMembers(tx *sql.Tx, team *entities.Team) error {
+	stmt, err := tx.Prepare("INSERT INTO team_members (team_id, member_name, identity) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, member := range team.Members {
+		for _, identity := range member.Identities {
+			if _, err := stmt.Exec(team.ID, member.Name, identity); err != nil {
+				return fmt.Errorf("failed to add team member %s: %w", member.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// getMembers loads the members of the given teams, grouping identities by member
+func (r *TeamRepository) getMembers(teamIDs []int) (map[int][]entities.TeamMember, error) {
+	members := make(map[int][]entities.TeamMember)
+	if len(teamIDs) == 0 {
+		return members, nil
+	}
+
+	rows, err := r.db.Query("SELECT team_id, member_name, identity FROM team_members ORDER BY team_id, member_name, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	defer rows.Close()
+
+	wanted := make(map[int]bool, len(teamIDs))
+	for _, id := range teamIDs {
+		wanted[id] = true
+	}
+
+	for rows.Next() {
+		var teamID int
+		var name, identity string
+		if err := rows.Scan(&teamID, &name, &identity); err != nil {
+			return nil, err
+		}
+		if !wanted[teamID] {
+			continue
+		}
+
+		list := members[teamID]
+		if n := len(list); n > 0 && list[n-1].Name == name {
+			list[n-1].Identities = append(list[n-1].Identities, identity)
+		} else {
+			list = append(list, entities.TeamMember{Name: name, Identities: []string{identity}})
+		}
+		members[teamID] = list
+	}
+
+	return members, rows.Err()
+}
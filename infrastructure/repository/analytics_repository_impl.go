@@ -1,8 +1,18 @@
 package repository
 
 import (
+	"codeecho/domain/entities"
+	"codeecho/domain/repositories"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
 	"codeecho/internal/models"
 	"database/sql"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -15,8 +25,9 @@ func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
 	return &AnalyticsRepository{db: db}
 }
 
-// GetProjectOverview returns overview statistics for a project
-func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.ProjectOverview, error) {
+// GetProjectOverview returns overview statistics for a project with the debt trend of its last
+// trendMonths health snapshots
+func (r *AnalyticsRepository) GetProjectOverview(projectID int, trendMonths int) (*models.ProjectOverview, error) {
 	overview := &models.ProjectOverview{}
 
 	// Get project basic info
@@ -27,12 +38,16 @@ func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.Project
 		return nil, err
 	}
 
-	// Get total files count (distinct file_paths from changes)
+	stats, err := sqlstore.FileStatsRelation(r.db, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get total files count (distinct files in the change history)
 	err = r.db.QueryRow(`
-		SELECT COUNT(DISTINCT ch.file_path)
-		FROM changes ch
-		JOIN commits c ON ch.commit_id = c.id
-		WHERE c.project_id = ?
+		SELECT COUNT(DISTINCT path_hash)
+		FROM `+stats+` file_stats
+		WHERE project_id = ?
 	`, projectID).Scan(&overview.TotalFiles)
 	if err != nil {
 		return nil, err
@@ -46,18 +61,30 @@ func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.Project
 		return nil, err
 	}
 
-	// Get total lines of code (sum of all lines added minus lines deleted)
-	var totalLinesAdded, totalLinesDeleted int
+	// Get total lines of code measured from the tree at the analysed commit
+	var measuredLOC sql.NullInt64
 	err = r.db.QueryRow(`
-		SELECT COALESCE(SUM(ch.lines_added), 0), COALESCE(SUM(ch.lines_deleted), 0)
-		FROM changes ch
-		JOIN commits c ON ch.commit_id = c.id
-		WHERE c.project_id = ?
-	`, projectID).Scan(&totalLinesAdded, &totalLinesDeleted)
+		SELECT SUM(code_lines) FROM file_metrics WHERE project_id = ? AND is_binary = FALSE
+	`, projectID).Scan(&measuredLOC)
 	if err != nil {
 		return nil, err
 	}
-	overview.TotalLOC = totalLinesAdded - totalLinesDeleted
+
+	if measuredLOC.Valid {
+		overview.TotalLOC = int(measuredLOC.Int64)
+	} else {
+		// Not measured yet: approximate with lines added minus lines deleted
+		var totalLinesAdded, totalLinesDeleted int
+		err = r.db.QueryRow(`
+			SELECT COALESCE(SUM(lines_added), 0), COALESCE(SUM(lines_deleted), 0)
+			FROM `+stats+` file_stats
+			WHERE project_id = ?
+		`, projectID).Scan(&totalLinesAdded, &totalLinesDeleted)
+		if err != nil {
+			return nil, err
+		}
+		overview.TotalLOC = totalLinesAdded - totalLinesDeleted
+	}
 
 	// Get unique contributors count
 	err = r.db.QueryRow(`
@@ -67,18 +94,18 @@ func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.Project
 		return nil, err
 	}
 
-	// Get technical debt trend (last 30 days)
+	// Get the technical debt trend from the newest monthly health snapshots, oldest first
 	rows, err := r.db.Query(`
-		SELECT DATE(c.timestamp) as date, 
-		       SUM(ch.lines_added) as added,
-		       SUM(ch.lines_deleted) as deleted
-		FROM commits c
-		JOIN changes ch ON c.id = ch.commit_id
-		WHERE c.project_id = ? AND c.timestamp >= DATE_SUB(NOW(), INTERVAL 30 DAY)
-		GROUP BY DATE(c.timestamp)
-		ORDER BY date DESC
-		LIMIT 30
-	`, projectID)
+		SELECT month, score, hotspot_complexity, coupling, bus_factor_risk, stale_code, policy_version
+		FROM (
+			SELECT month, score, hotspot_complexity, coupling, bus_factor_risk, stale_code, policy_version
+			FROM project_health_snapshots
+			WHERE project_id = ?
+			ORDER BY month DESC
+			LIMIT ?
+		) recent
+		ORDER BY month
+	`, projectID, trendMonths)
 	if err != nil {
 		return nil, err
 	}
@@ -87,26 +114,26 @@ func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.Project
 	overview.TechnicalDebtTrend = []models.DebtTrendPoint{}
 	for rows.Next() {
 		var point models.DebtTrendPoint
-		var dateStr string
-		var added, deleted int
-		err := rows.Scan(&dateStr, &added, &deleted)
-		if err != nil {
-			continue
+		var month time.Time
+		if err := rows.Scan(database.Timestamp(&month), &point.Value, &point.HotspotComplexity, &point.Coupling, &point.BusFactorRisk, &point.StaleCode, &point.PolicyVersion); err != nil {
+			return nil, err
 		}
-		point.Date = dateStr
-		point.Value = float64(added) / float64(deleted+1) // Simple debt ratio
+		point.Date = month.Format("2006-01-02")
+		point.Month = month.Format("Jan 2006")
+		point.Score = int(math.Round(point.Value))
 		overview.TechnicalDebtTrend = append(overview.TechnicalDebtTrend, point)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	// Get risk snapshots (high-churn files)
+	// Get risk snapshots (high-churn files); their levels come from the project's risk policy
 	rows, err = r.db.Query(`
-		SELECT ch.file_path, COUNT(*) as changes, 
-		       SUM(ch.lines_added + ch.lines_deleted) as total_changes
-		FROM changes ch
-		JOIN commits c ON ch.commit_id = c.id
-		WHERE c.project_id = ?
-		GROUP BY ch.file_path
-		HAVING changes > 5
+		SELECT file_path, SUM(commits) as changes,
+		       SUM(lines_added + lines_deleted) as total_changes
+		FROM `+stats+` file_stats
+		WHERE project_id = ?
+		GROUP BY path_hash, file_path
 		ORDER BY total_changes DESC
 		LIMIT 10
 	`, projectID)
@@ -124,15 +151,6 @@ func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.Project
 			continue
 		}
 
-		// Calculate risk level based on change frequency
-		if snapshot.Changes > 20 {
-			snapshot.Level = "High"
-		} else if snapshot.Changes > 10 {
-			snapshot.Level = "Medium"
-		} else {
-			snapshot.Level = "Low"
-		}
-
 		overview.RiskSnapshots = append(overview.RiskSnapshots, snapshot)
 	}
 
@@ -146,87 +164,189 @@ func (r *AnalyticsRepository) GetProjectOverview(projectID int) (*models.Project
 	return overview, nil
 }
 
-// GetFileOwnership returns file ownership data for knowledge risk analysis
-func (r *AnalyticsRepository) GetFileOwnership(projectID int) ([]models.FileOwnership, error) {
-	rows, err := r.db.Query(`
-		SELECT 
-			ch.file_path,
-			c.author,
-			COUNT(*) as commits,
-			SUM(ch.lines_added + ch.lines_deleted) as total_changes,
-			MAX(c.timestamp) as last_modified
-		FROM changes ch
-		JOIN commits c ON ch.commit_id = c.id
-		WHERE c.project_id = ?
-		GROUP BY ch.file_path, c.author
-		ORDER BY ch.file_path, total_changes DESC
-	`, projectID)
+// GetFileOwnership returns file ownership data for knowledge risk analysis, without risk levels.
+// metric selects whether authors are weighted by commits, lines changed or surviving (blamed) lines.
+func (r *AnalyticsRepository) GetFileOwnership(projectID int, metric values.OwnershipMetric) ([]models.FileOwnership, error) {
+	ownershipMap, err := r.getFileContributions(projectID, metric, nil, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var fileOwnerships []models.FileOwnership
+	for filePath, contributions := range ownershipMap {
+		// Determine primary owner (highest percentage)
+		primaryOwner := contributions[0].Author
+		ownershipPercentage := contributions[0].Percentage
+
+		totalLines := 0
+		for _, contrib := range contributions {
+			totalLines += contrib.Lines
+		}
+
+		fileOwnerships = append(fileOwnerships, models.FileOwnership{
+			FilePath:            filePath,
+			PrimaryOwner:        primaryOwner,
+			OwnershipPercentage: ownershipPercentage,
+			TotalContributors:   len(contributions),
+			TotalLines:          totalLines,
+			Contributors:        contributions,
+		})
+	}
+
+	return fileOwnerships, nil
+}
+
+// getFileContributions collects per-file author contributions from the daily file statistics and,
+// for blame ownership, from the surviving lines at the analysed commit. Each file's
+// contributions carry their ownership percentage under the given metric and are sorted
+// by it in descending order. Files without any weight under the metric are omitted.
+func (r *AnalyticsRepository) getFileContributions(projectID int, metric values.OwnershipMetric, startDate, endDate *time.Time, path string) (map[string][]models.AuthorContribution, error) {
+	stats, err := sqlstore.FileStatsRelation(r.db, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			file_path,
+			author,
+			SUM(commits) as commits,
+			SUM(lines_added + lines_deleted) as total_changes,
+			SUM(lines_added) as lines_added,
+			MAX(last_commit_at) as last_modified
+		FROM ` + stats + ` file_stats
+		WHERE project_id = ?`
+	args := []interface{}{projectID}
+	query, args = appendOwnershipFilters(query, args, "day", "file_path", startDate, endDate, path)
+	query += `
+		GROUP BY path_hash, file_path, author`
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	ownershipMap := make(map[string][]models.AuthorContribution)
+	type fileAuthor struct{ file, author string }
+	contributions := make(map[fileAuthor]*models.AuthorContribution)
+	var order []fileAuthor
+
 	for rows.Next() {
-		var filePath, author, lastModified string
-		var commits, totalChanges int
+		var filePath, author string
+		var commits, totalChanges, linesAdded int
+		var lastModified time.Time
 
-		err := rows.Scan(&filePath, &author, &commits, &totalChanges, &lastModified)
-		if err != nil {
+		if err := rows.Scan(&filePath, &author, &commits, &totalChanges, &linesAdded, database.Timestamp(&lastModified)); err != nil {
 			continue
 		}
 
-		ownershipMap[filePath] = append(ownershipMap[filePath], models.AuthorContribution{
+		key := fileAuthor{filePath, author}
+		contributions[key] = &models.AuthorContribution{
 			Author:       author,
 			Commits:      commits,
 			Changes:      totalChanges,
-			LastModified: lastModified,
-		})
+			LinesAdded:   linesAdded,
+			LastModified: lastModified.Format(time.RFC3339Nano),
+		}
+		order = append(order, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	var fileOwnerships []models.FileOwnership
-	for filePath, contributions := range ownershipMap {
-		// Calculate total changes for the file
-		totalChanges := 0
-		for _, contrib := range contributions {
-			totalChanges += contrib.Changes
+	if metric == values.OwnershipByBlame {
+		blameQuery := `
+			SELECT file_path, author, SUM(line_count), MAX(committed_at)
+			FROM file_blame_segments
+			WHERE project_id = ?`
+		blameArgs := []interface{}{projectID}
+		blameQuery, blameArgs = appendOwnershipFilters(blameQuery, blameArgs, "committed_at", "file_path", startDate, endDate, path)
+		blameQuery += `
+			GROUP BY file_path, author`
+
+		blameRows, err := r.db.Query(blameQuery, blameArgs...)
+		if err != nil {
+			return nil, err
 		}
+		defer blameRows.Close()
 
-		// Skip files with no changes to avoid division by zero
-		if totalChanges == 0 {
-			continue
+		for blameRows.Next() {
+			var filePath, author string
+			var lines int
+			var lastWritten time.Time
+
+			if err := blameRows.Scan(&filePath, &author, &lines, database.Timestamp(&lastWritten)); err != nil {
+				continue
+			}
+
+			key := fileAuthor{filePath, author}
+			if contributions[key] == nil {
+				// Surviving lines whose commits fall outside the recorded history
+				contributions[key] = &models.AuthorContribution{
+					Author:       author,
+					LastModified: lastWritten.Format(time.RFC3339Nano),
+				}
+				order = append(order, key)
+			}
+			contributions[key].Lines = lines
 		}
+		if err := blameRows.Err(); err != nil {
+			return nil, err
+		}
+	}
 
-		// Calculate ownership percentages
-		for i := range contributions {
-			contributions[i].Percentage = float64(contributions[i].Changes) / float64(totalChanges) * 100
+	weight := func(contrib *models.AuthorContribution) int {
+		switch metric {
+		case values.OwnershipByCommits:
+			return contrib.Commits
+		case values.OwnershipByBlame:
+			return contrib.Lines
+		default:
+			return contrib.Changes
 		}
+	}
 
-		// Determine primary owner (highest percentage)
-		primaryOwner := contributions[0].Author
-		ownershipPercentage := contributions[0].Percentage
+	totals := make(map[string]int)
+	for _, key := range order {
+		totals[key.file] += weight(contributions[key])
+	}
 
-		// Calculate risk level
-		riskLevel := "Low"
-		if ownershipPercentage > 90 {
-			riskLevel = "Critical"
-		} else if ownershipPercentage > 70 {
-			riskLevel = "High"
-		} else if ownershipPercentage > 50 {
-			riskLevel = "Medium"
+	byFile := make(map[string][]models.AuthorContribution)
+	for _, key := range order {
+		contrib := contributions[key]
+		w := weight(contrib)
+		// Skip files with no weight to avoid division by zero, and authors with no share
+		if totals[key.file] == 0 || w == 0 {
+			continue
 		}
+		contrib.Percentage = float64(w) / float64(totals[key.file]) * 100
+		byFile[key.file] = append(byFile[key.file], *contrib)
+	}
 
-		fileOwnerships = append(fileOwnerships, models.FileOwnership{
-			FilePath:            filePath,
-			PrimaryOwner:        primaryOwner,
-			OwnershipPercentage: ownershipPercentage,
-			TotalContributors:   len(contributions),
-			RiskLevel:           riskLevel,
-			Contributors:        contributions,
+	for _, contribs := range byFile {
+		sort.SliceStable(contribs, func(i, j int) bool {
+			return contribs[i].Percentage > contribs[j].Percentage
 		})
 	}
 
-	return fileOwnerships, nil
+	return byFile, nil
+}
+
+// appendOwnershipFilters adds the optional date range and path prefix filters to an ownership query
+func appendOwnershipFilters(query string, args []interface{}, timeColumn, pathColumn string, startDate, endDate *time.Time, path string) (string, []interface{}) {
+	if startDate != nil {
+		query += " AND " + timeColumn + " >= ?"
+		args = append(args, startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query += " AND " + timeColumn + " <= ?"
+		args = append(args, endDate.Format("2006-01-02"))
+	}
+	if path != "" {
+		query += " AND " + pathColumn + " LIKE ?"
+		args = append(args, path+"%")
+	}
+	return query, args
 }
 
 // GetAuthorHotspots returns author contribution data for hotspot analysis
@@ -253,7 +373,7 @@ func (r *AnalyticsRepository) GetAuthorHotspots(projectID int) ([]models.AuthorH
 	var hotspots []models.AuthorHotspot
 	for rows.Next() {
 		var hotspot models.AuthorHotspot
-		var lastActivity string
+		var lastActivity time.Time
 
 		err := rows.Scan(
 			&hotspot.Author,
@@ -261,13 +381,13 @@ func (r *AnalyticsRepository) GetAuthorHotspots(projectID int) ([]models.AuthorH
 			&hotspot.TotalCommits,
 			&hotspot.LinesAdded,
 			&hotspot.LinesDeleted,
-			&lastActivity,
+			database.Timestamp(&lastActivity),
 		)
 		if err != nil {
 			continue
 		}
 
-		hotspot.LastActivity = lastActivity
+		hotspot.LastActivity = lastActivity.Format(time.RFC3339Nano)
 
 		// Calculate risk score based on activity concentration
 		hotspot.RiskScore = float64(hotspot.TotalCommits)*0.4 +
@@ -286,56 +406,68 @@ func (r *AnalyticsRepository) GetTemporalCoupling(projectID int, limit int, star
 	if limit <= 0 || limit > 200 {
 		limit = 100
 	}
+	return r.queryTemporalCoupling(projectID, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+}
+
+// GetCouplingGraph returns every file pair passing the coupling filters, without the
+// result cap of GetTemporalCoupling, for graph-wide measures such as sum of coupling
+func (r *AnalyticsRepository) GetCouplingGraph(projectID int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error) {
+	return r.queryTemporalCoupling(projectID, 0, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+}
+
+// queryTemporalCoupling runs the coupling pair query; a limit of 0 returns every pair. Projects
+// whose pre-computed coupling is complete read the monthly buckets of file_pair_coupling when
+// the dates select whole months; others fall back to pairing the changes of every commit. Both
+// leave out the pairs of commits touching more than MaxCouplingCommitFiles files.
+func (r *AnalyticsRepository) queryTemporalCoupling(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error) {
 	if minSharedCommits <= 0 {
 		minSharedCommits = 2 // default threshold
 	}
 
-	// Build optional date predicates
-	dateFilter := ""
-	args := []interface{}{projectID}
-	if startDate != "" {
-		dateFilter += " AND c.timestamp >= ?"
-		args = append(args, startDate+" 00:00:00")
-	}
-	if endDate != "" {
-		dateFilter += " AND c.timestamp <= ?"
-		args = append(args, endDate+" 23:59:59")
-	}
-
-	// Build file type filter
-	fileTypeFilter := ""
-	if fileTypes != "" {
-		fileTypesParts := strings.Split(fileTypes, ",")
-		if len(fileTypesParts) > 0 {
-			fileTypeConditions := make([]string, len(fileTypesParts))
-			for i, ft := range fileTypesParts {
-				fileTypeConditions[i] = "ch.file_path LIKE ?"
-				args = append(args, "%."+strings.TrimSpace(ft))
-			}
-			fileTypeFilter = " AND (" + strings.Join(fileTypeConditions, " OR ") + ")"
+	if wholeMonths(startDate, endDate) {
+		complete, err := sqlstore.NewCouplingRepository(r.db).IsComplete(projectID)
+		if err != nil {
+			return nil, err
+		}
+		if complete {
+			return r.queryStoredTemporalCoupling(projectID, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
 		}
 	}
 
+	filters, args := couplingChangeFilters(projectID, startDate, endDate, fileTypes)
+
 	query := `
 		WITH file_commits AS (
 			SELECT ch.file_path AS file_path, c.id AS commit_id, c.timestamp
 			FROM changes ch
 			JOIN commits c ON ch.commit_id = c.id
-			WHERE c.project_id = ?` + dateFilter + fileTypeFilter + `
+			WHERE c.project_id = ?` + filters + `
 		), file_commit_counts AS (
 			SELECT file_path, COUNT(DISTINCT commit_id) AS total_commits, MAX(timestamp) AS last_modified
 			FROM file_commits
 			GROUP BY file_path
+		), large_commits AS (
+			SELECT ch.commit_id
+			FROM changes ch
+			JOIN commits c ON ch.commit_id = c.id
+			WHERE c.project_id = ?
+			GROUP BY ch.commit_id
+			HAVING COUNT(DISTINCT ch.file_path) > ?
+		), pairable_commits AS (
+			SELECT f.file_path, f.commit_id, f.timestamp
+			FROM file_commits f
+			LEFT JOIN large_commits l ON l.commit_id = f.commit_id
+			WHERE l.commit_id IS NULL
 		), pair_commits AS (
 			SELECT 
-				LEAST(a.file_path, b.file_path) AS file_a,
-				GREATEST(a.file_path, b.file_path) AS file_b,
+				a.file_path AS file_a,
+				b.file_path AS file_b,
 				COUNT(DISTINCT a.commit_id) AS shared_commits,
-				MAX(GREATEST(a.timestamp, b.timestamp)) AS last_modified
-			FROM file_commits a
-			JOIN file_commits b ON a.commit_id = b.commit_id AND a.file_path < b.file_path
-			GROUP BY file_a, file_b
-			HAVING shared_commits >= ?
+				MAX(a.timestamp) AS last_modified
+			FROM pairable_commits a
+			JOIN pairable_commits b ON a.commit_id = b.commit_id AND a.file_path < b.file_path
+			GROUP BY a.file_path, b.file_path
+			HAVING COUNT(DISTINCT a.commit_id) >= ?
 		)
 		SELECT 
 			p.file_a,
@@ -347,13 +479,118 @@ func (r *AnalyticsRepository) GetTemporalCoupling(projectID int, limit int, star
 		FROM pair_commits p
 		JOIN file_commit_counts ca ON ca.file_path = p.file_a
 		JOIN file_commit_counts cb ON cb.file_path = p.file_b
-		WHERE (p.shared_commits / LEAST(ca.total_commits, cb.total_commits)) >= ?
-		ORDER BY (p.shared_commits / LEAST(ca.total_commits, cb.total_commits)) DESC, p.shared_commits DESC
-		LIMIT ?
+		WHERE ` + couplingScoreExpr + ` >= ?
+		ORDER BY ` + couplingScoreExpr + ` DESC, p.shared_commits DESC, p.file_a, p.file_b
 	`
 
+	args = append(args, projectID, repositories.MaxCouplingCommitFiles)
 	// Append minSharedCommits, minCouplingScore, and limit arguments
-	args = append(args, minSharedCommits, minCouplingScore, limit)
+	args = append(args, minSharedCommits, minCouplingScore)
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return r.scanTemporalCoupling(query, args)
+}
+
+// wholeMonths reports whether a date range, either end of which may be open, selects whole
+// months, which the monthly buckets of the pre-computed coupling can answer
+func wholeMonths(startDate, endDate string) bool {
+	if startDate != "" {
+		start, err := time.Parse("2006-01-02", startDate)
+		if err != nil || start.Day() != 1 {
+			return false
+		}
+	}
+	if endDate != "" {
+		end, err := time.Parse("2006-01-02", endDate)
+		if err != nil || end.AddDate(0, 0, 1).Day() != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// queryStoredTemporalCoupling reads coupling pairs from the pre-computed monthly buckets.
+// Date filters select whole months: the months containing startDate and endDate are included.
+func (r *AnalyticsRepository) queryStoredTemporalCoupling(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string) ([]models.TemporalCoupling, error) {
+	monthFilter := ""
+	var monthArgs []interface{}
+	for _, bound := range []struct {
+		date string
+		op   string
+	}{{startDate, ">="}, {endDate, "<="}} {
+		if bound.date == "" {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", bound.date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", bound.date, err)
+		}
+		monthFilter += " AND bucket_month " + bound.op + " ?"
+		monthArgs = append(monthArgs, time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+	}
+
+	// Both files of a pair must match the file type filter, as changes are filtered before pairing
+	fileTypeFilter := ""
+	var fileTypeArgs []interface{}
+	if fileTypes != "" {
+		for _, column := range []string{"file_a", "file_b"} {
+			conditions := make([]string, 0)
+			for _, ft := range strings.Split(fileTypes, ",") {
+				conditions = append(conditions, column+" LIKE ?")
+				fileTypeArgs = append(fileTypeArgs, "%."+strings.TrimSpace(ft))
+			}
+			fileTypeFilter += " AND (" + strings.Join(conditions, " OR ") + ")"
+		}
+	}
+
+	query := `
+		WITH pair_commits AS (
+			SELECT file_a, file_b, SUM(shared_commits) AS shared_commits, MAX(last_commit_at) AS last_modified
+			FROM file_pair_coupling
+			WHERE project_id = ? AND file_a <> file_b` + monthFilter + fileTypeFilter + `
+			GROUP BY pair_hash, file_a, file_b
+			HAVING SUM(shared_commits) >= ?
+		), file_commit_counts AS (
+			SELECT file_a AS file_path, SUM(shared_commits) AS total_commits
+			FROM file_pair_coupling
+			WHERE project_id = ? AND file_a = file_b` + monthFilter + `
+			GROUP BY pair_hash, file_a
+		)
+		SELECT
+			p.file_a,
+			p.file_b,
+			p.shared_commits,
+			ca.total_commits AS total_commits_a,
+			cb.total_commits AS total_commits_b,
+			p.last_modified
+		FROM pair_commits p
+		JOIN file_commit_counts ca ON ca.file_path = p.file_a
+		JOIN file_commit_counts cb ON cb.file_path = p.file_b
+		WHERE ` + couplingScoreExpr + ` >= ?
+		ORDER BY ` + couplingScoreExpr + ` DESC, p.shared_commits DESC, p.file_a, p.file_b
+	`
+
+	args := []interface{}{projectID}
+	args = append(args, monthArgs...)
+	args = append(args, fileTypeArgs...)
+	args = append(args, minSharedCommits, projectID)
+	args = append(args, monthArgs...)
+	args = append(args, minCouplingScore)
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return r.scanTemporalCoupling(query, args)
+}
+
+// couplingScoreExpr computes a pair's coupling score in the coupling pair queries: shared commits
+// over the smaller of the two files' commits, divided as decimals on every backend
+const couplingScoreExpr = `(p.shared_commits * 1.0 / CASE WHEN ca.total_commits < cb.total_commits THEN ca.total_commits ELSE cb.total_commits END)`
+
+// scanTemporalCoupling runs a coupling pair query and computes each pair's coupling score
+func (r *AnalyticsRepository) scanTemporalCoupling(query string, args []interface{}) ([]models.TemporalCoupling, error) {
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -363,12 +600,12 @@ func (r *AnalyticsRepository) GetTemporalCoupling(projectID int, limit int, star
 	results := make([]models.TemporalCoupling, 0)
 	for rows.Next() {
 		var tc models.TemporalCoupling
-		var lastModified string
-		err := rows.Scan(&tc.FileA, &tc.FileB, &tc.SharedCommits, &tc.TotalCommitsA, &tc.TotalCommitsB, &lastModified)
+		var lastModified time.Time
+		err := rows.Scan(&tc.FileA, &tc.FileB, &tc.SharedCommits, &tc.TotalCommitsA, &tc.TotalCommitsB, database.Timestamp(&lastModified))
 		if err != nil {
 			continue
 		}
-		tc.LastModified = lastModified
+		tc.LastModified = lastModified.Format(time.RFC3339Nano)
 		// CouplingScore = shared / min(totalA,totalB)
 		minTotal := tc.TotalCommitsA
 		if tc.TotalCommitsB < minTotal {
@@ -383,131 +620,988 @@ func (r *AnalyticsRepository) GetTemporalCoupling(projectID int, limit int, star
 	return results, nil
 }
 
-// GetProjectFileTypes returns available file extensions for a project
-func (r *AnalyticsRepository) GetProjectFileTypes(projectID int) ([]string, error) {
-	query := `
-		SELECT DISTINCT 
-			SUBSTRING_INDEX(ch.file_path, '.', -1) AS extension
-		FROM changes ch
-		JOIN commits c ON ch.commit_id = c.id
-		WHERE c.project_id = ? 
-			AND ch.file_path LIKE '%.%'
-			AND LENGTH(SUBSTRING_INDEX(ch.file_path, '.', -1)) <= 10
-			AND LENGTH(SUBSTRING_INDEX(ch.file_path, '.', -1)) > 0
-			AND SUBSTRING_INDEX(ch.file_path, '.', -1) NOT LIKE '%/%'
-		ORDER BY extension
-	`
-
-	rows, err := r.db.Query(query, projectID)
-	if err != nil {
-		return nil, err
+// couplingChangeFilters builds the date range and file type predicates shared by the coupling
+// queries over changes (ch) joined with commits (c); the project ID is the first argument
+func couplingChangeFilters(projectID int, startDate, endDate, fileTypes string) (string, []interface{}) {
+	filters := ""
+	args := []interface{}{projectID}
+	if startDate != "" {
+		filters += " AND c.timestamp >= ?"
+		args = append(args, startDate+" 00:00:00")
+	}
+	if endDate != "" {
+		filters += " AND c.timestamp <= ?"
+		args = append(args, endDate+" 23:59:59")
 	}
-	defer rows.Close()
 
-	var fileTypes []string
-	for rows.Next() {
-		var ext string
-		err := rows.Scan(&ext)
-		if err != nil {
-			continue
-		}
-		// Add extension directly to list (already cleaned by SQL query)
-		if len(ext) > 0 && len(ext) <= 10 {
-			fileTypes = append(fileTypes, ext)
+	if fileTypes != "" {
+		fileTypesParts := strings.Split(fileTypes, ",")
+		fileTypeConditions := make([]string, len(fileTypesParts))
+		for i, ft := range fileTypesParts {
+			fileTypeConditions[i] = "ch.file_path LIKE ?"
+			args = append(args, "%."+strings.TrimSpace(ft))
 		}
+		filters += " AND (" + strings.Join(fileTypeConditions, " OR ") + ")"
 	}
-
-	return fileTypes, nil
+	return filters, args
 }
 
-// GetBusFactorAnalysis calculates bus factor data for all files in a project
-func (r *AnalyticsRepository) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string) ([]models.BusFactorData, error) {
-	// Build the SQL query with optional filters
-	query := `
-		SELECT 
-			c.file_path,
-			COUNT(*) as total_commits,
-			co.author,
-			COUNT(*) as author_commits,
-			(COUNT(*) * 100.0 / SUM(COUNT(*)) OVER (PARTITION BY c.file_path)) as ownership_percent,
-			MAX(co.timestamp) as last_modified
-		FROM changes c
-		JOIN commits co ON c.commit_id = co.id
-		WHERE co.project_id = ?`
-
-	args := []interface{}{projectID}
-
-	// Add optional date filters
-	if startDate != nil {
-		query += " AND co.timestamp >= ?"
-		args = append(args, startDate.Format("2006-01-02"))
-	}
-	if endDate != nil {
-		query += " AND co.timestamp <= ?"
-		args = append(args, endDate.Format("2006-01-02"))
+// GetAggregatedTemporalCoupling measures temporal coupling between groups of files such as
+// directories or architectural layers, over logical change sets of one or more commits.
+// group maps a file path to its group and reports false for files to leave out; changeSets
+// selects how commits are joined (the zero value keeps one change set per commit). A change
+// set counts once for every group it touches, and two groups share it when files of both
+// change in it; the score is shared change sets over the smaller group's change sets, as
+// for file pairs. As for file pairs, commits touching more than MaxCouplingCommitFiles files
+// count for each group but do not couple them. A limit of 0 returns every pair.
+func (r *AnalyticsRepository) GetAggregatedTemporalCoupling(projectID int, limit int, startDate, endDate string, minSharedCommits int, minCouplingScore float64, fileTypes string, group func(filePath string) (string, bool), changeSets services.ChangeSetGrouping) ([]models.TemporalCoupling, error) {
+	if minSharedCommits <= 0 {
+		minSharedCommits = 2 // default threshold
 	}
 
-	// Add optional path filter
-	if path != "" {
-		query += " AND c.file_path LIKE ?"
-		args = append(args, path+"%")
+	setOf, err := r.getChangeSets(projectID, startDate, endDate, changeSets)
+	if err != nil {
+		return nil, err
 	}
-
-	// Repository filter (not used in current schema but kept for future)
-	if repository != "" {
-		// Could filter by project name or add repository field later
+	large, err := r.getLargeCommits(projectID)
+	if err != nil {
+		return nil, err
 	}
 
-	query += `
-		GROUP BY c.file_path, co.author
-		ORDER BY c.file_path, ownership_percent DESC`
-
-	rows, err := r.db.Query(query, args...)
+	filters, args := couplingChangeFilters(projectID, startDate, endDate, fileTypes)
+	rows, err := r.db.Query(`
+		SELECT c.id, c.timestamp, ch.file_path
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		WHERE c.project_id = ?`+filters, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// Group results by file
-	fileData := make(map[string]*models.BusFactorData)
-
+	// pairable holds the groups changed by the set's commits within the file cap
+	type changeSet struct {
+		groups       map[string]bool
+		pairable     map[string]bool
+		lastModified time.Time
+	}
+	sets := make(map[int]*changeSet)
+	groupOf := make(map[string]string)
 	for rows.Next() {
-		var filePath, author string
-		var totalCommits, authorCommits int
-		var ownershipPercent float64
-		var lastModified time.Time
-
-		err := rows.Scan(&filePath, &totalCommits, &author, &authorCommits, &ownershipPercent, &lastModified)
-		if err != nil {
-			continue
+		var commitID int
+		var timestamp time.Time
+		var filePath string
+		if err := rows.Scan(&commitID, &timestamp, &filePath); err != nil {
+			return nil, err
 		}
 
-		// Initialize file data if not exists
-		if fileData[filePath] == nil {
-			fileData[filePath] = &models.BusFactorData{
-				FilePath:              filePath,
-				TotalCommits:          totalCommits,
-				OwnershipDistribution: make([]models.AuthorOwnership, 0),
-				LastModified:          &lastModified,
+		name, seen := groupOf[filePath]
+		if !seen {
+			if g, ok := group(filePath); ok {
+				name = g
 			}
+			groupOf[filePath] = name
+		}
+		if name == "" {
+			continue
 		}
 
-		// Add author ownership
-		fileData[filePath].OwnershipDistribution = append(
-			fileData[filePath].OwnershipDistribution,
-			models.AuthorOwnership{
-				Author:           author,
-				Commits:          authorCommits,
-				OwnershipPercent: ownershipPercent,
-			},
-		)
+		setID, ok := setOf[commitID]
+		if !ok {
+			setID = commitID
+		}
+		set := sets[setID]
+		if set == nil {
+			set = &changeSet{groups: make(map[string]bool), pairable: make(map[string]bool)}
+			sets[setID] = set
+		}
+		set.groups[name] = true
+		if !large[commitID] {
+			set.pairable[name] = true
+		}
+		if timestamp.After(set.lastModified) {
+			set.lastModified = timestamp
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	// Convert map to slice
-	result := make([]models.BusFactorData, 0, len(fileData))
-	for _, data := range fileData {
-		result = append(result, *data)
+	type groupPair struct{ a, b string }
+	type groupStats struct {
+		commits      int
+		lastModified time.Time
+	}
+	groups := make(map[string]*groupStats)
+	pairs := make(map[groupPair]*groupStats)
+	count := func(stats map[string]*groupStats, key string, at time.Time) {
+		if stats[key] == nil {
+			stats[key] = &groupStats{}
+		}
+		stats[key].commits++
+		if at.After(stats[key].lastModified) {
+			stats[key].lastModified = at
+		}
+	}
+	for _, set := range sets {
+		for name := range set.groups {
+			count(groups, name, set.lastModified)
+		}
+		names := make([]string, 0, len(set.pairable))
+		for name := range set.pairable {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, a := range names {
+			for _, b := range names[i+1:] {
+				key := groupPair{a, b}
+				if pairs[key] == nil {
+					pairs[key] = &groupStats{}
+				}
+				pairs[key].commits++
+				if set.lastModified.After(pairs[key].lastModified) {
+					pairs[key].lastModified = set.lastModified
+				}
+			}
+		}
 	}
 
-	return result, nil
+	results := make([]models.TemporalCoupling, 0)
+	for key, pair := range pairs {
+		if pair.commits < minSharedCommits {
+			continue
+		}
+		tc := models.TemporalCoupling{
+			FileA:         key.a,
+			FileB:         key.b,
+			SharedCommits: pair.commits,
+			TotalCommitsA: groups[key.a].commits,
+			TotalCommitsB: groups[key.b].commits,
+			LastModified:  pair.lastModified.Format(time.RFC3339Nano),
+		}
+		minTotal := tc.TotalCommitsA
+		if tc.TotalCommitsB < minTotal {
+			minTotal = tc.TotalCommitsB
+		}
+		if minTotal > 0 {
+			tc.CouplingScore = float64(tc.SharedCommits) / float64(minTotal)
+		}
+		if tc.CouplingScore < minCouplingScore {
+			continue
+		}
+		results = append(results, tc)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].CouplingScore != results[j].CouplingScore {
+			return results[i].CouplingScore > results[j].CouplingScore
+		}
+		if results[i].SharedCommits != results[j].SharedCommits {
+			return results[i].SharedCommits > results[j].SharedCommits
+		}
+		if results[i].FileA != results[j].FileA {
+			return results[i].FileA < results[j].FileA
+		}
+		return results[i].FileB < results[j].FileB
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// getLargeCommits returns the IDs of the project's commits touching more than
+// MaxCouplingCommitFiles files, whose files are not coupled
+func (r *AnalyticsRepository) getLargeCommits(projectID int) (map[int]bool, error) {
+	rows, err := r.db.Query(`
+		SELECT ch.commit_id
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		WHERE c.project_id = ?
+		GROUP BY ch.commit_id
+		HAVING COUNT(DISTINCT ch.file_path) > ?`, projectID, repositories.MaxCouplingCommitFiles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	large := make(map[int]bool)
+	for rows.Next() {
+		var commitID int
+		if err := rows.Scan(&commitID); err != nil {
+			return nil, err
+		}
+		large[commitID] = true
+	}
+	return large, rows.Err()
+}
+
+// getChangeSets maps the project's commits in the date range to their change set. Commits
+// are only loaded when the grouping joins commits; an empty map keeps one set per commit.
+func (r *AnalyticsRepository) getChangeSets(projectID int, startDate, endDate string, grouping services.ChangeSetGrouping) (map[int]int, error) {
+	if grouping.Mode == "" || grouping.Mode == values.ChangeSetByCommit {
+		return map[int]int{}, nil
+	}
+
+	query := `SELECT c.id, c.author, c.timestamp, COALESCE(c.message, '') FROM commits c WHERE c.project_id = ?`
+	filters, args := couplingChangeFilters(projectID, startDate, endDate, "")
+	rows, err := r.db.Query(query+filters, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []services.ChangeSetCommit
+	for rows.Next() {
+		var commit services.ChangeSetCommit
+		if err := rows.Scan(&commit.ID, &commit.Author, &commit.Timestamp, &commit.Message); err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return services.GroupChangeSets(commits, grouping), nil
+}
+
+// GetProjectFileTypes returns the file extensions seen in a project's history together with
+// the number of files and lines of code each extension has in the tree at the analysed commit
+func (r *AnalyticsRepository) GetProjectFileTypes(projectID int) ([]models.FileTypeStat, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT ch.file_path
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		WHERE c.project_id = ? AND ch.file_path LIKE '%.%'
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// The extension is what follows the last dot, unless that is in a directory name
+	seen := make(map[string]bool)
+	var extensions []string
+	for rows.Next() {
+		var filePath string
+		if err := rows.Scan(&filePath); err != nil {
+			continue
+		}
+		ext := filePath[strings.LastIndex(filePath, ".")+1:]
+		if len(ext) > 0 && len(ext) <= 10 && !strings.Contains(ext, "/") && !seen[ext] {
+			seen[ext] = true
+			extensions = append(extensions, ext)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(extensions)
+
+	// Aggregate current file counts and LOC per extension
+	metricRows, err := r.db.Query(`
+		SELECT file_path, code_lines FROM file_metrics WHERE project_id = ? AND is_binary = FALSE
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer metricRows.Close()
+
+	statsByExt := make(map[string]*models.FileTypeStat)
+	for metricRows.Next() {
+		var filePath string
+		var codeLines int
+		if err := metricRows.Scan(&filePath, &codeLines); err != nil {
+			continue
+		}
+		ext := strings.TrimPrefix(filepath.Ext(filePath), ".")
+		if statsByExt[ext] == nil {
+			statsByExt[ext] = &models.FileTypeStat{Extension: ext}
+		}
+		statsByExt[ext].Files++
+		statsByExt[ext].LinesOfCode += codeLines
+	}
+
+	fileTypes := make([]models.FileTypeStat, 0, len(extensions))
+	for _, ext := range extensions {
+		stat := models.FileTypeStat{Extension: ext}
+		if measured, ok := statsByExt[ext]; ok {
+			stat.Files = measured.Files
+			stat.LinesOfCode = measured.LinesOfCode
+		}
+		fileTypes = append(fileTypes, stat)
+	}
+
+	return fileTypes, nil
+}
+
+// GetLanguageBreakdown returns code, comment and blank line totals per language
+// for the tree at the analysed commit
+func (r *AnalyticsRepository) GetLanguageBreakdown(projectID int) ([]models.LanguageBreakdown, error) {
+	rows, err := r.db.Query(`
+		SELECT language, COUNT(*), SUM(code_lines), SUM(comment_lines), SUM(blank_lines)
+		FROM file_metrics
+		WHERE project_id = ? AND is_binary = FALSE
+		GROUP BY language
+		ORDER BY SUM(code_lines) DESC
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := make([]models.LanguageBreakdown, 0)
+	totalCode := 0
+	for rows.Next() {
+		var lb models.LanguageBreakdown
+		err := rows.Scan(&lb.Language, &lb.Files, &lb.CodeLines, &lb.CommentLines, &lb.BlankLines)
+		if err != nil {
+			continue
+		}
+		totalCode += lb.CodeLines
+		breakdown = append(breakdown, lb)
+	}
+
+	if totalCode > 0 {
+		for i := range breakdown {
+			breakdown[i].Percentage = float64(breakdown[i].CodeLines) / float64(totalCode) * 100
+		}
+	}
+
+	return breakdown, rows.Err()
+}
+
+// GetBusFactorAnalysis calculates bus factor data for all files in a project.
+// metric selects whether authors are weighted by commits, lines changed or surviving (blamed) lines.
+func (r *AnalyticsRepository) GetBusFactorAnalysis(projectID int, startDate, endDate *time.Time, repository, path string, metric values.OwnershipMetric) ([]models.BusFactorData, error) {
+	// Repository filter (not used in current schema but kept for future)
+	if repository != "" {
+		// Could filter by project name or add repository field later
+	}
+
+	contributionsByFile, err := r.getFileContributions(projectID, metric, startDate, endDate, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.BusFactorData, 0, len(contributionsByFile))
+	for filePath, contributions := range contributionsByFile {
+		data := models.BusFactorData{
+			FilePath:              filePath,
+			OwnershipDistribution: make([]models.AuthorOwnership, 0, len(contributions)),
+		}
+
+		for _, contrib := range contributions {
+			data.TotalCommits += contrib.Commits
+			if lastModified, err := time.Parse(time.RFC3339Nano, contrib.LastModified); err == nil {
+				if data.LastModified == nil || lastModified.After(*data.LastModified) {
+					data.LastModified = &lastModified
+				}
+			}
+
+			data.OwnershipDistribution = append(data.OwnershipDistribution, models.AuthorOwnership{
+				Author:           contrib.Author,
+				Commits:          contrib.Commits,
+				LinesChanged:     contrib.Changes,
+				LinesAdded:       contrib.LinesAdded,
+				Lines:            contrib.Lines,
+				OwnershipPercent: contrib.Percentage,
+			})
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// GetFileAges returns the files of the analysed tree with their total lines and the
+// timestamp of the last commit that touched them. Projects analysed before file
+// measurements existed fall back to every file seen in the change history.
+func (r *AnalyticsRepository) GetFileAges(projectID int) ([]models.FileAge, error) {
+	rows, err := r.db.Query(`
+		SELECT fm.file_path, fm.code_lines + fm.comment_lines + fm.blank_lines, lm.last_modified
+		FROM file_metrics fm
+		LEFT JOIN (
+			SELECT ch.file_path, MAX(c.timestamp) AS last_modified
+			FROM changes ch
+			JOIN commits c ON ch.commit_id = c.id
+			WHERE c.project_id = ?
+			GROUP BY ch.file_path
+		) lm ON lm.file_path = fm.file_path
+		WHERE fm.project_id = ?
+		ORDER BY fm.file_path
+	`, projectID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := scanFileAges(rows)
+	if err != nil || len(files) > 0 {
+		return files, err
+	}
+
+	rows, err = r.db.Query(`
+		SELECT ch.file_path, 0, MAX(c.timestamp)
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		WHERE c.project_id = ?
+		GROUP BY ch.file_path
+		ORDER BY ch.file_path
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return scanFileAges(rows)
+}
+
+// scanFileAges reads (file_path, lines, last_modified) rows and closes them
+func scanFileAges(rows *sql.Rows) ([]models.FileAge, error) {
+	defer rows.Close()
+
+	files := make([]models.FileAge, 0)
+	for rows.Next() {
+		var fa models.FileAge
+		var lastModified time.Time
+		if err := rows.Scan(&fa.FilePath, &fa.Lines, database.Timestamp(&lastModified)); err != nil {
+			return nil, err
+		}
+		if !lastModified.IsZero() {
+			fa.LastModified = &lastModified
+		}
+		files = append(files, fa)
+	}
+
+	return files, rows.Err()
+}
+
+// GetLineAges returns the blame segments of the analysed tree
+func (r *AnalyticsRepository) GetLineAges(projectID int) ([]models.LineAgeSegment, error) {
+	rows, err := r.db.Query(`
+		SELECT file_path, author, committed_at, line_count
+		FROM file_blame_segments
+		WHERE project_id = ?
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	segments := make([]models.LineAgeSegment, 0)
+	for rows.Next() {
+		var s models.LineAgeSegment
+		if err := rows.Scan(&s.FilePath, &s.Author, database.Timestamp(&s.CommittedAt), &s.Lines); err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+
+	return segments, rows.Err()
+}
+
+// GetAuthorActivity returns every author's commit count and first and last commit times
+func (r *AnalyticsRepository) GetAuthorActivity(projectID int) ([]models.AuthorActivity, error) {
+	rows, err := r.db.Query(`
+		SELECT author, COUNT(*), MIN(timestamp), MAX(timestamp)
+		FROM commits
+		WHERE project_id = ?
+		GROUP BY author
+		ORDER BY MAX(timestamp) DESC
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := make([]models.AuthorActivity, 0)
+	for rows.Next() {
+		var a models.AuthorActivity
+		var firstCommit, lastCommit time.Time
+		if err := rows.Scan(&a.Author, &a.Commits, database.Timestamp(&firstCommit), database.Timestamp(&lastCommit)); err != nil {
+			return nil, err
+		}
+		a.FirstCommit = &firstCommit
+		a.LastCommit = &lastCommit
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
+}
+
+// GetDefectHotspots returns the files touched by bug-fix commits with the number of those
+// commits, their authors and the latest one, together with the file's total commits from the
+// daily statistics and its code lines at the analysed commit. When the analysed tree has been
+// measured only files still present in it are returned.
+func (r *AnalyticsRepository) GetDefectHotspots(projectID int, startDate, endDate, path string) ([]models.DefectHotspot, error) {
+	filters, args := couplingChangeFilters(projectID, startDate, endDate, "")
+	if path != "" {
+		filters += " AND ch.file_path LIKE ?"
+		args = append(args, path+"%")
+	}
+
+	rows, err := r.db.Query(`
+		SELECT ch.file_path, COUNT(DISTINCT c.id), COUNT(DISTINCT c.author), MAX(c.timestamp)
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		WHERE c.project_id = ? AND c.category = 'bugfix'`+filters+`
+		GROUP BY ch.file_path
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hotspots := make(map[string]*models.DefectHotspot)
+	for rows.Next() {
+		var h models.DefectHotspot
+		var lastBugfix time.Time
+		if err := rows.Scan(&h.FilePath, &h.BugfixCommits, &h.Authors, database.Timestamp(&lastBugfix)); err != nil {
+			return nil, err
+		}
+		h.LastBugfix = lastBugfix.Format(time.RFC3339)
+		hotspots[h.FilePath] = &h
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(hotspots) == 0 {
+		return []models.DefectHotspot{}, nil
+	}
+
+	stats, err := sqlstore.FileStatsRelation(r.db, projectID)
+	if err != nil {
+		return nil, err
+	}
+	totalsQuery := `
+		SELECT file_path, SUM(commits)
+		FROM ` + stats + ` file_stats
+		WHERE project_id = ?`
+	totalsArgs := []interface{}{projectID}
+	if startDate != "" {
+		totalsQuery += " AND day >= ?"
+		totalsArgs = append(totalsArgs, startDate)
+	}
+	if endDate != "" {
+		totalsQuery += " AND day <= ?"
+		totalsArgs = append(totalsArgs, endDate)
+	}
+	if path != "" {
+		totalsQuery += " AND file_path LIKE ?"
+		totalsArgs = append(totalsArgs, path+"%")
+	}
+	totalsQuery += `
+		GROUP BY path_hash, file_path`
+
+	totalRows, err := r.db.Query(totalsQuery, totalsArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer totalRows.Close()
+	for totalRows.Next() {
+		var filePath string
+		var commits int
+		if err := totalRows.Scan(&filePath, &commits); err != nil {
+			return nil, err
+		}
+		if h := hotspots[filePath]; h != nil {
+			h.TotalCommits = commits
+		}
+	}
+	if err := totalRows.Err(); err != nil {
+		return nil, err
+	}
+
+	lineRows, err := r.db.Query("SELECT file_path, code_lines FROM file_metrics WHERE project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer lineRows.Close()
+	codeLines := make(map[string]int)
+	for lineRows.Next() {
+		var filePath string
+		var lines int
+		if err := lineRows.Scan(&filePath, &lines); err != nil {
+			return nil, err
+		}
+		codeLines[filePath] = lines
+	}
+	if err := lineRows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.DefectHotspot, 0, len(hotspots))
+	for filePath, h := range hotspots {
+		lines, current := codeLines[filePath]
+		if len(codeLines) > 0 && !current {
+			continue
+		}
+		h.CodeLines = lines
+		if h.TotalCommits < h.BugfixCommits {
+			// Commits ingested before the daily statistics existed
+			h.TotalCommits = h.BugfixCommits
+		}
+		result = append(result, *h)
+	}
+
+	return result, nil
+}
+
+// GetCommitCategoryCounts returns the number of commits per category, with unclassified
+// commits under the empty category
+func (r *AnalyticsRepository) GetCommitCategoryCounts(projectID int, startDate, endDate string) (map[string]int, error) {
+	query := `
+		SELECT COALESCE(category, ''), COUNT(*)
+		FROM commits c
+		WHERE c.project_id = ?`
+	filters, args := couplingChangeFilters(projectID, startDate, endDate, "")
+	query += filters + `
+		GROUP BY COALESCE(category, '')`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var commits int
+		if err := rows.Scan(&category, &commits); err != nil {
+			return nil, err
+		}
+		counts[category] = commits
+	}
+
+	return counts, rows.Err()
+}
+
+// GetFileAuthorship returns per-file, per-author commit counts and file creators. When the
+// analysed tree has been measured only files still present in it are returned.
+func (r *AnalyticsRepository) GetFileAuthorship(projectID int, path string) ([]models.FileAuthorship, error) {
+	currentFiles, err := r.getCurrentFiles(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ch.file_path, c.author, COUNT(*), MIN(c.timestamp)
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		WHERE c.project_id = ?`
+	args := []interface{}{projectID}
+	query, args = appendOwnershipFilters(query, args, "c.timestamp", "ch.file_path", nil, nil, path)
+	query += `
+		GROUP BY ch.file_path, c.author`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authorship := make([]models.FileAuthorship, 0)
+	firstCommit := make(map[string]time.Time)
+	creator := make(map[string]string)
+
+	for rows.Next() {
+		var fa models.FileAuthorship
+		var first time.Time
+		if err := rows.Scan(&fa.FilePath, &fa.Author, &fa.Commits, database.Timestamp(&first)); err != nil {
+			return nil, err
+		}
+		if len(currentFiles) > 0 && !currentFiles[fa.FilePath] {
+			continue
+		}
+
+		if earliest, seen := firstCommit[fa.FilePath]; !seen || first.Before(earliest) {
+			firstCommit[fa.FilePath] = first
+			creator[fa.FilePath] = fa.Author
+		}
+		authorship = append(authorship, fa)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range authorship {
+		authorship[i].Creator = creator[authorship[i].FilePath] == authorship[i].Author
+	}
+
+	return authorship, nil
+}
+
+// getCurrentFiles returns the set of files in the measured tree at the analysed commit
+func (r *AnalyticsRepository) getCurrentFiles(projectID int) (map[string]bool, error) {
+	rows, err := r.db.Query("SELECT file_path FROM file_metrics WHERE project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string]bool)
+	for rows.Next() {
+		var filePath string
+		if err := rows.Scan(&filePath); err != nil {
+			return nil, err
+		}
+		files[filePath] = true
+	}
+
+	return files, rows.Err()
+}
+
+// issueSummaryQuery aggregates the commits referencing each issue with the issue's imported
+// metadata; callers append their filters and the GROUP BY clause
+const issueSummaryQuery = `
+	SELECT
+		ci.issue_key,
+		COALESCE(i.title, ''),
+		COALESCE(i.issue_type, ''),
+		COALESCE(i.priority, ''),
+		COALESCE(i.status, ''),
+		COUNT(DISTINCT c.id),
+		COUNT(DISTINCT ch.file_path),
+		COUNT(DISTINCT c.author),
+		MIN(c.timestamp),
+		MAX(c.timestamp)
+	FROM commit_issues ci
+	JOIN commits c ON ci.commit_id = c.id
+	LEFT JOIN changes ch ON ch.commit_id = c.id
+	LEFT JOIN issues i ON i.project_id = ci.project_id AND i.issue_key = ci.issue_key
+	WHERE ci.project_id = ?`
+
+const issueSummaryGroupBy = `
+	GROUP BY ci.issue_key, i.title, i.issue_type, i.priority, i.status`
+
+// GetIssues returns the issues referenced by a project's commits, optionally of one type
+func (r *AnalyticsRepository) GetIssues(projectID int, issueType string) ([]models.IssueSummary, error) {
+	query := issueSummaryQuery
+	args := []interface{}{projectID}
+	if issueType != "" {
+		query += " AND LOWER(i.issue_type) = LOWER(?)"
+		args = append(args, issueType)
+	}
+
+	rows, err := r.db.Query(query+issueSummaryGroupBy, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := make([]models.IssueSummary, 0)
+	for rows.Next() {
+		issue, err := scanIssueSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, *issue)
+	}
+
+	return issues, rows.Err()
+}
+
+// GetIssue returns one referenced issue, or nil when no commit references it
+func (r *AnalyticsRepository) GetIssue(projectID int, key string) (*models.IssueSummary, error) {
+	rows, err := r.db.Query(issueSummaryQuery+" AND ci.issue_key = ?"+issueSummaryGroupBy, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanIssueSummary(rows)
+}
+
+// scanIssueSummary reads a row of issueSummaryQuery
+func scanIssueSummary(rows *sql.Rows) (*models.IssueSummary, error) {
+	var issue models.IssueSummary
+	var firstCommit, lastCommit time.Time
+	if err := rows.Scan(&issue.Key, &issue.Title, &issue.Type, &issue.Priority, &issue.Status,
+		&issue.Commits, &issue.Files, &issue.Authors, database.Timestamp(&firstCommit), database.Timestamp(&lastCommit)); err != nil {
+		return nil, err
+	}
+	issue.FirstCommit = firstCommit.Format(time.RFC3339)
+	issue.LastCommit = lastCommit.Format(time.RFC3339)
+	return &issue, nil
+}
+
+// GetIssueCommits returns the commits referencing an issue, newest first
+func (r *AnalyticsRepository) GetIssueCommits(projectID int, key string) ([]models.IssueCommit, error) {
+	rows, err := r.db.Query(`
+		SELECT c.hash, c.author, c.timestamp, COALESCE(c.message, ''), COALESCE(c.category, '')
+		FROM commit_issues ci
+		JOIN commits c ON ci.commit_id = c.id
+		WHERE ci.project_id = ? AND ci.issue_key = ?
+		ORDER BY c.timestamp DESC, c.id DESC
+	`, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	commits := make([]models.IssueCommit, 0)
+	for rows.Next() {
+		var commit models.IssueCommit
+		var timestamp time.Time
+		if err := rows.Scan(&commit.Hash, &commit.Author, &timestamp, &commit.Message, &commit.Category); err != nil {
+			return nil, err
+		}
+		commit.Timestamp = timestamp.Format(time.RFC3339)
+		commits = append(commits, commit)
+	}
+
+	return commits, rows.Err()
+}
+
+// GetIssueFiles returns the files changed by the commits referencing an issue, most changed first
+func (r *AnalyticsRepository) GetIssueFiles(projectID int, key string) ([]models.IssueFile, error) {
+	rows, err := r.db.Query(`
+		SELECT ch.file_path, COUNT(DISTINCT c.id), SUM(ch.lines_added), SUM(ch.lines_deleted)
+		FROM commit_issues ci
+		JOIN commits c ON ci.commit_id = c.id
+		JOIN changes ch ON ch.commit_id = c.id
+		WHERE ci.project_id = ? AND ci.issue_key = ?
+		GROUP BY ch.file_path
+		ORDER BY COUNT(DISTINCT c.id) DESC, ch.file_path
+	`, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]models.IssueFile, 0)
+	for rows.Next() {
+		var file models.IssueFile
+		if err := rows.Scan(&file.FilePath, &file.Commits, &file.LinesAdded, &file.LinesDeleted); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+// GetIssueAuthors returns the authors of the commits referencing an issue, most active first
+func (r *AnalyticsRepository) GetIssueAuthors(projectID int, key string) ([]models.IssueAuthor, error) {
+	rows, err := r.db.Query(`
+		SELECT c.author, COUNT(DISTINCT c.id), COALESCE(SUM(ch.lines_added), 0), COALESCE(SUM(ch.lines_deleted), 0)
+		FROM commit_issues ci
+		JOIN commits c ON ci.commit_id = c.id
+		LEFT JOIN changes ch ON ch.commit_id = c.id
+		WHERE ci.project_id = ? AND ci.issue_key = ?
+		GROUP BY c.author
+		ORDER BY COUNT(DISTINCT c.id) DESC, c.author
+	`, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authors := make([]models.IssueAuthor, 0)
+	for rows.Next() {
+		var author models.IssueAuthor
+		if err := rows.Scan(&author.Author, &author.Commits, &author.LinesAdded, &author.LinesDeleted); err != nil {
+			return nil, err
+		}
+		authors = append(authors, author)
+	}
+
+	return authors, rows.Err()
+}
+
+// GetFileIssues returns the issues referenced by the commits that changed a file, most recent first
+func (r *AnalyticsRepository) GetFileIssues(projectID int, filePath string) ([]models.FileIssue, error) {
+	rows, err := r.db.Query(`
+		SELECT
+			ci.issue_key,
+			COALESCE(i.title, ''),
+			COALESCE(i.issue_type, ''),
+			COALESCE(i.priority, ''),
+			COALESCE(i.status, ''),
+			COUNT(DISTINCT c.id),
+			MAX(c.timestamp)
+		FROM changes ch
+		JOIN commits c ON ch.commit_id = c.id
+		JOIN commit_issues ci ON ci.commit_id = c.id
+		LEFT JOIN issues i ON i.project_id = ci.project_id AND i.issue_key = ci.issue_key
+		WHERE c.project_id = ? AND ch.file_path = ?
+		GROUP BY ci.issue_key, i.title, i.issue_type, i.priority, i.status
+		ORDER BY MAX(c.timestamp) DESC, ci.issue_key
+	`, projectID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := make([]models.FileIssue, 0)
+	for rows.Next() {
+		var issue models.FileIssue
+		var lastCommit time.Time
+		if err := rows.Scan(&issue.Key, &issue.Title, &issue.Type, &issue.Priority, &issue.Status, &issue.Commits, database.Timestamp(&lastCommit)); err != nil {
+			return nil, err
+		}
+		issue.LastCommit = lastCommit.Format(time.RFC3339)
+		issues = append(issues, issue)
+	}
+
+	return issues, rows.Err()
+}
+
+// GetPeriodCommits returns the commits made after the start (the zero time for the beginning
+// of history) up to and including the end, with the files each one changed
+func (r *AnalyticsRepository) GetPeriodCommits(projectID int, start, end time.Time) ([]services.PeriodCommit, error) {
+	query := `
+		SELECT c.id, c.author, ch.file_path, ch.lines_added, ch.lines_deleted
+		FROM commits c
+		LEFT JOIN changes ch ON ch.commit_id = c.id
+		WHERE c.project_id = ? AND c.timestamp <= ?`
+	args := []interface{}{projectID, end.UTC()}
+	if !start.IsZero() {
+		query += " AND c.timestamp > ?"
+		args = append(args, start.UTC())
+	}
+	query += " ORDER BY c.id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	commits := make([]services.PeriodCommit, 0)
+	lastID := 0
+	for rows.Next() {
+		var commitID int
+		var author string
+		var filePath sql.NullString
+		var linesAdded, linesDeleted sql.NullInt64
+		if err := rows.Scan(&commitID, &author, &filePath, &linesAdded, &linesDeleted); err != nil {
+			return nil, err
+		}
+		if commitID != lastID {
+			commits = append(commits, services.PeriodCommit{Author: author})
+			lastID = commitID
+		}
+		if filePath.Valid {
+			commit := &commits[len(commits)-1]
+			commit.Changes = append(commit.Changes, services.PeriodChange{
+				FilePath:     filePath.String,
+				LinesAdded:   int(linesAdded.Int64),
+				LinesDeleted: int(linesDeleted.Int64),
+			})
+		}
+	}
+
+	return commits, rows.Err()
+}
+
+// GetRiskPolicy returns the current version of a project's risk policy, or nil when it uses the
+// default policy
+func (r *AnalyticsRepository) GetRiskPolicy(projectID int) (*entities.RiskPolicy, error) {
+	return sqlstore.NewRiskPolicyRepository(r.db).GetCurrent(projectID)
 }
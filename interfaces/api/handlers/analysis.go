@@ -7,7 +7,7 @@ import (
 
 	"codeecho/application/usecases/analysis"
 	"codeecho/infrastructure/database"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,7 +30,7 @@ func AnalyzeProject(c *gin.Context) {
 	}
 
 	// Initialize dependencies
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	analysisUseCase := analysis.NewProjectAnalysisUseCase(projectRepo)
 
 	// Get project to verify it exists
@@ -80,7 +80,7 @@ func RefreshProjectAnalysis(c *gin.Context) {
 	}
 
 	// Initialize dependencies
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	analysisUseCase := analysis.NewProjectAnalysisUseCase(projectRepo)
 
 	// Get project to check if it exists and has been analyzed before
@@ -127,7 +127,7 @@ func GetProjectAnalysisStatus(c *gin.Context) {
 	}
 
 	// Initialize dependencies
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	analysisUseCase := analysis.NewProjectAnalysisUseCase(projectRepo)
 
 	// Get analysis status
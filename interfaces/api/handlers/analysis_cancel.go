@@ -8,7 +8,7 @@ import (
 
 	"codeecho/application/usecases/analysis"
 	"codeecho/infrastructure/database"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,7 +22,7 @@ func CancelAnalysis(c *gin.Context) {
 	}
 
 	// Initialize dependencies
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	analysisUseCase := analysis.NewProjectAnalysisUseCase(projectRepo)
 
 	// Get the project to verify it exists
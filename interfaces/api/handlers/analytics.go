@@ -3,12 +3,17 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
 	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
 	"codeecho/infrastructure/repository"
 
 	"github.com/gin-gonic/gin"
@@ -30,12 +35,36 @@ func invalidateProjectCache(projectID int) {
 		getCacheKey("commits", projectID),
 		getCacheKey("hotspots", projectID),
 		getCacheKey("stats", projectID),
+		getCacheKey("languages", projectID),
+		fmt.Sprintf("project_file_types_%d", projectID),
+	}
+	// Parameterised entries are keyed by project ID followed by their query parameters
+	prefixes := []string{
+		fmt.Sprintf("hotspots_%d_", projectID),
+		fmt.Sprintf("code_age_%d_", projectID),
+		fmt.Sprintf("complexity_trend_%d_", projectID),
+		fmt.Sprintf("file_ownership_flat_%d_", projectID),
+		fmt.Sprintf("knowledge_risk_%d_", projectID),
+		fmt.Sprintf("knowledge_loss_%d_", projectID),
+		fmt.Sprintf("truck_factor_%d_", projectID),
+		fmt.Sprintf("fragmentation_%d_", projectID),
+		fmt.Sprintf("temporal_coupling_%d_", projectID),
+		fmt.Sprintf("temporal_coupling_flat_%d_", projectID),
+		fmt.Sprintf("coupling_sum_%d_", projectID),
+		fmt.Sprintf("coupling_clusters_%d_", projectID),
+		fmt.Sprintf("defect_hotspots_%d_", projectID),
+		fmt.Sprintf("issues_%d_", projectID),
+		fmt.Sprintf("file_issues_%d_", projectID),
+		fmt.Sprintf("releases_%d_", projectID),
+		fmt.Sprintf("team_ownership_%d_", projectID),
+		fmt.Sprintf("team_coupling_%d_", projectID),
 	}
 	cache.mu.Lock()
 	for _, k := range keys {
 		delete(cache.data, k)
 	}
 	cache.mu.Unlock()
+	cache.deletePrefixed(prefixes...)
 }
 
 // getCacheKey generates a cache key for the given prefix and ID
@@ -43,6 +72,16 @@ func getCacheKey(prefix string, id int) string {
 	return fmt.Sprintf("%s_%d", prefix, id)
 }
 
+// parseOwnershipMetric reads the ?ownership= query parameter, responding with 400 when it is invalid
+func parseOwnershipMetric(c *gin.Context, fallback values.OwnershipMetric) (values.OwnershipMetric, bool) {
+	metric, err := values.ParseOwnershipMetric(c.Query("ownership"), fallback)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return metric, true
+}
+
 // getFromCache retrieves data from cache
 func (c *Cache) get(key string) (interface{}, bool) {
 	c.mu.RLock()
@@ -58,6 +97,20 @@ func (c *Cache) set(key string, data interface{}) {
 	c.data[key] = data
 }
 
+// deletePrefixed removes the entries whose keys start with any of the prefixes
+func (c *Cache) deletePrefixed(prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.data {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				delete(c.data, key)
+				break
+			}
+		}
+	}
+}
+
 // GetProjectCommits returns commits for a project
 func GetProjectCommits(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -196,7 +249,15 @@ func GetProjectHotspots(c *gin.Context) {
 		"minComplexity": minComplexity,
 		"minChanges":    minChanges,
 	}
-	hotspots, totalCount, err := getProjectHotspotsFromDB(id, page, limit, filters)
+	assessor, err := loadRiskAssessor(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load risk policy",
+			"detail": err.Error(),
+		})
+		return
+	}
+	hotspots, totalCount, err := getProjectHotspotsFromDB(id, page, limit, filters, assessor)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":  "Failed to retrieve hotspots",
@@ -208,8 +269,9 @@ func GetProjectHotspots(c *gin.Context) {
 	totalPages := (totalCount + limit - 1) / limit // Ceiling division
 
 	result := gin.H{
-		"project_id": id,
-		"hotspots":   hotspots,
+		"project_id":          id,
+		"hotspots":            hotspots,
+		"risk_policy_version": assessor.Version(),
 		"pagination": gin.H{
 			"page":        page,
 			"limit":       limit,
@@ -290,44 +352,153 @@ func GetDashboardStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
-// GetProjectTemporalCoupling returns temporal coupling pairs for a project
-func GetProjectTemporalCoupling(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
-		return
+// couplingFilters holds the query parameters shared by the temporal coupling endpoints
+type couplingFilters struct {
+	limit            int
+	startDate        string
+	endDate          string
+	minSharedCommits int
+	minCouplingScore float64
+	fileTypes        string
+}
+
+// parseCouplingFilters reads the temporal coupling query parameters, ignoring invalid values
+func parseCouplingFilters(c *gin.Context) couplingFilters {
+	f := couplingFilters{
+		limit:            200, // enforce max 200
+		startDate:        c.Query("startDate"),
+		endDate:          c.Query("endDate"),
+		minSharedCommits: 2,
+		minCouplingScore: 0.0,
+		fileTypes:        c.Query("fileTypes"), // comma-separated list like "php,js,py"
 	}
 
-	// Optional query params
-	limit := 200                        // enforce max 200
 	if l := c.Query("limit"); l != "" { // allow smaller limits if provided
 		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 200 {
-			limit = v
+			f.limit = v
 		}
 	}
-	startDate := c.Query("startDate")
-	endDate := c.Query("endDate")
-
-	// New threshold parameters
-	minSharedCommits := 2 // default value
 	if msc := c.Query("minSharedCommits"); msc != "" {
 		if v, err := strconv.Atoi(msc); err == nil && v > 0 {
-			minSharedCommits = v
+			f.minSharedCommits = v
 		}
 	}
-
-	minCouplingScore := 0.0 // default value
 	if mcs := c.Query("minCouplingScore"); mcs != "" {
 		if v, err := strconv.ParseFloat(mcs, 64); err == nil && v >= 0.0 && v <= 1.0 {
-			minCouplingScore = v
+			f.minCouplingScore = v
 		}
 	}
 
-	// File types filter
-	fileTypes := c.Query("fileTypes") // comma-separated list like "php,js,py"
+	return f
+}
+
+// cacheKey encodes the filters for use in a cache key
+func (f couplingFilters) cacheKey() string {
+	return fmt.Sprintf("%d_%s_%s_%d_%.2f_%s", f.limit, f.startDate, f.endDate, f.minSharedCommits, f.minCouplingScore, f.fileTypes)
+}
+
+// params echoes the applied filters in responses
+func (f couplingFilters) params() gin.H {
+	return gin.H{
+		"limit":            f.limit,
+		"startDate":        f.startDate,
+		"endDate":          f.endDate,
+		"minSharedCommits": f.minSharedCommits,
+		"minCouplingScore": f.minCouplingScore,
+		"fileTypes":        f.fileTypes,
+	}
+}
+
+// parseCouplingLevel reads the level, depth, layer and change set query parameters of the
+// temporal coupling endpoints. Layers are given as repeated layer=name:glob[,glob] parameters;
+// groupBy=window joins an author's commits within windowMinutes and groupBy=ticket joins
+// commits referencing the same issue key, matched by the optional issuePattern regexp.
+func parseCouplingLevel(c *gin.Context) (analytics.CouplingOptions, bool) {
+	level, err := values.ParseCouplingLevel(c.Query("level"), values.CouplingByFile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return analytics.CouplingOptions{}, false
+	}
+	options := analytics.CouplingOptions{Level: level}
+
+	switch level {
+	case values.CouplingByDirectory:
+		depth, ok := parsePositiveIntQuery(c, "depth", 1)
+		if !ok {
+			return options, false
+		}
+		options.Depth = depth
+	case values.CouplingByLayer:
+		for _, definition := range c.QueryArray("layer") {
+			layer, err := services.ParseArchitectureLayer(definition)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return options, false
+			}
+			options.Layers = append(options.Layers, layer)
+		}
+		if len(options.Layers) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "level=layer requires at least one layer=name:glob[,glob] parameter"})
+			return options, false
+		}
+	}
+
+	mode, err := values.ParseChangeSetMode(c.Query("groupBy"), values.ChangeSetByCommit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return options, false
+	}
+	options.ChangeSets.Mode = mode
+
+	switch mode {
+	case values.ChangeSetByWindow:
+		minutes, ok := parsePositiveIntQuery(c, "windowMinutes", int(services.DefaultChangeSetWindow/time.Minute))
+		if !ok {
+			return options, false
+		}
+		options.ChangeSets.Window = time.Duration(minutes) * time.Minute
+	case values.ChangeSetByTicket:
+		if pattern := c.Query("issuePattern"); pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issuePattern", "detail": err.Error()})
+				return options, false
+			}
+			options.ChangeSets.IssuePattern = re
+		}
+	}
+	return options, true
+}
+
+// couplingLevelCacheKey encodes the coupling level options for use in a cache key
+func couplingLevelCacheKey(options analytics.CouplingOptions) string {
+	key := fmt.Sprintf("%s_%d", options.Level, options.Depth)
+	for _, layer := range options.Layers {
+		key += "_" + layer.Name + "=" + strings.Join(layer.Patterns, ",")
+	}
+	key += fmt.Sprintf("_%s_%s", options.ChangeSets.Mode, options.ChangeSets.Window)
+	if options.ChangeSets.IssuePattern != nil {
+		key += "_" + options.ChangeSets.IssuePattern.String()
+	}
+	return key
+}
+
+// GetProjectTemporalCoupling returns temporal coupling pairs for a project
+func GetProjectTemporalCoupling(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	f := parseCouplingFilters(c)
+	level, ok := parseCouplingLevel(c)
+	if !ok {
+		return
+	}
 
 	// Cache key includes parameters
-	cacheKey := fmt.Sprintf("temporal_coupling_%d_%d_%s_%s_%d_%.2f_%s", id, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	cacheKey := fmt.Sprintf("temporal_coupling_%d_%s_%s", id, f.cacheKey(), couplingLevelCacheKey(level))
 	if cached, exists := cache.get(cacheKey); exists {
 		c.Header("X-Cache", "HIT")
 		c.JSON(http.StatusOK, cached)
@@ -337,7 +508,7 @@ func GetProjectTemporalCoupling(c *gin.Context) {
 
 	repo := repository.NewAnalyticsRepository(database.DB)
 	useCase := analytics.NewAnalyticsUseCase(repo)
-	pairs, err := useCase.GetTemporalCoupling(id, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	pairs, err := useCase.GetLevelTemporalCoupling(id, level, f.limit, f.startDate, f.endDate, f.minSharedCommits, f.minCouplingScore, f.fileTypes)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve temporal coupling", "detail": err.Error()})
 		return
@@ -346,7 +517,9 @@ func GetProjectTemporalCoupling(c *gin.Context) {
 	result := gin.H{
 		"project_id":        id,
 		"temporal_coupling": pairs,
-		"params":            gin.H{"limit": limit, "startDate": startDate, "endDate": endDate, "minSharedCommits": minSharedCommits, "minCouplingScore": minCouplingScore, "fileTypes": fileTypes},
+		"params":            f.params(),
+		"level":             level.Level,
+		"groupBy":           level.ChangeSets.Mode,
 	}
 	cache.set(cacheKey, result)
 	c.JSON(http.StatusOK, result)
@@ -365,34 +538,13 @@ func GetTemporalCouplingFlat(c *gin.Context) {
 		return
 	}
 
-	limit := 200
-	if l := c.Query("limit"); l != "" {
-		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 200 {
-			limit = v
-		}
-	}
-	startDate := c.Query("startDate")
-	endDate := c.Query("endDate")
-
-	// New threshold parameters
-	minSharedCommits := 2 // default value
-	if msc := c.Query("minSharedCommits"); msc != "" {
-		if v, err := strconv.Atoi(msc); err == nil && v > 0 {
-			minSharedCommits = v
-		}
-	}
-
-	minCouplingScore := 0.0 // default value
-	if mcs := c.Query("minCouplingScore"); mcs != "" {
-		if v, err := strconv.ParseFloat(mcs, 64); err == nil && v >= 0.0 && v <= 1.0 {
-			minCouplingScore = v
-		}
+	f := parseCouplingFilters(c)
+	level, ok := parseCouplingLevel(c)
+	if !ok {
+		return
 	}
 
-	// File types filter
-	fileTypes := c.Query("fileTypes") // comma-separated list like "php,js,py"
-
-	cacheKey := fmt.Sprintf("temporal_coupling_flat_%d_%d_%s_%s_%d_%.2f_%s", id, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	cacheKey := fmt.Sprintf("temporal_coupling_flat_%d_%s_%s", id, f.cacheKey(), couplingLevelCacheKey(level))
 	if cached, exists := cache.get(cacheKey); exists {
 		c.Header("X-Cache", "HIT")
 		c.JSON(http.StatusOK, cached)
@@ -402,7 +554,7 @@ func GetTemporalCouplingFlat(c *gin.Context) {
 
 	repo := repository.NewAnalyticsRepository(database.DB)
 	useCase := analytics.NewAnalyticsUseCase(repo)
-	pairs, err := useCase.GetTemporalCoupling(id, limit, startDate, endDate, minSharedCommits, minCouplingScore, fileTypes)
+	pairs, err := useCase.GetLevelTemporalCoupling(id, level, f.limit, f.startDate, f.endDate, f.minSharedCommits, f.minCouplingScore, f.fileTypes)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve temporal coupling", "detail": err.Error()})
 		return
@@ -411,7 +563,9 @@ func GetTemporalCouplingFlat(c *gin.Context) {
 	result := gin.H{
 		"projectId":        id,
 		"temporalCoupling": pairs,
-		"params":           gin.H{"limit": limit, "startDate": startDate, "endDate": endDate, "minSharedCommits": minSharedCommits, "minCouplingScore": minCouplingScore, "fileTypes": fileTypes},
+		"params":           f.params(),
+		"level":            level.Level,
+		"groupBy":          level.ChangeSets.Mode,
 	}
 	cache.set(cacheKey, result)
 	c.JSON(http.StatusOK, result)
@@ -450,6 +604,92 @@ func GetProjectFileTypes(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetProjectLanguages returns the language breakdown (code, comment and blank lines) for a project
+func GetProjectLanguages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	cacheKey := getCacheKey("languages", id)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	languages, err := useCase.GetLanguageBreakdown(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve language breakdown", "detail": err.Error()})
+		return
+	}
+
+	totalCode, totalComment, totalBlank := 0, 0, 0
+	for _, lang := range languages {
+		totalCode += lang.CodeLines
+		totalComment += lang.CommentLines
+		totalBlank += lang.BlankLines
+	}
+
+	result := gin.H{
+		"project_id": id,
+		"languages":  languages,
+		"totals": gin.H{
+			"code_lines":    totalCode,
+			"comment_lines": totalComment,
+			"blank_lines":   totalBlank,
+		},
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetProjectCodeAge returns the code age map of a project: files and lines bucketed by
+// how long ago they last changed, plus a directory treemap of line-weighted ages
+func GetProjectCodeAge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	staleAfterDays := analytics.DefaultStaleAfterDays
+	if v := c.Query("stale_after_days"); v != "" {
+		staleAfterDays, err = strconv.Atoi(v)
+		if err != nil || staleAfterDays <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "stale_after_days must be a positive integer"})
+			return
+		}
+	}
+	path := c.Query("path")
+
+	noCache := c.Query("nocache") == "1"
+	cacheKey := fmt.Sprintf("code_age_%d_%d_%s", id, staleAfterDays, path)
+	if !noCache {
+		if cached, exists := cache.get(cacheKey); exists {
+			c.Header("X-Cache", "HIT")
+			c.JSON(http.StatusOK, cached)
+			return
+		}
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	report, err := useCase.GetCodeAge(id, path, staleAfterDays, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve code age", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, report)
+	c.JSON(http.StatusOK, report)
+}
+
 // getDashboardStatsFromDB calculates dashboard statistics from the database
 func getDashboardStatsFromDB(repo *repository.AnalyticsRepository) (gin.H, error) {
 	// Query for aggregated statistics using raw SQL
@@ -549,14 +789,14 @@ func getProjectStatsFromDB(projectID int) (gin.H, error) {
 			COUNT(DISTINCT ch.file_path) as total_files,
 			COALESCE(SUM(ch.lines_added), 0) as lines_added,
 			COALESCE(SUM(ch.lines_deleted), 0) as lines_deleted,
-			COALESCE(MAX(c.timestamp), '') as last_commit
+			MAX(c.timestamp) as last_commit
 		FROM commits c
 		LEFT JOIN changes ch ON c.id = ch.commit_id
 		WHERE c.project_id = ?
 	`
 
 	var totalCommits, contributors, totalFiles, linesAdded, linesDeleted int
-	var lastCommit string
+	var lastCommitAt time.Time
 
 	err := database.DB.QueryRow(query, projectID).Scan(
 		&totalCommits,
@@ -564,13 +804,18 @@ func getProjectStatsFromDB(projectID int) (gin.H, error) {
 		&totalFiles,
 		&linesAdded,
 		&linesDeleted,
-		&lastCommit,
+		database.Timestamp(&lastCommitAt),
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project stats: %w", err)
 	}
 
+	lastCommit := ""
+	if !lastCommitAt.IsZero() {
+		lastCommit = lastCommitAt.Format("2006-01-02 15:04:05")
+	}
+
 	// Get total number of hotspots (files with more than 1 change)
 	var totalHotspots int
 	hotspotQuery := `
@@ -602,35 +847,36 @@ func getProjectStatsFromDB(projectID int) (gin.H, error) {
 	}, nil
 }
 
-// getProjectHotspotsFromDB gets hotspots (frequently changed files) for a project with pagination and filters
-func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[string]interface{}) ([]gin.H, int, error) {
+// getProjectHotspotsFromDB gets hotspots (frequently changed files) for a project with pagination and filters,
+// rated by change frequency under the assessor's risk policy
+func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[string]interface{}, assessor *services.RiskAssessor) ([]gin.H, int, error) {
+	stats, err := sqlstore.FileStatsRelation(database.DB, projectID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read file statistics: %w", err)
+	}
+
 	// Build WHERE clause for filters
-	whereConditions := []string{"c.project_id = ?"}
+	whereConditions := []string{"project_id = ?"}
 	countArgs := []interface{}{projectID}
 	queryArgs := []interface{}{projectID}
 
 	// Date range filter
 	if startDate, ok := filters["startDate"].(string); ok && startDate != "" {
-		whereConditions = append(whereConditions, "c.timestamp >= ?")
+		whereConditions = append(whereConditions, "day >= ?")
 		countArgs = append(countArgs, startDate)
 		queryArgs = append(queryArgs, startDate)
 	}
 	if endDate, ok := filters["endDate"].(string); ok && endDate != "" {
-		whereConditions = append(whereConditions, "c.timestamp <= ?")
+		whereConditions = append(whereConditions, "day <= ?")
 		countArgs = append(countArgs, endDate)
 		queryArgs = append(queryArgs, endDate)
 	}
 
-	// Repository filter (if applicable)
-	if repository, ok := filters["repository"].(string); ok && repository != "" && repository != "all" {
-		whereConditions = append(whereConditions, "c.repository = ?")
-		countArgs = append(countArgs, repository)
-		queryArgs = append(queryArgs, repository)
-	}
+	// Repository filter is not applicable: a project has a single repository
 
 	// Path filter
 	if path, ok := filters["path"].(string); ok && path != "" {
-		whereConditions = append(whereConditions, "ch.file_path LIKE ?")
+		whereConditions = append(whereConditions, "file_path LIKE ?")
 		pathPattern := fmt.Sprintf("%%%s%%", path)
 		countArgs = append(countArgs, pathPattern)
 		queryArgs = append(queryArgs, pathPattern)
@@ -642,7 +888,7 @@ func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[st
 		if len(types) > 0 {
 			typeConditions := make([]string, len(types))
 			for i, fileType := range types {
-				typeConditions[i] = "ch.file_path LIKE ?"
+				typeConditions[i] = "file_path LIKE ?"
 				pattern := fmt.Sprintf("%%.%s", strings.TrimSpace(fileType))
 				countArgs = append(countArgs, pattern)
 				queryArgs = append(queryArgs, pattern)
@@ -654,10 +900,10 @@ func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[st
 	whereClause := strings.Join(whereConditions, " AND ")
 
 	// Build HAVING clause for complexity and change filters
-	havingConditions := []string{"COUNT(*) > 1"}
+	havingConditions := []string{"SUM(commits) > 1"}
 
 	if minChanges, ok := filters["minChanges"].(int); ok && minChanges > 0 {
-		havingConditions = append(havingConditions, "COUNT(*) >= ?")
+		havingConditions = append(havingConditions, "SUM(commits) >= ?")
 		countArgs = append(countArgs, minChanges)
 		queryArgs = append(queryArgs, minChanges)
 	}
@@ -668,17 +914,16 @@ func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[st
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*) 
 		FROM (
-			SELECT ch.file_path
-			FROM changes ch
-			JOIN commits c ON ch.commit_id = c.id
+			SELECT path_hash
+			FROM %s file_stats
 			WHERE %s
-			GROUP BY ch.file_path
+			GROUP BY path_hash
 			HAVING %s
 		) AS hotspot_files
-	`, whereClause, havingClause)
+	`, stats, whereClause, havingClause)
 
 	var totalCount int
-	err := database.DB.QueryRow(countQuery, countArgs...).Scan(&totalCount)
+	err = database.DB.QueryRow(countQuery, countArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
 	}
@@ -690,20 +935,19 @@ func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[st
 	queryArgs = append(queryArgs, limit, offset)
 
 	query := fmt.Sprintf(`
-		SELECT 
-			ch.file_path,
-			COUNT(*) as change_count,
-			SUM(ch.lines_added + ch.lines_deleted) as total_changes,
-			COUNT(DISTINCT c.author) as authors,
-			MAX(c.timestamp) as last_modified
-		FROM changes ch
-		JOIN commits c ON ch.commit_id = c.id
+		SELECT
+			file_path,
+			SUM(commits) as change_count,
+			SUM(lines_added + lines_deleted) as total_changes,
+			COUNT(DISTINCT author) as authors,
+			MAX(last_commit_at) as last_modified
+		FROM %s file_stats
 		WHERE %s
-		GROUP BY ch.file_path
+		GROUP BY path_hash, file_path
 		HAVING %s
 		ORDER BY total_changes DESC
 		LIMIT ? OFFSET ?
-	`, whereClause, havingClause)
+	`, stats, whereClause, havingClause)
 
 	rows, err := database.DB.Query(query, queryArgs...)
 	if err != nil {
@@ -713,28 +957,24 @@ func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[st
 
 	var hotspots []gin.H
 	for rows.Next() {
-		var filePath, lastModified string
+		var filePath string
 		var changeCount, totalChanges, authors int
+		var lastModified time.Time
 
-		err := rows.Scan(&filePath, &changeCount, &totalChanges, &authors, &lastModified)
+		err := rows.Scan(&filePath, &changeCount, &totalChanges, &authors, database.Timestamp(&lastModified))
 		if err != nil {
 			continue
 		}
 
 		// Calculate risk level based on change frequency
-		riskLevel := "Low"
-		if changeCount > 10 {
-			riskLevel = "High"
-		} else if changeCount > 5 {
-			riskLevel = "Medium"
-		}
+		riskLevel := assessor.ChangeFrequencyLevel(changeCount).Title()
 
 		hotspots = append(hotspots, gin.H{
 			"file_path":     filePath,
 			"change_count":  changeCount,
 			"total_changes": totalChanges,
 			"authors":       authors,
-			"last_modified": lastModified,
+			"last_modified": lastModified.Format(time.RFC3339Nano),
 			"risk_level":    riskLevel,
 		})
 	}
@@ -742,7 +982,8 @@ func getProjectHotspotsFromDB(projectID int, page int, limit int, filters map[st
 	return hotspots, totalCount, nil
 }
 
-// GetProjectOverview returns project overview with health trends and risk metrics
+// GetProjectOverview returns project overview with health trends and risk metrics. The months
+// query parameter sets how many monthly debt scores the trend covers.
 func GetProjectOverview(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -750,12 +991,17 @@ func GetProjectOverview(c *gin.Context) {
 		return
 	}
 
+	months, ok := parsePositiveIntQuery(c, "months", analytics.DefaultDebtTrendMonths)
+	if !ok {
+		return
+	}
+
 	// Initialize repository and use case
 	repo := repository.NewAnalyticsRepository(database.DB)
 	useCase := analytics.NewAnalyticsUseCase(repo)
 
 	// Get project overview from database
-	overview, err := useCase.GetProjectOverview(id)
+	overview, err := useCase.GetProjectOverview(id, months)
 	if err != nil {
 		// Fallback to mock data if database query fails
 		mockOverview := gin.H{
@@ -788,12 +1034,28 @@ func GetFileOwnership(c *gin.Context) {
 		return
 	}
 
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByLinesChanged)
+	if !ok {
+		return
+	}
+
+	if c.Query("view") == "team" {
+		getTeamFileOwnership(c, id, metric)
+		return
+	}
+
 	// Initialize repository and use case
 	repo := repository.NewAnalyticsRepository(database.DB)
 	useCase := analytics.NewAnalyticsUseCase(repo)
 
+	assessor, err := useCase.GetRiskAssessor(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load risk policy", "detail": err.Error()})
+		return
+	}
+
 	// Get file ownership from database
-	fileOwnership, err := useCase.GetFileOwnership(id)
+	fileOwnership, err := useCase.GetFileOwnership(id, metric, assessor)
 	if err != nil {
 		// Fallback to mock data if database query fails
 		mockFileOwnership := []gin.H{
@@ -828,8 +1090,10 @@ func GetFileOwnership(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"projectId":     id,
-		"fileOwnership": fileOwnership,
+		"projectId":         id,
+		"ownership":         metric,
+		"fileOwnership":     fileOwnership,
+		"riskPolicyVersion": assessor.Version(),
 	})
 }
 
@@ -847,7 +1111,12 @@ func GetOwnership(c *gin.Context) {
 		return
 	}
 
-	cacheKey := getCacheKey("file_ownership_flat", id)
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByLinesChanged)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("file_ownership_flat_%d_%s", id, metric)
 	if cached, exists := cache.get(cacheKey); exists {
 		c.Header("X-Cache", "HIT")
 		c.JSON(http.StatusOK, cached)
@@ -857,7 +1126,12 @@ func GetOwnership(c *gin.Context) {
 
 	repo := repository.NewAnalyticsRepository(database.DB)
 	useCase := analytics.NewAnalyticsUseCase(repo)
-	ownership, err := useCase.GetFileOwnership(id)
+	assessor, err := useCase.GetRiskAssessor(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load risk policy", "detail": err.Error()})
+		return
+	}
+	ownership, err := useCase.GetFileOwnership(id, metric, assessor)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve ownership", "detail": err.Error()})
 		return
@@ -865,8 +1139,10 @@ func GetOwnership(c *gin.Context) {
 
 	// Transform to simpler shape matching /projects/:id/file-ownership but flat
 	result := gin.H{
-		"projectId":     id,
-		"fileOwnership": ownership,
+		"projectId":         id,
+		"ownership":         metric,
+		"fileOwnership":     ownership,
+		"riskPolicyVersion": assessor.Version(),
 	}
 	cache.set(cacheKey, result)
 	c.JSON(http.StatusOK, result)
@@ -916,8 +1192,13 @@ func GetProjectKnowledgeRisk(c *gin.Context) {
 		return
 	}
 
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByLinesChanged)
+	if !ok {
+		return
+	}
+
 	// Check cache first
-	cacheKey := getCacheKey("knowledge_risk", id)
+	cacheKey := fmt.Sprintf("knowledge_risk_%d_%s", id, metric)
 	if cached, exists := cache.get(cacheKey); exists {
 		c.JSON(http.StatusOK, cached)
 		return
@@ -927,8 +1208,17 @@ func GetProjectKnowledgeRisk(c *gin.Context) {
 	repo := repository.NewAnalyticsRepository(database.DB)
 	useCase := analytics.NewAnalyticsUseCase(repo)
 
+	assessor, err := useCase.GetRiskAssessor(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load risk policy",
+			"detail": err.Error(),
+		})
+		return
+	}
+
 	// Fetch real data
-	ownership, err := useCase.GetFileOwnership(id)
+	ownership, err := useCase.GetFileOwnership(id, metric, assessor)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":  "Failed to retrieve file ownership",
@@ -972,11 +1262,16 @@ func GetProjectKnowledgeRisk(c *gin.Context) {
 			risk = "high"
 		}
 
+		// Blame ownership knows the surviving lines; otherwise totalChanges is a proxy for total lines
+		totalLines := totalChanges
+		if fo.TotalLines > 0 {
+			totalLines = fo.TotalLines
+		}
+
 		fileOwnership = append(fileOwnership, map[string]interface{}{
-			"filePath": fo.FilePath,
-			"authors":  authors,
-			// Use totalChanges as a proxy for total lines displayed in UI
-			"totalLines":   totalChanges,
+			"filePath":     fo.FilePath,
+			"authors":      authors,
+			"totalLines":   totalLines,
 			"lastModified": lastModified,
 			"riskLevel":    strings.ToLower(risk),
 		})
@@ -1025,9 +1320,11 @@ func GetProjectKnowledgeRisk(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"projectId":      id,
-		"fileOwnership":  fileOwnership,
-		"authorHotspots": authorHotspots,
+		"projectId":         id,
+		"ownership":         metric,
+		"fileOwnership":     fileOwnership,
+		"authorHotspots":    authorHotspots,
+		"riskPolicyVersion": assessor.Version(),
 		"summary": gin.H{
 			"totalFiles":      len(fileOwnership),
 			"highRiskFiles":   highRisk,
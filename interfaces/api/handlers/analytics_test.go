@@ -3,7 +3,11 @@ package handlers
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"codeecho/domain/values"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +32,32 @@ func clearCache() {
 	}
 }
 
+// TestInvalidateProjectCache ensures reanalysing a project drops its parameterised entries only
+func TestInvalidateProjectCache(t *testing.T) {
+	clearCache()
+	for _, key := range []string{
+		"complexity_trend_7_main.go_month",
+		"temporal_coupling_7_all",
+		"team_coupling_7_commits_all",
+		"complexity_trend_70_main.go_month",
+	} {
+		cache.set(key, true)
+	}
+
+	invalidateProjectCache(7)
+
+	for key, want := range map[string]bool{
+		"complexity_trend_7_main.go_month":  false,
+		"temporal_coupling_7_all":           false,
+		"team_coupling_7_commits_all":       false,
+		"complexity_trend_70_main.go_month": true,
+	} {
+		if _, got := cache.get(key); got != want {
+			t.Errorf("%s cached = %v, want %v", key, got, want)
+		}
+	}
+}
+
 // TestGetProjectTemporalCoupling_CacheBehavior exercises the handler and ensures cache header toggles from MISS to HIT.
 // If database isn't initialized (common in unit test context), the handler may return 500; in that case we skip cache assertions.
 func TestGetProjectTemporalCoupling_CacheBehavior(t *testing.T) {
@@ -60,3 +90,138 @@ func TestGetProjectTemporalCoupling_CacheBehavior(t *testing.T) {
 		t.Fatalf("unexpected status code %d (body=%s)", w1.Code, w1.Body.String())
 	}
 }
+
+// queryContext returns a request context for the query string and the recorder of its response
+func queryContext(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/"+query, nil)
+	return c, w
+}
+
+// TestQueryParsers covers the query parameter parsers shared by the analytics endpoints, which
+// answer 400 and report false on malformed input
+func TestQueryParsers(t *testing.T) {
+	t.Run("CouplingLevel", func(t *testing.T) {
+		c, _ := queryContext("?level=directory&depth=2&groupBy=window&windowMinutes=30")
+		options, ok := parseCouplingLevel(c)
+		if !ok || options.Level != values.CouplingByDirectory || options.Depth != 2 ||
+			options.ChangeSets.Mode != values.ChangeSetByWindow || options.ChangeSets.Window != 30*time.Minute {
+			t.Errorf("unexpected options %+v", options)
+		}
+
+		c, _ = queryContext("?level=layer&layer=api:interfaces/api/**&layer=domain:domain/**&groupBy=ticket&issuePattern=PROJ-[0-9]%2B")
+		options, ok = parseCouplingLevel(c)
+		if !ok || len(options.Layers) != 2 || options.Layers[1].Name != "domain" || options.ChangeSets.IssuePattern.String() != "PROJ-[0-9]+" {
+			t.Errorf("unexpected options %+v", options)
+		}
+
+		for _, query := range []string{
+			"?level=module",
+			"?level=directory&depth=0",
+			"?level=layer",
+			"?level=layer&layer=api",
+			"?groupBy=session",
+			"?groupBy=ticket&issuePattern=(",
+		} {
+			c, w := queryContext(query)
+			if _, ok := parseCouplingLevel(c); ok || w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, w.Code)
+			}
+		}
+	})
+
+	t.Run("PositiveInt", func(t *testing.T) {
+		c, _ := queryContext("")
+		if value, ok := parsePositiveIntQuery(c, "limit", 20); !ok || value != 20 {
+			t.Errorf("expected the fallback, got %d", value)
+		}
+		for _, query := range []string{"?limit=0", "?limit=-1", "?limit=ten"} {
+			c, w := queryContext(query)
+			if _, ok := parsePositiveIntQuery(c, "limit", 20); ok || w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, w.Code)
+			}
+		}
+	})
+
+	t.Run("OwnershipMetric", func(t *testing.T) {
+		c, _ := queryContext("?ownership=blame")
+		if metric, ok := parseOwnershipMetric(c, values.OwnershipByCommits); !ok || metric != values.OwnershipByBlame {
+			t.Errorf("expected blame, got %q", metric)
+		}
+		c, w := queryContext("?ownership=age")
+		if _, ok := parseOwnershipMetric(c, values.OwnershipByCommits); ok || w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+}
+
+// TestGetProjectDefectHotspots_InvalidParams ensures malformed filters are rejected before any query
+func TestGetProjectDefectHotspots_InvalidParams(t *testing.T) {
+	clearCache()
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/projects/:id/defect-hotspots", GetProjectDefectHotspots)
+
+	for _, path := range []string{
+		"/projects/42/defect-hotspots?limit=0",
+		"/projects/42/defect-hotspots?minBugfixes=x",
+		"/projects/42/defect-hotspots?startDate=2025-13-01",
+		"/projects/42/defect-hotspots?endDate=yesterday",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, w.Code)
+		}
+	}
+}
+
+// TestUpdateClassificationRules_InvalidRules ensures invalid rules are rejected before they are stored
+func TestUpdateClassificationRules_InvalidRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.PUT("/projects/:id/classification-rules", UpdateClassificationRules)
+
+	for _, body := range []string{
+		`{"rules":[{"category":"cleanup","keywords":["tidy"]}]}`,
+		`{"rules":[{"category":"bugfix"}]}`,
+		`{"rules":[{"category":"bugfix","pattern":"("}]}`,
+		`{"rules":[{"keywords":["fix"]}]}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/projects/42/classification-rules", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+// TestUpdateRiskPolicy_InvalidInput ensures malformed policy updates are rejected before the
+// current policy is loaded
+func TestUpdateRiskPolicy_InvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.PUT("/projects/:id/risk-policy", UpdateRiskPolicy)
+
+	cases := []struct {
+		path, body string
+	}{
+		{"/projects/abc/risk-policy", `{}`},
+		{"/projects/42/risk-policy", `{"thresholds":`},
+		{"/projects/42/risk-policy", `not json`},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected 400, got %d", tc.path, tc.body, w.Code)
+		}
+	}
+}
@@ -5,7 +5,7 @@ import (
 
 	"codeecho/domain/services"
 	"codeecho/infrastructure/database"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 	infraServices "codeecho/infrastructure/services"
 
 	"github.com/gin-gonic/gin"
@@ -19,7 +19,7 @@ type AuthHandler struct {
 
 // NewAuthHandler creates a new authentication handler
 func NewAuthHandler() *AuthHandler {
-	authRepo := mysql.NewAuthRepository(database.DB)
+	authRepo := sqlstore.NewAuthRepository(database.DB)
 	authService := services.NewAuthService(authRepo)
 	jwtService := infraServices.NewJWTService()
 
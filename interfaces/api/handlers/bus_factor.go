@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
 	"codeecho/infrastructure/database"
 	"codeecho/infrastructure/repository"
 	"codeecho/internal/models"
@@ -15,29 +17,39 @@ import (
 
 // BusFactorResult represents the bus factor analysis for a single file
 type BusFactorResult struct {
-	File                  string            `json:"file"`
-	BusFactor             int               `json:"bus_factor"`
-	TopAuthors            []AuthorOwnership `json:"top_authors"`
-	OwnershipDistribution []AuthorOwnership `json:"ownership_distribution"`
-	LastModified          *time.Time        `json:"last_modified"`
-	RiskLevel             string            `json:"risk_level"`
-	TotalCommits          int               `json:"total_commits"`
+	File                  string                `json:"file"`
+	BusFactor             int                   `json:"bus_factor"`
+	TopAuthors            []AuthorOwnership     `json:"top_authors"`
+	OwnershipDistribution []AuthorOwnership     `json:"ownership_distribution"`
+	LastModified          *time.Time            `json:"last_modified"`
+	RiskLevel             string                `json:"risk_level"`
+	TotalCommits          int                   `json:"total_commits"`
+	Fragmentation         float64               `json:"fragmentation"`
+	MainDeveloper         string                `json:"main_developer"`
+	MainDeveloperShare    float64               `json:"main_developer_share"`
+	Effort                []models.AuthorEffort `json:"effort"`
 }
 
 // AuthorOwnership represents an author's ownership percentage of a file
 type AuthorOwnership struct {
 	Author           string  `json:"author"`
 	Commits          int     `json:"commits"`
+	LinesChanged     int     `json:"lines_changed"`
+	LinesAdded       int     `json:"lines_added"`
+	Lines            int     `json:"lines"`
 	OwnershipPercent float64 `json:"ownership_percent"`
 }
 
 // BusFactorResponse represents the complete bus factor analysis response
 type BusFactorResponse struct {
-	Files         []BusFactorResult `json:"files"`
-	Summary       BusFactorSummary  `json:"summary"`
-	ProjectID     int               `json:"project_id"`
-	DateRange     DateRange         `json:"date_range"`
-	FilterApplied FilterInfo        `json:"filter_applied"`
+	Files         []BusFactorResult      `json:"files"`
+	Summary       BusFactorSummary       `json:"summary"`
+	ProjectID     int                    `json:"project_id"`
+	Ownership     values.OwnershipMetric `json:"ownership"`
+	DateRange     DateRange              `json:"date_range"`
+	FilterApplied FilterInfo             `json:"filter_applied"`
+	// RiskPolicyVersion is the risk policy version that rated the files
+	RiskPolicyVersion int `json:"risk_policy_version"`
 }
 
 // BusFactorSummary provides aggregate statistics
@@ -48,6 +60,8 @@ type BusFactorSummary struct {
 	LowRiskFiles     int         `json:"low_risk_files"`
 	Distribution     map[int]int `json:"distribution"` // bus_factor -> count
 	AverageBusFactor float64     `json:"average_bus_factor"`
+	// AverageFragmentation is the mean fractal value of the files in the response
+	AverageFragmentation float64 `json:"average_fragmentation"`
 }
 
 // DateRange represents the time period analyzed
@@ -79,6 +93,11 @@ func GetProjectBusFactor(c *gin.Context) {
 	pathFilter := c.Query("path")
 	riskLevel := c.Query("riskLevel")
 
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByCommits)
+	if !ok {
+		return
+	}
+
 	var startDate, endDate *time.Time
 	if startDateStr != "" {
 		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
@@ -95,8 +114,17 @@ func GetProjectBusFactor(c *gin.Context) {
 	analyticsRepo := repository.NewAnalyticsRepository(database.DB)
 	analyticsUseCase := analytics.NewAnalyticsUseCase(analyticsRepo)
 
+	assessor, err := analyticsUseCase.GetRiskAssessor(projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load risk policy",
+			"detail": err.Error(),
+		})
+		return
+	}
+
 	// Get bus factor data
-	busFactorData, err := analyticsUseCase.GetBusFactorAnalysis(projectID, startDate, endDate, repositoryFilter, pathFilter)
+	busFactorData, err := analyticsUseCase.GetBusFactorAnalysis(projectID, startDate, endDate, repositoryFilter, pathFilter, metric)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":  "Failed to calculate bus factor",
@@ -109,6 +137,7 @@ func GetProjectBusFactor(c *gin.Context) {
 	results := make([]BusFactorResult, 0, len(busFactorData))
 	distribution := make(map[int]int)
 	totalBusFactor := 0
+	totalFragmentation := 0.0
 	highRisk, mediumRisk, lowRisk := 0, 0, 0
 
 	for _, data := range busFactorData {
@@ -116,16 +145,16 @@ func GetProjectBusFactor(c *gin.Context) {
 		busFactor := calculateBusFactor(data.OwnershipDistribution)
 
 		// Determine risk level
-		riskLevelCalc := getRiskLevel(busFactor)
+		riskLevelCalc := string(assessor.BusFactorLevel(busFactor))
 
 		// Filter by risk level if specified
 		if riskLevel != "" && riskLevel != "all" && riskLevelCalc != riskLevel {
 			continue
 		}
 
-		// Count risk levels
+		// Count risk levels; critical files count as high risk
 		switch riskLevelCalc {
-		case "high":
+		case "high", "critical":
 			highRisk++
 		case "medium":
 			mediumRisk++
@@ -136,6 +165,7 @@ func GetProjectBusFactor(c *gin.Context) {
 		// Update distribution
 		distribution[busFactor]++
 		totalBusFactor += busFactor
+		totalFragmentation += data.Fragmentation
 
 		// Convert ownership data
 		topAuthors := make([]AuthorOwnership, 0, 5) // Top 5 authors
@@ -145,6 +175,9 @@ func GetProjectBusFactor(c *gin.Context) {
 			authorOwnership := AuthorOwnership{
 				Author:           ownership.Author,
 				Commits:          ownership.Commits,
+				LinesChanged:     ownership.LinesChanged,
+				LinesAdded:       ownership.LinesAdded,
+				Lines:            ownership.Lines,
 				OwnershipPercent: ownership.OwnershipPercent,
 			}
 			allOwnership = append(allOwnership, authorOwnership)
@@ -163,29 +196,36 @@ func GetProjectBusFactor(c *gin.Context) {
 			LastModified:          data.LastModified,
 			RiskLevel:             riskLevelCalc,
 			TotalCommits:          data.TotalCommits,
+			Fragmentation:         data.Fragmentation,
+			MainDeveloper:         data.MainDeveloper,
+			MainDeveloperShare:    data.MainDeveloperShare,
+			Effort:                data.Effort,
 		}
 
 		results = append(results, result)
 	}
 
 	// Calculate average
-	avgBusFactor := 0.0
+	avgBusFactor, avgFragmentation := 0.0, 0.0
 	if len(results) > 0 {
 		avgBusFactor = float64(totalBusFactor) / float64(len(results))
+		avgFragmentation = totalFragmentation / float64(len(results))
 	}
 
 	// Build response
 	response := BusFactorResponse{
 		Files: results,
 		Summary: BusFactorSummary{
-			TotalFiles:       len(results),
-			HighRiskFiles:    highRisk,
-			MediumRiskFiles:  mediumRisk,
-			LowRiskFiles:     lowRisk,
-			Distribution:     distribution,
-			AverageBusFactor: avgBusFactor,
+			TotalFiles:           len(results),
+			HighRiskFiles:        highRisk,
+			MediumRiskFiles:      mediumRisk,
+			LowRiskFiles:         lowRisk,
+			Distribution:         distribution,
+			AverageBusFactor:     avgBusFactor,
+			AverageFragmentation: avgFragmentation,
 		},
 		ProjectID: projectID,
+		Ownership: metric,
 		DateRange: DateRange{
 			StartDate: startDate,
 			EndDate:   endDate,
@@ -195,6 +235,7 @@ func GetProjectBusFactor(c *gin.Context) {
 			Path:       pathFilter,
 			RiskLevel:  riskLevel,
 		},
+		RiskPolicyVersion: assessor.Version(),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -202,34 +243,9 @@ func GetProjectBusFactor(c *gin.Context) {
 
 // calculateBusFactor determines the minimum number of developers needed for 50% knowledge coverage
 func calculateBusFactor(ownership []models.AuthorOwnership) int {
-	if len(ownership) == 0 {
-		return 0
-	}
-
-	cumulativeOwnership := 0.0
-	busFactor := 0
-
+	shares := make([]float64, 0, len(ownership))
 	for _, owner := range ownership {
-		cumulativeOwnership += owner.OwnershipPercent
-		busFactor++
-
-		// Stop when we reach 50% coverage
-		if cumulativeOwnership >= 50.0 {
-			break
-		}
-	}
-
-	return busFactor
-}
-
-// getRiskLevel categorizes risk based on bus factor
-func getRiskLevel(busFactor int) string {
-	switch busFactor {
-	case 1:
-		return "high"
-	case 2:
-		return "medium"
-	default:
-		return "low"
+		shares = append(shares, owner.OwnershipPercent)
 	}
+	return services.BusFactor(shares)
 }
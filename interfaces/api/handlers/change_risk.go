@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeRiskRequest represents the refs of a change risk assessment
+type ChangeRiskRequest struct {
+	Base string `json:"base" binding:"required"`
+	Head string `json:"head" binding:"required"`
+}
+
+// AssessChangeRisk reports the risk of the files changed on head since it diverged from base:
+// hotspots, bus factor and owners, suggested reviewers and coupled files left out. The
+// ownership query parameter weights owners as in the bus factor endpoint.
+func AssessChangeRisk(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req ChangeRiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "base and head refs are required", "detail": err.Error()})
+		return
+	}
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByCommits)
+	if !ok {
+		return
+	}
+
+	project, err := sqlstore.NewProjectRepository(database.DB).GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found", "detail": err.Error()})
+		return
+	}
+
+	useCase := analytics.NewChangeRiskUseCase(repository.NewAnalyticsRepository(database.DB), git.NewGitService())
+	risk, err := useCase.Assess(id, project.RepoPath, req.Base, req.Head, metric)
+	if errors.Is(err, analytics.ErrInvalidDiff) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to diff refs", "detail": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assess change risk", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, risk)
+}
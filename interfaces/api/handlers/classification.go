@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClassificationRulesRequest represents the body of classification rule updates; rules are
+// applied in order and an empty list restores the defaults
+type ClassificationRulesRequest struct {
+	Rules []ClassificationRuleRequest `json:"rules"`
+}
+
+// ClassificationRuleRequest assigns a category to commits whose message contains one of the
+// keywords or matches the regular expression
+type ClassificationRuleRequest struct {
+	Category string   `json:"category" binding:"required"`
+	Keywords []string `json:"keywords"`
+	Pattern  string   `json:"pattern"`
+}
+
+// newClassificationUseCase wires the classification use case to the database
+func newClassificationUseCase() *analysis.ClassificationUseCase {
+	return analysis.NewClassificationUseCase(
+		sqlstore.NewCommitRepository(database.DB),
+		sqlstore.NewClassificationRuleRepository(database.DB),
+		sqlstore.NewIssueRepository(database.DB),
+	)
+}
+
+// classificationRulesResponse converts rules to their API representation
+func classificationRulesResponse(projectID int, rules []entities.ClassificationRule, isDefault bool) gin.H {
+	response := make([]gin.H, 0, len(rules))
+	for _, rule := range rules {
+		keywords := rule.Keywords
+		if keywords == nil {
+			keywords = []string{}
+		}
+		response = append(response, gin.H{
+			"category": rule.Category,
+			"keywords": keywords,
+			"pattern":  rule.Pattern,
+		})
+	}
+	return gin.H{
+		"project_id": projectID,
+		"default":    isDefault,
+		"rules":      response,
+	}
+}
+
+// GetClassificationRules returns the rules that classify a project's commits
+func GetClassificationRules(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	rules, isDefault, err := newClassificationUseCase().GetRules(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get classification rules", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, classificationRulesResponse(id, rules, isDefault))
+}
+
+// UpdateClassificationRules replaces the rules of a project and reclassifies its commits
+func UpdateClassificationRules(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req ClassificationRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+
+	rules := make([]entities.ClassificationRule, 0, len(req.Rules))
+	for _, r := range req.Rules {
+		rules = append(rules, entities.ClassificationRule{
+			Category: values.CommitCategory(r.Category),
+			Keywords: r.Keywords,
+			Pattern:  r.Pattern,
+		})
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid classification rule", "detail": err.Error()})
+			return
+		}
+	}
+
+	useCase := newClassificationUseCase()
+	reclassified, err := useCase.ReplaceRules(id, rules)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update classification rules", "detail": err.Error()})
+		return
+	}
+	invalidateProjectCache(id)
+
+	rules, isDefault, err := useCase.GetRules(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get classification rules", "detail": err.Error()})
+		return
+	}
+	response := classificationRulesResponse(id, rules, isDefault)
+	response["reclassified_commits"] = reclassified
+	c.JSON(http.StatusOK, response)
+}
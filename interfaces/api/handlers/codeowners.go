@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CodeownersProposalRequest represents the options of a CODEOWNERS proposal. Options left out
+// use the generator's defaults.
+type CodeownersProposalRequest struct {
+	Depth     int     `json:"depth" binding:"min=0"`
+	MinShare  float64 `json:"min_share" binding:"min=0,max=100"`
+	MaxOwners int     `json:"max_owners" binding:"min=0"`
+	// Teams lists a team instead of its members
+	Teams bool `json:"teams"`
+	CodeownersHandles
+}
+
+// CodeownersDriftRequest represents the options of a CODEOWNERS drift check
+type CodeownersDriftRequest struct {
+	StaleAfterMonths int `json:"stale_after_months" binding:"min=0"`
+	CodeownersHandles
+}
+
+// CodeownersHandles maps authors and teams to CODEOWNERS handles. Authors without a handle
+// become @name and teams @org/team-name.
+type CodeownersHandles struct {
+	Org     string            `json:"org"`
+	Handles map[string]string `json:"handles"`
+}
+
+// newCodeownersUseCase wires the CODEOWNERS use case to the database and git
+func newCodeownersUseCase() *analytics.CodeownersUseCase {
+	return analytics.NewCodeownersUseCase(
+		repository.NewAnalyticsRepository(database.DB),
+		sqlstore.NewTeamRepository(database.DB),
+		git.NewGitService(),
+	)
+}
+
+// ProposeCodeowners proposes CODEOWNERS entries at directory granularity from the ownership of
+// the files at HEAD, weighted by the ownership query parameter
+func ProposeCodeowners(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req CodeownersProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByCommits)
+	if !ok {
+		return
+	}
+
+	project, err := sqlstore.NewProjectRepository(database.DB).GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found", "detail": err.Error()})
+		return
+	}
+
+	proposal, err := newCodeownersUseCase().Propose(id, project.RepoPath, metric, services.CodeownersOptions{
+		Depth:     req.Depth,
+		MinShare:  req.MinShare,
+		MaxOwners: req.MaxOwners,
+	}, analytics.CodeownersMapping{Teams: req.Teams, Org: req.Org, Handles: req.Handles})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to propose CODEOWNERS", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, proposal)
+}
+
+// CheckCodeownersDrift reports the rules of the repository's CODEOWNERS file at HEAD whose
+// owners have not touched their files for stale_after_months, cannot be resolved, or own no files
+func CheckCodeownersDrift(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req CodeownersDriftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+
+	project, err := sqlstore.NewProjectRepository(database.DB).GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found", "detail": err.Error()})
+		return
+	}
+
+	mapping := analytics.CodeownersMapping{Org: req.Org, Handles: req.Handles}
+	report, err := newCodeownersUseCase().CheckDrift(id, project.RepoPath, req.StaleAfterMonths, mapping, time.Now())
+	switch {
+	case errors.Is(err, analytics.ErrNoCodeowners):
+		c.JSON(http.StatusNotFound, gin.H{"error": "CODEOWNERS file not found", "detail": err.Error()})
+		return
+	case errors.Is(err, analytics.ErrInvalidCodeowners):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid CODEOWNERS file", "detail": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check CODEOWNERS", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
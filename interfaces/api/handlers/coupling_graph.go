@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProjectCouplingSum returns each file's sum of coupling over the full coupling graph,
+// highest first, to surface architectural hubs
+func GetProjectCouplingSum(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	f := parseCouplingFilters(c)
+
+	cacheKey := fmt.Sprintf("coupling_sum_%d_%s", id, f.cacheKey())
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	sums, err := useCase.GetCouplingSum(id, f.limit, f.startDate, f.endDate, f.minSharedCommits, f.minCouplingScore, f.fileTypes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate sum of coupling", "detail": err.Error()})
+		return
+	}
+
+	result := gin.H{
+		"project_id":   id,
+		"coupling_sum": sums,
+		"params":       f.params(),
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetProjectCouplingClusters returns the communities of co-changing files detected in the
+// coupling graph
+func GetProjectCouplingClusters(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	f := parseCouplingFilters(c)
+	minSize, ok := parsePositiveIntQuery(c, "minClusterSize", 2)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("coupling_clusters_%d_%s_%d", id, f.cacheKey(), minSize)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	report, err := useCase.GetCouplingClusters(id, f.limit, f.startDate, f.endDate, f.minSharedCommits, f.minCouplingScore, f.fileTypes, minSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detect coupling clusters", "detail": err.Error()})
+		return
+	}
+
+	params := f.params()
+	params["minClusterSize"] = minSize
+	result := gin.H{
+		"project_id": id,
+		"clusters":   report,
+		"params":     params,
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseDateQuery reads an optional YYYY-MM-DD query parameter, responding with 400 and
+// returning false when it is malformed
+func parseDateQuery(c *gin.Context, name string) (string, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return "", true
+	}
+	if _, err := time.Parse("2006-01-02", raw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be a date in YYYY-MM-DD format", name)})
+		return "", false
+	}
+	return raw, true
+}
+
+// GetProjectDefectHotspots ranks the files of a project by the number of bug-fix commits that
+// touched them, with their bug-fix ratio and defect density
+func GetProjectDefectHotspots(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	limit, ok := parsePositiveIntQuery(c, "limit", 50)
+	if !ok {
+		return
+	}
+	minBugfixes, ok := parsePositiveIntQuery(c, "minBugfixes", 1)
+	if !ok {
+		return
+	}
+	startDate, ok := parseDateQuery(c, "startDate")
+	if !ok {
+		return
+	}
+	endDate, ok := parseDateQuery(c, "endDate")
+	if !ok {
+		return
+	}
+	path := c.Query("path")
+
+	cacheKey := fmt.Sprintf("defect_hotspots_%d_%d_%d_%s_%s_%s", id, limit, minBugfixes, startDate, endDate, path)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	report, err := useCase.GetDefectHotspots(id, startDate, endDate, path, minBugfixes, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate defect hotspots", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, report)
+	c.JSON(http.StatusOK, report)
+}
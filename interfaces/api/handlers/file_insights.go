@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fileResourceHandlers maps the trailing segment of /projects/:id/files/*path to its handler.
+// Gin only allows a catch-all parameter at the end of a route, so file paths containing
+// slashes are resolved here instead of in the router.
+var fileResourceHandlers = map[string]func(c *gin.Context, projectID int, filePath string){
+	"complexity-trend": getFileComplexityTrend,
+	"issues":           getFileIssues,
+}
+
+// GetProjectFileResource dispatches /projects/:id/files/<file path>/<resource> requests
+func GetProjectFileResource(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	fullPath := strings.Trim(c.Param("path"), "/")
+	separator := strings.LastIndex(fullPath, "/")
+	if separator <= 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File resource not found"})
+		return
+	}
+
+	filePath, resource := fullPath[:separator], fullPath[separator+1:]
+	handler, exists := fileResourceHandlers[resource]
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Unknown file resource: %s", resource)})
+		return
+	}
+
+	handler(c, id, filePath)
+}
+
+// getFileComplexityTrend returns the complexity and LOC series for a single file
+func getFileComplexityTrend(c *gin.Context, projectID int, filePath string) {
+	interval := c.DefaultQuery("interval", analytics.SampleIntervalCommit)
+	if interval != analytics.SampleIntervalCommit && interval != analytics.SampleIntervalWeek && interval != analytics.SampleIntervalMonth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be one of commit, week, month"})
+		return
+	}
+
+	cacheKey := fmt.Sprintf("complexity_trend_%d_%s_%s", projectID, filePath, interval)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	useCase := analytics.NewComplexityTrendUseCase(
+		sqlstore.NewProjectRepository(database.DB),
+		sqlstore.NewCommitRepository(database.DB),
+		sqlstore.NewComplexityRepository(database.DB),
+		git.NewGitService(),
+	)
+
+	trend, err := useCase.GetComplexityTrend(projectID, filePath, interval)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute complexity trend", "detail": err.Error()})
+		return
+	}
+
+	result := gin.H{
+		"project_id":       projectID,
+		"complexity_trend": trend,
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProjectFragmentation ranks the files of a project by how fragmented their development
+// is among authors (fractal value), with each file's main developer
+func GetProjectFragmentation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	limit, ok := parsePositiveIntQuery(c, "limit", 100)
+	if !ok {
+		return
+	}
+	minAuthors, ok := parsePositiveIntQuery(c, "min_authors", 1)
+	if !ok {
+		return
+	}
+	path := c.Query("path")
+
+	cacheKey := fmt.Sprintf("fragmentation_%d_%d_%d_%s", id, limit, minAuthors, path)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	report, err := useCase.GetFragmentation(id, path, minAuthors, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate fragmentation", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, report)
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+	"codeecho/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IssuePatternsRequest replaces the regular expressions extracting issue keys from a
+// project's commit messages; an empty list restores the defaults
+type IssuePatternsRequest struct {
+	Patterns []string `json:"patterns"`
+}
+
+// newIssueUseCase wires the issue use case to the database
+func newIssueUseCase() *analysis.IssueUseCase {
+	return analysis.NewIssueUseCase(
+		sqlstore.NewCommitRepository(database.DB),
+		sqlstore.NewClassificationRuleRepository(database.DB),
+		sqlstore.NewIssueRepository(database.DB),
+	)
+}
+
+// GetIssuePatterns returns the patterns that extract issue keys from a project's commits
+func GetIssuePatterns(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	patterns, isDefault, err := newIssueUseCase().GetPatterns(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get issue patterns", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"project_id": id, "default": isDefault, "patterns": patterns})
+}
+
+// UpdateIssuePatterns replaces the issue key patterns of a project and relinks its commits
+func UpdateIssuePatterns(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req IssuePatternsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+	if _, err := services.CompileIssuePatterns(req.Patterns); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue pattern", "detail": err.Error()})
+		return
+	}
+
+	useCase := newIssueUseCase()
+	linked, err := useCase.ReplacePatterns(id, req.Patterns)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update issue patterns", "detail": err.Error()})
+		return
+	}
+	invalidateProjectCache(id)
+
+	patterns, isDefault, err := useCase.GetPatterns(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get issue patterns", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"project_id": id, "default": isDefault, "patterns": patterns, "linked_issues": linked})
+}
+
+// ImportIssues stores issue metadata such as type and priority for a project, so issues can
+// be described and commits classified without a live tracker. The body is a JSON array of
+// issues or an object with the array under "issues".
+func ImportIssues(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+	issues, err := analysis.ParseIssueImport(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid issue import", "detail": err.Error()})
+		return
+	}
+
+	reclassified, err := newIssueUseCase().ImportIssues(id, issues)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import issues", "detail": err.Error()})
+		return
+	}
+	invalidateProjectCache(id)
+
+	c.JSON(http.StatusOK, gin.H{"project_id": id, "imported": len(issues), "reclassified_commits": reclassified})
+}
+
+// GetProjectIssues lists the issues referenced by a project's commits, optionally of one type
+func GetProjectIssues(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	limit, ok := parsePositiveIntQuery(c, "limit", 100)
+	if !ok {
+		return
+	}
+	issueType := c.Query("type")
+
+	cacheKey := fmt.Sprintf("issues_%d_list_%d_%s", id, limit, issueType)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	useCase := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(database.DB))
+	report, err := useCase.GetIssues(id, issueType, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get issues", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, report)
+	c.JSON(http.StatusOK, report)
+}
+
+// GetIssueCommits returns the commits referencing an issue
+func GetIssueCommits(c *gin.Context) {
+	respondIssueDetail(c, "commits", (*analytics.AnalyticsUseCase).GetIssueCommits)
+}
+
+// GetIssueFiles returns the files changed by the commits referencing an issue
+func GetIssueFiles(c *gin.Context) {
+	respondIssueDetail(c, "files", (*analytics.AnalyticsUseCase).GetIssueFiles)
+}
+
+// GetIssueAuthors returns the authors of the commits referencing an issue
+func GetIssueAuthors(c *gin.Context) {
+	respondIssueDetail(c, "authors", (*analytics.AnalyticsUseCase).GetIssueAuthors)
+}
+
+// respondIssueDetail serves one of the /projects/:id/issues/:key lists
+func respondIssueDetail(c *gin.Context, list string, get func(*analytics.AnalyticsUseCase, int, string) (*models.IssueDetail, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	key := c.Param("key")
+
+	cacheKey := fmt.Sprintf("issues_%d_%s_%s", id, list, key)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	useCase := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(database.DB))
+	detail, err := get(useCase, id, key)
+	if errors.Is(err, analytics.ErrIssueNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No commit references issue %s", key)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get issue %s", list), "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, detail)
+	c.JSON(http.StatusOK, detail)
+}
+
+// getFileIssues returns the issues referenced by the commits that changed a file
+func getFileIssues(c *gin.Context, projectID int, filePath string) {
+	cacheKey := fmt.Sprintf("file_issues_%d_%s", projectID, filePath)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	useCase := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(database.DB))
+	issues, err := useCase.GetFileIssues(projectID, filePath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get file issues", "detail": err.Error()})
+		return
+	}
+
+	result := gin.H{
+		"project_id": projectID,
+		"file_path":  filePath,
+		"issues":     issues,
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateAuthorStatusRequest represents a manual change of an author's activity status
+type UpdateAuthorStatusRequest struct {
+	Author string `json:"author" binding:"required"`
+	// Status is one of "active", "inactive" or "auto" (remove the override)
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// newKnowledgeLossUseCase wires the knowledge loss use case to the database
+func newKnowledgeLossUseCase() *analytics.KnowledgeLossUseCase {
+	return analytics.NewKnowledgeLossUseCase(
+		repository.NewAnalyticsRepository(database.DB),
+		sqlstore.NewAuthorStatusRepository(database.DB),
+	)
+}
+
+// parsePositiveIntQuery reads an optional positive integer query parameter, responding with 400 when it is invalid
+func parsePositiveIntQuery(c *gin.Context, name string, fallback int) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be a positive integer", name)})
+		return 0, false
+	}
+	return value, true
+}
+
+// GetProjectAuthors lists the authors of a project with their activity status
+func GetProjectAuthors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	inactiveAfterMonths, ok := parsePositiveIntQuery(c, "inactive_after_months", analytics.DefaultInactiveAfterMonths)
+	if !ok {
+		return
+	}
+
+	authors, err := newKnowledgeLossUseCase().GetAuthorActivity(id, inactiveAfterMonths, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve authors", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project_id":            id,
+		"inactive_after_months": inactiveAfterMonths,
+		"authors":               authors,
+	})
+}
+
+// UpdateAuthorStatus manually marks an author as active or inactive, or reverts to automatic classification
+func UpdateAuthorStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req UpdateAuthorStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+
+	switch req.Status {
+	case analytics.AuthorStatusActive, analytics.AuthorStatusInactive, analytics.AuthorStatusAuto:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of active, inactive, auto"})
+		return
+	}
+
+	if err := newKnowledgeLossUseCase().SetAuthorStatus(id, req.Author, req.Status, req.Note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update author status", "detail": err.Error()})
+		return
+	}
+
+	invalidateProjectCache(id)
+	c.JSON(http.StatusOK, gin.H{
+		"project_id": id,
+		"author":     req.Author,
+		"status":     req.Status,
+	})
+}
+
+// GetProjectKnowledgeLoss reports which files and directories are mostly owned by inactive authors
+func GetProjectKnowledgeLoss(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	inactiveAfterMonths, ok := parsePositiveIntQuery(c, "inactive_after_months", analytics.DefaultInactiveAfterMonths)
+	if !ok {
+		return
+	}
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByCommits)
+	if !ok {
+		return
+	}
+
+	threshold := analytics.DefaultAbandonedThreshold
+	if v := c.Query("threshold"); v != "" {
+		threshold, err = strconv.ParseFloat(v, 64)
+		if err != nil || threshold <= 0 || threshold > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a percentage between 0 and 100"})
+			return
+		}
+	}
+	path := c.Query("path")
+
+	// Authors become inactive as time passes, so a report is only reused on the day it was made
+	now := time.Now()
+	cacheKey := fmt.Sprintf("knowledge_loss_%d_%d_%s_%.1f_%s_%s", id, inactiveAfterMonths, metric, threshold, path, now.Format("2006-01-02"))
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	report, err := newKnowledgeLossUseCase().GetKnowledgeLoss(id, path, metric, inactiveAfterMonths, threshold, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate knowledge loss", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, report)
+	c.JSON(http.StatusOK, report)
+}
@@ -5,7 +5,7 @@ import (
 	"strconv"
 
 	"codeecho/infrastructure/database"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,7 +20,7 @@ func HealthCheck(c *gin.Context) {
 
 // GetProjects returns all projects
 func GetProjects(c *gin.Context) {
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 
 	projects, err := projectRepo.GetAll()
 	if err != nil {
@@ -59,7 +59,7 @@ func GetProject(c *gin.Context) {
 		return
 	}
 
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	project, err := projectRepo.GetByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -100,7 +100,7 @@ func UpdateProject(c *gin.Context) {
 		return
 	}
 
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	project, err := projectRepo.GetByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -137,7 +137,7 @@ func DeleteProject(c *gin.Context) {
 		return
 	}
 
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	project, err := projectRepo.GetByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
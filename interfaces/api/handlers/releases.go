@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newReleaseUseCase wires the release use case to the database
+func newReleaseUseCase() *analytics.ReleaseUseCase {
+	return analytics.NewReleaseUseCase(
+		sqlstore.NewReleaseRepository(database.DB),
+		repository.NewAnalyticsRepository(database.DB),
+	)
+}
+
+// GetProjectReleases lists the releases ingested from a project's repository tags
+func GetProjectReleases(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	cacheKey := fmt.Sprintf("releases_%d_list", id)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	releases, err := newReleaseUseCase().GetReleases(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get releases", "detail": err.Error()})
+		return
+	}
+
+	result := gin.H{
+		"project_id": id,
+		"releases":   releases,
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// CompareProjectReleases compares the development periods of two releases given as the from
+// and to query parameters, with the coupling filters of the temporal coupling endpoint
+func CompareProjectReleases(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to release names are required"})
+		return
+	}
+	filters := parseCouplingFilters(c)
+
+	cacheKey := fmt.Sprintf("releases_%d_compare_%s_%s_%s", id, from, to, filters.cacheKey())
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	comparison, err := newReleaseUseCase().CompareReleases(id, from, to, filters.limit, filters.minSharedCommits, filters.minCouplingScore)
+	if errors.Is(err, analytics.ErrReleaseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Release not found", "detail": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare releases", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, comparison)
+	c.JSON(http.StatusOK, comparison)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewerRecommendationRequest represents the change to recommend reviewers for. Options left
+// out use the recommender's defaults.
+type ReviewerRecommendationRequest struct {
+	Files               []string       `json:"files" binding:"required,min=1"`
+	Author              string         `json:"author"`
+	Exclude             []string       `json:"exclude"`
+	Load                map[string]int `json:"load"`
+	Count               int            `json:"count" binding:"min=0"`
+	HalfLifeDays        int            `json:"half_life_days" binding:"min=0"`
+	InactiveAfterMonths int            `json:"inactive_after_months" binding:"min=0"`
+	// LoadPenalty may be 0 to ignore review load
+	LoadPenalty *float64 `json:"load_penalty" binding:"omitempty,min=0"`
+}
+
+// RecommendReviewers ranks the reviewers of a set of files by their decaying expertise, leaving
+// out the change's author, excluded and inactive authors, and spreading picks by review load.
+// The ownership query parameter weights expertise as in the bus factor endpoint.
+func RecommendReviewers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req ReviewerRecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByCommits)
+	if !ok {
+		return
+	}
+
+	loadPenalty := services.DefaultReviewLoadPenalty
+	if req.LoadPenalty != nil {
+		loadPenalty = *req.LoadPenalty
+	}
+	useCase := analytics.NewReviewerUseCase(
+		repository.NewAnalyticsRepository(database.DB),
+		sqlstore.NewAuthorStatusRepository(database.DB),
+	)
+	recommendation, err := useCase.Recommend(id, analytics.ReviewerRequest{
+		Files:               req.Files,
+		Author:              req.Author,
+		Exclude:             req.Exclude,
+		InactiveAfterMonths: req.InactiveAfterMonths,
+		Options: services.ReviewerOptions{
+			Count:        req.Count,
+			HalfLifeDays: req.HalfLifeDays,
+			LoadPenalty:  loadPenalty,
+			Load:         req.Load,
+		},
+	}, metric, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recommend reviewers", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendation)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/entities"
+	"codeecho/domain/services"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RiskPolicyRequest represents the body of risk policy updates. Fields left out keep their
+// value from the current policy.
+type RiskPolicyRequest struct {
+	Thresholds               RiskPolicyThresholds `json:"thresholds"`
+	CouplingMinSharedCommits int                  `json:"coupling_min_shared_commits"`
+	DebtWeights              RiskPolicyWeights    `json:"debt_weights"`
+}
+
+// RiskPolicyThresholds holds the risk level thresholds of every rated metric
+type RiskPolicyThresholds struct {
+	ChangeFrequency RiskThresholdValues `json:"change_frequency"`
+	Ownership       RiskThresholdValues `json:"ownership"`
+	BusFactor       RiskThresholdValues `json:"bus_factor"`
+	Coupling        RiskThresholdValues `json:"coupling"`
+}
+
+// RiskThresholdValues are the values at which a metric reaches each risk level; 0 disables a level
+type RiskThresholdValues struct {
+	Medium   float64 `json:"medium"`
+	High     float64 `json:"high"`
+	Critical float64 `json:"critical"`
+}
+
+// RiskPolicyWeights are the relative weights of the debt score components
+type RiskPolicyWeights struct {
+	HotspotComplexity float64 `json:"hotspot_complexity"`
+	Coupling          float64 `json:"coupling"`
+	BusFactorRisk     float64 `json:"bus_factor_risk"`
+	StaleCode         float64 `json:"stale_code"`
+}
+
+// newRiskPolicyUseCase wires the risk policy use case to the database
+func newRiskPolicyUseCase() *analytics.RiskPolicyUseCase {
+	return analytics.NewRiskPolicyUseCase(sqlstore.NewRiskPolicyRepository(database.DB))
+}
+
+// loadRiskAssessor returns the assessor of a project's current risk policy
+func loadRiskAssessor(projectID int) (*services.RiskAssessor, error) {
+	return analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(database.DB)).GetRiskAssessor(projectID)
+}
+
+// riskPolicyRequest converts a policy to the request shape so updates can start from it
+func riskPolicyRequest(policy entities.RiskPolicy) RiskPolicyRequest {
+	thresholds := func(t entities.RiskThresholds) RiskThresholdValues {
+		return RiskThresholdValues{Medium: t.Medium, High: t.High, Critical: t.Critical}
+	}
+	return RiskPolicyRequest{
+		Thresholds: RiskPolicyThresholds{
+			ChangeFrequency: thresholds(policy.ChangeFrequency),
+			Ownership:       thresholds(policy.Ownership),
+			BusFactor:       thresholds(policy.BusFactor),
+			Coupling:        thresholds(policy.Coupling),
+		},
+		CouplingMinSharedCommits: policy.CouplingMinSharedCommits,
+		DebtWeights: RiskPolicyWeights{
+			HotspotComplexity: policy.DebtWeights.HotspotComplexity,
+			Coupling:          policy.DebtWeights.Coupling,
+			BusFactorRisk:     policy.DebtWeights.BusFactorRisk,
+			StaleCode:         policy.DebtWeights.StaleCode,
+		},
+	}
+}
+
+// policy converts the request to a risk policy entity
+func (r RiskPolicyRequest) policy() entities.RiskPolicy {
+	thresholds := func(t RiskThresholdValues) entities.RiskThresholds {
+		return entities.RiskThresholds{Medium: t.Medium, High: t.High, Critical: t.Critical}
+	}
+	return entities.RiskPolicy{
+		ChangeFrequency:          thresholds(r.Thresholds.ChangeFrequency),
+		Ownership:                thresholds(r.Thresholds.Ownership),
+		BusFactor:                thresholds(r.Thresholds.BusFactor),
+		Coupling:                 thresholds(r.Thresholds.Coupling),
+		CouplingMinSharedCommits: r.CouplingMinSharedCommits,
+		DebtWeights: entities.DebtWeights{
+			HotspotComplexity: r.DebtWeights.HotspotComplexity,
+			Coupling:          r.DebtWeights.Coupling,
+			BusFactorRisk:     r.DebtWeights.BusFactorRisk,
+			StaleCode:         r.DebtWeights.StaleCode,
+		},
+	}
+}
+
+// riskPolicyResponse converts a policy to its API representation
+func riskPolicyResponse(policy entities.RiskPolicy, isDefault bool) gin.H {
+	request := riskPolicyRequest(policy)
+	var createdAt *time.Time
+	if !policy.CreatedAt.IsZero() {
+		createdAt = &policy.CreatedAt
+	}
+	return gin.H{
+		"project_id":                  policy.ProjectID,
+		"version":                     policy.Version,
+		"default":                     isDefault,
+		"created_at":                  createdAt,
+		"thresholds":                  request.Thresholds,
+		"coupling_min_shared_commits": request.CouplingMinSharedCommits,
+		"debt_weights":                request.DebtWeights,
+	}
+}
+
+// GetRiskPolicy returns the risk policy that rates a project's metrics
+func GetRiskPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	policy, isDefault, err := newRiskPolicyUseCase().GetPolicy(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risk policy", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, riskPolicyResponse(policy, isDefault))
+}
+
+// UpdateRiskPolicy stores a new version of a project's risk policy. Analytics are rated with it
+// immediately; health snapshots are rebuilt under it in the background.
+func UpdateRiskPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil || !json.Valid(body) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	useCase := newRiskPolicyUseCase()
+	current, _, err := useCase.GetPolicy(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risk policy", "detail": err.Error()})
+		return
+	}
+
+	// The body is decoded over the current policy so that omitted fields keep their value
+	req := riskPolicyRequest(current)
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+	policy := req.policy()
+	if err := policy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid risk policy", "detail": err.Error()})
+		return
+	}
+
+	policy, err = useCase.UpdatePolicy(id, policy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update risk policy", "detail": err.Error()})
+		return
+	}
+	invalidateProjectCache(id)
+	go rebuildHealthSnapshots(id)
+
+	c.JSON(http.StatusOK, riskPolicyResponse(policy, false))
+}
+
+// rebuildHealthSnapshots recomputes every health snapshot of a project under its current risk
+// policy. It reads a tree from git for each month of history, so policy updates run it in the
+// background.
+func rebuildHealthSnapshots(projectID int) {
+	project, err := sqlstore.NewProjectRepository(database.DB).GetByID(projectID)
+	if err != nil {
+		log.Printf("Failed to rebuild health snapshots for project %d: %v", projectID, err)
+		return
+	}
+
+	useCase := analysis.NewHealthSnapshotUseCase(
+		sqlstore.NewCommitRepository(database.DB),
+		sqlstore.NewChangeRepository(database.DB),
+		sqlstore.NewHealthSnapshotRepository(database.DB),
+		sqlstore.NewRiskPolicyRepository(database.DB),
+		git.NewGitService(),
+	)
+	if _, err := useCase.RecordSnapshots(projectID, project.RepoPath, true); err != nil {
+		log.Printf("Failed to rebuild health snapshots for project %d: %v", projectID, err)
+		return
+	}
+	invalidateProjectCache(projectID)
+}
+
+// GetRiskPolicyVersions lists every stored version of a project's risk policy, newest first
+func GetRiskPolicyVersions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	versions, err := newRiskPolicyUseCase().GetVersions(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risk policy versions", "detail": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(versions))
+	for _, policy := range versions {
+		response = append(response, riskPolicyResponse(policy, false))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"project_id": id,
+		"versions":   response,
+	})
+}
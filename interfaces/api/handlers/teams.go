@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"codeecho/application/usecases/analytics"
+	teamusecase "codeecho/application/usecases/team"
+	"codeecho/domain/entities"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamRequest represents the body of team create and update requests
+type TeamRequest struct {
+	Name        string              `json:"name" binding:"required"`
+	Description string              `json:"description"`
+	Members     []TeamMemberRequest `json:"members"`
+}
+
+// TeamMemberRequest represents a team member and the author identities they commit under
+type TeamMemberRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	Identities []string `json:"identities"`
+}
+
+// toMembers converts request members to domain members
+func (r TeamRequest) toMembers() []entities.TeamMember {
+	members := make([]entities.TeamMember, 0, len(r.Members))
+	for _, m := range r.Members {
+		members = append(members, entities.TeamMember{Name: m.Name, Identities: m.Identities})
+	}
+	return members
+}
+
+// teamResponse converts a team to its API representation
+func teamResponse(team *entities.Team) gin.H {
+	members := make([]gin.H, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, gin.H{
+			"name":       m.Name,
+			"identities": m.Identities,
+		})
+	}
+	return gin.H{
+		"id":          team.ID,
+		"name":        team.Name,
+		"description": team.Description,
+		"members":     members,
+		"created_at":  team.CreatedAt,
+	}
+}
+
+// newTeamUseCase wires the team use case to the database
+func newTeamUseCase() *teamusecase.TeamUseCase {
+	return teamusecase.NewTeamUseCase(sqlstore.NewTeamRepository(database.DB))
+}
+
+// newTeamAnalysisUseCase wires the team analysis use case to the database
+func newTeamAnalysisUseCase() *analytics.TeamAnalysisUseCase {
+	return analytics.NewTeamAnalysisUseCase(
+		repository.NewAnalyticsRepository(database.DB),
+		sqlstore.NewTeamRepository(database.DB),
+	)
+}
+
+// invalidateTeamCache removes the cached team views of every project, which group authors by
+// the team definitions
+func invalidateTeamCache() {
+	cache.deletePrefixed("team_ownership_", "team_coupling_")
+}
+
+// teamErrorStatus maps team use case errors to HTTP status codes
+func teamErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "required"), strings.Contains(err.Error(), "already belongs"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetTeams returns all teams
+func GetTeams(c *gin.Context) {
+	teams, err := newTeamUseCase().GetAllTeams()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(teams))
+	for _, team := range teams {
+		response = append(response, teamResponse(team))
+	}
+	c.JSON(http.StatusOK, gin.H{"teams": response})
+}
+
+// GetTeam returns a specific team
+func GetTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	team, err := newTeamUseCase().GetTeamByID(id)
+	if err != nil {
+		c.JSON(teamErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, teamResponse(team))
+}
+
+// CreateTeam creates a new team
+func CreateTeam(c *gin.Context) {
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+
+	team, err := newTeamUseCase().CreateTeam(req.Name, req.Description, req.toMembers())
+	if err != nil {
+		c.JSON(teamErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	invalidateTeamCache()
+	c.JSON(http.StatusCreated, teamResponse(team))
+}
+
+// UpdateTeam replaces a team's details and members
+func UpdateTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "detail": err.Error()})
+		return
+	}
+
+	team, err := newTeamUseCase().UpdateTeam(id, req.Name, req.Description, req.toMembers())
+	if err != nil {
+		c.JSON(teamErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	invalidateTeamCache()
+	c.JSON(http.StatusOK, teamResponse(team))
+}
+
+// DeleteTeam removes a team
+func DeleteTeam(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := newTeamUseCase().DeleteTeam(id); err != nil {
+		c.JSON(teamErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	invalidateTeamCache()
+	c.JSON(http.StatusOK, gin.H{"message": "Team deleted successfully"})
+}
+
+// getTeamFileOwnership serves the team view of /projects/:id/file-ownership
+func getTeamFileOwnership(c *gin.Context, projectID int, metric values.OwnershipMetric) {
+	depth, ok := parsePositiveIntQuery(c, "depth", 1)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("team_ownership_%d_%s_%d", projectID, metric, depth)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	report, err := newTeamAnalysisUseCase().GetTeamOwnership(projectID, metric, depth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team ownership", "detail": err.Error()})
+		return
+	}
+
+	result := gin.H{
+		"projectId":     projectID,
+		"ownership":     metric,
+		"view":          "team",
+		"teamOwnership": report,
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetProjectTeamCoupling returns temporally coupled file pairs annotated with owning teams,
+// highlighting pairs that cross team boundaries
+func GetProjectTeamCoupling(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	metric, ok := parseOwnershipMetric(c, values.OwnershipByLinesChanged)
+	if !ok {
+		return
+	}
+	f := parseCouplingFilters(c)
+
+	cacheKey := fmt.Sprintf("team_coupling_%d_%s_%s", id, metric, f.cacheKey())
+	if cached, exists := cache.get(cacheKey); exists {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	report, err := newTeamAnalysisUseCase().GetTeamCoupling(id, metric, f.limit, f.startDate, f.endDate, f.minSharedCommits, f.minCouplingScore, f.fileTypes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team coupling", "detail": err.Error()})
+		return
+	}
+
+	result := gin.H{
+		"project_id":    id,
+		"team_coupling": report,
+		"params":        f.params(),
+	}
+	cache.set(cacheKey, result)
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codeecho/infrastructure/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useTestDatabase points the handlers at a new SQLite database for the duration of a test
+func useTestDatabase(t *testing.T) {
+	t.Helper()
+	db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "codeecho.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		db.Close()
+	})
+}
+
+func TestTeamMutationsInvalidateTeamViews(t *testing.T) {
+	useTestDatabase(t)
+	clearCache()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/teams", CreateTeam)
+	router.PUT("/teams/:id", UpdateTeam)
+	router.DELETE("/teams/:id", DeleteTeam)
+
+	requests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodPost, "/teams", `{"name": "core", "members": [{"name": "Ann", "identities": ["ann"]}]}`, http.StatusCreated},
+		{http.MethodPut, "/teams/1", `{"name": "core", "members": [{"name": "Bob", "identities": ["bob"]}]}`, http.StatusOK},
+		{http.MethodDelete, "/teams/1", "", http.StatusOK},
+	}
+	for _, r := range requests {
+		cache.set("team_ownership_1_lines_changed_1", "ownership")
+		cache.set("team_coupling_7_lines_changed_100", "coupling")
+		cache.set("hotspots_1_page_1", "hotspots")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(r.method, r.path, strings.NewReader(r.body)))
+		if w.Code != r.status {
+			t.Fatalf("%s %s: expected %d, got %d: %s", r.method, r.path, r.status, w.Code, w.Body.String())
+		}
+
+		for _, key := range []string{"team_ownership_1_lines_changed_1", "team_coupling_7_lines_changed_100"} {
+			if _, exists := cache.get(key); exists {
+				t.Errorf("%s %s: expected %s to be invalidated", r.method, r.path, key)
+			}
+		}
+		if _, exists := cache.get("hotspots_1_page_1"); !exists {
+			t.Errorf("%s %s: expected the cached hotspots to be kept", r.method, r.path)
+		}
+	}
+}
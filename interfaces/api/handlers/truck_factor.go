@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProjectTruckFactor returns the project-wide truck factor, the ordered key developers,
+// the coverage curve and per-directory truck factors
+func GetProjectTruckFactor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	depth, ok := parsePositiveIntQuery(c, "depth", 1)
+	if !ok {
+		return
+	}
+
+	coverage := services.DefaultTruckFactorCoverage
+	if v := c.Query("coverage"); v != "" {
+		coverage, err = strconv.ParseFloat(v, 64)
+		if err != nil || coverage <= 0 || coverage > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "coverage must be a fraction between 0 and 1"})
+			return
+		}
+	}
+	path := c.Query("path")
+
+	cacheKey := fmt.Sprintf("truck_factor_%d_%d_%.2f_%s", id, depth, coverage, path)
+	if cached, exists := cache.get(cacheKey); exists {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+	c.Header("X-Cache", "MISS")
+
+	repo := repository.NewAnalyticsRepository(database.DB)
+	useCase := analytics.NewAnalyticsUseCase(repo)
+	report, err := useCase.GetTruckFactor(id, path, depth, coverage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate truck factor", "detail": err.Error()})
+		return
+	}
+
+	cache.set(cacheKey, report)
+	c.JSON(http.StatusOK, report)
+}
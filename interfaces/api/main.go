@@ -1,13 +1,16 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"os"
 
+	"codeecho/application/usecases/analysis"
 	"codeecho/application/usecases/project"
 	"codeecho/infrastructure/database"
 	"codeecho/infrastructure/git"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 	infraServices "codeecho/infrastructure/services"
 	"codeecho/interfaces/api/handlers"
 	"codeecho/interfaces/api/middleware"
@@ -20,6 +23,8 @@ func main() {
 	if err := database.InitDB(); err != nil {
 		log.Printf("Failed to initialize database: %v", err)
 		log.Println("Continuing with fallback to mock data...")
+	} else if os.Getenv("DB_AUTO_MIGRATE") == "true" {
+		migrateDB()
 	}
 	defer database.CloseDB()
 
@@ -28,7 +33,7 @@ func main() {
 
 	// Initialize enhanced project capabilities
 	gitService := git.NewGitService()
-	projectRepo := mysql.NewProjectRepository(database.DB)
+	projectRepo := sqlstore.NewProjectRepository(database.DB)
 	createProjectUseCase := project.NewCreateProjectUseCase(projectRepo, gitService)
 	enhancedProjectHandler := handlers.NewProjectHandler(createProjectUseCase)
 
@@ -81,6 +86,13 @@ func main() {
 			protected.GET("/upload/:id", uploadHandler.GetUploadInfo)
 			protected.DELETE("/upload/:id", uploadHandler.CleanupUpload)
 
+			// Teams
+			protected.GET("/teams", handlers.GetTeams)
+			protected.POST("/teams", handlers.CreateTeam)
+			protected.GET("/teams/:id", handlers.GetTeam)
+			protected.PUT("/teams/:id", handlers.UpdateTeam)
+			protected.DELETE("/teams/:id", handlers.DeleteTeam)
+
 			// Commits
 			protected.GET("/projects/:id/commits", handlers.GetProjectCommits)
 			protected.GET("/commits/:id", handlers.GetCommit)
@@ -94,8 +106,30 @@ func main() {
 			protected.GET("/projects/:id/author-hotspots", handlers.GetAuthorHotspots)
 			protected.GET("/projects/:id/knowledge-risk", handlers.GetProjectKnowledgeRisk)
 			protected.GET("/projects/:id/temporal-coupling", handlers.GetProjectTemporalCoupling)
+			protected.GET("/projects/:id/coupling/sum", handlers.GetProjectCouplingSum)
+			protected.GET("/projects/:id/coupling/clusters", handlers.GetProjectCouplingClusters)
 			protected.GET("/projects/:id/file-types", handlers.GetProjectFileTypes)
+			protected.GET("/projects/:id/languages", handlers.GetProjectLanguages)
+			protected.GET("/projects/:id/code-age", handlers.GetProjectCodeAge)
 			protected.GET("/projects/:id/bus-factor", handlers.GetProjectBusFactor)
+			protected.GET("/projects/:id/knowledge-loss", handlers.GetProjectKnowledgeLoss)
+			protected.GET("/projects/:id/truck-factor", handlers.GetProjectTruckFactor)
+			protected.GET("/projects/:id/fragmentation", handlers.GetProjectFragmentation)
+			protected.GET("/projects/:id/defect-hotspots", handlers.GetProjectDefectHotspots)
+			protected.GET("/projects/:id/issues", handlers.GetProjectIssues)
+			protected.GET("/projects/:id/issues/:key/commits", handlers.GetIssueCommits)
+			protected.GET("/projects/:id/issues/:key/files", handlers.GetIssueFiles)
+			protected.GET("/projects/:id/issues/:key/authors", handlers.GetIssueAuthors)
+			protected.GET("/projects/:id/releases", handlers.GetProjectReleases)
+			protected.GET("/projects/:id/compare", handlers.CompareProjectReleases)
+			protected.GET("/projects/:id/team-coupling", handlers.GetProjectTeamCoupling)
+			protected.POST("/projects/:id/change-risk", handlers.AssessChangeRisk)
+			protected.POST("/projects/:id/reviewers", handlers.RecommendReviewers)
+			protected.POST("/projects/:id/codeowners/proposal", handlers.ProposeCodeowners)
+			protected.POST("/projects/:id/codeowners/drift", handlers.CheckCodeownersDrift)
+			protected.GET("/projects/:id/authors", handlers.GetProjectAuthors)
+			protected.PUT("/projects/:id/authors/status", handlers.UpdateAuthorStatus)
+			protected.GET("/projects/:id/files/*path", handlers.GetProjectFileResource)
 			protected.GET("/temporal-coupling", handlers.GetTemporalCouplingFlat)
 			protected.GET("/dashboard/stats", handlers.GetDashboardStats)
 
@@ -104,6 +138,14 @@ func main() {
 			protected.POST("/projects/:id/refresh", handlers.RefreshProjectAnalysis)
 			protected.POST("/projects/:id/cancel-analysis", handlers.CancelAnalysis)
 			protected.GET("/projects/:id/analysis-status", handlers.GetProjectAnalysisStatus)
+			protected.GET("/projects/:id/classification-rules", handlers.GetClassificationRules)
+			protected.PUT("/projects/:id/classification-rules", handlers.UpdateClassificationRules)
+			protected.GET("/projects/:id/issue-patterns", handlers.GetIssuePatterns)
+			protected.PUT("/projects/:id/issue-patterns", handlers.UpdateIssuePatterns)
+			protected.GET("/projects/:id/risk-policy", handlers.GetRiskPolicy)
+			protected.PUT("/projects/:id/risk-policy", handlers.UpdateRiskPolicy)
+			protected.GET("/projects/:id/risk-policy/versions", handlers.GetRiskPolicyVersions)
+			protected.POST("/projects/:id/issues/import", handlers.ImportIssues)
 
 			// Project Upload (if needed for future use)
 
@@ -116,3 +158,41 @@ func main() {
 	log.Println("Starting CodeEcho API server on :8080")
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
+
+// migrateDB applies the pending schema migrations and backfills the daily file statistics. When
+// the applied migrations differ from this build's, e.g. while an older and a newer release run
+// side by side, nothing is migrated and the server keeps running on the schema it finds; any
+// other migration failure stops the server, as it cannot work on a schema it does not know.
+func migrateDB() {
+	migrator, err := database.NewMigrator(database.DB)
+	if err != nil {
+		log.Fatalf("Failed to load the schema migrations: %v", err)
+	}
+	applied, err := migrator.Up()
+	for _, migration := range applied {
+		log.Printf("Applied schema migration %03d_%s", migration.Version, migration.Name)
+	}
+	if errors.Is(err, database.ErrMigrationsDiverged) {
+		log.Printf("Skipping schema migrations: %v", err)
+		log.Println("See 'codeecho-cli migrate status' and the Database Migrations section of README.DDD.md to recover")
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to migrate the database: %v", err)
+	}
+
+	// Projects analysed before the daily file statistics were kept get them before serving, as
+	// the ownership and hotspot analytics read nothing else
+	rebuild := analysis.NewRebuildUseCase(
+		sqlstore.NewProjectRepository(database.DB),
+		sqlstore.NewCouplingRepository(database.DB),
+		sqlstore.NewFileStatsRepository(database.DB),
+	)
+	rebuilt, err := rebuild.BackfillFileStats()
+	if len(rebuilt) > 0 {
+		log.Printf("Backfilled the daily file statistics of projects %v", rebuilt)
+	}
+	if err != nil {
+		log.Printf("Error backfilling daily file statistics: %v", err)
+	}
+}
@@ -1,15 +1,13 @@
 package commands
 
 import (
-	"database/sql"
 	"fmt"
 
 	"codeecho/application/usecases/analysis"
 	"codeecho/domain/entities"
 	"codeecho/infrastructure/database"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/spf13/cobra"
 )
 
@@ -39,7 +37,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Database DSN: %s\n", dbDSN)
 
 	// Initialize database connection
-	db, err := sql.Open("mysql", dbDSN)
+	db, err := database.Open(dbDSN)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -56,7 +54,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	database.DB = db
 
 	// Initialize repositories
-	projectRepo := mysql.NewProjectRepository(db)
+	projectRepo := sqlstore.NewProjectRepository(db)
 
 	// Initialize use case
 	analysisUseCase := analysis.NewProjectAnalysisUseCase(projectRepo)
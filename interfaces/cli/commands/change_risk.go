@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	changeRiskBase      string
+	changeRiskHead      string
+	changeRiskOwnership string
+	changeRiskJSON      bool
+
+	changeRiskCmd = &cobra.Command{
+		Use:   "change-risk",
+		Short: "Assess the risk of the changes between two refs",
+		Long: "Report the files changed on --head since it diverged from --base: which are hotspots, their " +
+			"bus factor and owners, the owners to ask for review, and the files that usually change with " +
+			"them but were left out.",
+		RunE: runChangeRisk,
+	}
+)
+
+func init() {
+	changeRiskCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project (required)")
+	changeRiskCmd.Flags().StringVar(&changeRiskBase, "base", "", "Base ref, e.g. main (required)")
+	changeRiskCmd.Flags().StringVar(&changeRiskHead, "head", "HEAD", "Head ref")
+	changeRiskCmd.Flags().StringVar(&changeRiskOwnership, "ownership", string(values.OwnershipByCommits), "Ownership metric: commits, lines_changed or blame")
+	changeRiskCmd.Flags().BoolVar(&changeRiskJSON, "json", false, "Print the assessment as JSON")
+	changeRiskCmd.MarkFlagRequired("project-id")
+	changeRiskCmd.MarkFlagRequired("base")
+}
+
+func runChangeRisk(cmd *cobra.Command, args []string) error {
+	metric, err := values.ParseOwnershipMetric(changeRiskOwnership, values.OwnershipByCommits)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Open(dbDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	database.DB = db
+
+	project, err := sqlstore.NewProjectRepository(db).GetByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	useCase := analytics.NewChangeRiskUseCase(repository.NewAnalyticsRepository(db), git.NewGitService())
+	risk, err := useCase.Assess(projectID, project.RepoPath, changeRiskBase, changeRiskHead, metric)
+	if err != nil {
+		return err
+	}
+
+	if changeRiskJSON {
+		output, err := json.MarshalIndent(risk, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Printf("\n=== Change Risk %s...%s: %s (risk policy v%d) ===\n", risk.Base, risk.Head, strings.ToUpper(risk.RiskLevel), risk.RiskPolicyVersion)
+	fmt.Printf("%-50s %8s %8s %10s %-30s\n", "File", "Changes", "Bus", "Risk", "Owners")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, file := range risk.Files {
+		owners := make([]string, 0, len(file.Owners))
+		for _, owner := range file.Owners {
+			owners = append(owners, fmt.Sprintf("%s %.0f%%", owner.Author, owner.OwnershipPercent))
+		}
+		fmt.Printf("%-50s %8d %8d %10s %-30s\n",
+			truncateString(file.FilePath, 50),
+			file.ChangeCount,
+			file.BusFactor,
+			file.RiskLevel,
+			strings.Join(owners, ", "),
+		)
+	}
+	fmt.Printf("\n%d files changed, %d hotspots, %d with a low bus factor\n",
+		risk.Summary.FilesChanged, risk.Summary.Hotspots, risk.Summary.LowBusFactorFiles)
+
+	if len(risk.SuggestedReviewers) > 0 {
+		fmt.Println("\nSuggested reviewers:")
+		for _, reviewer := range risk.SuggestedReviewers {
+			fmt.Printf("  %-30s %d file(s)\n", reviewer.Author, len(reviewer.Files))
+		}
+	}
+	if len(risk.MissingCoupledFiles) > 0 {
+		fmt.Println("\nUsually changed together but not changed:")
+		for _, file := range risk.MissingCoupledFiles {
+			fmt.Printf("  %s (with %s, %d shared commits, %.0f%%)\n",
+				file.FilePath, file.CoupledWith, file.SharedCommits, file.CouplingScore*100)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	codeownersOrg       string
+	codeownersHandles   map[string]string
+	codeownersDepth     int
+	codeownersMinShare  float64
+	codeownersMaxOwners int
+	codeownersTeams     bool
+	codeownersOwnership string
+	codeownersOutput    string
+	codeownersMonths    int
+	codeownersJSON      bool
+
+	codeownersCmd = &cobra.Command{
+		Use:   "codeowners",
+		Short: "Propose a CODEOWNERS file or check an existing one for drift",
+		Long: "Authors become @name and teams @org/team-name in CODEOWNERS; use --handle to map " +
+			"authors or teams to other handles, e.g. --handle \"Jane Doe=@jdoe,Platform=@acme/infra\".",
+	}
+
+	codeownersGenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Propose CODEOWNERS entries from ownership",
+		Long: "Propose a CODEOWNERS entry for every directory at --depth, listing up to --max-owners owners " +
+			"holding at least --min-share percent of the directory's files. With --teams, team members' " +
+			"ownership goes to their team.",
+		RunE: runCodeownersGenerate,
+	}
+
+	codeownersCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Report CODEOWNERS rules whose owners no longer work on their files",
+		Long: "Parse the repository's CODEOWNERS file at HEAD and report the rules whose owners have not " +
+			"touched the files they own for --months, that name unknown owners, or that own no files.",
+		RunE: runCodeownersCheck,
+	}
+)
+
+func init() {
+	codeownersCmd.PersistentFlags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project (required)")
+	codeownersCmd.PersistentFlags().StringVar(&codeownersOrg, "org", "", "Organization of team handles (@org/team-name)")
+	codeownersCmd.PersistentFlags().StringToStringVar(&codeownersHandles, "handle", nil, "Handles of authors or teams, e.g. \"Jane Doe=@jdoe\"")
+	codeownersCmd.PersistentFlags().BoolVar(&codeownersJSON, "json", false, "Print the result as JSON")
+	codeownersCmd.MarkPersistentFlagRequired("project-id")
+
+	codeownersGenerateCmd.Flags().IntVar(&codeownersDepth, "depth", services.DefaultCodeownersDepth, "Directory depth of the entries")
+	codeownersGenerateCmd.Flags().Float64Var(&codeownersMinShare, "min-share", services.DefaultCodeownersMinShare, "Minimum ownership share (percent) of a directory to be listed")
+	codeownersGenerateCmd.Flags().IntVar(&codeownersMaxOwners, "max-owners", services.DefaultCodeownersMaxOwners, "Maximum number of owners per entry")
+	codeownersGenerateCmd.Flags().BoolVar(&codeownersTeams, "teams", false, "List teams instead of their members")
+	codeownersGenerateCmd.Flags().StringVar(&codeownersOwnership, "ownership", string(values.OwnershipByCommits), "Ownership metric: commits, lines_changed or blame")
+	codeownersGenerateCmd.Flags().StringVarP(&codeownersOutput, "output", "o", "", "Write the proposed CODEOWNERS file here instead of stdout")
+	codeownersCmd.AddCommand(codeownersGenerateCmd)
+
+	codeownersCheckCmd.Flags().IntVar(&codeownersMonths, "months", analytics.DefaultCodeownersStaleMonths, "Months without touching their files after which owners are stale")
+	codeownersCmd.AddCommand(codeownersCheckCmd)
+}
+
+// openCodeownersUseCase connects to the database and wires the CODEOWNERS use case
+func openCodeownersUseCase() (*analytics.CodeownersUseCase, *sql.DB, error) {
+	db, err := openRebuildDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	useCase := analytics.NewCodeownersUseCase(repository.NewAnalyticsRepository(db), sqlstore.NewTeamRepository(db), git.NewGitService())
+	return useCase, db, nil
+}
+
+func runCodeownersGenerate(cmd *cobra.Command, args []string) error {
+	metric, err := values.ParseOwnershipMetric(codeownersOwnership, values.OwnershipByCommits)
+	if err != nil {
+		return err
+	}
+	if codeownersDepth < 1 || codeownersMaxOwners < 1 || codeownersMinShare <= 0 || codeownersMinShare > 100 {
+		return fmt.Errorf("--depth and --max-owners must be positive and --min-share between 0 and 100")
+	}
+
+	useCase, db, err := openCodeownersUseCase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	project, err := sqlstore.NewProjectRepository(db).GetByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	proposal, err := useCase.Propose(projectID, project.RepoPath, metric, services.CodeownersOptions{
+		Depth:     codeownersDepth,
+		MinShare:  codeownersMinShare,
+		MaxOwners: codeownersMaxOwners,
+	}, analytics.CodeownersMapping{Teams: codeownersTeams, Org: codeownersOrg, Handles: codeownersHandles})
+	if err != nil {
+		return err
+	}
+
+	if codeownersJSON {
+		output, err := json.MarshalIndent(proposal, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+	if codeownersOutput != "" {
+		if err := os.WriteFile(codeownersOutput, []byte(proposal.Content), 0o644); err != nil {
+			return fmt.Errorf("failed to write CODEOWNERS: %w", err)
+		}
+		fmt.Printf("Wrote %d entries to %s\n", len(proposal.Entries), codeownersOutput)
+		return nil
+	}
+	fmt.Print(proposal.Content)
+	return nil
+}
+
+func runCodeownersCheck(cmd *cobra.Command, args []string) error {
+	if codeownersMonths < 1 {
+		return fmt.Errorf("--months must be positive")
+	}
+
+	useCase, db, err := openCodeownersUseCase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	project, err := sqlstore.NewProjectRepository(db).GetByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	mapping := analytics.CodeownersMapping{Org: codeownersOrg, Handles: codeownersHandles}
+	report, err := useCase.CheckDrift(projectID, project.RepoPath, codeownersMonths, mapping, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if codeownersJSON {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Printf("\n=== %s: %d of %d rules drifted (owners stale after %d months) ===\n",
+		report.Path, len(report.DriftedRules), report.Rules, report.StaleAfterMonths)
+	for _, rule := range report.DriftedRules {
+		fmt.Printf("\nline %d: %s %s (%d files)\n", rule.Line, rule.Pattern, strings.Join(rule.Owners, " "), rule.Files)
+		if rule.Files == 0 {
+			fmt.Println("  owns no files at HEAD")
+		}
+		for _, owner := range rule.StaleOwners {
+			if owner.LastTouched == nil {
+				fmt.Printf("  %s never touched these files\n", owner.Owner)
+			} else {
+				fmt.Printf("  %s last touched these files on %s\n", owner.Owner, owner.LastTouched.Format("2006-01-02"))
+			}
+		}
+		for _, owner := range rule.UnknownOwners {
+			fmt.Printf("  %s is not a known author or team\n", owner)
+		}
+		if len(rule.RecentAuthors) > 0 {
+			fmt.Printf("  recently changed by: %s\n", strings.Join(rule.RecentAuthors, ", "))
+		}
+	}
+	return nil
+}
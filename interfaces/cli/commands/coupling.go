@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	couplingLevel         string
+	couplingDepth         int
+	couplingLayers        []string
+	couplingGroupBy       string
+	couplingWindowMinutes int
+	couplingIssuePattern  string
+	couplingMinShared     int
+	couplingMinScore      float64
+	couplingFileTypes     string
+	couplingStartDate     string
+	couplingEndDate       string
+	couplingLimit         int
+
+	couplingCmd = &cobra.Command{
+		Use:   "coupling",
+		Short: "Analyze temporal coupling",
+		Long: "List files, directories or architectural layers that change together. Commits can be grouped " +
+			"into logical change sets by author time window or by referenced issue key.",
+		RunE: runCoupling,
+	}
+)
+
+func init() {
+	couplingCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project to analyze (required)")
+	couplingCmd.Flags().StringVar(&couplingLevel, "level", string(values.CouplingByFile), "Coupling level: file, directory or layer")
+	couplingCmd.Flags().IntVar(&couplingDepth, "depth", 1, "Directory depth for --level directory")
+	couplingCmd.Flags().StringArrayVar(&couplingLayers, "layer", nil, "Layer definition name:glob[,glob] for --level layer (repeatable)")
+	couplingCmd.Flags().StringVar(&couplingGroupBy, "group-by", string(values.ChangeSetByCommit), "Change sets: commit, window (same author within --window-minutes) or ticket (same issue key)")
+	couplingCmd.Flags().IntVar(&couplingWindowMinutes, "window-minutes", int(services.DefaultChangeSetWindow/time.Minute), "Time window for --group-by window")
+	couplingCmd.Flags().StringVar(&couplingIssuePattern, "issue-pattern", "", "Issue key regexp for --group-by ticket (default Jira-style KEY-123)")
+	couplingCmd.Flags().IntVar(&couplingMinShared, "min-shared", 2, "Minimum number of shared change sets")
+	couplingCmd.Flags().Float64Var(&couplingMinScore, "min-score", 0, "Minimum coupling score (0-1)")
+	couplingCmd.Flags().StringVar(&couplingFileTypes, "file-types", "", "Comma-separated file extensions, e.g. go,js")
+	couplingCmd.Flags().StringVar(&couplingStartDate, "start-date", "", "Only include commits from this date (YYYY-MM-DD)")
+	couplingCmd.Flags().StringVar(&couplingEndDate, "end-date", "", "Only include commits up to this date (YYYY-MM-DD)")
+	couplingCmd.Flags().IntVar(&couplingLimit, "limit", 20, "Maximum number of pairs to show")
+	couplingCmd.MarkFlagRequired("project-id")
+}
+
+func runCoupling(cmd *cobra.Command, args []string) error {
+	options, err := couplingOptionsFromFlags()
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Open(dbDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	database.DB = db
+
+	useCase := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(db))
+	pairs, err := useCase.GetLevelTemporalCoupling(projectID, options, couplingLimit, couplingStartDate, couplingEndDate, couplingMinShared, couplingMinScore, couplingFileTypes)
+	if err != nil {
+		return fmt.Errorf("failed to get temporal coupling: %w", err)
+	}
+
+	if len(pairs) == 0 {
+		fmt.Println("No coupled pairs found for this project.")
+		return nil
+	}
+
+	fmt.Printf("\n=== Temporal Coupling (level: %s, change sets: %s) ===\n", options.Level, options.ChangeSets.Mode)
+	fmt.Printf("%-40s %-40s %8s %8s\n", "A", "B", "Shared", "Score")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, pair := range pairs {
+		fmt.Printf("%-40s %-40s %8d %7.0f%%\n",
+			truncateString(pair.FileA, 40),
+			truncateString(pair.FileB, 40),
+			pair.SharedCommits,
+			pair.CouplingScore*100,
+		)
+	}
+	fmt.Printf("\nTotal pairs shown: %d\n", len(pairs))
+
+	return nil
+}
+
+// couplingOptionsFromFlags validates the level and change set flags of the coupling command
+func couplingOptionsFromFlags() (analytics.CouplingOptions, error) {
+	level, err := values.ParseCouplingLevel(couplingLevel, values.CouplingByFile)
+	if err != nil {
+		return analytics.CouplingOptions{}, err
+	}
+	options := analytics.CouplingOptions{Level: level, Depth: couplingDepth}
+	for _, definition := range couplingLayers {
+		layer, err := services.ParseArchitectureLayer(definition)
+		if err != nil {
+			return options, err
+		}
+		options.Layers = append(options.Layers, layer)
+	}
+
+	mode, err := values.ParseChangeSetMode(couplingGroupBy, values.ChangeSetByCommit)
+	if err != nil {
+		return options, err
+	}
+	options.ChangeSets = services.ChangeSetGrouping{
+		Mode:   mode,
+		Window: time.Duration(couplingWindowMinutes) * time.Minute,
+	}
+	if couplingIssuePattern != "" {
+		options.ChangeSets.IssuePattern, err = regexp.Compile(couplingIssuePattern)
+		if err != nil {
+			return options, fmt.Errorf("invalid issue pattern: %w", err)
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/entities"
+	"codeecho/domain/services"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+	"codeecho/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	gateRulesPath  string
+	gateReportPath string
+
+	gateCmd = &cobra.Command{
+		Use:   "gate",
+		Short: "Evaluate quality gate rules for CI",
+		Long: "Run an incremental analysis of a repository and evaluate quality gate rules against the " +
+			"state before it. Prints a JSON report and exits non-zero when a rule is violated. Without " +
+			"--rules, the top 10 hotspots must not grow by more than 20% and no top-level directories " +
+			"may become coupled above the risk policy's high coupling threshold.",
+		RunE: runGate,
+	}
+)
+
+func init() {
+	gateCmd.Flags().StringVarP(&projectName, "project-name", "n", "", "Name of the project (required)")
+	gateCmd.Flags().StringVarP(&repoPath, "repo-path", "r", "", "Path to the Git repository (required)")
+	gateCmd.Flags().StringVar(&gateRulesPath, "rules", "", "JSON file with the gate rules, e.g. {\"rules\": [{\"type\": \"bus_factor\", \"path\": \"core/\"}]}")
+	gateCmd.Flags().StringVar(&gateReportPath, "report", "", "Also write the JSON report to this file")
+	gateCmd.MarkFlagRequired("project-name")
+	gateCmd.MarkFlagRequired("repo-path")
+}
+
+func runGate(cmd *cobra.Command, args []string) error {
+	config, err := loadGateConfig(gateRulesPath)
+	if err != nil {
+		return err
+	}
+	// Rule violations are reported in the JSON report, not as usage errors
+	cmd.SilenceUsage = true
+	progress := cmd.ErrOrStderr()
+
+	db, err := database.Open(dbDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	database.DB = db
+
+	projectRepo := sqlstore.NewProjectRepository(db)
+	analysisUseCase := analysis.NewProjectAnalysisUseCase(projectRepo)
+	if err := analysisUseCase.ValidateRepository(repoPath); err != nil {
+		return fmt.Errorf("invalid repository: %w", err)
+	}
+
+	project, err := projectRepo.GetByName(projectName)
+	if err != nil {
+		project = entities.NewProject(projectName, repoPath)
+		if err := projectRepo.Create(project); err != nil {
+			return fmt.Errorf("failed to create project: %w", err)
+		}
+		fmt.Fprintf(progress, "Created new project: %s (ID: %d)\n", project.Name, project.ID)
+	}
+
+	gate := analytics.NewQualityGateUseCase(repository.NewAnalyticsRepository(db), sqlstore.NewChangeRepository(db))
+	rules, err := gate.ResolveRules(project.ID, config)
+	if err != nil {
+		return fmt.Errorf("invalid gate rules: %w", err)
+	}
+
+	report := models.QualityGateReport{ProjectID: project.ID, ProjectName: project.Name}
+	var baseline []services.GateMeasurement
+	if project.IsAnalyzed() {
+		report.BaselineCommit = project.LastAnalyzedHash.String()
+		fmt.Fprintf(progress, "Measuring baseline at %s...\n", report.BaselineCommit)
+		if baseline, err = gate.Measure(project.ID, rules); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(progress, "First analysis of this project: rules pass without a baseline")
+	}
+
+	fmt.Fprintln(progress, "Analyzing new commits...")
+	if err := analysisUseCase.AnalyzeRepository(project.ID, repoPath); err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+	if project, err = projectRepo.GetByID(project.ID); err != nil {
+		return fmt.Errorf("failed to reload project: %w", err)
+	}
+	if project.IsAnalyzed() {
+		report.HeadCommit = project.LastAnalyzedHash.String()
+	}
+
+	current, err := gate.Measure(project.ID, rules)
+	if err != nil {
+		return err
+	}
+	report.Rules, report.Violations = gate.Evaluate(rules, baseline, current)
+	report.Passed = report.Violations == 0
+
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	output = append(output, '\n')
+	if _, err := cmd.OutOrStdout().Write(output); err != nil {
+		return err
+	}
+	if gateReportPath != "" {
+		if err := os.WriteFile(gateReportPath, output, 0o644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("quality gate failed with %d violation(s)", report.Violations)
+	}
+	return nil
+}
+
+// loadGateConfig reads the gate rules from a JSON file, or returns the default rules when no
+// file is given
+func loadGateConfig(path string) (entities.GateConfig, error) {
+	if path == "" {
+		return entities.GateConfig{Rules: append([]entities.GateRule(nil), services.DefaultGateConfig.Rules...)}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entities.GateConfig{}, fmt.Errorf("failed to read gate rules: %w", err)
+	}
+	var config entities.GateConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return entities.GateConfig{}, fmt.Errorf("invalid gate rules file %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return entities.GateConfig{}, fmt.Errorf("invalid gate rules: %w", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/application/usecases/analytics"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	issuesType  string
+	issuesLimit int
+	issuesFile  string
+
+	issuesCmd = &cobra.Command{
+		Use:   "issues",
+		Short: "List the issues referenced by commit messages",
+		Long:  "List the issue keys such as PROJ-123 or #456 referenced by a project's commits, with imported issue metadata",
+		RunE:  runIssues,
+	}
+
+	issuesImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import issue metadata from a JSON file",
+		Long: "Store the type, priority, status and title of issues from a JSON array of " +
+			`{"key", "title", "type", "priority", "status"} objects, then reclassify the project's commits`,
+		RunE: runIssuesImport,
+	}
+
+	rebuildIssuesCmd = &cobra.Command{
+		Use:   "issues",
+		Short: "Relink the commits of a project to the issues they reference",
+		Long:  "Extract the issue keys of every stored commit of a project under its issue patterns, replacing the existing links",
+		RunE:  runRebuildIssues,
+	}
+)
+
+func init() {
+	issuesCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project (required)")
+	issuesCmd.Flags().StringVar(&issuesType, "type", "", "Only list imported issues of this type, e.g. Bug")
+	issuesCmd.Flags().IntVar(&issuesLimit, "limit", 20, "Maximum number of issues to show")
+	issuesCmd.MarkFlagRequired("project-id")
+
+	issuesImportCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project (required)")
+	issuesImportCmd.Flags().StringVarP(&issuesFile, "file", "f", "", "JSON file with the issue metadata (required)")
+	issuesImportCmd.MarkFlagRequired("project-id")
+	issuesImportCmd.MarkFlagRequired("file")
+	issuesCmd.AddCommand(issuesImportCmd)
+
+	rebuildIssuesCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project to rebuild (required)")
+	rebuildIssuesCmd.MarkFlagRequired("project-id")
+	rebuildCmd.AddCommand(rebuildIssuesCmd)
+}
+
+func runIssues(cmd *cobra.Command, args []string) error {
+	db, err := openRebuildDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	useCase := analytics.NewAnalyticsUseCase(repository.NewAnalyticsRepository(db))
+	report, err := useCase.GetIssues(projectID, issuesType, issuesLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get issues: %w", err)
+	}
+
+	if len(report.Issues) == 0 {
+		fmt.Println("No issue references found")
+		return nil
+	}
+
+	fmt.Printf("%-16s %-12s %-10s %8s %6s %8s  %s\n", "ISSUE", "TYPE", "PRIORITY", "COMMITS", "FILES", "AUTHORS", "LAST COMMIT")
+	for _, issue := range report.Issues {
+		fmt.Printf("%-16s %-12s %-10s %8d %6d %8d  %s\n", issue.Key, issue.Type, issue.Priority, issue.Commits, issue.Files, issue.Authors, issue.LastCommit)
+	}
+	return nil
+}
+
+func runIssuesImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(issuesFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", issuesFile, err)
+	}
+	issues, err := analysis.ParseIssueImport(data)
+	if err != nil {
+		return err
+	}
+
+	db, err := openRebuildDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	useCase := analysis.NewIssueUseCase(sqlstore.NewCommitRepository(db), sqlstore.NewClassificationRuleRepository(db), sqlstore.NewIssueRepository(db))
+	reclassified, err := useCase.ImportIssues(projectID, issues)
+	if err != nil {
+		return fmt.Errorf("failed to import issues: %w", err)
+	}
+
+	fmt.Printf("Imported %d issues, updated the category of %d commits\n", len(issues), reclassified)
+	return nil
+}
+
+func runRebuildIssues(cmd *cobra.Command, args []string) error {
+	db, err := openRebuildDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	useCase := analysis.NewIssueUseCase(sqlstore.NewCommitRepository(db), sqlstore.NewClassificationRuleRepository(db), sqlstore.NewIssueRepository(db))
+
+	fmt.Printf("Relinking issues for project ID: %d\n", projectID)
+	linked, err := useCase.Relink(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to relink issues: %w", err)
+	}
+
+	fmt.Printf("Linked %d issue references\n", linked)
+	return nil
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateSteps int
+
+	migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Create or upgrade the database schema",
+		Long:  "Apply, revert or list the versioned schema migrations of the database's backend, which are built into the binary",
+	}
+
+	migrateUpCmd = &cobra.Command{
+		Use:   "up",
+		Short: "Apply the pending migrations",
+		Long:  "Apply every migration the database does not have yet, in version order, then backfill the daily file statistics of projects analysed before they were kept. Databases created from schema.sql before migrations were tracked are adopted.",
+		Args:  cobra.NoArgs,
+		RunE:  runMigrateUp,
+	}
+
+	migrateDownCmd = &cobra.Command{
+		Use:   "down",
+		Short: "Revert the latest migrations",
+		Long:  "Revert the latest applied migrations, newest first. Reverting the initial schema drops every table and all stored data.",
+		Args:  cobra.NoArgs,
+		RunE:  runMigrateDown,
+	}
+
+	migrateStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "List the migrations and whether they are applied",
+		Args:  cobra.NoArgs,
+		RunE:  runMigrateStatus,
+	}
+)
+
+func init() {
+	migrateDownCmd.Flags().IntVar(&migrateSteps, "steps", 1, "Number of migrations to revert")
+
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+}
+
+// openMigrator connects to the database and loads the migrations of its backend
+func openMigrator() (*database.Migrator, *sql.DB, error) {
+	db, err := database.Open(dbDSN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return migrator, db, nil
+}
+
+func runMigrateUp(cmd *cobra.Command, args []string) error {
+	migrator, db, err := openMigrator()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := migrator.Up()
+	for _, migration := range applied {
+		fmt.Printf("Applied %03d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("The database is up to date")
+	}
+
+	useCase := analysis.NewRebuildUseCase(
+		sqlstore.NewProjectRepository(db),
+		sqlstore.NewCouplingRepository(db),
+		sqlstore.NewFileStatsRepository(db),
+	)
+	rebuilt, err := useCase.BackfillFileStats()
+	for _, id := range rebuilt {
+		fmt.Printf("Backfilled the daily file statistics of project %d\n", id)
+	}
+	return err
+}
+
+func runMigrateDown(cmd *cobra.Command, args []string) error {
+	if migrateSteps < 1 {
+		return fmt.Errorf("--steps must be at least 1")
+	}
+	migrator, db, err := openMigrator()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	reverted, err := migrator.Down(migrateSteps)
+	for _, migration := range reverted {
+		fmt.Printf("Reverted %03d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(reverted) == 0 {
+		fmt.Println("No migration is applied")
+	}
+	return nil
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) error {
+	migrator, db, err := openMigrator()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-40s %-20s %s\n", "Migration", "Applied at", "Status")
+	for _, status := range statuses {
+		appliedAt, state := "-", "pending"
+		if status.AppliedAt != nil {
+			appliedAt, state = status.AppliedAt.UTC().Format("2006-01-02 15:04:05"), "applied"
+		}
+		switch {
+		case status.Unknown:
+			state = "applied, not part of this build"
+		case status.Modified:
+			state = "applied, changed since (checksum mismatch)"
+		}
+		fmt.Printf("%-40s %-20s %s\n", fmt.Sprintf("%03d_%s", status.Version, status.Name), appliedAt, state)
+	}
+	return nil
+}
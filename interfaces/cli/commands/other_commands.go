@@ -1,13 +1,11 @@
 package commands
 
 import (
-	"database/sql"
 	"fmt"
 
 	"codeecho/infrastructure/database"
-	"codeecho/infrastructure/persistence/mysql"
+	"codeecho/infrastructure/persistence/sqlstore"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/spf13/cobra"
 )
 
@@ -53,7 +51,7 @@ func runHotspots(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Database DSN: %s\n", dbDSN)
 
 	// Initialize database connection
-	db, err := sql.Open("mysql", dbDSN)
+	db, err := database.Open(dbDSN)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -68,7 +66,7 @@ func runHotspots(cmd *cobra.Command, args []string) error {
 	database.DB = db
 
 	// Initialize repositories
-	changeRepo := mysql.NewChangeRepository(db)
+	changeRepo := sqlstore.NewChangeRepository(db)
 
 	// Get hotspots using the repository method
 	fmt.Println("Retrieving code hotspots...")
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+
+	"codeecho/application/usecases/analysis"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/git"
+	"codeecho/infrastructure/persistence/sqlstore"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rebuildCmd = &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild derived analytics tables",
+		Long:  "Recompute tables that are maintained during ingestion from a project's stored commit history",
+	}
+
+	rebuildCouplingCmd = &cobra.Command{
+		Use:   "coupling",
+		Short: "Rebuild the pre-computed temporal coupling of a project",
+		Long:  "Recompute the monthly file pair coupling buckets from the stored commits and changes of a project",
+		RunE:  runRebuildCoupling,
+	}
+
+	rebuildFileStatsCmd = &cobra.Command{
+		Use:   "daily-stats",
+		Short: "Rebuild the per-file daily statistics of a project",
+		Long:  "Recompute the per-file, per-author daily statistics from the stored commits and changes of a project",
+		RunE:  runRebuildFileStats,
+	}
+
+	rebuildCategoriesCmd = &cobra.Command{
+		Use:   "categories",
+		Short: "Reclassify the commits of a project",
+		Long:  "Recompute the bugfix, feature, refactor, docs, test or chore category of every stored commit of a project under its classification rules",
+		RunE:  runRebuildCategories,
+	}
+
+	rebuildHealthCmd = &cobra.Command{
+		Use:   "health",
+		Short: "Rebuild the monthly health snapshots of a project",
+		Long:  "Recompute the technical debt score of every month of a project's history from its stored commits and repository trees",
+		RunE:  runRebuildHealth,
+	}
+)
+
+func init() {
+	rebuildCouplingCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project to rebuild (required)")
+	rebuildCouplingCmd.MarkFlagRequired("project-id")
+
+	rebuildFileStatsCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project to rebuild (required)")
+	rebuildFileStatsCmd.MarkFlagRequired("project-id")
+
+	rebuildCategoriesCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project to rebuild (required)")
+	rebuildCategoriesCmd.MarkFlagRequired("project-id")
+
+	rebuildHealthCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project to rebuild (required)")
+	rebuildHealthCmd.MarkFlagRequired("project-id")
+
+	rebuildCmd.AddCommand(rebuildCouplingCmd)
+	rebuildCmd.AddCommand(rebuildFileStatsCmd)
+	rebuildCmd.AddCommand(rebuildCategoriesCmd)
+	rebuildCmd.AddCommand(rebuildHealthCmd)
+}
+
+// openRebuildDB connects to the database the rebuild commands work on
+func openRebuildDB() (*sql.DB, error) {
+	db, err := database.Open(dbDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	database.DB = db
+	return db, nil
+}
+
+// openRebuildUseCase connects to the database and wires the rebuild use case
+func openRebuildUseCase() (*analysis.RebuildUseCase, *sql.DB, error) {
+	db, err := openRebuildDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	useCase := analysis.NewRebuildUseCase(
+		sqlstore.NewProjectRepository(db),
+		sqlstore.NewCouplingRepository(db),
+		sqlstore.NewFileStatsRepository(db),
+	)
+	return useCase, db, nil
+}
+
+func runRebuildCoupling(cmd *cobra.Command, args []string) error {
+	useCase, db, err := openRebuildUseCase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	fmt.Printf("Rebuilding temporal coupling for project ID: %d\n", projectID)
+	recorded, err := useCase.RebuildCoupling(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild coupling: %w", err)
+	}
+
+	fmt.Printf("Recorded coupling for %d commits\n", recorded)
+	return nil
+}
+
+func runRebuildFileStats(cmd *cobra.Command, args []string) error {
+	useCase, db, err := openRebuildUseCase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	fmt.Printf("Rebuilding daily file statistics for project ID: %d\n", projectID)
+	recorded, err := useCase.RebuildFileStats(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild daily file statistics: %w", err)
+	}
+
+	fmt.Printf("Recorded statistics for %d commits\n", recorded)
+	return nil
+}
+
+func runRebuildCategories(cmd *cobra.Command, args []string) error {
+	db, err := openRebuildDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	useCase := analysis.NewClassificationUseCase(sqlstore.NewCommitRepository(db), sqlstore.NewClassificationRuleRepository(db), sqlstore.NewIssueRepository(db))
+
+	fmt.Printf("Reclassifying commits for project ID: %d\n", projectID)
+	changed, err := useCase.Reclassify(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to reclassify commits: %w", err)
+	}
+
+	fmt.Printf("Updated the category of %d commits\n", changed)
+	return nil
+}
+
+func runRebuildHealth(cmd *cobra.Command, args []string) error {
+	db, err := openRebuildDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	project, err := sqlstore.NewProjectRepository(db).GetByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	useCase := analysis.NewHealthSnapshotUseCase(
+		sqlstore.NewCommitRepository(db),
+		sqlstore.NewChangeRepository(db),
+		sqlstore.NewHealthSnapshotRepository(db),
+		sqlstore.NewRiskPolicyRepository(db),
+		git.NewGitService(),
+	)
+
+	fmt.Printf("Rebuilding health snapshots for project ID: %d\n", projectID)
+	recorded, err := useCase.RecordSnapshots(projectID, project.RepoPath, true)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild health snapshots: %w", err)
+	}
+
+	fmt.Printf("Recorded %d monthly snapshots\n", recorded)
+	return nil
+}
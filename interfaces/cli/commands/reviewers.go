@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeecho/application/usecases/analytics"
+	"codeecho/domain/services"
+	"codeecho/domain/values"
+	"codeecho/infrastructure/database"
+	"codeecho/infrastructure/persistence/sqlstore"
+	"codeecho/infrastructure/repository"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewersAuthor        string
+	reviewersExclude       []string
+	reviewersLoad          map[string]int
+	reviewersCount         int
+	reviewersHalfLife      int
+	reviewersInactiveAfter int
+	reviewersLoadPenalty   float64
+	reviewersOwnership     string
+	reviewersJSON          bool
+
+	reviewersCmd = &cobra.Command{
+		Use:   "reviewers [file or directory/]...",
+		Short: "Recommend reviewers for a set of files",
+		Long: "Rank the authors who know the given files best as reviewers. Expertise is the ownership of " +
+			"each file, halved for every --half-life days since the author last touched it. The change's " +
+			"author, --exclude'd and inactive authors are left out, and reviewers with open reviews " +
+			"(--load) or whose files are already covered give way to others.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: runReviewers,
+	}
+)
+
+func init() {
+	reviewersCmd.Flags().IntVarP(&projectID, "project-id", "i", 0, "ID of the project (required)")
+	reviewersCmd.Flags().StringVar(&reviewersAuthor, "author", "", "Author of the change, who is not recommended")
+	reviewersCmd.Flags().StringSliceVar(&reviewersExclude, "exclude", nil, "Authors not to recommend")
+	reviewersCmd.Flags().StringToIntVar(&reviewersLoad, "load", nil, "Open reviews per author, e.g. ann=3,bob=1")
+	reviewersCmd.Flags().IntVarP(&reviewersCount, "count", "c", services.DefaultReviewerCount, "Number of reviewers to pick")
+	reviewersCmd.Flags().IntVar(&reviewersHalfLife, "half-life", services.DefaultExpertiseHalfLifeDays, "Days after which expertise counts half")
+	reviewersCmd.Flags().IntVar(&reviewersInactiveAfter, "inactive-after", analytics.DefaultInactiveAfterMonths, "Months without commits after which an author is inactive")
+	reviewersCmd.Flags().Float64Var(&reviewersLoadPenalty, "load-penalty", services.DefaultReviewLoadPenalty, "Score penalty per open review; 0 ignores load")
+	reviewersCmd.Flags().StringVar(&reviewersOwnership, "ownership", string(values.OwnershipByCommits), "Ownership metric: commits, lines_changed or blame")
+	reviewersCmd.Flags().BoolVar(&reviewersJSON, "json", false, "Print the recommendation as JSON")
+	reviewersCmd.MarkFlagRequired("project-id")
+}
+
+func runReviewers(cmd *cobra.Command, args []string) error {
+	metric, err := values.ParseOwnershipMetric(reviewersOwnership, values.OwnershipByCommits)
+	if err != nil {
+		return err
+	}
+	if reviewersCount < 1 || reviewersHalfLife < 1 || reviewersInactiveAfter < 1 || reviewersLoadPenalty < 0 {
+		return fmt.Errorf("--count, --half-life and --inactive-after must be positive and --load-penalty not negative")
+	}
+
+	db, err := database.Open(dbDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	database.DB = db
+
+	useCase := analytics.NewReviewerUseCase(repository.NewAnalyticsRepository(db), sqlstore.NewAuthorStatusRepository(db))
+	recommendation, err := useCase.Recommend(projectID, analytics.ReviewerRequest{
+		Files:               args,
+		Author:              reviewersAuthor,
+		Exclude:             reviewersExclude,
+		InactiveAfterMonths: reviewersInactiveAfter,
+		Options: services.ReviewerOptions{
+			Count:        reviewersCount,
+			HalfLifeDays: reviewersHalfLife,
+			LoadPenalty:  reviewersLoadPenalty,
+			Load:         reviewersLoad,
+		},
+	}, metric, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if reviewersJSON {
+		output, err := json.MarshalIndent(recommendation, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Printf("\n=== Reviewers for %d path(s) (expertise half-life %d days) ===\n", len(args), recommendation.HalfLifeDays)
+	fmt.Printf("%-3s %-30s %10s %8s %6s %-12s %s\n", "", "Author", "Expertise", "Score", "Load", "Last touch", "Files")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, candidate := range recommendation.Candidates {
+		mark := ""
+		if candidate.Selected {
+			mark = "*"
+		}
+		lastTouched := ""
+		if candidate.LastTouched != nil {
+			lastTouched = candidate.LastTouched.Format("2006-01-02")
+		}
+		fmt.Printf("%-3s %-30s %10.2f %8.2f %6d %-12s %d\n",
+			mark,
+			truncateString(candidate.Author, 30),
+			candidate.Expertise,
+			candidate.Score,
+			candidate.Load,
+			lastTouched,
+			len(candidate.Files),
+		)
+	}
+	if len(recommendation.Candidates) == 0 {
+		fmt.Println("No candidates with expertise in these files")
+	}
+
+	if len(recommendation.Excluded) > 0 {
+		fmt.Println("\nLeft out:")
+		for _, excluded := range recommendation.Excluded {
+			fmt.Printf("  %-30s %s\n", excluded.Author, excluded.Reason)
+		}
+	}
+	if len(recommendation.UncoveredFiles) > 0 {
+		fmt.Printf("\nNo expertise found for: %s\n", strings.Join(recommendation.UncoveredFiles, ", "))
+	}
+
+	return nil
+}
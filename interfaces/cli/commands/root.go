@@ -18,12 +18,20 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&dbDSN, "db-dsn",
 		"codeecho_user:codeecho_pass@tcp(codeecho-mysql:3306)/codeecho_db?parseTime=true",
-		"Database connection string")
+		"Database connection string: a MySQL DSN, a postgres:// URL, or sqlite:///path/to/codeecho.db for a local SQLite database")
 
 	// Add commands
 	rootCmd.AddCommand(analyzeCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(hotspotsCmd)
+	rootCmd.AddCommand(couplingCmd)
+	rootCmd.AddCommand(rebuildCmd)
+	rootCmd.AddCommand(issuesCmd)
+	rootCmd.AddCommand(gateCmd)
+	rootCmd.AddCommand(changeRiskCmd)
+	rootCmd.AddCommand(reviewersCmd)
+	rootCmd.AddCommand(codeownersCmd)
+	rootCmd.AddCommand(migrateCmd)
 }
 
 // Execute executes the root command
@@ -16,14 +16,22 @@ type ProjectOverview struct {
 	HighCouplingRisks  int              `json:"highCouplingRisks"`
 	LastAnalysisTime   string           `json:"lastAnalysisTime"`
 	AnalysisStatus     AnalysisStatus   `json:"analysisStatus"`
+	// RiskPolicyVersion is the risk policy version that rated the snapshots and counts
+	RiskPolicyVersion int `json:"riskPolicyVersion"`
 }
 
-// DebtTrendPoint represents a point in the technical debt trend
+// DebtTrendPoint represents a point in the technical debt trend: the debt score of a month
+// (0-100, rounded in Score) with its components as shares between 0 and 1
 type DebtTrendPoint struct {
-	Date  string  `json:"date"`
-	Month string  `json:"month"`
-	Score int     `json:"score"`
-	Value float64 `json:"value"`
+	Date              string  `json:"date"`
+	Month             string  `json:"month"`
+	Score             int     `json:"score"`
+	Value             float64 `json:"value"`
+	HotspotComplexity float64 `json:"hotspotComplexity"`
+	Coupling          float64 `json:"coupling"`
+	BusFactorRisk     float64 `json:"busFactorRisk"`
+	StaleCode         float64 `json:"staleCode"`
+	PolicyVersion     int     `json:"policyVersion"`
 }
 
 // RiskSnapshot represents a risk snapshot for a component
@@ -51,6 +59,10 @@ type FileOwnership struct {
 	LastModified        string               `json:"lastModified"`
 	RiskLevel           string               `json:"riskLevel"`
 	Contributors        []AuthorContribution `json:"authors"`
+	Fragmentation       float64              `json:"fragmentation"`
+	MainDeveloper       string               `json:"mainDeveloper"`
+	MainDeveloperShare  float64              `json:"mainDeveloperShare"`
+	Effort              []AuthorEffort       `json:"effort"`
 }
 
 // AuthorContribution represents an author's contribution to a file
@@ -61,6 +73,9 @@ type AuthorContribution struct {
 	Contribution int     `json:"contribution"`
 	Percentage   float64 `json:"percentage"`
 	LastModified string  `json:"lastModified"`
+	LinesAdded   int     `json:"linesAdded"`
+	// Lines is the number of the author's lines surviving at the analysed commit (blame ownership only)
+	Lines int `json:"lines"`
 }
 
 // AuthorHotspot represents author hotspot data
@@ -86,17 +101,683 @@ type TemporalCoupling struct {
 	LastModified  string  `json:"last_modified"`
 }
 
+// FileCouplingSum aggregates all temporal coupling of a single file; files with a high
+// sum of coupling are architectural hubs that change together with many others
+type FileCouplingSum struct {
+	FilePath string `json:"file_path"`
+	// CoupledFiles is the number of other files the file is coupled with
+	CoupledFiles int `json:"coupled_files"`
+	// SumOfCoupling is the total number of commits shared with coupled files
+	SumOfCoupling    int     `json:"sum_of_coupling"`
+	TotalCommits     int     `json:"total_commits"`
+	AverageCoupling  float64 `json:"average_coupling"`
+	MaxCoupling      float64 `json:"max_coupling"`
+	StrongestPartner string  `json:"strongest_partner"`
+}
+
+// CouplingCluster is a community of files that tend to change together
+type CouplingCluster struct {
+	ID    int      `json:"id"`
+	Size  int      `json:"size"`
+	Files []string `json:"files"`
+	// Hub is the member with the highest sum of coupling inside the cluster
+	Hub           string  `json:"hub"`
+	InternalPairs int     `json:"internal_pairs"`
+	ExternalPairs int     `json:"external_pairs"`
+	Density       float64 `json:"density"`
+	AverageScore  float64 `json:"average_score"`
+}
+
+// CouplingClusterReport holds the communities detected in a project's coupling graph
+type CouplingClusterReport struct {
+	ProjectID  int               `json:"project_id"`
+	TotalFiles int               `json:"total_files"`
+	TotalPairs int               `json:"total_pairs"`
+	Modularity float64           `json:"modularity"`
+	Clusters   []CouplingCluster `json:"clusters"`
+}
+
 // BusFactorData represents bus factor analysis data for a single file
 type BusFactorData struct {
 	FilePath              string            `json:"file_path"`
 	TotalCommits          int               `json:"total_commits"`
 	OwnershipDistribution []AuthorOwnership `json:"ownership_distribution"`
 	LastModified          *time.Time        `json:"last_modified"`
+	// Fragmentation is the fractal value of the commit distribution (0 = single author)
+	Fragmentation float64 `json:"fragmentation"`
+	// MainDeveloper is the author who added the most lines to the file
+	MainDeveloper string `json:"main_developer"`
+	// MainDeveloperShare is the main developer's percentage of all added lines
+	MainDeveloperShare float64        `json:"main_developer_share"`
+	Effort             []AuthorEffort `json:"effort"`
 }
 
 // AuthorOwnership represents an author's ownership of a file
 type AuthorOwnership struct {
 	Author           string  `json:"author"`
 	Commits          int     `json:"commits"`
+	LinesChanged     int     `json:"lines_changed"`
+	LinesAdded       int     `json:"lines_added"`
+	Lines            int     `json:"lines"`
+	OwnershipPercent float64 `json:"ownership_percent"`
+}
+
+// AuthorEffort represents an author's share of the commits (revisions) made to a file
+type AuthorEffort struct {
+	Author     string  `json:"author"`
+	Commits    int     `json:"commits"`
+	Percentage float64 `json:"percentage"`
+}
+
+// FileFragmentation ranks a file by how spread its development is among authors
+type FileFragmentation struct {
+	FilePath           string  `json:"file_path"`
+	Fragmentation      float64 `json:"fragmentation"`
+	Authors            int     `json:"authors"`
+	TotalCommits       int     `json:"total_commits"`
+	MainDeveloper      string  `json:"main_developer"`
+	MainDeveloperShare float64 `json:"main_developer_share"`
+}
+
+// ComplexityTrend represents the evolution of a file's size and complexity over its history
+type ComplexityTrend struct {
+	FilePath  string             `json:"file_path"`
+	Interval  string             `json:"interval"`
+	Samples   []ComplexitySample `json:"samples"`
+	Direction string             `json:"direction"`
+	// ComplexityDelta is the change in total complexity between the first and last sample
+	ComplexityDelta int `json:"complexity_delta"`
+	// LOCDelta is the change in lines of code between the first and last sample
+	LOCDelta int `json:"loc_delta"`
+}
+
+// ComplexitySample represents a file's size and complexity at a single commit
+type ComplexitySample struct {
+	CommitHash      string  `json:"commit_hash"`
+	Author          string  `json:"author"`
+	Timestamp       string  `json:"timestamp"`
+	LinesOfCode     int     `json:"lines_of_code"`
+	TotalComplexity int     `json:"total_complexity"`
+	MeanComplexity  float64 `json:"mean_complexity"`
+	MaxComplexity   int     `json:"max_complexity"`
+}
+
+// FileTypeStat represents a file extension with its file count and lines of code
+type FileTypeStat struct {
+	Extension   string `json:"extension"`
+	Files       int    `json:"files"`
+	LinesOfCode int    `json:"loc"`
+}
+
+// LanguageBreakdown represents the measured lines of a single language in a project
+type LanguageBreakdown struct {
+	Language     string  `json:"language"`
+	Files        int     `json:"files"`
+	CodeLines    int     `json:"code_lines"`
+	CommentLines int     `json:"comment_lines"`
+	BlankLines   int     `json:"blank_lines"`
+	Percentage   float64 `json:"percentage"`
+}
+
+// FileAge represents how long ago a file in the analysed tree last changed
+type FileAge struct {
+	FilePath     string     `json:"file_path"`
+	Lines        int        `json:"lines"`
+	LastModified *time.Time `json:"last_modified"`
+	AgeDays      int        `json:"age_days"`
+	Bucket       string     `json:"bucket"`
+	// MedianLineAgeDays is the median age of the file's lines according to blame
+	MedianLineAgeDays int `json:"median_line_age_days"`
+	// LineAgeBuckets counts the file's lines per age bucket label
+	LineAgeBuckets map[string]int `json:"line_age_buckets,omitempty"`
+}
+
+// LineAgeSegment represents lines of a file that originate from the same commit
+type LineAgeSegment struct {
+	FilePath    string    `json:"file_path"`
+	Author      string    `json:"author"`
+	CommittedAt time.Time `json:"committed_at"`
+	Lines       int       `json:"lines"`
+}
+
+// CodeAgeReport represents the age distribution of the code in a project
+type CodeAgeReport struct {
+	ProjectID        int             `json:"project_id"`
+	GeneratedAt      time.Time       `json:"generated_at"`
+	StaleAfterDays   int             `json:"stale_after_days"`
+	TotalFiles       int             `json:"total_files"`
+	TotalLines       int             `json:"total_lines"`
+	StaleFiles       int             `json:"stale_files"`
+	StaleLines       int             `json:"stale_lines"`
+	StaleLinePercent float64         `json:"stale_line_percent"`
+	Buckets          []CodeAgeBucket `json:"buckets"`
+	Files            []FileAge       `json:"files"`
+	Treemap          *CodeAgeNode    `json:"treemap"`
+}
+
+// CodeAgeBucket counts the files and lines whose age falls within a range of days
+type CodeAgeBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	// MaxDays is exclusive; nil means the bucket has no upper bound
+	MaxDays *int `json:"max_days"`
+	Files   int  `json:"files"`
+	Lines   int  `json:"lines"`
+}
+
+// CodeAgeNode is a directory or file in the code age treemap. Directories aggregate
+// the lines of their descendants and report the line-weighted mean age.
+type CodeAgeNode struct {
+	Name         string         `json:"name"`
+	Path         string         `json:"path"`
+	Type         string         `json:"type"`
+	Lines        int            `json:"lines"`
+	AgeDays      float64        `json:"age_days"`
+	LastModified *time.Time     `json:"last_modified"`
+	Children     []*CodeAgeNode `json:"children,omitempty"`
+}
+
+// AuthorActivity represents an author's commit activity and whether they are considered active
+type AuthorActivity struct {
+	Author      string     `json:"author"`
+	Commits     int        `json:"commits"`
+	FirstCommit *time.Time `json:"first_commit"`
+	LastCommit  *time.Time `json:"last_commit"`
+	Active      bool       `json:"active"`
+	// StatusSource is "manual" when the status was set through the API, otherwise "auto"
+	StatusSource string `json:"status_source"`
+	Note         string `json:"note,omitempty"`
+}
+
+// KnowledgeLossReport represents how much of a project's knowledge is held by inactive authors
+type KnowledgeLossReport struct {
+	ProjectID           int                      `json:"project_id"`
+	Ownership           string                   `json:"ownership"`
+	InactiveAfterMonths int                      `json:"inactive_after_months"`
+	AbandonedThreshold  float64                  `json:"abandoned_threshold"`
+	InactiveAuthors     []AuthorActivity         `json:"inactive_authors"`
+	Summary             KnowledgeLossSummary     `json:"summary"`
+	Files               []FileKnowledgeLoss      `json:"files"`
+	Directories         []DirectoryKnowledgeLoss `json:"directories"`
+}
+
+// KnowledgeLossSummary aggregates knowledge loss over all analysed files
+type KnowledgeLossSummary struct {
+	TotalFiles       int     `json:"total_files"`
+	AbandonedFiles   int     `json:"abandoned_files"`
+	AbandonedPercent float64 `json:"abandoned_percent"`
+}
+
+// FileKnowledgeLoss represents the share of a file's ownership held by inactive authors
+type FileKnowledgeLoss struct {
+	FilePath          string            `json:"file_path"`
+	AbandonedPercent  float64           `json:"abandoned_percent"`
+	Abandoned         bool              `json:"abandoned"`
+	MainOwner         string            `json:"main_owner"`
+	MainOwnerInactive bool              `json:"main_owner_inactive"`
+	InactiveOwners    []AuthorOwnership `json:"inactive_owners"`
+	ActiveOwners      []AuthorOwnership `json:"active_owners"`
+}
+
+// DirectoryKnowledgeLoss rolls file knowledge loss up to a directory
+type DirectoryKnowledgeLoss struct {
+	Path           string `json:"path"`
+	Files          int    `json:"files"`
+	AbandonedFiles int    `json:"abandoned_files"`
+	// AbandonedPercent is the mean abandoned ownership of the files below the directory
+	AbandonedPercent float64 `json:"abandoned_percent"`
+}
+
+// FileAuthorship represents one author's commit history with one file
+type FileAuthorship struct {
+	FilePath string `json:"file_path"`
+	Author   string `json:"author"`
+	Commits  int    `json:"commits"`
+	// Creator reports whether the author made the file's first commit
+	Creator bool `json:"creator"`
+}
+
+// TruckFactorReport represents the project-wide and per-directory truck factor
+type TruckFactorReport struct {
+	ProjectID       int                    `json:"project_id"`
+	Path            string                 `json:"path,omitempty"`
+	Coverage        float64                `json:"coverage_threshold"`
+	TruckFactor     int                    `json:"truck_factor"`
+	TotalFiles      int                    `json:"total_files"`
+	InitialCoverage float64                `json:"initial_coverage"`
+	KeyDevelopers   []KeyDeveloper         `json:"key_developers"`
+	CoverageCurve   []CoveragePoint        `json:"coverage_curve"`
+	Directories     []DirectoryTruckFactor `json:"directories"`
+}
+
+// KeyDeveloper is an author whose departure orphans files, in removal order
+type KeyDeveloper struct {
+	Author        string  `json:"author"`
+	FilesAuthored int     `json:"files_authored"`
+	CoverageAfter float64 `json:"coverage_after"`
+}
+
+// CoveragePoint is the share of files still having an author after removing a number of key developers
+type CoveragePoint struct {
+	Removed  int     `json:"removed"`
+	Coverage float64 `json:"coverage"`
+}
+
+// DirectoryTruckFactor represents the truck factor computed over the files of one directory
+type DirectoryTruckFactor struct {
+	Path          string   `json:"path"`
+	TruckFactor   int      `json:"truck_factor"`
+	TotalFiles    int      `json:"total_files"`
+	KeyDevelopers []string `json:"key_developers"`
+}
+
+// TeamShare represents a team's share of the ownership of a file or directory
+type TeamShare struct {
+	Team       string   `json:"team"`
+	Percentage float64  `json:"percentage"`
+	Authors    []string `json:"authors,omitempty"`
+}
+
+// FileTeamOwnership represents the ownership of a file aggregated by team
+type FileTeamOwnership struct {
+	FilePath              string      `json:"file_path"`
+	PrimaryTeam           string      `json:"primary_team"`
+	PrimaryTeamPercentage float64     `json:"primary_team_percentage"`
+	Teams                 []TeamShare `json:"teams"`
+}
+
+// DirectoryTeamOwnership represents the mean team ownership of the files below a directory
+type DirectoryTeamOwnership struct {
+	Path                  string      `json:"path"`
+	Files                 int         `json:"files"`
+	PrimaryTeam           string      `json:"primary_team"`
+	PrimaryTeamPercentage float64     `json:"primary_team_percentage"`
+	Teams                 []TeamShare `json:"teams"`
+}
+
+// TeamOwnershipReport represents file and directory ownership aggregated by team
+type TeamOwnershipReport struct {
+	ProjectID   int                      `json:"project_id"`
+	Ownership   string                   `json:"ownership"`
+	Files       []FileTeamOwnership      `json:"files"`
+	Directories []DirectoryTeamOwnership `json:"directories"`
+}
+
+// TeamCouplingPair is a temporally coupled file pair annotated with the primary team of each file
+type TeamCouplingPair struct {
+	TemporalCoupling
+	TeamA     string `json:"team_a"`
+	TeamB     string `json:"team_b"`
+	CrossTeam bool   `json:"cross_team"`
+}
+
+// TeamCouplingEdge aggregates the coupled pairs between two teams (or within one team)
+type TeamCouplingEdge struct {
+	TeamA         string `json:"team_a"`
+	TeamB         string `json:"team_b"`
+	Pairs         int    `json:"pairs"`
+	SharedCommits int    `json:"shared_commits"`
+}
+
+// TeamCouplingReport represents temporal coupling at team level
+type TeamCouplingReport struct {
+	ProjectID        int                `json:"project_id"`
+	Ownership        string             `json:"ownership"`
+	TotalPairs       int                `json:"total_pairs"`
+	CrossTeamPairs   int                `json:"cross_team_pairs"`
+	CrossTeamPercent float64            `json:"cross_team_percent"`
+	Teams            []TeamCouplingEdge `json:"teams"`
+	Pairs            []TeamCouplingPair `json:"pairs"`
+}
+
+// FragmentationReport ranks the files of a project by the fragmentation of their development
+type FragmentationReport struct {
+	ProjectID            int                 `json:"project_id"`
+	Path                 string              `json:"path,omitempty"`
+	MinAuthors           int                 `json:"min_authors"`
+	AverageFragmentation float64             `json:"average_fragmentation"`
+	Files                []FileFragmentation `json:"files"`
+}
+
+// DefectHotspot ranks a file by the bug-fix commits that touched it
+type DefectHotspot struct {
+	FilePath      string  `json:"file_path"`
+	BugfixCommits int     `json:"bugfix_commits"`
+	TotalCommits  int     `json:"total_commits"`
+	BugfixRatio   float64 `json:"bugfix_ratio"`
+	CodeLines     int     `json:"code_lines"`
+	DefectDensity float64 `json:"defect_density"` // bug-fix commits per 1,000 lines of code
+	Authors       int     `json:"authors"`
+	LastBugfix    string  `json:"last_bugfix"`
+}
+
+// DefectHotspotReport ranks the files of a project by bug-fix commits, with the number of
+// commits in each category over the same period
+type DefectHotspotReport struct {
+	ProjectID    int             `json:"project_id"`
+	Path         string          `json:"path,omitempty"`
+	StartDate    string          `json:"start_date,omitempty"`
+	EndDate      string          `json:"end_date,omitempty"`
+	Categories   map[string]int  `json:"categories"`
+	Unclassified int             `json:"unclassified"`
+	Files        []DefectHotspot `json:"files"`
+}
+
+// IssueSummary describes an issue referenced by commit messages, with its imported metadata
+// when available
+type IssueSummary struct {
+	Key         string `json:"key"`
+	Title       string `json:"title,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Commits     int    `json:"commits"`
+	Files       int    `json:"files"`
+	Authors     int    `json:"authors"`
+	FirstCommit string `json:"first_commit"`
+	LastCommit  string `json:"last_commit"`
+}
+
+// IssueCommit is a commit that references an issue
+type IssueCommit struct {
+	Hash      string `json:"hash"`
+	Author    string `json:"author"`
+	Timestamp string `json:"timestamp"`
+	Message   string `json:"message"`
+	Category  string `json:"category,omitempty"`
+}
+
+// IssueFile is a file changed by the commits that reference an issue
+type IssueFile struct {
+	FilePath     string `json:"file_path"`
+	Commits      int    `json:"commits"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesDeleted int    `json:"lines_deleted"`
+}
+
+// IssueAuthor is an author of the commits that reference an issue
+type IssueAuthor struct {
+	Author       string `json:"author"`
+	Commits      int    `json:"commits"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesDeleted int    `json:"lines_deleted"`
+}
+
+// FileIssue is an issue referenced by the commits that changed a file
+type FileIssue struct {
+	Key        string `json:"key"`
+	Title      string `json:"title,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Priority   string `json:"priority,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Commits    int    `json:"commits"`
+	LastCommit string `json:"last_commit"`
+}
+
+// IssueReport lists the issues referenced by a project's commits
+type IssueReport struct {
+	ProjectID int            `json:"project_id"`
+	Type      string         `json:"type,omitempty"`
+	Issues    []IssueSummary `json:"issues"`
+}
+
+// IssueDetail is one issue with the commits, files or authors behind it; only the requested
+// list is set
+type IssueDetail struct {
+	ProjectID int           `json:"project_id"`
+	Issue     *IssueSummary `json:"issue"`
+	Commits   []IssueCommit `json:"commits,omitempty"`
+	Files     []IssueFile   `json:"files,omitempty"`
+	Authors   []IssueAuthor `json:"authors,omitempty"`
+}
+
+// Release is a repository tag with the commit it points at
+type Release struct {
+	Name            string `json:"name"`
+	CommitHash      string `json:"commit_hash"`
+	CommitDate      string `json:"commit_date"`
+	ReleasedAt      string `json:"released_at"`
+	PreviousRelease string `json:"previous_release,omitempty"`
+}
+
+// ReleasePeriod summarises the development period of a release: the commits after the
+// previous release up to the release's commit
+type ReleasePeriod struct {
+	Release
+	Start        string `json:"start,omitempty"`
+	Commits      int    `json:"commits"`
+	Authors      int    `json:"authors"`
+	FilesChanged int    `json:"files_changed"`
+	Churn        int    `json:"churn"`
+}
+
+// FileChurnDelta compares the activity of a file between two periods. Status is new, inactive,
+// hotter, cooler or unchanged.
+type FileChurnDelta struct {
+	FilePath    string `json:"file_path"`
+	FromCommits int    `json:"from_commits"`
+	ToCommits   int    `json:"to_commits"`
+	CommitDelta int    `json:"commit_delta"`
+	FromChurn   int    `json:"from_churn"`
+	ToChurn     int    `json:"to_churn"`
+	ChurnDelta  int    `json:"churn_delta"`
+	Status      string `json:"status"`
+}
+
+// AuthorDelta compares the commits of an author between two periods. Status is new,
+// departed or continuing.
+type AuthorDelta struct {
+	Author      string `json:"author"`
+	FromCommits int    `json:"from_commits"`
+	ToCommits   int    `json:"to_commits"`
+	CommitDelta int    `json:"commit_delta"`
+	Status      string `json:"status"`
+}
+
+// CouplingDelta compares the coupling of a file pair between two periods
+type CouplingDelta struct {
+	FileA             string  `json:"file_a"`
+	FileB             string  `json:"file_b"`
+	FromSharedCommits int     `json:"from_shared_commits"`
+	ToSharedCommits   int     `json:"to_shared_commits"`
+	FromScore         float64 `json:"from_score"`
+	ToScore           float64 `json:"to_score"`
+}
+
+// ReleaseComparison compares the development periods of two releases: which files became
+// hotter, how the authors changed and which coupling appeared or disappeared
+type ReleaseComparison struct {
+	ProjectID        int              `json:"project_id"`
+	From             ReleasePeriod    `json:"from"`
+	To               ReleasePeriod    `json:"to"`
+	MinSharedCommits int              `json:"min_shared_commits"`
+	MinCouplingScore float64          `json:"min_coupling_score"`
+	Files            []FileChurnDelta `json:"files"`
+	Authors          []AuthorDelta    `json:"authors"`
+	NewCoupling      []CouplingDelta  `json:"new_coupling"`
+	RemovedCoupling  []CouplingDelta  `json:"removed_coupling"`
+}
+
+// QualityGateReport is the machine-readable outcome of a quality gate run
+type QualityGateReport struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	// BaselineCommit is the last analysed commit before the run; empty on a first analysis,
+	// when every rule passes for lack of a baseline
+	BaselineCommit string                  `json:"baseline_commit"`
+	HeadCommit     string                  `json:"head_commit"`
+	Passed         bool                    `json:"passed"`
+	Violations     int                     `json:"violations"`
+	Rules          []QualityGateRuleResult `json:"rules"`
+}
+
+// QualityGateRuleResult is the outcome of one quality gate rule
+type QualityGateRuleResult struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Limit is the resolved limit of the rule: the maximum bus factor, the maximum growth in
+	// percent or the minimum coupling score
+	Limit      float64                `json:"limit"`
+	Passed     bool                   `json:"passed"`
+	Checked    int                    `json:"checked"`
+	Violations []QualityGateViolation `json:"violations"`
+}
+
+// QualityGateViolation is a file, component pair or hotspot set that fails a rule
+type QualityGateViolation struct {
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+// ChangeRisk is the risk assessment of the files changed on a head ref since it diverged from
+// a base ref. Risk levels are lowercase and come from the project's risk policy.
+type ChangeRisk struct {
+	ProjectID         int    `json:"project_id"`
+	Base              string `json:"base"`
+	Head              string `json:"head"`
+	RiskPolicyVersion int    `json:"risk_policy_version"`
+	// RiskLevel is the highest level among the changed files and the coupled files left out
+	RiskLevel           string               `json:"risk_level"`
+	Summary             ChangeRiskSummary    `json:"summary"`
+	Files               []ChangedFileRisk    `json:"files"`
+	SuggestedReviewers  []SuggestedReviewer  `json:"suggested_reviewers"`
+	MissingCoupledFiles []MissingCoupledFile `json:"missing_coupled_files"`
+}
+
+// ChangeRiskSummary counts the risky files of a change
+type ChangeRiskSummary struct {
+	FilesChanged        int `json:"files_changed"`
+	Hotspots            int `json:"hotspots"`
+	LowBusFactorFiles   int `json:"low_bus_factor_files"`
+	MissingCoupledFiles int `json:"missing_coupled_files"`
+}
+
+// ChangedFileRisk is the history of a changed file: how often it changed before (hotspots are
+// rated high or critical) and who knows it
+type ChangedFileRisk struct {
+	FilePath       string      `json:"file_path"`
+	LinesAdded     int         `json:"lines_added"`
+	LinesDeleted   int         `json:"lines_deleted"`
+	ChangeCount    int         `json:"change_count"`
+	Hotspot        bool        `json:"hotspot"`
+	HotspotLevel   string      `json:"hotspot_level"`
+	BusFactor      int         `json:"bus_factor"`
+	BusFactorLevel string      `json:"bus_factor_level"`
+	RiskLevel      string      `json:"risk_level"`
+	Owners         []FileOwner `json:"owners"`
+}
+
+// FileOwner is an author's share of a file
+type FileOwner struct {
+	Author           string  `json:"author"`
 	OwnershipPercent float64 `json:"ownership_percent"`
 }
+
+// SuggestedReviewer is a main owner of some of the changed files
+type SuggestedReviewer struct {
+	Author string   `json:"author"`
+	Files  []string `json:"files"`
+	// Ownership is the sum of the author's ownership percentages of those files
+	Ownership float64 `json:"ownership"`
+}
+
+// MissingCoupledFile is a file that usually changes with a changed file but was not changed
+type MissingCoupledFile struct {
+	FilePath      string  `json:"file_path"`
+	CoupledWith   string  `json:"coupled_with"`
+	SharedCommits int     `json:"shared_commits"`
+	CouplingScore float64 `json:"coupling_score"`
+	RiskLevel     string  `json:"risk_level"`
+}
+
+// ReviewerRecommendation ranks the candidate reviewers of a set of files by their expertise,
+// which halves for every HalfLifeDays since they last touched a file
+type ReviewerRecommendation struct {
+	ProjectID    int      `json:"project_id"`
+	Files        []string `json:"files"`
+	HalfLifeDays int      `json:"half_life_days"`
+	// Candidates lists the picked reviewers first, in pick order, then the others by score
+	Candidates []ReviewerCandidate `json:"candidates"`
+	Excluded   []ExcludedReviewer  `json:"excluded"`
+	// UncoveredFiles are the requested files no candidate has expertise in
+	UncoveredFiles []string `json:"uncovered_files"`
+}
+
+// ReviewerCandidate is an author's expertise in the requested files
+type ReviewerCandidate struct {
+	Author    string  `json:"author"`
+	Selected  bool    `json:"selected"`
+	Expertise float64 `json:"expertise"`
+	// Score is the expertise lowered by the author's open reviews
+	Score       float64    `json:"score"`
+	Load        int        `json:"load"`
+	Files       []string   `json:"files"`
+	LastTouched *time.Time `json:"last_touched"`
+}
+
+// ExcludedReviewer is an author with expertise in the requested files who cannot review them
+type ExcludedReviewer struct {
+	Author string `json:"author"`
+	// Reason is "author" for the change's author, "excluded" for requested exclusions and
+	// "inactive" for authors classified as inactive
+	Reason string `json:"reason"`
+}
+
+// CodeownersProposal is a CODEOWNERS file proposed from a project's ownership at directory
+// granularity
+type CodeownersProposal struct {
+	ProjectID int               `json:"project_id"`
+	Ownership string            `json:"ownership"`
+	Depth     int               `json:"depth"`
+	MinShare  float64           `json:"min_share"`
+	MaxOwners int               `json:"max_owners"`
+	Teams     bool              `json:"teams"`
+	Entries   []CodeownersEntry `json:"entries"`
+	// Content is the proposed CODEOWNERS file
+	Content string `json:"content"`
+}
+
+// CodeownersEntry is a proposed CODEOWNERS entry
+type CodeownersEntry struct {
+	Pattern string                 `json:"pattern"`
+	Files   int                    `json:"files"`
+	Owners  []CodeownersOwnerShare `json:"owners"`
+}
+
+// CodeownersOwnerShare is an owner's average ownership share (percent) of a directory's files
+type CodeownersOwnerShare struct {
+	Owner string  `json:"owner"`
+	Share float64 `json:"share"`
+}
+
+// CodeownersDriftReport lists the rules of a repository's CODEOWNERS file at HEAD whose
+// declared owners have not touched the files they own for StaleAfterMonths
+type CodeownersDriftReport struct {
+	ProjectID        int                   `json:"project_id"`
+	Path             string                `json:"path"`
+	StaleAfterMonths int                   `json:"stale_after_months"`
+	Rules            int                   `json:"rules"`
+	DriftedRules     []CodeownersRuleDrift `json:"drifted_rules"`
+}
+
+// CodeownersRuleDrift is a CODEOWNERS rule with stale or unknown owners, or owning no files
+type CodeownersRuleDrift struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	// Files is the number of files at HEAD the rule owns
+	Files       int                    `json:"files"`
+	LastChange  *time.Time             `json:"last_change"`
+	StaleOwners []CodeownersStaleOwner `json:"stale_owners"`
+	// UnknownOwners cannot be resolved to any author or team
+	UnknownOwners []string `json:"unknown_owners"`
+	// RecentAuthors changed the rule's files within StaleAfterMonths, most recent first
+	RecentAuthors []string `json:"recent_authors"`
+}
+
+// CodeownersStaleOwner is a declared owner who has not touched a rule's files recently;
+// LastTouched is null when they never did
+type CodeownersStaleOwner struct {
+	Owner       string     `json:"owner"`
+	LastTouched *time.Time `json:"last_touched"`
+}
@@ -0,0 +1,15 @@
+// Package migrations embeds the versioned schema migrations of every storage backend into the
+// binaries, one directory per database dialect. A version is a pair of scripts,
+// NNN_name.up.sql and NNN_name.down.sql, applied in version order by database.Migrator.
+//
+// Applied migrations are recorded with a checksum and must not be edited afterwards; schema
+// changes go into a new version for every dialect. A dialect's optional baseline.sql upgrades
+// databases created before migrations were tracked and runs together with the first version.
+package migrations
+
+import "embed"
+
+// FS holds the mysql, postgres and sqlite migration directories
+//
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,22 @@
+-- Drops every table of the initial schema, and with them all stored data, children first
+
+DROP TABLE IF EXISTS risk_policies;
+DROP TABLE IF EXISTS project_health_snapshots;
+DROP TABLE IF EXISTS releases;
+DROP TABLE IF EXISTS issues;
+DROP TABLE IF EXISTS commit_issues;
+DROP TABLE IF EXISTS issue_patterns;
+DROP TABLE IF EXISTS commit_classification_rules;
+DROP TABLE IF EXISTS file_daily_stats;
+DROP TABLE IF EXISTS file_pair_coupling;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS author_statuses;
+DROP TABLE IF EXISTS file_blame_segments;
+DROP TABLE IF EXISTS file_metrics;
+DROP TABLE IF EXISTS file_complexity_samples;
+DROP TABLE IF EXISTS changes;
+DROP TABLE IF EXISTS commits;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS projects;
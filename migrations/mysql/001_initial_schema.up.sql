@@ -0,0 +1,310 @@
+-- CodeEcho schema for MySQL 8.0. Tables are only created when missing, so that databases created
+-- from schema.sql before migrations were tracked keep theirs; the migrator then runs baseline.sql
+-- to bring those tables up to this schema.
+
+-- Projects table
+CREATE TABLE IF NOT EXISTS projects (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    repo_path VARCHAR(500) NOT NULL,
+    last_analyzed_hash VARCHAR(40),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    repo_type ENUM('git_url', 'local_dir', 'private_git', 'local_path') NOT NULL DEFAULT 'git_url',
+    auth_username VARCHAR(255) NULL,
+    auth_token TEXT NULL,
+    auth_ssh_key TEXT NULL,
+    INDEX idx_name (name),
+    INDEX idx_repo_path (repo_path(255)),
+    INDEX idx_last_analyzed_hash (last_analyzed_hash),
+    INDEX idx_projects_repo_type (repo_type)
+);
+
+-- Users table for authentication
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    role ENUM('admin', 'user') DEFAULT 'user',
+    is_active BOOLEAN DEFAULT TRUE,
+    email_verified_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    INDEX idx_email (email),
+    INDEX idx_role (role),
+    INDEX idx_is_active (is_active)
+);
+
+-- Refresh tokens table for JWT management
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id),
+    INDEX idx_token_hash (token_hash),
+    INDEX idx_expires_at (expires_at)
+);
+
+-- Commits table
+CREATE TABLE IF NOT EXISTS commits (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    hash VARCHAR(40) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    category VARCHAR(20) NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_hash (project_id, hash),
+    INDEX idx_project_id (project_id),
+    INDEX idx_hash (hash),
+    INDEX idx_author (author),
+    INDEX idx_timestamp (timestamp),
+    INDEX idx_commits_project_category (project_id, category)
+);
+
+-- Changes table
+CREATE TABLE IF NOT EXISTS changes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    commit_id INT NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    lines_added INT NOT NULL DEFAULT 0,
+    lines_deleted INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (commit_id) REFERENCES commits(id) ON DELETE CASCADE,
+    INDEX idx_commit_id (commit_id),
+    INDEX idx_file_path (file_path(255)),
+    INDEX idx_lines_stats (lines_added, lines_deleted)
+);
+
+-- File complexity samples (indentation complexity and LOC per file revision)
+CREATE TABLE IF NOT EXISTS file_complexity_samples (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    commit_id INT NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    lines_of_code INT NOT NULL DEFAULT 0,
+    total_complexity INT NOT NULL DEFAULT 0,
+    mean_complexity DOUBLE NOT NULL DEFAULT 0,
+    max_complexity INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (commit_id) REFERENCES commits(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_commit_file (commit_id, file_path(255)),
+    INDEX idx_project_file (project_id, file_path(255))
+);
+
+-- Per-file size measured from the tree at the analysed commit
+CREATE TABLE IF NOT EXISTS file_metrics (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    language VARCHAR(50) NOT NULL,
+    code_lines INT NOT NULL DEFAULT 0,
+    comment_lines INT NOT NULL DEFAULT 0,
+    blank_lines INT NOT NULL DEFAULT 0,
+    is_binary BOOLEAN NOT NULL DEFAULT FALSE,
+    measured_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    INDEX idx_project_file (project_id, file_path(255)),
+    INDEX idx_project_language (project_id, language)
+);
+
+-- Line-level authorship (git blame) of the tree at the analysed commit
+CREATE TABLE IF NOT EXISTS file_blame_segments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    committed_at TIMESTAMP NOT NULL,
+    line_count INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    INDEX idx_project_file (project_id, file_path(255)),
+    INDEX idx_project_author (project_id, author)
+);
+
+-- Manual active/inactive overrides for project authors
+CREATE TABLE IF NOT EXISTS author_statuses (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_author (project_id, author)
+);
+
+-- Teams and the author identities of their members
+CREATE TABLE IF NOT EXISTS teams (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_team_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    team_id INT NOT NULL,
+    member_name VARCHAR(255) NOT NULL,
+    identity VARCHAR(255) NOT NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    INDEX idx_team_id (team_id),
+    INDEX idx_identity (identity)
+);
+
+-- Pre-computed temporal coupling: commits per month shared by two files (file_a = file_b holds the file's own commits)
+CREATE TABLE IF NOT EXISTS file_pair_coupling (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    pair_hash CHAR(40) NOT NULL,
+    file_a VARCHAR(1000) NOT NULL,
+    file_b VARCHAR(1000) NOT NULL,
+    bucket_month DATE NOT NULL,
+    shared_commits INT NOT NULL DEFAULT 0,
+    last_commit_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_pair_month (project_id, pair_hash, bucket_month),
+    INDEX idx_project_month (project_id, bucket_month)
+);
+
+-- Per-file, per-author daily rollup of the change history
+CREATE TABLE IF NOT EXISTS file_daily_stats (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    path_hash CHAR(40) NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    commits INT NOT NULL DEFAULT 0,
+    lines_added INT NOT NULL DEFAULT 0,
+    lines_deleted INT NOT NULL DEFAULT 0,
+    last_commit_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_file_author_day (project_id, path_hash, author, day),
+    INDEX idx_project_day (project_id, day)
+);
+
+-- Per-project commit classification rules, applied in position order
+CREATE TABLE IF NOT EXISTS commit_classification_rules (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    category VARCHAR(20) NOT NULL,
+    keywords TEXT NOT NULL,
+    pattern VARCHAR(500) NOT NULL DEFAULT '',
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    INDEX idx_project_position (project_id, position)
+);
+
+-- Issue keys referenced by commit messages, the per-project patterns extracting them and imported issue metadata
+CREATE TABLE IF NOT EXISTS issue_patterns (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    pattern VARCHAR(500) NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    INDEX idx_project_position (project_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS commit_issues (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    commit_id INT NOT NULL,
+    issue_key VARCHAR(100) NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (commit_id) REFERENCES commits(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_commit_issue (commit_id, issue_key),
+    INDEX idx_project_issue (project_id, issue_key)
+);
+
+CREATE TABLE IF NOT EXISTS issues (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    issue_key VARCHAR(100) NOT NULL,
+    title VARCHAR(500) NOT NULL DEFAULT '',
+    issue_type VARCHAR(50) NOT NULL DEFAULT '',
+    priority VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL DEFAULT '',
+    imported_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_issue (project_id, issue_key)
+);
+
+-- Repository tags recorded as releases
+CREATE TABLE IF NOT EXISTS releases (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    commit_date TIMESTAMP NOT NULL,
+    released_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_release (project_id, name),
+    INDEX idx_project_commit_date (project_id, commit_date)
+);
+
+-- Monthly technical debt snapshots
+CREATE TABLE IF NOT EXISTS project_health_snapshots (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    month DATE NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    score DOUBLE NOT NULL,
+    hotspot_complexity DOUBLE NOT NULL,
+    coupling DOUBLE NOT NULL,
+    bus_factor_risk DOUBLE NOT NULL,
+    stale_code DOUBLE NOT NULL,
+    files INT NOT NULL,
+    active_files INT NOT NULL,
+    policy_version INT NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_month (project_id, month)
+);
+
+-- Versioned per-project risk policies
+CREATE TABLE IF NOT EXISTS risk_policies (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    version INT NOT NULL,
+    change_frequency_medium DOUBLE NOT NULL,
+    change_frequency_high DOUBLE NOT NULL,
+    change_frequency_critical DOUBLE NOT NULL,
+    ownership_medium DOUBLE NOT NULL,
+    ownership_high DOUBLE NOT NULL,
+    ownership_critical DOUBLE NOT NULL,
+    bus_factor_medium DOUBLE NOT NULL,
+    bus_factor_high DOUBLE NOT NULL,
+    bus_factor_critical DOUBLE NOT NULL,
+    coupling_medium DOUBLE NOT NULL,
+    coupling_high DOUBLE NOT NULL,
+    coupling_critical DOUBLE NOT NULL,
+    coupling_min_shared_commits INT NOT NULL,
+    weight_hotspot_complexity DOUBLE NOT NULL,
+    weight_coupling DOUBLE NOT NULL,
+    weight_bus_factor_risk DOUBLE NOT NULL,
+    weight_stale_code DOUBLE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_version (project_id, version)
+);
+
+-- Insert a default admin user for testing (password: admin123)
+INSERT INTO users (email, password_hash, first_name, last_name, role) 
+VALUES (
+    'admin@codeecho.com', 
+    '$2a$10$mKkNIVP4s4eSdXo65Vw8WeplO8Ff/0IX/awtnx2OlCHrWNvxYL.ke', 
+    'Admin', 
+    'User', 
+    'admin'
+) ON DUPLICATE KEY UPDATE email = email;
@@ -0,0 +1,65 @@
+-- Upgrades a database created from schema.sql and the loose scripts in migrations/ before
+-- migrations were tracked to the schema of 001_initial_schema. The migrator runs it after 001, in
+-- the same transaction, when the projects table predates schema_migrations. Which of the loose
+-- scripts ran is unknown, so every column and index is only added when it is missing.
+
+-- 002 and 003: repository type and credentials
+SET @add_repository_columns = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'projects' AND column_name = 'repo_type') = 0,
+    'ALTER TABLE projects
+        ADD COLUMN repo_type ENUM(''git_url'', ''local_dir'', ''private_git'', ''local_path'') NOT NULL DEFAULT ''git_url'',
+        ADD COLUMN auth_username VARCHAR(255) NULL,
+        ADD COLUMN auth_token TEXT NULL,
+        ADD COLUMN auth_ssh_key TEXT NULL',
+    'SELECT 1'
+);
+PREPARE add_repository_columns FROM @add_repository_columns;
+EXECUTE add_repository_columns;
+DEALLOCATE PREPARE add_repository_columns;
+
+-- Databases that got repo_type before local_path was added
+ALTER TABLE projects
+MODIFY COLUMN repo_type ENUM('git_url', 'local_dir', 'private_git', 'local_path') NOT NULL DEFAULT 'git_url';
+
+SET @add_repository_type_index = IF(
+    (SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'projects' AND index_name = 'idx_projects_repo_type') = 0,
+    'CREATE INDEX idx_projects_repo_type ON projects(repo_type)',
+    'SELECT 1'
+);
+PREPARE add_repository_type_index FROM @add_repository_type_index;
+EXECUTE add_repository_type_index;
+DEALLOCATE PREPARE add_repository_type_index;
+
+-- 011: commit categories
+SET @add_commit_category = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'commits' AND column_name = 'category') = 0,
+    'ALTER TABLE commits ADD COLUMN category VARCHAR(20) NULL',
+    'SELECT 1'
+);
+PREPARE add_commit_category FROM @add_commit_category;
+EXECUTE add_commit_category;
+DEALLOCATE PREPARE add_commit_category;
+
+SET @add_commit_category_index = IF(
+    (SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'commits' AND index_name = 'idx_commits_project_category') = 0,
+    'CREATE INDEX idx_commits_project_category ON commits(project_id, category)',
+    'SELECT 1'
+);
+PREPARE add_commit_category_index FROM @add_commit_category_index;
+EXECUTE add_commit_category_index;
+DEALLOCATE PREPARE add_commit_category_index;
+
+-- 015: the risk policy version a health snapshot was computed with
+SET @add_policy_version = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'project_health_snapshots' AND column_name = 'policy_version') = 0,
+    'ALTER TABLE project_health_snapshots ADD COLUMN policy_version INT NOT NULL DEFAULT 0',
+    'SELECT 1'
+);
+PREPARE add_policy_version FROM @add_policy_version;
+EXECUTE add_policy_version;
+DEALLOCATE PREPARE add_policy_version;
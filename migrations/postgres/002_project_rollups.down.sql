@@ -0,0 +1,3 @@
+-- Drops the rollup completeness marks; the analytics then read the change history again
+
+DROP TABLE IF EXISTS project_rollups;
@@ -0,0 +1,10 @@
+-- Marks the derived tables that cover a project's whole stored history; the analytics only read
+-- a rollup such as file_pair_coupling for projects it is complete for
+
+CREATE TABLE project_rollups (
+    project_id INTEGER NOT NULL,
+    rollup VARCHAR(50) NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, rollup),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
@@ -0,0 +1,305 @@
+-- CodeEcho schema for SQLite 3, the MySQL schema in migrations/mysql translated. Timestamps are
+-- stored as UTC text in the form 'YYYY-MM-DD HH:MM:SS' and dates as 'YYYY-MM-DD'. Every statement
+-- is idempotent, so databases created before the migrations were tracked are adopted as they are.
+
+-- Projects table
+CREATE TABLE IF NOT EXISTS projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    repo_path VARCHAR(500) NOT NULL,
+    last_analyzed_hash VARCHAR(40),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    repo_type VARCHAR(20) NOT NULL DEFAULT 'git_url'
+        CHECK (repo_type IN ('git_url', 'local_dir', 'private_git', 'local_path')),
+    auth_username VARCHAR(255),
+    auth_token TEXT,
+    auth_ssh_key TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_projects_name ON projects(name);
+CREATE INDEX IF NOT EXISTS idx_projects_repo_path ON projects(repo_path);
+CREATE INDEX IF NOT EXISTS idx_projects_last_analyzed_hash ON projects(last_analyzed_hash);
+CREATE INDEX IF NOT EXISTS idx_projects_repo_type ON projects(repo_type);
+
+-- Users table for authentication
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    role VARCHAR(10) DEFAULT 'user' CHECK (role IN ('admin', 'user')),
+    is_active BOOLEAN DEFAULT TRUE,
+    email_verified_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active);
+
+-- Refresh tokens table for JWT management
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- Commits table
+CREATE TABLE IF NOT EXISTS commits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    hash VARCHAR(40) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    category VARCHAR(20),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, hash)
+);
+CREATE INDEX IF NOT EXISTS idx_commits_hash ON commits(hash);
+CREATE INDEX IF NOT EXISTS idx_commits_author ON commits(author);
+CREATE INDEX IF NOT EXISTS idx_commits_timestamp ON commits(timestamp);
+CREATE INDEX IF NOT EXISTS idx_commits_project_category ON commits(project_id, category);
+
+-- Changes table
+CREATE TABLE IF NOT EXISTS changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    commit_id INTEGER NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    lines_added INTEGER NOT NULL DEFAULT 0,
+    lines_deleted INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (commit_id) REFERENCES commits(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_changes_commit_id ON changes(commit_id);
+CREATE INDEX IF NOT EXISTS idx_changes_file_path ON changes(file_path);
+
+-- File complexity samples (indentation complexity and LOC per file revision)
+CREATE TABLE IF NOT EXISTS file_complexity_samples (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    commit_id INTEGER NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    lines_of_code INTEGER NOT NULL DEFAULT 0,
+    total_complexity INTEGER NOT NULL DEFAULT 0,
+    mean_complexity DOUBLE NOT NULL DEFAULT 0,
+    max_complexity INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (commit_id) REFERENCES commits(id) ON DELETE CASCADE,
+    UNIQUE (commit_id, file_path)
+);
+CREATE INDEX IF NOT EXISTS idx_file_complexity_samples_project_file ON file_complexity_samples(project_id, file_path);
+
+-- Per-file size measured from the tree at the analysed commit
+CREATE TABLE IF NOT EXISTS file_metrics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    language VARCHAR(50) NOT NULL,
+    code_lines INTEGER NOT NULL DEFAULT 0,
+    comment_lines INTEGER NOT NULL DEFAULT 0,
+    blank_lines INTEGER NOT NULL DEFAULT 0,
+    is_binary BOOLEAN NOT NULL DEFAULT FALSE,
+    measured_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_file_metrics_project_file ON file_metrics(project_id, file_path);
+CREATE INDEX IF NOT EXISTS idx_file_metrics_project_language ON file_metrics(project_id, language);
+
+-- Line-level authorship (git blame) of the tree at the analysed commit
+CREATE TABLE IF NOT EXISTS file_blame_segments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    committed_at TIMESTAMP NOT NULL,
+    line_count INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_file_blame_segments_project_file ON file_blame_segments(project_id, file_path);
+CREATE INDEX IF NOT EXISTS idx_file_blame_segments_project_author ON file_blame_segments(project_id, author);
+
+-- Manual active/inactive overrides for project authors
+CREATE TABLE IF NOT EXISTS author_statuses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, author)
+);
+
+-- Teams and the author identities of their members
+CREATE TABLE IF NOT EXISTS teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL,
+    member_name VARCHAR(255) NOT NULL,
+    identity VARCHAR(255) NOT NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_identity ON team_members(identity);
+
+-- Pre-computed temporal coupling: commits per month shared by two files (file_a = file_b holds the file's own commits)
+CREATE TABLE IF NOT EXISTS file_pair_coupling (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    pair_hash CHAR(40) NOT NULL,
+    file_a VARCHAR(1000) NOT NULL,
+    file_b VARCHAR(1000) NOT NULL,
+    bucket_month DATE NOT NULL,
+    shared_commits INTEGER NOT NULL DEFAULT 0,
+    last_commit_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, pair_hash, bucket_month)
+);
+CREATE INDEX IF NOT EXISTS idx_file_pair_coupling_project_month ON file_pair_coupling(project_id, bucket_month);
+
+-- Per-file, per-author daily rollup of the change history
+CREATE TABLE IF NOT EXISTS file_daily_stats (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    path_hash CHAR(40) NOT NULL,
+    file_path VARCHAR(1000) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    commits INTEGER NOT NULL DEFAULT 0,
+    lines_added INTEGER NOT NULL DEFAULT 0,
+    lines_deleted INTEGER NOT NULL DEFAULT 0,
+    last_commit_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, path_hash, author, day)
+);
+CREATE INDEX IF NOT EXISTS idx_file_daily_stats_project_day ON file_daily_stats(project_id, day);
+
+-- Per-project commit classification rules, applied in position order
+CREATE TABLE IF NOT EXISTS commit_classification_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    category VARCHAR(20) NOT NULL,
+    keywords TEXT NOT NULL,
+    pattern VARCHAR(500) NOT NULL DEFAULT '',
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_commit_classification_rules_project_position ON commit_classification_rules(project_id, position);
+
+-- Issue keys referenced by commit messages, the per-project patterns extracting them and imported issue metadata
+CREATE TABLE IF NOT EXISTS issue_patterns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    pattern VARCHAR(500) NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_issue_patterns_project_position ON issue_patterns(project_id, position);
+
+CREATE TABLE IF NOT EXISTS commit_issues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    commit_id INTEGER NOT NULL,
+    issue_key VARCHAR(100) NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (commit_id) REFERENCES commits(id) ON DELETE CASCADE,
+    UNIQUE (commit_id, issue_key)
+);
+CREATE INDEX IF NOT EXISTS idx_commit_issues_project_issue ON commit_issues(project_id, issue_key);
+
+CREATE TABLE IF NOT EXISTS issues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    issue_key VARCHAR(100) NOT NULL,
+    title VARCHAR(500) NOT NULL DEFAULT '',
+    issue_type VARCHAR(50) NOT NULL DEFAULT '',
+    priority VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL DEFAULT '',
+    imported_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, issue_key)
+);
+
+-- Repository tags recorded as releases
+CREATE TABLE IF NOT EXISTS releases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    commit_date TIMESTAMP NOT NULL,
+    released_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, name)
+);
+CREATE INDEX IF NOT EXISTS idx_releases_project_commit_date ON releases(project_id, commit_date);
+
+-- Monthly technical debt snapshots
+CREATE TABLE IF NOT EXISTS project_health_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    month DATE NOT NULL,
+    commit_hash VARCHAR(40) NOT NULL,
+    score DOUBLE NOT NULL,
+    hotspot_complexity DOUBLE NOT NULL,
+    coupling DOUBLE NOT NULL,
+    bus_factor_risk DOUBLE NOT NULL,
+    stale_code DOUBLE NOT NULL,
+    files INTEGER NOT NULL,
+    active_files INTEGER NOT NULL,
+    policy_version INTEGER NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, month)
+);
+
+-- Versioned per-project risk policies
+CREATE TABLE IF NOT EXISTS risk_policies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    change_frequency_medium DOUBLE NOT NULL,
+    change_frequency_high DOUBLE NOT NULL,
+    change_frequency_critical DOUBLE NOT NULL,
+    ownership_medium DOUBLE NOT NULL,
+    ownership_high DOUBLE NOT NULL,
+    ownership_critical DOUBLE NOT NULL,
+    bus_factor_medium DOUBLE NOT NULL,
+    bus_factor_high DOUBLE NOT NULL,
+    bus_factor_critical DOUBLE NOT NULL,
+    coupling_medium DOUBLE NOT NULL,
+    coupling_high DOUBLE NOT NULL,
+    coupling_critical DOUBLE NOT NULL,
+    coupling_min_shared_commits INTEGER NOT NULL,
+    weight_hotspot_complexity DOUBLE NOT NULL,
+    weight_coupling DOUBLE NOT NULL,
+    weight_bus_factor_risk DOUBLE NOT NULL,
+    weight_stale_code DOUBLE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, version)
+);
+
+-- Insert a default admin user for testing (password: admin123)
+INSERT INTO users (email, password_hash, first_name, last_name, role)
+VALUES (
+    'admin@codeecho.com',
+    '$2a$10$mKkNIVP4s4eSdXo65Vw8WeplO8Ff/0IX/awtnx2OlCHrWNvxYL.ke',
+    'Admin',
+    'User',
+    'admin'
+) ON CONFLICT (email) DO NOTHING;